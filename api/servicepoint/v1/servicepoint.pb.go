@@ -0,0 +1,1431 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: servicepoint/v1/servicepoint.proto
+
+package servicepointv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ServicePoint struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ShortName    string                 `protobuf:"bytes,3,opt,name=short_name,json=shortName,proto3" json:"short_name,omitempty"`
+	OfficeNumber string                 `protobuf:"bytes,4,opt,name=office_number,json=officeNumber,proto3" json:"office_number,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ShardId      int32                  `protobuf:"varint,7,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
+	// Bumped on every write; send it back as expected_version to make an
+	// update conditional.
+	Version int64 `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	// Set once the service point is soft-deleted.
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// Most tickets waiting at once, zero for no limit.
+	MaxQueueLength int32 `protobuf:"varint,10,opt,name=max_queue_length,json=maxQueueLength,proto3" json:"max_queue_length,omitempty"`
+	// Most tickets issued per day, zero for no limit.
+	DailyTicketQuota int32 `protobuf:"varint,11,opt,name=daily_ticket_quota,json=dailyTicketQuota,proto3" json:"daily_ticket_quota,omitempty"`
+	// open, paused or closed.
+	Status string `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`
+	// Why the service point is paused or closed, shown to visitors.
+	StatusReason string `protobuf:"bytes,13,opt,name=status_reason,json=statusReason,proto3" json:"status_reason,omitempty"`
+	// When a paused service point is expected to resume.
+	ReturnAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=return_at,json=returnAt,proto3" json:"return_at,omitempty"`
+	// Office the service point is in, zero if none.
+	OfficeId      int64 `protobuf:"varint,15,opt,name=office_id,json=officeId,proto3" json:"office_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServicePoint) Reset() {
+	*x = ServicePoint{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServicePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServicePoint) ProtoMessage() {}
+
+func (x *ServicePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServicePoint.ProtoReflect.Descriptor instead.
+func (*ServicePoint) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ServicePoint) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ServicePoint) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServicePoint) GetShortName() string {
+	if x != nil {
+		return x.ShortName
+	}
+	return ""
+}
+
+func (x *ServicePoint) GetOfficeNumber() string {
+	if x != nil {
+		return x.OfficeNumber
+	}
+	return ""
+}
+
+func (x *ServicePoint) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ServicePoint) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *ServicePoint) GetShardId() int32 {
+	if x != nil {
+		return x.ShardId
+	}
+	return 0
+}
+
+func (x *ServicePoint) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ServicePoint) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *ServicePoint) GetMaxQueueLength() int32 {
+	if x != nil {
+		return x.MaxQueueLength
+	}
+	return 0
+}
+
+func (x *ServicePoint) GetDailyTicketQuota() int32 {
+	if x != nil {
+		return x.DailyTicketQuota
+	}
+	return 0
+}
+
+func (x *ServicePoint) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ServicePoint) GetStatusReason() string {
+	if x != nil {
+		return x.StatusReason
+	}
+	return ""
+}
+
+func (x *ServicePoint) GetReturnAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReturnAt
+	}
+	return nil
+}
+
+func (x *ServicePoint) GetOfficeId() int64 {
+	if x != nil {
+		return x.OfficeId
+	}
+	return 0
+}
+
+type Ticket struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ticket string                 `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	// Place in the queue counting the ticket itself: 1 is next, with nobody
+	// ahead. Zero once the ticket is called or when it could not be recorded.
+	Position int32 `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	// Unset until the service point has called enough tickets to estimate.
+	EstimatedWaitSeconds *int64 `protobuf:"varint,3,opt,name=estimated_wait_seconds,json=estimatedWaitSeconds,proto3,oneof" json:"estimated_wait_seconds,omitempty"`
+	// waiting, called, served, no-show, cancelled or transferred; empty when
+	// the ticket could not be recorded.
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// Set once the ticket is called.
+	OfficeNumber string `protobuf:"bytes,5,opt,name=office_number,json=officeNumber,proto3" json:"office_number,omitempty"`
+	// Line of the service point the ticket is in.
+	Class string `protobuf:"bytes,6,opt,name=class,proto3" json:"class,omitempty"`
+	// Desk of the operator who called the ticket, if one was logged in.
+	DeskNumber string `protobuf:"bytes,7,opt,name=desk_number,json=deskNumber,proto3" json:"desk_number,omitempty"`
+	// Category the ticket was issued for, if any.
+	CategoryId    int64 `protobuf:"varint,8,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ticket) Reset() {
+	*x = Ticket{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ticket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ticket) ProtoMessage() {}
+
+func (x *Ticket) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ticket.ProtoReflect.Descriptor instead.
+func (*Ticket) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Ticket) GetTicket() string {
+	if x != nil {
+		return x.Ticket
+	}
+	return ""
+}
+
+func (x *Ticket) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *Ticket) GetEstimatedWaitSeconds() int64 {
+	if x != nil && x.EstimatedWaitSeconds != nil {
+		return *x.EstimatedWaitSeconds
+	}
+	return 0
+}
+
+func (x *Ticket) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Ticket) GetOfficeNumber() string {
+	if x != nil {
+		return x.OfficeNumber
+	}
+	return ""
+}
+
+func (x *Ticket) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *Ticket) GetDeskNumber() string {
+	if x != nil {
+		return x.DeskNumber
+	}
+	return ""
+}
+
+func (x *Ticket) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+// CreateServicePointRequest creates a service point under a server-allocated
+// id.
+type CreateServicePointRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ShortName        string                 `protobuf:"bytes,2,opt,name=short_name,json=shortName,proto3" json:"short_name,omitempty"`
+	OfficeNumber     string                 `protobuf:"bytes,3,opt,name=office_number,json=officeNumber,proto3" json:"office_number,omitempty"`
+	MaxQueueLength   int32                  `protobuf:"varint,4,opt,name=max_queue_length,json=maxQueueLength,proto3" json:"max_queue_length,omitempty"`
+	DailyTicketQuota int32                  `protobuf:"varint,5,opt,name=daily_ticket_quota,json=dailyTicketQuota,proto3" json:"daily_ticket_quota,omitempty"`
+	// Places the service point in an office; office_number may then be left
+	// empty and defaults to the number of the office.
+	OfficeId      int64 `protobuf:"varint,6,opt,name=office_id,json=officeId,proto3" json:"office_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServicePointRequest) Reset() {
+	*x = CreateServicePointRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServicePointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServicePointRequest) ProtoMessage() {}
+
+func (x *CreateServicePointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServicePointRequest.ProtoReflect.Descriptor instead.
+func (*CreateServicePointRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateServicePointRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateServicePointRequest) GetShortName() string {
+	if x != nil {
+		return x.ShortName
+	}
+	return ""
+}
+
+func (x *CreateServicePointRequest) GetOfficeNumber() string {
+	if x != nil {
+		return x.OfficeNumber
+	}
+	return ""
+}
+
+func (x *CreateServicePointRequest) GetMaxQueueLength() int32 {
+	if x != nil {
+		return x.MaxQueueLength
+	}
+	return 0
+}
+
+func (x *CreateServicePointRequest) GetDailyTicketQuota() int32 {
+	if x != nil {
+		return x.DailyTicketQuota
+	}
+	return 0
+}
+
+func (x *CreateServicePointRequest) GetOfficeId() int64 {
+	if x != nil {
+		return x.OfficeId
+	}
+	return 0
+}
+
+type UpsertServicePointRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ShortName    string                 `protobuf:"bytes,3,opt,name=short_name,json=shortName,proto3" json:"short_name,omitempty"`
+	OfficeNumber string                 `protobuf:"bytes,4,opt,name=office_number,json=officeNumber,proto3" json:"office_number,omitempty"`
+	// When set, only an existing service point at this version is replaced.
+	ExpectedVersion  int64 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	MaxQueueLength   int32 `protobuf:"varint,6,opt,name=max_queue_length,json=maxQueueLength,proto3" json:"max_queue_length,omitempty"`
+	DailyTicketQuota int32 `protobuf:"varint,7,opt,name=daily_ticket_quota,json=dailyTicketQuota,proto3" json:"daily_ticket_quota,omitempty"`
+	// Places the service point in an office; office_number may then be left
+	// empty and defaults to the number of the office.
+	OfficeId      int64 `protobuf:"varint,8,opt,name=office_id,json=officeId,proto3" json:"office_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertServicePointRequest) Reset() {
+	*x = UpsertServicePointRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertServicePointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertServicePointRequest) ProtoMessage() {}
+
+func (x *UpsertServicePointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertServicePointRequest.ProtoReflect.Descriptor instead.
+func (*UpsertServicePointRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpsertServicePointRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpsertServicePointRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpsertServicePointRequest) GetShortName() string {
+	if x != nil {
+		return x.ShortName
+	}
+	return ""
+}
+
+func (x *UpsertServicePointRequest) GetOfficeNumber() string {
+	if x != nil {
+		return x.OfficeNumber
+	}
+	return ""
+}
+
+func (x *UpsertServicePointRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *UpsertServicePointRequest) GetMaxQueueLength() int32 {
+	if x != nil {
+		return x.MaxQueueLength
+	}
+	return 0
+}
+
+func (x *UpsertServicePointRequest) GetDailyTicketQuota() int32 {
+	if x != nil {
+		return x.DailyTicketQuota
+	}
+	return 0
+}
+
+func (x *UpsertServicePointRequest) GetOfficeId() int64 {
+	if x != nil {
+		return x.OfficeId
+	}
+	return 0
+}
+
+type GetServicePointRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Also return the service point if it is soft-deleted.
+	IncludeDeleted bool `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetServicePointRequest) Reset() {
+	*x = GetServicePointRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServicePointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServicePointRequest) ProtoMessage() {}
+
+func (x *GetServicePointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServicePointRequest.ProtoReflect.Descriptor instead.
+func (*GetServicePointRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetServicePointRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetServicePointRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type DeleteServicePointRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// When set, the service point is only deleted at this version.
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteServicePointRequest) Reset() {
+	*x = DeleteServicePointRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServicePointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServicePointRequest) ProtoMessage() {}
+
+func (x *DeleteServicePointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServicePointRequest.ProtoReflect.Descriptor instead.
+func (*DeleteServicePointRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteServicePointRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteServicePointRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type RestoreServicePointRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// When set, the service point is only restored at this version.
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RestoreServicePointRequest) Reset() {
+	*x = RestoreServicePointRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreServicePointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreServicePointRequest) ProtoMessage() {}
+
+func (x *RestoreServicePointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreServicePointRequest.ProtoReflect.Descriptor instead.
+func (*RestoreServicePointRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RestoreServicePointRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RestoreServicePointRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type PauseServicePointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	ReturnAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=return_at,json=returnAt,proto3" json:"return_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseServicePointRequest) Reset() {
+	*x = PauseServicePointRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseServicePointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseServicePointRequest) ProtoMessage() {}
+
+func (x *PauseServicePointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseServicePointRequest.ProtoReflect.Descriptor instead.
+func (*PauseServicePointRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PauseServicePointRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PauseServicePointRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PauseServicePointRequest) GetReturnAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReturnAt
+	}
+	return nil
+}
+
+type ResumeServicePointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeServicePointRequest) Reset() {
+	*x = ResumeServicePointRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeServicePointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeServicePointRequest) ProtoMessage() {}
+
+func (x *ResumeServicePointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeServicePointRequest.ProtoReflect.Descriptor instead.
+func (*ResumeServicePointRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ResumeServicePointRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CloseServicePointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	ReturnAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=return_at,json=returnAt,proto3" json:"return_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseServicePointRequest) Reset() {
+	*x = CloseServicePointRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseServicePointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseServicePointRequest) ProtoMessage() {}
+
+func (x *CloseServicePointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseServicePointRequest.ProtoReflect.Descriptor instead.
+func (*CloseServicePointRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CloseServicePointRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CloseServicePointRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CloseServicePointRequest) GetReturnAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReturnAt
+	}
+	return nil
+}
+
+type ListServicePointsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Also return soft-deleted service points.
+	IncludeDeleted bool `protobuf:"varint,1,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListServicePointsRequest) Reset() {
+	*x = ListServicePointsRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServicePointsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServicePointsRequest) ProtoMessage() {}
+
+func (x *ListServicePointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServicePointsRequest.ProtoReflect.Descriptor instead.
+func (*ListServicePointsRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListServicePointsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type ListServicePointsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServicePoints []*ServicePoint        `protobuf:"bytes,1,rep,name=service_points,json=servicePoints,proto3" json:"service_points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServicePointsResponse) Reset() {
+	*x = ListServicePointsResponse{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServicePointsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServicePointsResponse) ProtoMessage() {}
+
+func (x *ListServicePointsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServicePointsResponse.ProtoReflect.Descriptor instead.
+func (*ListServicePointsResponse) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListServicePointsResponse) GetServicePoints() []*ServicePoint {
+	if x != nil {
+		return x.ServicePoints
+	}
+	return nil
+}
+
+type EnqueueRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServicePointId int64                  `protobuf:"varint,1,opt,name=service_point_id,json=servicePointId,proto3" json:"service_point_id,omitempty"`
+	// appointment, priority or regular; regular if empty.
+	Class         string `protobuf:"bytes,2,opt,name=class,proto3" json:"class,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnqueueRequest) Reset() {
+	*x = EnqueueRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnqueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnqueueRequest) ProtoMessage() {}
+
+func (x *EnqueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnqueueRequest.ProtoReflect.Descriptor instead.
+func (*EnqueueRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *EnqueueRequest) GetServicePointId() int64 {
+	if x != nil {
+		return x.ServicePointId
+	}
+	return 0
+}
+
+func (x *EnqueueRequest) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+type DequeueRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServicePointId int64                  `protobuf:"varint,1,opt,name=service_point_id,json=servicePointId,proto3" json:"service_point_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DequeueRequest) Reset() {
+	*x = DequeueRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DequeueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DequeueRequest) ProtoMessage() {}
+
+func (x *DequeueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DequeueRequest.ProtoReflect.Descriptor instead.
+func (*DequeueRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DequeueRequest) GetServicePointId() int64 {
+	if x != nil {
+		return x.ServicePointId
+	}
+	return 0
+}
+
+// RecallRequest calls the current ticket again, or marks it as a no-show once
+// it has been recalled too often.
+type RecallRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServicePointId int64                  `protobuf:"varint,1,opt,name=service_point_id,json=servicePointId,proto3" json:"service_point_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RecallRequest) Reset() {
+	*x = RecallRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecallRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecallRequest) ProtoMessage() {}
+
+func (x *RecallRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecallRequest.ProtoReflect.Descriptor instead.
+func (*RecallRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RecallRequest) GetServicePointId() int64 {
+	if x != nil {
+		return x.ServicePointId
+	}
+	return 0
+}
+
+// SkipRequest marks the current ticket as a no-show and calls the next one.
+type SkipRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServicePointId int64                  `protobuf:"varint,1,opt,name=service_point_id,json=servicePointId,proto3" json:"service_point_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SkipRequest) Reset() {
+	*x = SkipRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkipRequest) ProtoMessage() {}
+
+func (x *SkipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkipRequest.ProtoReflect.Descriptor instead.
+func (*SkipRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SkipRequest) GetServicePointId() int64 {
+	if x != nil {
+		return x.ServicePointId
+	}
+	return 0
+}
+
+type CancelTicketRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServicePointId int64                  `protobuf:"varint,1,opt,name=service_point_id,json=servicePointId,proto3" json:"service_point_id,omitempty"`
+	Code           string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CancelTicketRequest) Reset() {
+	*x = CancelTicketRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTicketRequest) ProtoMessage() {}
+
+func (x *CancelTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTicketRequest.ProtoReflect.Descriptor instead.
+func (*CancelTicketRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CancelTicketRequest) GetServicePointId() int64 {
+	if x != nil {
+		return x.ServicePointId
+	}
+	return 0
+}
+
+func (x *CancelTicketRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// TransferTicketRequest sends the called ticket with code to the queue of
+// another service point under the same code.
+type TransferTicketRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Code           string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	ServicePointId int64                  `protobuf:"varint,2,opt,name=service_point_id,json=servicePointId,proto3" json:"service_point_id,omitempty"`
+	// Join the other queue at the front instead of the back.
+	Front bool `protobuf:"varint,3,opt,name=front,proto3" json:"front,omitempty"`
+	// Service point that called the ticket, needed when several service
+	// points have a ticket with the code called.
+	FromServicePointId int64 `protobuf:"varint,4,opt,name=from_service_point_id,json=fromServicePointId,proto3" json:"from_service_point_id,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TransferTicketRequest) Reset() {
+	*x = TransferTicketRequest{}
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferTicketRequest) ProtoMessage() {}
+
+func (x *TransferTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicepoint_v1_servicepoint_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferTicketRequest.ProtoReflect.Descriptor instead.
+func (*TransferTicketRequest) Descriptor() ([]byte, []int) {
+	return file_servicepoint_v1_servicepoint_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TransferTicketRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *TransferTicketRequest) GetServicePointId() int64 {
+	if x != nil {
+		return x.ServicePointId
+	}
+	return 0
+}
+
+func (x *TransferTicketRequest) GetFront() bool {
+	if x != nil {
+		return x.Front
+	}
+	return false
+}
+
+func (x *TransferTicketRequest) GetFromServicePointId() int64 {
+	if x != nil {
+		return x.FromServicePointId
+	}
+	return 0
+}
+
+var File_servicepoint_v1_servicepoint_proto protoreflect.FileDescriptor
+
+const file_servicepoint_v1_servicepoint_proto_rawDesc = "" +
+	"\n" +
+	"\"servicepoint/v1/servicepoint.proto\x12\x0fservicepoint.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc7\x04\n" +
+	"\fServicePoint\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"short_name\x18\x03 \x01(\tR\tshortName\x12#\n" +
+	"\roffice_number\x18\x04 \x01(\tR\fofficeNumber\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x19\n" +
+	"\bshard_id\x18\a \x01(\x05R\ashardId\x12\x18\n" +
+	"\aversion\x18\b \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"deleted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12(\n" +
+	"\x10max_queue_length\x18\n" +
+	" \x01(\x05R\x0emaxQueueLength\x12,\n" +
+	"\x12daily_ticket_quota\x18\v \x01(\x05R\x10dailyTicketQuota\x12\x16\n" +
+	"\x06status\x18\f \x01(\tR\x06status\x12#\n" +
+	"\rstatus_reason\x18\r \x01(\tR\fstatusReason\x127\n" +
+	"\treturn_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\breturnAt\x12\x1b\n" +
+	"\toffice_id\x18\x0f \x01(\x03R\bofficeId\"\xa7\x02\n" +
+	"\x06Ticket\x12\x16\n" +
+	"\x06ticket\x18\x01 \x01(\tR\x06ticket\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\x05R\bposition\x129\n" +
+	"\x16estimated_wait_seconds\x18\x03 \x01(\x03H\x00R\x14estimatedWaitSeconds\x88\x01\x01\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12#\n" +
+	"\roffice_number\x18\x05 \x01(\tR\fofficeNumber\x12\x14\n" +
+	"\x05class\x18\x06 \x01(\tR\x05class\x12\x1f\n" +
+	"\vdesk_number\x18\a \x01(\tR\n" +
+	"deskNumber\x12\x1f\n" +
+	"\vcategory_id\x18\b \x01(\x03R\n" +
+	"categoryIdB\x19\n" +
+	"\x17_estimated_wait_seconds\"\xe8\x01\n" +
+	"\x19CreateServicePointRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"short_name\x18\x02 \x01(\tR\tshortName\x12#\n" +
+	"\roffice_number\x18\x03 \x01(\tR\fofficeNumber\x12(\n" +
+	"\x10max_queue_length\x18\x04 \x01(\x05R\x0emaxQueueLength\x12,\n" +
+	"\x12daily_ticket_quota\x18\x05 \x01(\x05R\x10dailyTicketQuota\x12\x1b\n" +
+	"\toffice_id\x18\x06 \x01(\x03R\bofficeId\"\xa3\x02\n" +
+	"\x19UpsertServicePointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"short_name\x18\x03 \x01(\tR\tshortName\x12#\n" +
+	"\roffice_number\x18\x04 \x01(\tR\fofficeNumber\x12)\n" +
+	"\x10expected_version\x18\x05 \x01(\x03R\x0fexpectedVersion\x12(\n" +
+	"\x10max_queue_length\x18\x06 \x01(\x05R\x0emaxQueueLength\x12,\n" +
+	"\x12daily_ticket_quota\x18\a \x01(\x05R\x10dailyTicketQuota\x12\x1b\n" +
+	"\toffice_id\x18\b \x01(\x03R\bofficeId\"Q\n" +
+	"\x16GetServicePointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"V\n" +
+	"\x19DeleteServicePointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"W\n" +
+	"\x1aRestoreServicePointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"{\n" +
+	"\x18PauseServicePointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x127\n" +
+	"\treturn_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\breturnAt\"+\n" +
+	"\x19ResumeServicePointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"{\n" +
+	"\x18CloseServicePointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x127\n" +
+	"\treturn_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\breturnAt\"C\n" +
+	"\x18ListServicePointsRequest\x12'\n" +
+	"\x0finclude_deleted\x18\x01 \x01(\bR\x0eincludeDeleted\"a\n" +
+	"\x19ListServicePointsResponse\x12D\n" +
+	"\x0eservice_points\x18\x01 \x03(\v2\x1d.servicepoint.v1.ServicePointR\rservicePoints\"P\n" +
+	"\x0eEnqueueRequest\x12(\n" +
+	"\x10service_point_id\x18\x01 \x01(\x03R\x0eservicePointId\x12\x14\n" +
+	"\x05class\x18\x02 \x01(\tR\x05class\":\n" +
+	"\x0eDequeueRequest\x12(\n" +
+	"\x10service_point_id\x18\x01 \x01(\x03R\x0eservicePointId\"9\n" +
+	"\rRecallRequest\x12(\n" +
+	"\x10service_point_id\x18\x01 \x01(\x03R\x0eservicePointId\"7\n" +
+	"\vSkipRequest\x12(\n" +
+	"\x10service_point_id\x18\x01 \x01(\x03R\x0eservicePointId\"S\n" +
+	"\x13CancelTicketRequest\x12(\n" +
+	"\x10service_point_id\x18\x01 \x01(\x03R\x0eservicePointId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"\x9e\x01\n" +
+	"\x15TransferTicketRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12(\n" +
+	"\x10service_point_id\x18\x02 \x01(\x03R\x0eservicePointId\x12\x14\n" +
+	"\x05front\x18\x03 \x01(\bR\x05front\x121\n" +
+	"\x15from_service_point_id\x18\x04 \x01(\x03R\x12fromServicePointId2\xaf\n" +
+	"\n" +
+	"\x13ServicePointService\x12_\n" +
+	"\x12CreateServicePoint\x12*.servicepoint.v1.CreateServicePointRequest\x1a\x1d.servicepoint.v1.ServicePoint\x12_\n" +
+	"\x12UpsertServicePoint\x12*.servicepoint.v1.UpsertServicePointRequest\x1a\x1d.servicepoint.v1.ServicePoint\x12Y\n" +
+	"\x0fGetServicePoint\x12'.servicepoint.v1.GetServicePointRequest\x1a\x1d.servicepoint.v1.ServicePoint\x12_\n" +
+	"\x12DeleteServicePoint\x12*.servicepoint.v1.DeleteServicePointRequest\x1a\x1d.servicepoint.v1.ServicePoint\x12a\n" +
+	"\x13RestoreServicePoint\x12+.servicepoint.v1.RestoreServicePointRequest\x1a\x1d.servicepoint.v1.ServicePoint\x12]\n" +
+	"\x11PauseServicePoint\x12).servicepoint.v1.PauseServicePointRequest\x1a\x1d.servicepoint.v1.ServicePoint\x12_\n" +
+	"\x12ResumeServicePoint\x12*.servicepoint.v1.ResumeServicePointRequest\x1a\x1d.servicepoint.v1.ServicePoint\x12]\n" +
+	"\x11CloseServicePoint\x12).servicepoint.v1.CloseServicePointRequest\x1a\x1d.servicepoint.v1.ServicePoint\x12j\n" +
+	"\x11ListServicePoints\x12).servicepoint.v1.ListServicePointsRequest\x1a*.servicepoint.v1.ListServicePointsResponse\x12C\n" +
+	"\aEnqueue\x12\x1f.servicepoint.v1.EnqueueRequest\x1a\x17.servicepoint.v1.Ticket\x12C\n" +
+	"\aDequeue\x12\x1f.servicepoint.v1.DequeueRequest\x1a\x17.servicepoint.v1.Ticket\x12A\n" +
+	"\x06Recall\x12\x1e.servicepoint.v1.RecallRequest\x1a\x17.servicepoint.v1.Ticket\x12=\n" +
+	"\x04Skip\x12\x1c.servicepoint.v1.SkipRequest\x1a\x17.servicepoint.v1.Ticket\x12M\n" +
+	"\fCancelTicket\x12$.servicepoint.v1.CancelTicketRequest\x1a\x17.servicepoint.v1.Ticket\x12Q\n" +
+	"\x0eTransferTicket\x12&.servicepoint.v1.TransferTicketRequest\x1a\x17.servicepoint.v1.TicketBAZ?github.com/snnus/mainservice/api/servicepoint/v1;servicepointv1b\x06proto3"
+
+var (
+	file_servicepoint_v1_servicepoint_proto_rawDescOnce sync.Once
+	file_servicepoint_v1_servicepoint_proto_rawDescData []byte
+)
+
+func file_servicepoint_v1_servicepoint_proto_rawDescGZIP() []byte {
+	file_servicepoint_v1_servicepoint_proto_rawDescOnce.Do(func() {
+		file_servicepoint_v1_servicepoint_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_servicepoint_v1_servicepoint_proto_rawDesc), len(file_servicepoint_v1_servicepoint_proto_rawDesc)))
+	})
+	return file_servicepoint_v1_servicepoint_proto_rawDescData
+}
+
+var file_servicepoint_v1_servicepoint_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_servicepoint_v1_servicepoint_proto_goTypes = []any{
+	(*ServicePoint)(nil),               // 0: servicepoint.v1.ServicePoint
+	(*Ticket)(nil),                     // 1: servicepoint.v1.Ticket
+	(*CreateServicePointRequest)(nil),  // 2: servicepoint.v1.CreateServicePointRequest
+	(*UpsertServicePointRequest)(nil),  // 3: servicepoint.v1.UpsertServicePointRequest
+	(*GetServicePointRequest)(nil),     // 4: servicepoint.v1.GetServicePointRequest
+	(*DeleteServicePointRequest)(nil),  // 5: servicepoint.v1.DeleteServicePointRequest
+	(*RestoreServicePointRequest)(nil), // 6: servicepoint.v1.RestoreServicePointRequest
+	(*PauseServicePointRequest)(nil),   // 7: servicepoint.v1.PauseServicePointRequest
+	(*ResumeServicePointRequest)(nil),  // 8: servicepoint.v1.ResumeServicePointRequest
+	(*CloseServicePointRequest)(nil),   // 9: servicepoint.v1.CloseServicePointRequest
+	(*ListServicePointsRequest)(nil),   // 10: servicepoint.v1.ListServicePointsRequest
+	(*ListServicePointsResponse)(nil),  // 11: servicepoint.v1.ListServicePointsResponse
+	(*EnqueueRequest)(nil),             // 12: servicepoint.v1.EnqueueRequest
+	(*DequeueRequest)(nil),             // 13: servicepoint.v1.DequeueRequest
+	(*RecallRequest)(nil),              // 14: servicepoint.v1.RecallRequest
+	(*SkipRequest)(nil),                // 15: servicepoint.v1.SkipRequest
+	(*CancelTicketRequest)(nil),        // 16: servicepoint.v1.CancelTicketRequest
+	(*TransferTicketRequest)(nil),      // 17: servicepoint.v1.TransferTicketRequest
+	(*timestamppb.Timestamp)(nil),      // 18: google.protobuf.Timestamp
+}
+var file_servicepoint_v1_servicepoint_proto_depIdxs = []int32{
+	18, // 0: servicepoint.v1.ServicePoint.created_at:type_name -> google.protobuf.Timestamp
+	18, // 1: servicepoint.v1.ServicePoint.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 2: servicepoint.v1.ServicePoint.deleted_at:type_name -> google.protobuf.Timestamp
+	18, // 3: servicepoint.v1.ServicePoint.return_at:type_name -> google.protobuf.Timestamp
+	18, // 4: servicepoint.v1.PauseServicePointRequest.return_at:type_name -> google.protobuf.Timestamp
+	18, // 5: servicepoint.v1.CloseServicePointRequest.return_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: servicepoint.v1.ListServicePointsResponse.service_points:type_name -> servicepoint.v1.ServicePoint
+	2,  // 7: servicepoint.v1.ServicePointService.CreateServicePoint:input_type -> servicepoint.v1.CreateServicePointRequest
+	3,  // 8: servicepoint.v1.ServicePointService.UpsertServicePoint:input_type -> servicepoint.v1.UpsertServicePointRequest
+	4,  // 9: servicepoint.v1.ServicePointService.GetServicePoint:input_type -> servicepoint.v1.GetServicePointRequest
+	5,  // 10: servicepoint.v1.ServicePointService.DeleteServicePoint:input_type -> servicepoint.v1.DeleteServicePointRequest
+	6,  // 11: servicepoint.v1.ServicePointService.RestoreServicePoint:input_type -> servicepoint.v1.RestoreServicePointRequest
+	7,  // 12: servicepoint.v1.ServicePointService.PauseServicePoint:input_type -> servicepoint.v1.PauseServicePointRequest
+	8,  // 13: servicepoint.v1.ServicePointService.ResumeServicePoint:input_type -> servicepoint.v1.ResumeServicePointRequest
+	9,  // 14: servicepoint.v1.ServicePointService.CloseServicePoint:input_type -> servicepoint.v1.CloseServicePointRequest
+	10, // 15: servicepoint.v1.ServicePointService.ListServicePoints:input_type -> servicepoint.v1.ListServicePointsRequest
+	12, // 16: servicepoint.v1.ServicePointService.Enqueue:input_type -> servicepoint.v1.EnqueueRequest
+	13, // 17: servicepoint.v1.ServicePointService.Dequeue:input_type -> servicepoint.v1.DequeueRequest
+	14, // 18: servicepoint.v1.ServicePointService.Recall:input_type -> servicepoint.v1.RecallRequest
+	15, // 19: servicepoint.v1.ServicePointService.Skip:input_type -> servicepoint.v1.SkipRequest
+	16, // 20: servicepoint.v1.ServicePointService.CancelTicket:input_type -> servicepoint.v1.CancelTicketRequest
+	17, // 21: servicepoint.v1.ServicePointService.TransferTicket:input_type -> servicepoint.v1.TransferTicketRequest
+	0,  // 22: servicepoint.v1.ServicePointService.CreateServicePoint:output_type -> servicepoint.v1.ServicePoint
+	0,  // 23: servicepoint.v1.ServicePointService.UpsertServicePoint:output_type -> servicepoint.v1.ServicePoint
+	0,  // 24: servicepoint.v1.ServicePointService.GetServicePoint:output_type -> servicepoint.v1.ServicePoint
+	0,  // 25: servicepoint.v1.ServicePointService.DeleteServicePoint:output_type -> servicepoint.v1.ServicePoint
+	0,  // 26: servicepoint.v1.ServicePointService.RestoreServicePoint:output_type -> servicepoint.v1.ServicePoint
+	0,  // 27: servicepoint.v1.ServicePointService.PauseServicePoint:output_type -> servicepoint.v1.ServicePoint
+	0,  // 28: servicepoint.v1.ServicePointService.ResumeServicePoint:output_type -> servicepoint.v1.ServicePoint
+	0,  // 29: servicepoint.v1.ServicePointService.CloseServicePoint:output_type -> servicepoint.v1.ServicePoint
+	11, // 30: servicepoint.v1.ServicePointService.ListServicePoints:output_type -> servicepoint.v1.ListServicePointsResponse
+	1,  // 31: servicepoint.v1.ServicePointService.Enqueue:output_type -> servicepoint.v1.Ticket
+	1,  // 32: servicepoint.v1.ServicePointService.Dequeue:output_type -> servicepoint.v1.Ticket
+	1,  // 33: servicepoint.v1.ServicePointService.Recall:output_type -> servicepoint.v1.Ticket
+	1,  // 34: servicepoint.v1.ServicePointService.Skip:output_type -> servicepoint.v1.Ticket
+	1,  // 35: servicepoint.v1.ServicePointService.CancelTicket:output_type -> servicepoint.v1.Ticket
+	1,  // 36: servicepoint.v1.ServicePointService.TransferTicket:output_type -> servicepoint.v1.Ticket
+	22, // [22:37] is the sub-list for method output_type
+	7,  // [7:22] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_servicepoint_v1_servicepoint_proto_init() }
+func file_servicepoint_v1_servicepoint_proto_init() {
+	if File_servicepoint_v1_servicepoint_proto != nil {
+		return
+	}
+	file_servicepoint_v1_servicepoint_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_servicepoint_v1_servicepoint_proto_rawDesc), len(file_servicepoint_v1_servicepoint_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_servicepoint_v1_servicepoint_proto_goTypes,
+		DependencyIndexes: file_servicepoint_v1_servicepoint_proto_depIdxs,
+		MessageInfos:      file_servicepoint_v1_servicepoint_proto_msgTypes,
+	}.Build()
+	File_servicepoint_v1_servicepoint_proto = out.File
+	file_servicepoint_v1_servicepoint_proto_goTypes = nil
+	file_servicepoint_v1_servicepoint_proto_depIdxs = nil
+}
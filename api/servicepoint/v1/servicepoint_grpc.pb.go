@@ -0,0 +1,657 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: servicepoint/v1/servicepoint.proto
+
+package servicepointv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ServicePointService_CreateServicePoint_FullMethodName  = "/servicepoint.v1.ServicePointService/CreateServicePoint"
+	ServicePointService_UpsertServicePoint_FullMethodName  = "/servicepoint.v1.ServicePointService/UpsertServicePoint"
+	ServicePointService_GetServicePoint_FullMethodName     = "/servicepoint.v1.ServicePointService/GetServicePoint"
+	ServicePointService_DeleteServicePoint_FullMethodName  = "/servicepoint.v1.ServicePointService/DeleteServicePoint"
+	ServicePointService_RestoreServicePoint_FullMethodName = "/servicepoint.v1.ServicePointService/RestoreServicePoint"
+	ServicePointService_PauseServicePoint_FullMethodName   = "/servicepoint.v1.ServicePointService/PauseServicePoint"
+	ServicePointService_ResumeServicePoint_FullMethodName  = "/servicepoint.v1.ServicePointService/ResumeServicePoint"
+	ServicePointService_CloseServicePoint_FullMethodName   = "/servicepoint.v1.ServicePointService/CloseServicePoint"
+	ServicePointService_ListServicePoints_FullMethodName   = "/servicepoint.v1.ServicePointService/ListServicePoints"
+	ServicePointService_Enqueue_FullMethodName             = "/servicepoint.v1.ServicePointService/Enqueue"
+	ServicePointService_Dequeue_FullMethodName             = "/servicepoint.v1.ServicePointService/Dequeue"
+	ServicePointService_Recall_FullMethodName              = "/servicepoint.v1.ServicePointService/Recall"
+	ServicePointService_Skip_FullMethodName                = "/servicepoint.v1.ServicePointService/Skip"
+	ServicePointService_CancelTicket_FullMethodName        = "/servicepoint.v1.ServicePointService/CancelTicket"
+	ServicePointService_TransferTicket_FullMethodName      = "/servicepoint.v1.ServicePointService/TransferTicket"
+)
+
+// ServicePointServiceClient is the client API for ServicePointService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ServicePointService mirrors the REST routes served by SPHandler.
+type ServicePointServiceClient interface {
+	CreateServicePoint(ctx context.Context, in *CreateServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error)
+	UpsertServicePoint(ctx context.Context, in *UpsertServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error)
+	GetServicePoint(ctx context.Context, in *GetServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error)
+	DeleteServicePoint(ctx context.Context, in *DeleteServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error)
+	RestoreServicePoint(ctx context.Context, in *RestoreServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error)
+	PauseServicePoint(ctx context.Context, in *PauseServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error)
+	ResumeServicePoint(ctx context.Context, in *ResumeServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error)
+	CloseServicePoint(ctx context.Context, in *CloseServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error)
+	ListServicePoints(ctx context.Context, in *ListServicePointsRequest, opts ...grpc.CallOption) (*ListServicePointsResponse, error)
+	Enqueue(ctx context.Context, in *EnqueueRequest, opts ...grpc.CallOption) (*Ticket, error)
+	Dequeue(ctx context.Context, in *DequeueRequest, opts ...grpc.CallOption) (*Ticket, error)
+	Recall(ctx context.Context, in *RecallRequest, opts ...grpc.CallOption) (*Ticket, error)
+	Skip(ctx context.Context, in *SkipRequest, opts ...grpc.CallOption) (*Ticket, error)
+	CancelTicket(ctx context.Context, in *CancelTicketRequest, opts ...grpc.CallOption) (*Ticket, error)
+	TransferTicket(ctx context.Context, in *TransferTicketRequest, opts ...grpc.CallOption) (*Ticket, error)
+}
+
+type servicePointServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewServicePointServiceClient(cc grpc.ClientConnInterface) ServicePointServiceClient {
+	return &servicePointServiceClient{cc}
+}
+
+func (c *servicePointServiceClient) CreateServicePoint(ctx context.Context, in *CreateServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServicePoint)
+	err := c.cc.Invoke(ctx, ServicePointService_CreateServicePoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) UpsertServicePoint(ctx context.Context, in *UpsertServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServicePoint)
+	err := c.cc.Invoke(ctx, ServicePointService_UpsertServicePoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) GetServicePoint(ctx context.Context, in *GetServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServicePoint)
+	err := c.cc.Invoke(ctx, ServicePointService_GetServicePoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) DeleteServicePoint(ctx context.Context, in *DeleteServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServicePoint)
+	err := c.cc.Invoke(ctx, ServicePointService_DeleteServicePoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) RestoreServicePoint(ctx context.Context, in *RestoreServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServicePoint)
+	err := c.cc.Invoke(ctx, ServicePointService_RestoreServicePoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) PauseServicePoint(ctx context.Context, in *PauseServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServicePoint)
+	err := c.cc.Invoke(ctx, ServicePointService_PauseServicePoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) ResumeServicePoint(ctx context.Context, in *ResumeServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServicePoint)
+	err := c.cc.Invoke(ctx, ServicePointService_ResumeServicePoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) CloseServicePoint(ctx context.Context, in *CloseServicePointRequest, opts ...grpc.CallOption) (*ServicePoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServicePoint)
+	err := c.cc.Invoke(ctx, ServicePointService_CloseServicePoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) ListServicePoints(ctx context.Context, in *ListServicePointsRequest, opts ...grpc.CallOption) (*ListServicePointsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListServicePointsResponse)
+	err := c.cc.Invoke(ctx, ServicePointService_ListServicePoints_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) Enqueue(ctx context.Context, in *EnqueueRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, ServicePointService_Enqueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) Dequeue(ctx context.Context, in *DequeueRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, ServicePointService_Dequeue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) Recall(ctx context.Context, in *RecallRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, ServicePointService_Recall_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) Skip(ctx context.Context, in *SkipRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, ServicePointService_Skip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) CancelTicket(ctx context.Context, in *CancelTicketRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, ServicePointService_CancelTicket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicePointServiceClient) TransferTicket(ctx context.Context, in *TransferTicketRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, ServicePointService_TransferTicket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ServicePointServiceServer is the server API for ServicePointService service.
+// All implementations must embed UnimplementedServicePointServiceServer
+// for forward compatibility.
+//
+// ServicePointService mirrors the REST routes served by SPHandler.
+type ServicePointServiceServer interface {
+	CreateServicePoint(context.Context, *CreateServicePointRequest) (*ServicePoint, error)
+	UpsertServicePoint(context.Context, *UpsertServicePointRequest) (*ServicePoint, error)
+	GetServicePoint(context.Context, *GetServicePointRequest) (*ServicePoint, error)
+	DeleteServicePoint(context.Context, *DeleteServicePointRequest) (*ServicePoint, error)
+	RestoreServicePoint(context.Context, *RestoreServicePointRequest) (*ServicePoint, error)
+	PauseServicePoint(context.Context, *PauseServicePointRequest) (*ServicePoint, error)
+	ResumeServicePoint(context.Context, *ResumeServicePointRequest) (*ServicePoint, error)
+	CloseServicePoint(context.Context, *CloseServicePointRequest) (*ServicePoint, error)
+	ListServicePoints(context.Context, *ListServicePointsRequest) (*ListServicePointsResponse, error)
+	Enqueue(context.Context, *EnqueueRequest) (*Ticket, error)
+	Dequeue(context.Context, *DequeueRequest) (*Ticket, error)
+	Recall(context.Context, *RecallRequest) (*Ticket, error)
+	Skip(context.Context, *SkipRequest) (*Ticket, error)
+	CancelTicket(context.Context, *CancelTicketRequest) (*Ticket, error)
+	TransferTicket(context.Context, *TransferTicketRequest) (*Ticket, error)
+	mustEmbedUnimplementedServicePointServiceServer()
+}
+
+// UnimplementedServicePointServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedServicePointServiceServer struct{}
+
+func (UnimplementedServicePointServiceServer) CreateServicePoint(context.Context, *CreateServicePointRequest) (*ServicePoint, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateServicePoint not implemented")
+}
+func (UnimplementedServicePointServiceServer) UpsertServicePoint(context.Context, *UpsertServicePointRequest) (*ServicePoint, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpsertServicePoint not implemented")
+}
+func (UnimplementedServicePointServiceServer) GetServicePoint(context.Context, *GetServicePointRequest) (*ServicePoint, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetServicePoint not implemented")
+}
+func (UnimplementedServicePointServiceServer) DeleteServicePoint(context.Context, *DeleteServicePointRequest) (*ServicePoint, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteServicePoint not implemented")
+}
+func (UnimplementedServicePointServiceServer) RestoreServicePoint(context.Context, *RestoreServicePointRequest) (*ServicePoint, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestoreServicePoint not implemented")
+}
+func (UnimplementedServicePointServiceServer) PauseServicePoint(context.Context, *PauseServicePointRequest) (*ServicePoint, error) {
+	return nil, status.Error(codes.Unimplemented, "method PauseServicePoint not implemented")
+}
+func (UnimplementedServicePointServiceServer) ResumeServicePoint(context.Context, *ResumeServicePointRequest) (*ServicePoint, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResumeServicePoint not implemented")
+}
+func (UnimplementedServicePointServiceServer) CloseServicePoint(context.Context, *CloseServicePointRequest) (*ServicePoint, error) {
+	return nil, status.Error(codes.Unimplemented, "method CloseServicePoint not implemented")
+}
+func (UnimplementedServicePointServiceServer) ListServicePoints(context.Context, *ListServicePointsRequest) (*ListServicePointsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListServicePoints not implemented")
+}
+func (UnimplementedServicePointServiceServer) Enqueue(context.Context, *EnqueueRequest) (*Ticket, error) {
+	return nil, status.Error(codes.Unimplemented, "method Enqueue not implemented")
+}
+func (UnimplementedServicePointServiceServer) Dequeue(context.Context, *DequeueRequest) (*Ticket, error) {
+	return nil, status.Error(codes.Unimplemented, "method Dequeue not implemented")
+}
+func (UnimplementedServicePointServiceServer) Recall(context.Context, *RecallRequest) (*Ticket, error) {
+	return nil, status.Error(codes.Unimplemented, "method Recall not implemented")
+}
+func (UnimplementedServicePointServiceServer) Skip(context.Context, *SkipRequest) (*Ticket, error) {
+	return nil, status.Error(codes.Unimplemented, "method Skip not implemented")
+}
+func (UnimplementedServicePointServiceServer) CancelTicket(context.Context, *CancelTicketRequest) (*Ticket, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelTicket not implemented")
+}
+func (UnimplementedServicePointServiceServer) TransferTicket(context.Context, *TransferTicketRequest) (*Ticket, error) {
+	return nil, status.Error(codes.Unimplemented, "method TransferTicket not implemented")
+}
+func (UnimplementedServicePointServiceServer) mustEmbedUnimplementedServicePointServiceServer() {}
+func (UnimplementedServicePointServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeServicePointServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ServicePointServiceServer will
+// result in compilation errors.
+type UnsafeServicePointServiceServer interface {
+	mustEmbedUnimplementedServicePointServiceServer()
+}
+
+func RegisterServicePointServiceServer(s grpc.ServiceRegistrar, srv ServicePointServiceServer) {
+	// If the following call panics, it indicates UnimplementedServicePointServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ServicePointService_ServiceDesc, srv)
+}
+
+func _ServicePointService_CreateServicePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateServicePointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).CreateServicePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_CreateServicePoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).CreateServicePoint(ctx, req.(*CreateServicePointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_UpsertServicePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertServicePointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).UpsertServicePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_UpsertServicePoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).UpsertServicePoint(ctx, req.(*UpsertServicePointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_GetServicePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServicePointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).GetServicePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_GetServicePoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).GetServicePoint(ctx, req.(*GetServicePointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_DeleteServicePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteServicePointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).DeleteServicePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_DeleteServicePoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).DeleteServicePoint(ctx, req.(*DeleteServicePointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_RestoreServicePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreServicePointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).RestoreServicePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_RestoreServicePoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).RestoreServicePoint(ctx, req.(*RestoreServicePointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_PauseServicePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseServicePointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).PauseServicePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_PauseServicePoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).PauseServicePoint(ctx, req.(*PauseServicePointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_ResumeServicePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeServicePointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).ResumeServicePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_ResumeServicePoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).ResumeServicePoint(ctx, req.(*ResumeServicePointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_CloseServicePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseServicePointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).CloseServicePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_CloseServicePoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).CloseServicePoint(ctx, req.(*CloseServicePointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_ListServicePoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServicePointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).ListServicePoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_ListServicePoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).ListServicePoints(ctx, req.(*ListServicePointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_Enqueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnqueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).Enqueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_Enqueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).Enqueue(ctx, req.(*EnqueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_Dequeue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DequeueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).Dequeue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_Dequeue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).Dequeue(ctx, req.(*DequeueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_Recall_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecallRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).Recall(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_Recall_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).Recall(ctx, req.(*RecallRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_Skip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SkipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).Skip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_Skip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).Skip(ctx, req.(*SkipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_CancelTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).CancelTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_CancelTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).CancelTicket(ctx, req.(*CancelTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServicePointService_TransferTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicePointServiceServer).TransferTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServicePointService_TransferTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicePointServiceServer).TransferTicket(ctx, req.(*TransferTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ServicePointService_ServiceDesc is the grpc.ServiceDesc for ServicePointService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ServicePointService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "servicepoint.v1.ServicePointService",
+	HandlerType: (*ServicePointServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateServicePoint",
+			Handler:    _ServicePointService_CreateServicePoint_Handler,
+		},
+		{
+			MethodName: "UpsertServicePoint",
+			Handler:    _ServicePointService_UpsertServicePoint_Handler,
+		},
+		{
+			MethodName: "GetServicePoint",
+			Handler:    _ServicePointService_GetServicePoint_Handler,
+		},
+		{
+			MethodName: "DeleteServicePoint",
+			Handler:    _ServicePointService_DeleteServicePoint_Handler,
+		},
+		{
+			MethodName: "RestoreServicePoint",
+			Handler:    _ServicePointService_RestoreServicePoint_Handler,
+		},
+		{
+			MethodName: "PauseServicePoint",
+			Handler:    _ServicePointService_PauseServicePoint_Handler,
+		},
+		{
+			MethodName: "ResumeServicePoint",
+			Handler:    _ServicePointService_ResumeServicePoint_Handler,
+		},
+		{
+			MethodName: "CloseServicePoint",
+			Handler:    _ServicePointService_CloseServicePoint_Handler,
+		},
+		{
+			MethodName: "ListServicePoints",
+			Handler:    _ServicePointService_ListServicePoints_Handler,
+		},
+		{
+			MethodName: "Enqueue",
+			Handler:    _ServicePointService_Enqueue_Handler,
+		},
+		{
+			MethodName: "Dequeue",
+			Handler:    _ServicePointService_Dequeue_Handler,
+		},
+		{
+			MethodName: "Recall",
+			Handler:    _ServicePointService_Recall_Handler,
+		},
+		{
+			MethodName: "Skip",
+			Handler:    _ServicePointService_Skip_Handler,
+		},
+		{
+			MethodName: "CancelTicket",
+			Handler:    _ServicePointService_CancelTicket_Handler,
+		},
+		{
+			MethodName: "TransferTicket",
+			Handler:    _ServicePointService_TransferTicket_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "servicepoint/v1/servicepoint.proto",
+}
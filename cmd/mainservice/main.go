@@ -1,19 +1,39 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"time"
+
+	// Working hours name IANA time zones, which minimal images lack.
+	_ "time/tzdata"
 
-	"github.com/gorilla/mux"
 	"github.com/snnus/mainservice/config"
 	"github.com/snnus/mainservice/internal/client"
+	"github.com/snnus/mainservice/internal/grpcserver"
 	"github.com/snnus/mainservice/internal/handlers"
 	"github.com/snnus/mainservice/internal/producer"
-	"github.com/snnus/mainservice/internal/services/mainservice"
+	"github.com/snnus/mainservice/internal/services/spservice"
+	"github.com/snnus/mainservice/internal/storage/memstorage"
 	"github.com/snnus/mainservice/internal/storage/spstorage"
 )
 
+func newStorage(cfg *config.Config) (spservice.SPStorage, func() error, error) {
+	switch cfg.Storage.Driver {
+	case "", config.StorageDriverPostgres:
+		return spstorage.NewSPStorage(cfg)
+	case config.StorageDriverMemory:
+		log.Print("using in-memory storage, data will not survive a restart")
+		return memstorage.NewSPStorage(cfg)
+	default:
+		return nil, nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
 func main() {
 	cfg, err := config.LoadConfig(os.Getenv("CONFIG_PATH"))
 
@@ -24,23 +44,53 @@ func main() {
 	spClient := client.NewClient(cfg)
 	spProducer := producer.NewSPProducer(cfg)
 
-	spStorage, close, err := spstorage.NewSPStorage(cfg)
+	spStorage, close, err := newStorage(cfg)
 
 	if err != nil {
 		panic(err)
 	}
 	defer close()
 
-	spService := mainservice.NewSPService(spStorage, spClient, spProducer)
+	spService := spservice.NewSPService(spStorage, spClient, spProducer)
+	if cfg.Tickets.MaxRecalls > 0 {
+		spService.SetMaxRecalls(cfg.Tickets.MaxRecalls)
+	}
+	if cfg.Queue.Policy != "" {
+		if err := spService.SetDequeuePolicy(cfg.Queue.Policy, cfg.Queue.Weights); err != nil {
+			panic(err)
+		}
+	}
+	spService.SetAdminHost(cfg.Tenants.AdminHost)
 	spHandler := handlers.NewSPHandler(spService)
 
-	r := mux.NewRouter()
+	if cfg.Purge.Retention > 0 {
+		interval := cfg.Purge.Interval
+		if interval <= 0 {
+			interval = time.Hour
+		}
+		go spService.RunPurge(context.Background(), interval, cfg.Purge.Retention)
+	}
 
-	r.HandleFunc("/servicepoint/{id:[0-9]+}", spHandler.UpsertSP).Methods("PUT", "POST")
-	r.HandleFunc("/servicepoint/{id:[0-9]+}", spHandler.GetSP).Methods("GET")
-	r.HandleFunc("/servicepoint/{id:[0-9]+}", spHandler.DeleteSP).Methods("DELETE")
-	r.HandleFunc("/enqueue/{id:[0-9]+}", spHandler.Enqueue).Methods("POST")
-	r.HandleFunc("/dequeue/{id:[0-9]+}", spHandler.Dequeue).Methods("POST")
+	expiryInterval := cfg.Appointments.ExpiryInterval
+	if expiryInterval <= 0 {
+		expiryInterval = time.Minute
+	}
+	go spService.RunAppointmentExpiry(context.Background(), expiryInterval)
+
+	r := handlers.NewRouter(spHandler)
+
+	grpcServer := grpcserver.NewServer(spService)
+	lis, err := net.Listen("tcp", "0.0.0.0:"+cfg.GRPC.Port)
+	if err != nil {
+		panic(err)
+	}
+	go func() {
+		log.Printf("grpc listening on %s", lis.Addr())
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Printf("grpc server stopped: %s", err)
+		}
+	}()
+	defer grpcServer.GracefulStop()
 
 	log.Print("listening now")
 
@@ -3,20 +3,79 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"go.yaml.in/yaml/v4"
 )
 
 type Config struct {
-	Postgres    PgConfig    `yaml:"postgres"`
-	Queueengine QeConfig    `yaml:"queueengine"`
-	Kafka       KafkaConfig `yaml:"kafka"`
+	Storage      StorageConfig      `yaml:"storage"`
+	Postgres     PgConfig           `yaml:"postgres"`
+	Queueengine  QeConfig           `yaml:"queueengine"`
+	Kafka        KafkaConfig        `yaml:"kafka"`
+	GRPC         GRPCConfig         `yaml:"grpc"`
+	Purge        PurgeConfig        `yaml:"purge"`
+	Tickets      TicketsConfig      `yaml:"tickets"`
+	Queue        QueueConfig        `yaml:"queue"`
+	Appointments AppointmentsConfig `yaml:"appointments"`
+	Tenants      TenantsConfig      `yaml:"tenants"`
 }
 
+// TenantsConfig names the host administrators send requests to. Only those
+// requests may create and list organisations; without an AdminHost none may.
+type TenantsConfig struct {
+	AdminHost string `yaml:"admin_host"`
+}
+
+// AppointmentsConfig controls how often booked appointments whose slot has
+// passed are expired. A zero ExpiryInterval means every minute.
+type AppointmentsConfig struct {
+	ExpiryInterval time.Duration `yaml:"expiry_interval"`
+}
+
+// QueueConfig selects how a service point picks among its lines: "strict"
+// (the default) or "weighted" by Weights per ticket class.
+type QueueConfig struct {
+	Policy  string         `yaml:"policy"`
+	Weights map[string]int `yaml:"weights"`
+}
+
+// TicketsConfig controls the desk operations on tickets. A zero MaxRecalls
+// keeps the service default.
+type TicketsConfig struct {
+	MaxRecalls int `yaml:"max_recalls"`
+}
+
+// PurgeConfig controls the job that hard-deletes soft-deleted service points
+// once they are older than Retention. A zero Retention disables the job.
+type PurgeConfig struct {
+	Retention time.Duration `yaml:"retention"`
+	Interval  time.Duration `yaml:"interval"`
+}
+
+type GRPCConfig struct {
+	Port string `yaml:"port"`
+}
+
+const (
+	StorageDriverPostgres = "postgres"
+	StorageDriverMemory   = "memory"
+)
+
+// StorageConfig selects the SPStorage implementation. An empty driver
+// means postgres.
+type StorageConfig struct {
+	Driver string `yaml:"driver"`
+}
+
+// KafkaConfig sets where messages are published. Topic only carries called
+// tickets, as it always has; every other ticket and service point event goes
+// to EventsTopic, "ticket-events" if empty.
 type KafkaConfig struct {
-	Broker    string `yaml:"broker"`
-	Topic     string `yaml:"topic"`
-	BatchSize int    `yaml:"batch_size"`
+	Broker      string `yaml:"broker"`
+	Topic       string `yaml:"topic"`
+	EventsTopic string `yaml:"events_topic"`
+	BatchSize   int    `yaml:"batch_size"`
 }
 
 type QeConfig struct {
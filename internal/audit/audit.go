@@ -0,0 +1,24 @@
+// Package audit carries who made a request through the context so storage
+// can record it next to every change.
+package audit
+
+import "context"
+
+// Meta identifies the caller and request behind a change.
+type Meta struct {
+	Caller    string
+	RequestID string
+}
+
+type metaKey struct{}
+
+func NewContext(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, meta)
+}
+
+// FromContext returns the Meta stored in ctx, or the zero Meta if there is
+// none.
+func FromContext(ctx context.Context) Meta {
+	meta, _ := ctx.Value(metaKey{}).(Meta)
+	return meta
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 
 	"github.com/snnus/mainservice/config"
 	"github.com/snnus/mainservice/internal/models"
@@ -25,7 +26,7 @@ func NewClient(cfg *config.Config) *Client {
 }
 
 func (c *Client) Enqueue(ctx context.Context, id string, shortname string) (*models.Ticket, error) {
-	url := fmt.Sprintf("%s/enqueue/%s?sname=%s", c.baseURL, id, shortname)
+	url := fmt.Sprintf("%s/enqueue/%s?sname=%s", c.baseURL, id, url.QueryEscape(shortname))
 
 	// Create POST request
 	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
@@ -85,3 +86,91 @@ func (c *Client) Dequeue(ctx context.Context, id string) (*models.Ticket, error)
 
 	return &result, nil
 }
+
+// Cancel takes a ticket out of the queue of a service point before it is
+// called.
+func (c *Client) Cancel(ctx context.Context, id string, ticket string) error {
+	url := fmt.Sprintf("%s/cancel/%s?ticket=%s", c.baseURL, id, url.QueryEscape(ticket))
+
+	// Create POST request
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Send request
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check response status
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// Insert puts a ticket issued elsewhere into the queue of a service point,
+// keeping its code, at the front or the back.
+func (c *Client) Insert(ctx context.Context, id string, ticket string, front bool) error {
+	url := fmt.Sprintf("%s/insert/%s?ticket=%s&front=%t", c.baseURL, id, url.QueryEscape(ticket), front)
+
+	// Create POST request
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Send request
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check response status
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// Length returns how many tickets wait in the queue of a service point.
+func (c *Client) Length(ctx context.Context, id string) (int, error) {
+	url := fmt.Sprintf("%s/length/%s", c.baseURL, id)
+
+	// Create GET request
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Send request
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check response status
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var result struct {
+		Length int `json:"length"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return result.Length, nil
+}
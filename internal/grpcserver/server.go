@@ -0,0 +1,393 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	pb "github.com/snnus/mainservice/api/servicepoint/v1"
+	"github.com/snnus/mainservice/internal/audit"
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/tenant"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type mainService interface {
+	CreateSP(context.Context, models.NewServicePointRequest) (*models.ServicePoint, error)
+	UpsertSP(context.Context, string, models.NewServicePointRequest, int64) (*models.ServicePoint, error)
+	DeleteSP(context.Context, string, int64) (*models.ServicePoint, error)
+	RestoreSP(context.Context, string, int64) (*models.ServicePoint, error)
+	PauseSP(context.Context, string, models.StatusChange) (*models.ServicePoint, error)
+	ResumeSP(context.Context, string) (*models.ServicePoint, error)
+	CloseSP(context.Context, string, models.StatusChange) (*models.ServicePoint, error)
+	GetSPByID(context.Context, string, bool) (*models.ServicePoint, error)
+	ListSP(context.Context, bool) ([]models.ServicePoint, error)
+	Enqueue(context.Context, string, string) (*models.Ticket, error)
+	Dequeue(context.Context, string) (*models.Ticket, error)
+	Recall(context.Context, string) (*models.Ticket, error)
+	Skip(context.Context, string) (*models.Ticket, error)
+	CancelTicket(context.Context, string, string) (*models.Ticket, error)
+	TransferTicket(context.Context, string, models.TicketTransfer) (*models.Ticket, error)
+	ResolveTenant(context.Context, string, string) (string, error)
+	IsAdminHost(string) bool
+}
+
+// SPServer serves the ServicePointService gRPC API from the same service
+// the REST handlers use.
+type SPServer struct {
+	pb.UnimplementedServicePointServiceServer
+	service mainService
+}
+
+func NewSPServer(service mainService) *SPServer {
+	return &SPServer{service: service}
+}
+
+// NewServer returns a gRPC server with the service point API, the standard
+// health service and server reflection registered.
+func NewServer(service mainService) *grpc.Server {
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(withAuditMeta, withTenant(service)))
+
+	pb.RegisterServicePointServiceServer(s, NewSPServer(service))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pb.ServicePointService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, healthServer)
+
+	reflection.Register(s)
+
+	return s
+}
+
+// withAuditMeta puts the caller and request id from the x-caller and
+// x-request-id metadata into the context for the audit log, like the REST
+// headers of the same names.
+func withAuditMeta(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var meta audit.Meta
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("x-caller"); len(v) > 0 {
+			meta.Caller = v[0]
+		}
+		if v := md.Get("x-request-id"); len(v) > 0 {
+			meta.RequestID = v[0]
+		}
+	}
+	return handler(audit.NewContext(ctx, meta), req)
+}
+
+// withTenant puts the organisation named by the x-tenant metadata, or found
+// by the :authority the call was sent to, into the context of every call of
+// the service point API, like the REST X-Tenant header. Health checks and
+// reflection need none.
+func withTenant(service mainService) grpc.UnaryServerInterceptor {
+	prefix := "/" + pb.ServicePointService_ServiceDesc.ServiceName + "/"
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+
+		var id, host string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get("x-tenant"); len(v) > 0 {
+				id = v[0]
+			}
+			if v := md.Get(":authority"); len(v) > 0 {
+				host = v[0]
+			}
+		}
+
+		resolved, err := service.ResolveTenant(ctx, id, host)
+		if err != nil {
+			log.Printf("error resolving tenant: %s", err)
+			return nil, toStatus(err)
+		}
+		ctx = tenant.NewContext(ctx, resolved)
+		if service.IsAdminHost(host) {
+			ctx = tenant.NewAdminContext(ctx)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (m *SPServer) CreateServicePoint(ctx context.Context, req *pb.CreateServicePointRequest) (*pb.ServicePoint, error) {
+	sp, err := m.service.CreateSP(ctx, models.NewServicePointRequest{
+		Name:             req.GetName(),
+		ShortName:        req.GetShortName(),
+		OfficeNumber:     req.GetOfficeNumber(),
+		OfficeID:         req.GetOfficeId(),
+		MaxQueueLength:   int(req.GetMaxQueueLength()),
+		DailyTicketQuota: int(req.GetDailyTicketQuota()),
+	})
+	if err != nil {
+		log.Printf("error creating service point: %s", err)
+		return nil, toStatus(err)
+	}
+	return toServicePoint(sp), nil
+}
+
+func (m *SPServer) UpsertServicePoint(ctx context.Context, req *pb.UpsertServicePointRequest) (*pb.ServicePoint, error) {
+	sp, err := m.service.UpsertSP(ctx, formatID(req.GetId()), models.NewServicePointRequest{
+		Name:             req.GetName(),
+		ShortName:        req.GetShortName(),
+		OfficeNumber:     req.GetOfficeNumber(),
+		OfficeID:         req.GetOfficeId(),
+		MaxQueueLength:   int(req.GetMaxQueueLength()),
+		DailyTicketQuota: int(req.GetDailyTicketQuota()),
+	}, req.GetExpectedVersion())
+	if err != nil {
+		log.Printf("error upserting service point: %s", err)
+		return nil, toStatus(err)
+	}
+	return toServicePoint(sp), nil
+}
+
+func (m *SPServer) GetServicePoint(ctx context.Context, req *pb.GetServicePointRequest) (*pb.ServicePoint, error) {
+	sp, err := m.service.GetSPByID(ctx, formatID(req.GetId()), req.GetIncludeDeleted())
+	if err != nil {
+		log.Printf("error getting service point: %s", err)
+		return nil, toStatus(err)
+	}
+	return toServicePoint(sp), nil
+}
+
+func (m *SPServer) DeleteServicePoint(ctx context.Context, req *pb.DeleteServicePointRequest) (*pb.ServicePoint, error) {
+	sp, err := m.service.DeleteSP(ctx, formatID(req.GetId()), req.GetExpectedVersion())
+	if err != nil {
+		log.Printf("error deleting service point: %s", err)
+		return nil, toStatus(err)
+	}
+	return toServicePoint(sp), nil
+}
+
+func (m *SPServer) RestoreServicePoint(ctx context.Context, req *pb.RestoreServicePointRequest) (*pb.ServicePoint, error) {
+	sp, err := m.service.RestoreSP(ctx, formatID(req.GetId()), req.GetExpectedVersion())
+	if err != nil {
+		log.Printf("error restoring service point: %s", err)
+		return nil, toStatus(err)
+	}
+	return toServicePoint(sp), nil
+}
+
+func (m *SPServer) PauseServicePoint(ctx context.Context, req *pb.PauseServicePointRequest) (*pb.ServicePoint, error) {
+	sp, err := m.service.PauseSP(ctx, formatID(req.GetId()), toStatusChange(req.GetReason(), req.GetReturnAt()))
+	if err != nil {
+		log.Printf("error pausing service point: %s", err)
+		return nil, toStatus(err)
+	}
+	return toServicePoint(sp), nil
+}
+
+func (m *SPServer) ResumeServicePoint(ctx context.Context, req *pb.ResumeServicePointRequest) (*pb.ServicePoint, error) {
+	sp, err := m.service.ResumeSP(ctx, formatID(req.GetId()))
+	if err != nil {
+		log.Printf("error resuming service point: %s", err)
+		return nil, toStatus(err)
+	}
+	return toServicePoint(sp), nil
+}
+
+func (m *SPServer) CloseServicePoint(ctx context.Context, req *pb.CloseServicePointRequest) (*pb.ServicePoint, error) {
+	sp, err := m.service.CloseSP(ctx, formatID(req.GetId()), toStatusChange(req.GetReason(), req.GetReturnAt()))
+	if err != nil {
+		log.Printf("error closing service point: %s", err)
+		return nil, toStatus(err)
+	}
+	return toServicePoint(sp), nil
+}
+
+func (m *SPServer) ListServicePoints(ctx context.Context, req *pb.ListServicePointsRequest) (*pb.ListServicePointsResponse, error) {
+	sps, err := m.service.ListSP(ctx, req.GetIncludeDeleted())
+	if err != nil {
+		log.Printf("error listing service points: %s", err)
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListServicePointsResponse{ServicePoints: make([]*pb.ServicePoint, 0, len(sps))}
+	for i := range sps {
+		resp.ServicePoints = append(resp.ServicePoints, toServicePoint(&sps[i]))
+	}
+	return resp, nil
+}
+
+func (m *SPServer) Enqueue(ctx context.Context, req *pb.EnqueueRequest) (*pb.Ticket, error) {
+	ticket, err := m.service.Enqueue(ctx, formatID(req.GetServicePointId()), req.GetClass())
+	if err != nil {
+		log.Printf("error enqueueing: %s", err)
+		return nil, toStatus(err)
+	}
+	return toTicket(ticket), nil
+}
+
+func (m *SPServer) Dequeue(ctx context.Context, req *pb.DequeueRequest) (*pb.Ticket, error) {
+	ticket, err := m.service.Dequeue(ctx, formatID(req.GetServicePointId()))
+	if err != nil {
+		log.Printf("error dequeueing: %s", err)
+		return nil, toStatus(err)
+	}
+	return toTicket(ticket), nil
+}
+
+func (m *SPServer) Recall(ctx context.Context, req *pb.RecallRequest) (*pb.Ticket, error) {
+	ticket, err := m.service.Recall(ctx, formatID(req.GetServicePointId()))
+	if err != nil {
+		log.Printf("error recalling: %s", err)
+		return nil, toStatus(err)
+	}
+	return toTicket(ticket), nil
+}
+
+func (m *SPServer) Skip(ctx context.Context, req *pb.SkipRequest) (*pb.Ticket, error) {
+	ticket, err := m.service.Skip(ctx, formatID(req.GetServicePointId()))
+	if err != nil {
+		log.Printf("error skipping: %s", err)
+		return nil, toStatus(err)
+	}
+	return toTicket(ticket), nil
+}
+
+func (m *SPServer) CancelTicket(ctx context.Context, req *pb.CancelTicketRequest) (*pb.Ticket, error) {
+	ticket, err := m.service.CancelTicket(ctx, formatID(req.GetServicePointId()), req.GetCode())
+	if err != nil {
+		log.Printf("error cancelling ticket: %s", err)
+		return nil, toStatus(err)
+	}
+	return toTicket(ticket), nil
+}
+
+func (m *SPServer) TransferTicket(ctx context.Context, req *pb.TransferTicketRequest) (*pb.Ticket, error) {
+	transfer := models.TicketTransfer{
+		ServicePointID:     req.GetServicePointId(),
+		FromServicePointID: req.GetFromServicePointId(),
+		Position:           models.TransferBack,
+	}
+	if req.GetFront() {
+		transfer.Position = models.TransferFront
+	}
+
+	ticket, err := m.service.TransferTicket(ctx, req.GetCode(), transfer)
+	if err != nil {
+		log.Printf("error transferring ticket: %s", err)
+		return nil, toStatus(err)
+	}
+	return toTicket(ticket), nil
+}
+
+// toStatus maps a service error to the gRPC status it is reported with.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, models.ErrNotFound), errors.Is(err, models.ErrTicketNotFound),
+		errors.Is(err, models.ErrScheduleNotFound), errors.Is(err, models.ErrAppointmentNotFound),
+		errors.Is(err, models.ErrHoursNotFound), errors.Is(err, models.ErrOperatorNotFound),
+		errors.Is(err, models.ErrSessionNotFound), errors.Is(err, models.ErrCategoryNotFound),
+		errors.Is(err, models.ErrOrganisationNotFound), errors.Is(err, models.ErrBuildingNotFound),
+		errors.Is(err, models.ErrFloorNotFound), errors.Is(err, models.ErrOfficeNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, models.ErrInvalidArgument):
+		return invalidArgument(err)
+	case errors.Is(err, models.ErrVersionMismatch), errors.Is(err, models.ErrDeleted),
+		errors.Is(err, models.ErrAppointmentState), errors.Is(err, models.ErrClosed),
+		errors.Is(err, models.ErrPaused), errors.Is(err, models.ErrSessionActive),
+		errors.Is(err, models.ErrNotServed), errors.Is(err, models.ErrAmbiguousTicket):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, models.ErrSlotFull), errors.Is(err, models.ErrQueueFull), errors.Is(err, models.ErrQuotaReached):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, models.ErrAlreadyExists), errors.Is(err, models.ErrOrganisationExists),
+		errors.Is(err, models.ErrFloorExists), errors.Is(err, models.ErrOfficeExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, models.ErrNotAdmin), errors.Is(err, models.ErrTenantMismatch):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// invalidArgument attaches the fields of a validation error as BadRequest
+// details so clients see every violation, not just the message.
+func invalidArgument(err error) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+
+	var verr *models.ValidationError
+	if !errors.As(err, &verr) {
+		return st.Err()
+	}
+
+	br := &errdetails.BadRequest{}
+	for _, f := range verr.Fields {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+		})
+	}
+
+	withDetails, detailsErr := st.WithDetails(br)
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+func toStatusChange(reason string, returnAt *timestamppb.Timestamp) models.StatusChange {
+	change := models.StatusChange{Reason: reason}
+	if returnAt != nil {
+		t := returnAt.AsTime()
+		change.ReturnAt = &t
+	}
+	return change
+}
+
+func toServicePoint(sp *models.ServicePoint) *pb.ServicePoint {
+	res := &pb.ServicePoint{
+		Id:               sp.ID,
+		Name:             sp.Name,
+		ShortName:        sp.ShortName,
+		OfficeNumber:     sp.OfficeNumber,
+		OfficeId:         sp.OfficeID,
+		MaxQueueLength:   int32(sp.MaxQueueLength),
+		DailyTicketQuota: int32(sp.DailyTicketQuota),
+		Status:           sp.Status,
+		StatusReason:     sp.StatusReason,
+		CreatedAt:        timestamppb.New(sp.CreatedAt),
+		UpdatedAt:        timestamppb.New(sp.UpdatedAt),
+		ShardId:          int32(sp.ShardID),
+		Version:          sp.Version,
+	}
+	if sp.DeletedAt != nil {
+		res.DeletedAt = timestamppb.New(*sp.DeletedAt)
+	}
+	if sp.ReturnAt != nil {
+		res.ReturnAt = timestamppb.New(*sp.ReturnAt)
+	}
+	return res
+}
+
+func toTicket(ticket *models.Ticket) *pb.Ticket {
+	return &pb.Ticket{
+		Ticket:               ticket.Ticket,
+		Position:             int32(ticket.Position),
+		EstimatedWaitSeconds: ticket.EstimatedWaitSeconds,
+		Status:               ticket.Status,
+		OfficeNumber:         ticket.OfficeNumber,
+		DeskNumber:           ticket.DeskNumber,
+		Class:                ticket.Class,
+		CategoryId:           ticket.CategoryID,
+	}
+}
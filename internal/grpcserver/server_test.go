@@ -0,0 +1,363 @@
+package grpcserver_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	pb "github.com/snnus/mainservice/api/servicepoint/v1"
+	"github.com/snnus/mainservice/config"
+	"github.com/snnus/mainservice/internal/client"
+	"github.com/snnus/mainservice/internal/grpcserver"
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/services/spservice"
+	"github.com/snnus/mainservice/internal/tenant"
+	"github.com/snnus/mainservice/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type env struct {
+	qe       *testutil.QueueEngine
+	storage  *testutil.Storage
+	producer *testutil.Producer
+	conn     *grpc.ClientConn
+	client   pb.ServicePointServiceClient
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+
+	qe := testutil.NewQueueEngine()
+	t.Cleanup(qe.Close)
+
+	storage := testutil.NewStorage()
+	producer := testutil.NewProducer()
+	cfg := &config.Config{Queueengine: qe.Config()}
+	service := spservice.NewSPService(storage, client.NewClient(cfg), producer)
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpcserver.NewServer(service)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &env{qe: qe, storage: storage, producer: producer, conn: conn, client: pb.NewServicePointServiceClient(conn)}
+}
+
+func cashDesk(id int64) *pb.UpsertServicePointRequest {
+	return &pb.UpsertServicePointRequest{Id: id, Name: "Cash desk", ShortName: "C", OfficeNumber: "101"}
+}
+
+func TestServicePointLifecycle(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+
+	created, err := e.client.UpsertServicePoint(ctx, cashDesk(2))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), created.GetId())
+	assert.Equal(t, "C", created.GetShortName())
+	assert.NotNil(t, created.GetCreatedAt())
+
+	_, err = e.client.UpsertServicePoint(ctx, cashDesk(1))
+	require.NoError(t, err)
+
+	got, err := e.client.GetServicePoint(ctx, &pb.GetServicePointRequest{Id: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "Cash desk", got.GetName())
+	assert.Equal(t, created.GetShardId(), got.GetShardId())
+
+	list, err := e.client.ListServicePoints(ctx, &pb.ListServicePointsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetServicePoints(), 2)
+	assert.Equal(t, int64(1), list.GetServicePoints()[0].GetId())
+	assert.Equal(t, int64(2), list.GetServicePoints()[1].GetId())
+
+	ticket, err := e.client.Enqueue(ctx, &pb.EnqueueRequest{ServicePointId: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "C001", ticket.GetTicket())
+
+	ticket, err = e.client.Dequeue(ctx, &pb.DequeueRequest{ServicePointId: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "C001", ticket.GetTicket())
+	require.Len(t, e.producer.Messages(), 1)
+	assert.Equal(t, "101", e.producer.Messages()[0].OfficeNumber)
+
+	deleted, err := e.client.DeleteServicePoint(ctx, &pb.DeleteServicePointRequest{Id: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted.GetId())
+}
+
+func TestErrorCodes(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		setup func(e *env)
+		call  func(e *env) error
+		want  codes.Code
+	}{
+		{
+			name: "upsert missing fields",
+			call: func(e *env) error {
+				_, err := e.client.UpsertServicePoint(ctx, &pb.UpsertServicePointRequest{Id: 1, Name: "Cash desk"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "get unknown service point",
+			call: func(e *env) error {
+				_, err := e.client.GetServicePoint(ctx, &pb.GetServicePointRequest{Id: 404})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "delete unknown service point",
+			call: func(e *env) error {
+				_, err := e.client.DeleteServicePoint(ctx, &pb.DeleteServicePointRequest{Id: 404})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "enqueue unknown service point",
+			call: func(e *env) error {
+				_, err := e.client.Enqueue(ctx, &pb.EnqueueRequest{ServicePointId: 404})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "enqueue with queue engine down",
+			setup: func(e *env) {
+				_, err := e.client.UpsertServicePoint(ctx, cashDesk(1))
+				require.NoError(t, err)
+				e.qe.SetFailing(true)
+			},
+			call: func(e *env) error {
+				_, err := e.client.Enqueue(ctx, &pb.EnqueueRequest{ServicePointId: 1})
+				return err
+			},
+			want: codes.Internal,
+		},
+		{
+			name: "dequeue empty queue",
+			setup: func(e *env) {
+				_, err := e.client.UpsertServicePoint(ctx, cashDesk(1))
+				require.NoError(t, err)
+			},
+			call: func(e *env) error {
+				_, err := e.client.Dequeue(ctx, &pb.DequeueRequest{ServicePointId: 1})
+				return err
+			},
+			want: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnv(t)
+			if tt.setup != nil {
+				tt.setup(e)
+			}
+
+			err := tt.call(e)
+			assert.Equal(t, tt.want, status.Code(err), err)
+		})
+	}
+}
+
+func TestInvalidArgumentDetails(t *testing.T) {
+	e := newEnv(t)
+
+	_, err := e.client.UpsertServicePoint(context.Background(), &pb.UpsertServicePointRequest{
+		Id:           1,
+		Name:         "Cash desk",
+		ShortName:    "C1",
+		OfficeNumber: "12345678901",
+	})
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+
+	br, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+
+	got := map[string]string{}
+	for _, v := range br.GetFieldViolations() {
+		got[v.GetField()] = v.GetDescription()
+	}
+	assert.Equal(t, map[string]string{
+		"shortName":    "must not end with a digit",
+		"officeNumber": "must be at most 10 characters",
+	}, got)
+}
+
+func TestHealth(t *testing.T) {
+	e := newEnv(t)
+
+	resp, err := healthpb.NewHealthClient(e.conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: pb.ServicePointService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
+
+func TestCreateServicePoint(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+
+	_, err := e.client.UpsertServicePoint(ctx, cashDesk(1))
+	require.NoError(t, err)
+
+	created, err := e.client.CreateServicePoint(ctx, &pb.CreateServicePointRequest{
+		Name: "Accounts", ShortName: "A", OfficeNumber: "202",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), created.GetId())
+	assert.Equal(t, int64(1), created.GetVersion())
+
+	_, err = e.client.CreateServicePoint(ctx, &pb.CreateServicePointRequest{Name: "Accounts"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServicePointOffice(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+
+	building, err := e.storage.CreateBuilding(ctx, models.NewBuildingRequest{Name: "Main building"})
+	require.NoError(t, err)
+	floor, err := e.storage.CreateFloor(ctx, strconv.FormatInt(building.ID, 10), models.NewFloorRequest{Name: "Second floor", Level: 2})
+	require.NoError(t, err)
+	office, err := e.storage.CreateOffice(ctx, strconv.FormatInt(floor.ID, 10), models.NewOfficeRequest{Number: "201"})
+	require.NoError(t, err)
+
+	created, err := e.client.CreateServicePoint(ctx, &pb.CreateServicePointRequest{
+		Name: "Accounts", ShortName: "A", OfficeId: office.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, office.ID, created.GetOfficeId())
+	assert.Equal(t, "201", created.GetOfficeNumber())
+
+	_, err = e.client.CreateServicePoint(ctx, &pb.CreateServicePointRequest{
+		Name: "Accounts", ShortName: "A", OfficeId: office.ID + 1,
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestRestoreServicePoint(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+
+	_, err := e.client.UpsertServicePoint(ctx, cashDesk(1))
+	require.NoError(t, err)
+
+	deleted, err := e.client.DeleteServicePoint(ctx, &pb.DeleteServicePointRequest{Id: 1})
+	require.NoError(t, err)
+	assert.NotNil(t, deleted.GetDeletedAt())
+
+	_, err = e.client.GetServicePoint(ctx, &pb.GetServicePointRequest{Id: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = e.client.UpsertServicePoint(ctx, cashDesk(1))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	got, err := e.client.GetServicePoint(ctx, &pb.GetServicePointRequest{Id: 1, IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.NotNil(t, got.GetDeletedAt())
+
+	restored, err := e.client.RestoreServicePoint(ctx, &pb.RestoreServicePointRequest{Id: 1, ExpectedVersion: deleted.GetVersion()})
+	require.NoError(t, err)
+	assert.Nil(t, restored.GetDeletedAt())
+
+	list, err := e.client.ListServicePoints(ctx, &pb.ListServicePointsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.GetServicePoints(), 1)
+}
+
+func TestPauseServicePoint(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+
+	_, err := e.client.UpsertServicePoint(ctx, cashDesk(1))
+	require.NoError(t, err)
+
+	returnAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	paused, err := e.client.PauseServicePoint(ctx, &pb.PauseServicePointRequest{Id: 1, Reason: "Lunch break", ReturnAt: timestamppb.New(returnAt)})
+	require.NoError(t, err)
+	assert.Equal(t, "paused", paused.GetStatus())
+	assert.Equal(t, "Lunch break", paused.GetStatusReason())
+	assert.True(t, returnAt.Equal(paused.GetReturnAt().AsTime()))
+
+	_, err = e.client.Enqueue(ctx, &pb.EnqueueRequest{ServicePointId: 1})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "Lunch break")
+
+	resumed, err := e.client.ResumeServicePoint(ctx, &pb.ResumeServicePointRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "open", resumed.GetStatus())
+	assert.Nil(t, resumed.GetReturnAt())
+
+	_, err = e.client.Enqueue(ctx, &pb.EnqueueRequest{ServicePointId: 1})
+	require.NoError(t, err)
+
+	_, err = e.client.CloseServicePoint(ctx, &pb.CloseServicePointRequest{Id: 404})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAuditMetadata(t *testing.T) {
+	e := newEnv(t)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-caller", "alice", "x-request-id", "req-1")
+	_, err := e.client.UpsertServicePoint(ctx, cashDesk(1))
+	require.NoError(t, err)
+
+	records, err := e.storage.GetServicePointHistory(context.Background(), "1", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "alice", records[0].Caller)
+	assert.Equal(t, "req-1", records[0].RequestID)
+}
+
+func TestTenantMetadata(t *testing.T) {
+	e := newEnv(t)
+
+	_, err := e.storage.CreateOrganisation(context.Background(), models.NewOrganisationRequest{ID: "city", Name: "City hall"})
+	require.NoError(t, err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "city")
+	_, err = e.client.UpsertServicePoint(ctx, cashDesk(1))
+	require.NoError(t, err)
+
+	_, err = e.client.GetServicePoint(context.Background(), &pb.GetServicePointRequest{Id: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err), "other organisations do not see it")
+
+	sp, err := e.storage.GetServicePointByID(tenant.NewContext(context.Background(), "city"), "1", false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), sp.ID)
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "nobody")
+	_, err = e.client.GetServicePoint(ctx, &pb.GetServicePointRequest{Id: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
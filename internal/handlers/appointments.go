@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (m *SPHandler) SetSlotSchedule(w http.ResponseWriter, r *http.Request) {
+	log.Print("set slot schedule handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var req models.SlotScheduleRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "SlotScheduleRequest", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	schedule, err := m.service.SetSlotSchedule(ctx, id, req)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error setting slot schedule: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(schedule); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - slot schedule of service point %s", id)
+}
+
+func (m *SPHandler) GetSlotSchedule(w http.ResponseWriter, r *http.Request) {
+	log.Print("get slot schedule handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	schedule, err := m.service.GetSlotSchedule(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting slot schedule: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(schedule); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - slot schedule of service point %s", id)
+}
+
+// ListSlots lists the slots of one UTC day, given as the date query
+// parameter and defaulting to today.
+func (m *SPHandler) ListSlots(w http.ResponseWriter, r *http.Request) {
+	log.Print("list slots handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	date := time.Now().UTC()
+	if value := r.URL.Query().Get("date"); value != "" {
+		d, err := time.Parse(dateLayout, value)
+		if err != nil {
+			var verr models.ValidationError
+			verr.Add("date", "must be a date formatted as YYYY-MM-DD")
+			writeError(w, &verr)
+			return
+		}
+		date = d
+	}
+
+	slots, err := m.service.ListSlots(ctx, id, date)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error listing slots: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(slots); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %d slots", len(slots))
+}
+
+func (m *SPHandler) BookAppointment(w http.ResponseWriter, r *http.Request) {
+	log.Print("book appointment handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var req models.NewAppointmentRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "NewAppointmentRequest", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	appointment, err := m.service.BookAppointment(ctx, req)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error booking appointment: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("%s/appointments/%d", APIPrefix, appointment.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(appointment); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("201 created - appointment ID: %d", appointment.ID)
+}
+
+func (m *SPHandler) GetAppointment(w http.ResponseWriter, r *http.Request) {
+	log.Print("get appointment handler called")
+
+	m.serveAppointment(w, r, m.service.GetAppointment, "getting")
+}
+
+func (m *SPHandler) CancelAppointment(w http.ResponseWriter, r *http.Request) {
+	log.Print("cancel appointment handler called")
+
+	m.serveAppointment(w, r, m.service.CancelAppointment, "cancelling")
+}
+
+func (m *SPHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	log.Print("check in handler called")
+
+	m.serveAppointment(w, r, m.service.CheckIn, "checking in")
+}
+
+// serveAppointment writes the appointment op returns for the id in the path.
+func (m *SPHandler) serveAppointment(w http.ResponseWriter, r *http.Request, op func(context.Context, string) (*models.Appointment, error), verb string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	appointment, err := op(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error %s appointment: %s", verb, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(appointment); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - appointment %d is %s", appointment.ID, appointment.Status)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (m *SPHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	log.Print("create category handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var req models.NewCategoryRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "NewCategoryRequest", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	category, err := m.service.CreateCategory(ctx, req)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error creating category: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("%s/categories/%d", APIPrefix, category.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(category); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("201 created - category ID: %d", category.ID)
+}
+
+func (m *SPHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	log.Print("get category handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	category, err := m.service.GetCategory(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting category: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(category); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - category ID: %d", category.ID)
+}
+
+func (m *SPHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	log.Print("list categories handler called")
+
+	m.serveCategories(w, r, func(ctx context.Context, _ string) ([]models.Category, error) {
+		return m.service.ListCategories(ctx)
+	}, "listing")
+}
+
+func (m *SPHandler) GetSPCategories(w http.ResponseWriter, r *http.Request) {
+	log.Print("get service point categories handler called")
+
+	m.serveCategories(w, r, m.service.GetSPCategories, "getting")
+}
+
+func (m *SPHandler) SetSPCategories(w http.ResponseWriter, r *http.Request) {
+	log.Print("set service point categories handler called")
+
+	var req models.CategoryAssignment
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "CategoryAssignment", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	m.serveCategories(w, r, func(ctx context.Context, id string) ([]models.Category, error) {
+		return m.service.SetSPCategories(ctx, id, req)
+	}, "setting")
+}
+
+// serveCategories writes the categories op returns for the service point in
+// the path, if any.
+func (m *SPHandler) serveCategories(w http.ResponseWriter, r *http.Request, op func(context.Context, string) ([]models.Category, error), verb string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	categories, err := op(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error %s categories: %s", verb, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(categories); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %d categories", len(categories))
+}
+
+func (m *SPHandler) EnqueueCategory(w http.ResponseWriter, r *http.Request) {
+	log.Print("enqueue category handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	ticket, err := m.service.EnqueueCategory(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error enqueueing for category: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(ticket); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("201 created - ticket %s for category %s", ticket.Ticket, id)
+}
+
+func (m *SPHandler) CancelCategoryTicket(w http.ResponseWriter, r *http.Request) {
+	log.Print("cancel category ticket handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+	code := vars["code"]
+
+	ticket, err := m.service.CancelCategoryTicket(ctx, id, code)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error cancelling category ticket: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ticket); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - ticket %s of category %s", ticket.Ticket, id)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// etag is the strong entity tag of a service point at the given version.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatchVersion returns the version the If-Match header requires, or 0 when
+// the header is absent or "*". Tags that can never match a version, such as
+// weak tags, are reported as models.ErrVersionMismatch since If-Match uses
+// strong comparison.
+func ifMatchVersion(r *http.Request) (int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	if strings.Contains(header, ",") {
+		var verr models.ValidationError
+		verr.Add("If-Match", "only a single entity tag is supported")
+		return 0, &verr
+	}
+
+	unquoted, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if !ok || err != nil || version <= 0 {
+		return 0, models.ErrVersionMismatch
+	}
+
+	return version, nil
+}
@@ -3,10 +3,12 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-
-	// "fmt"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -14,11 +16,70 @@ import (
 )
 
 type mainService interface {
-	UpsertSP(context.Context, string, models.NewServicePointRequest) (*models.ServicePoint, error)
-	DeleteSP(context.Context, string) (*models.ServicePoint, error)
-	GetSPByID(context.Context, string) (*models.ServicePoint, error)
-	Enqueue(context.Context, string) (*models.Ticket, error)
+	CreateSP(context.Context, models.NewServicePointRequest) (*models.ServicePoint, error)
+	UpsertSP(context.Context, string, models.NewServicePointRequest, int64) (*models.ServicePoint, error)
+	PatchSP(context.Context, string, models.ServicePointPatch, int64) (*models.ServicePoint, error)
+	DeleteSP(context.Context, string, int64) (*models.ServicePoint, error)
+	RestoreSP(context.Context, string, int64) (*models.ServicePoint, error)
+	PauseSP(context.Context, string, models.StatusChange) (*models.ServicePoint, error)
+	ResumeSP(context.Context, string) (*models.ServicePoint, error)
+	CloseSP(context.Context, string, models.StatusChange) (*models.ServicePoint, error)
+	GetSPByID(context.Context, string, bool) (*models.ServicePoint, error)
+	ListSP(context.Context, bool) ([]models.ServicePoint, error)
+	ImportSP(context.Context, []models.ImportRow, string, bool) (*models.ImportResult, error)
+	ExportSP(context.Context) ([]models.ImportRow, error)
+	GetSPHistory(context.Context, string, int64, int) (*models.HistoryPage, error)
+	ListTickets(context.Context, string, models.TicketFilter) ([]models.TicketRecord, error)
+	TicketStatus(context.Context, string) (*models.Ticket, error)
+	ServicePointTicketStatus(context.Context, string, string) (*models.Ticket, error)
+	Recall(context.Context, string) (*models.Ticket, error)
+	Skip(context.Context, string) (*models.Ticket, error)
+	CancelTicket(context.Context, string, string) (*models.Ticket, error)
+	TransferTicket(context.Context, string, models.TicketTransfer) (*models.Ticket, error)
+	ServicePointStats(context.Context, string, time.Time, time.Time) (*models.QueueStats, error)
+	OfficeStats(context.Context, time.Time, time.Time) ([]models.QueueStats, error)
+	Enqueue(context.Context, string, string) (*models.Ticket, error)
 	Dequeue(context.Context, string) (*models.Ticket, error)
+	SetSlotSchedule(context.Context, string, models.SlotScheduleRequest) (*models.SlotSchedule, error)
+	GetSlotSchedule(context.Context, string) (*models.SlotSchedule, error)
+	ListSlots(context.Context, string, time.Time) ([]models.Slot, error)
+	BookAppointment(context.Context, models.NewAppointmentRequest) (*models.Appointment, error)
+	GetAppointment(context.Context, string) (*models.Appointment, error)
+	CancelAppointment(context.Context, string) (*models.Appointment, error)
+	CheckIn(context.Context, string) (*models.Appointment, error)
+	SetWorkingHours(context.Context, string, models.WorkingHoursRequest) (*models.WorkingHours, error)
+	GetWorkingHours(context.Context, string) (*models.WorkingHours, error)
+	DeleteWorkingHours(context.Context, string) error
+	SetOfficeHours(context.Context, string, models.WorkingHoursRequest) (*models.WorkingHours, error)
+	GetOfficeHours(context.Context, string) (*models.WorkingHours, error)
+	CreateOperator(context.Context, models.NewOperatorRequest) (*models.Operator, error)
+	GetOperator(context.Context, string) (*models.Operator, error)
+	ListOperators(context.Context) ([]models.Operator, error)
+	StartSession(context.Context, string, models.NewSessionRequest) (*models.Session, error)
+	GetSession(context.Context, string) (*models.Session, error)
+	EndSession(context.Context, string) (*models.Session, error)
+	OperatorStats(context.Context, time.Time, time.Time) ([]models.OperatorStats, error)
+	CreateCategory(context.Context, models.NewCategoryRequest) (*models.Category, error)
+	GetCategory(context.Context, string) (*models.Category, error)
+	ListCategories(context.Context) ([]models.Category, error)
+	SetSPCategories(context.Context, string, models.CategoryAssignment) ([]models.Category, error)
+	GetSPCategories(context.Context, string) ([]models.Category, error)
+	EnqueueCategory(context.Context, string) (*models.Ticket, error)
+	CancelCategoryTicket(context.Context, string, string) (*models.Ticket, error)
+	CreateOrganisation(context.Context, models.NewOrganisationRequest) (*models.Organisation, error)
+	GetOrganisation(context.Context, string) (*models.Organisation, error)
+	ListOrganisations(context.Context) ([]models.Organisation, error)
+	CreateBuilding(context.Context, models.NewBuildingRequest) (*models.Building, error)
+	GetBuilding(context.Context, string) (*models.Building, error)
+	ListBuildings(context.Context) ([]models.Building, error)
+	CreateFloor(context.Context, string, models.NewFloorRequest) (*models.Floor, error)
+	GetFloor(context.Context, string) (*models.Floor, error)
+	ListFloors(context.Context, string) ([]models.Floor, error)
+	CreateOffice(context.Context, string, models.NewOfficeRequest) (*models.Office, error)
+	ListOffices(context.Context, string) ([]models.Office, error)
+	GetLocation(context.Context, string) (*models.Location, error)
+	ResolveTenant(context.Context, string, string) (string, error)
+	IsAdminHost(string) bool
 }
 
 type SPHandler struct {
@@ -29,10 +90,113 @@ func NewSPHandler(service mainService) *SPHandler {
 	return &SPHandler{service: service}
 }
 
+// errorResponse is the body of every JSON error reply. Fields is only set
+// for validation errors.
+type errorResponse struct {
+	Error  string              `json:"error"`
+	Fields []models.FieldError `json:"fields,omitempty"`
+}
+
+// writeError reports err with the status errorStatus picks. Validation errors
+// are written as JSON listing every invalid field, anything else as text.
+func writeError(w http.ResponseWriter, err error) {
+	var verr *models.ValidationError
+	if errors.As(err, &verr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(errorStatus(err))
+		json.NewEncoder(w).Encode(errorResponse{Error: models.ErrInvalidArgument.Error(), Fields: verr.Fields})
+		return
+	}
+
+	w.WriteHeader(errorStatus(err))
+	w.Write([]byte(err.Error()))
+}
+
+// errorStatus maps a service error to the HTTP status it is reported with.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrNotFound), errors.Is(err, models.ErrTicketNotFound),
+		errors.Is(err, models.ErrScheduleNotFound), errors.Is(err, models.ErrAppointmentNotFound),
+		errors.Is(err, models.ErrHoursNotFound), errors.Is(err, models.ErrOperatorNotFound),
+		errors.Is(err, models.ErrSessionNotFound), errors.Is(err, models.ErrCategoryNotFound),
+		errors.Is(err, models.ErrOrganisationNotFound), errors.Is(err, models.ErrBuildingNotFound),
+		errors.Is(err, models.ErrFloorNotFound), errors.Is(err, models.ErrOfficeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidArgument):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, models.ErrAlreadyExists), errors.Is(err, models.ErrDeleted),
+		errors.Is(err, models.ErrSlotFull), errors.Is(err, models.ErrAppointmentState),
+		errors.Is(err, models.ErrClosed), errors.Is(err, models.ErrPaused), errors.Is(err, models.ErrQuotaReached),
+		errors.Is(err, models.ErrSessionActive), errors.Is(err, models.ErrNotServed),
+		errors.Is(err, models.ErrOrganisationExists), errors.Is(err, models.ErrFloorExists),
+		errors.Is(err, models.ErrOfficeExists), errors.Is(err, models.ErrAmbiguousTicket):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrNotAdmin), errors.Is(err, models.ErrTenantMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrQueueFull):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// includeDeleted reads the includeDeleted query parameter that makes reads
+// return soft-deleted service points too.
+func includeDeleted(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("includeDeleted")
+	if value == "" {
+		return false, nil
+	}
+
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		var verr models.ValidationError
+		verr.Add("includeDeleted", "must be a boolean")
+		return false, &verr
+	}
+	return include, nil
+}
+
+// CreateSP stores a new service point under a server-allocated id and
+// points to it with the Location header.
+func (m *SPHandler) CreateSP(w http.ResponseWriter, r *http.Request) {
+	log.Print("create service point handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var newSp models.NewServicePointRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "NewServicePointRequest", &newSp); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	createdSP, err := m.service.CreateSP(ctx, newSp)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error creating service point: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("%s/servicepoint/%d", APIPrefix, createdSP.ID))
+	w.Header().Set("ETag", etag(createdSP.Version))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(createdSP); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("201 created - service point ID: %d", createdSP.ID)
+}
+
 func (m *SPHandler) UpsertSP(w http.ResponseWriter, r *http.Request) {
 	log.Print("upsert service point handler called")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
 	var newSp models.NewServicePointRequest
@@ -40,27 +204,27 @@ func (m *SPHandler) UpsertSP(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	if err := json.NewDecoder(r.Body).Decode(&newSp); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	defer r.Body.Close()
+	if err := decodeBody(r, "NewServicePointRequest", &newSp); err != nil {
+		writeError(w, err)
 		return
 	}
-	defer r.Body.Close()
 
-	// Validate required fields
-	if newSp.Name == "" || newSp.ShortName == "" || newSp.OfficeNumber == "" {
-		http.Error(w, "name, shortName and officeNumber are required", http.StatusBadRequest)
+	ifVersion, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
-	upsertedSP, err := m.service.UpsertSP(ctx, id, newSp)
+	upsertedSP, err := m.service.UpsertSP(ctx, id, newSp, ifVersion)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
+		writeError(w, err)
 		log.Printf("error upserting service point: %s", err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(upsertedSP.Version))
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(upsertedSP); err != nil {
 		log.Printf("failed to encode response: %s", err)
@@ -69,25 +233,80 @@ func (m *SPHandler) UpsertSP(w http.ResponseWriter, r *http.Request) {
 	log.Printf("200 ok - service point ID: %d", upsertedSP.ID)
 }
 
+// PatchSP applies a JSON Merge Patch (RFC 7396) to a service point.
+func (m *SPHandler) PatchSP(w http.ResponseWriter, r *http.Request) {
+	log.Print("patch service point handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	mediaType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";")
+	switch strings.TrimSpace(mediaType) {
+	case "application/merge-patch+json", "application/json":
+	default:
+		http.Error(w, "content type must be application/merge-patch+json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var patch models.ServicePointPatch
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "ServicePointPatch", &patch); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ifVersion, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	patchedSP, err := m.service.PatchSP(ctx, id, patch, ifVersion)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error patching service point: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(patchedSP.Version))
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(patchedSP); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - service point ID: %d", patchedSP.ID)
+}
+
 func (m *SPHandler) DeleteSP(w http.ResponseWriter, r *http.Request) {
 	log.Print("delete service point handler called")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	deletedSP, err := m.service.DeleteSP(ctx, id)
+	ifVersion, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	deletedSP, err := m.service.DeleteSP(ctx, id, ifVersion)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
+		writeError(w, err)
 		log.Printf("error deleting service point: %s", err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.Header().Set("ETag", etag(deletedSP.Version))
+	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(deletedSP); err != nil {
 		log.Printf("failed to encode response: %s", err)
 	}
@@ -95,25 +314,63 @@ func (m *SPHandler) DeleteSP(w http.ResponseWriter, r *http.Request) {
 	log.Printf("200 ok - service point ID: %d", deletedSP.ID)
 }
 
+func (m *SPHandler) RestoreSP(w http.ResponseWriter, r *http.Request) {
+	log.Print("restore service point handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	ifVersion, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	restoredSP, err := m.service.RestoreSP(ctx, id, ifVersion)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error restoring service point: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(restoredSP.Version))
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(restoredSP); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - service point ID: %d", restoredSP.ID)
+}
+
 func (m *SPHandler) GetSP(w http.ResponseWriter, r *http.Request) {
 	log.Print("get service point handler called")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	sp, err := m.service.GetSPByID(ctx, id)
+	withDeleted, err := includeDeleted(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	sp, err := m.service.GetSPByID(ctx, id, withDeleted)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
+		writeError(w, err)
 		log.Printf("error getting service point: %s", err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.Header().Set("ETag", etag(sp.Version))
+	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(sp); err != nil {
 		log.Printf("failed to encode response: %s", err)
 	}
@@ -121,20 +378,229 @@ func (m *SPHandler) GetSP(w http.ResponseWriter, r *http.Request) {
 	log.Printf("200 ok - service point ID: %d", sp.ID)
 }
 
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// historyPage reads the cursor and limit query parameters of the history
+// endpoint.
+func historyPage(r *http.Request) (int64, int, error) {
+	var verr models.ValidationError
+	query := r.URL.Query()
+
+	var cursor int64
+	if value := query.Get("cursor"); value != "" {
+		var err error
+		cursor, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || cursor < 0 {
+			verr.Add("cursor", "must be a non-negative integer")
+		}
+	}
+
+	limit := defaultHistoryLimit
+	if value := query.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxHistoryLimit {
+			verr.Add("limit", "must be an integer between 1 and %d", maxHistoryLimit)
+		}
+	}
+
+	return cursor, limit, verr.Err()
+}
+
+func (m *SPHandler) GetSPHistory(w http.ResponseWriter, r *http.Request) {
+	log.Print("service point history handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	cursor, limit, err := historyPage(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	page, err := m.service.GetSPHistory(ctx, id, cursor, limit)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting service point history: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %d history records", len(page.Records))
+}
+
+func (m *SPHandler) ListSP(w http.ResponseWriter, r *http.Request) {
+	log.Print("list service points handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	withDeleted, err := includeDeleted(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	sps, err := m.service.ListSP(ctx, withDeleted)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error listing service points: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(sps); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %d service points", len(sps))
+}
+
+// ticketFilter reads the code, from and to query parameters of the ticket
+// listing; from and to are RFC 3339 times.
+func ticketFilter(r *http.Request) (models.TicketFilter, error) {
+	var verr models.ValidationError
+	query := r.URL.Query()
+
+	filter := models.TicketFilter{Code: query.Get("code"), Status: query.Get("status")}
+	switch filter.Status {
+	case "", models.TicketStatusWaiting, models.TicketStatusCalled, models.TicketStatusServed,
+		models.TicketStatusNoShow, models.TicketStatusCancelled, models.TicketStatusTransferred:
+	default:
+		verr.Add("status", "must be a ticket status")
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"from", &filter.IssuedFrom},
+		{"to", &filter.IssuedTo},
+	} {
+		value := query.Get(p.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			verr.Add(p.name, "must be an RFC 3339 time")
+			continue
+		}
+		*p.dst = t
+	}
+
+	return filter, verr.Err()
+}
+
+func (m *SPHandler) ListTickets(w http.ResponseWriter, r *http.Request) {
+	log.Print("list tickets handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	filter, err := ticketFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	tickets, err := m.service.ListTickets(ctx, id, filter)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error listing tickets: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(tickets); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %d tickets", len(tickets))
+}
+
+func (m *SPHandler) TicketStatus(w http.ResponseWriter, r *http.Request) {
+	log.Print("ticket status handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	code := vars["code"]
+
+	ticket, err := m.service.TicketStatus(ctx, code)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting ticket status: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ticket); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - ticket %s", ticket.Ticket)
+}
+
+func (m *SPHandler) ServicePointTicketStatus(w http.ResponseWriter, r *http.Request) {
+	log.Print("service point ticket status handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+	code := vars["code"]
+
+	ticket, err := m.service.ServicePointTicketStatus(ctx, id, code)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting ticket status: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ticket); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - ticket %s", ticket.Ticket)
+}
+
 func (m *SPHandler) Enqueue(w http.ResponseWriter, r *http.Request) {
 	log.Print("enqueue handler called")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	ticket, err := m.service.Enqueue(ctx, id)
+	class := r.URL.Query().Get("class")
+
+	ticket, err := m.service.Enqueue(ctx, id, class)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
-		log.Printf("error getting service point: %s", err)
+		writeError(w, err)
+		log.Printf("error enqueueing: %s", err)
 		return
 	}
 
@@ -148,9 +614,9 @@ func (m *SPHandler) Enqueue(w http.ResponseWriter, r *http.Request) {
 }
 
 func (m *SPHandler) Dequeue(w http.ResponseWriter, r *http.Request) {
-	log.Print("enqueue handler called")
+	log.Print("dequeue handler called")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
 	vars := mux.Vars(r)
@@ -158,17 +624,125 @@ func (m *SPHandler) Dequeue(w http.ResponseWriter, r *http.Request) {
 
 	ticket, err := m.service.Dequeue(ctx, id)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
-		log.Printf("error getting service point: %s", err)
+		writeError(w, err)
+		log.Printf("error dequeueing: %s", err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(ticket); err != nil {
 		log.Printf("failed to encode response: %s", err)
 	}
 
 	log.Printf("200 ok")
 }
+
+func (m *SPHandler) Recall(w http.ResponseWriter, r *http.Request) {
+	log.Print("recall handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	ticket, err := m.service.Recall(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error recalling: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ticket); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - ticket %s %s", ticket.Ticket, ticket.Status)
+}
+
+func (m *SPHandler) Skip(w http.ResponseWriter, r *http.Request) {
+	log.Print("skip handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	ticket, err := m.service.Skip(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error skipping: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ticket); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok")
+}
+
+func (m *SPHandler) CancelTicket(w http.ResponseWriter, r *http.Request) {
+	log.Print("cancel ticket handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+	code := vars["code"]
+
+	ticket, err := m.service.CancelTicket(ctx, id, code)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error cancelling ticket: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ticket); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - ticket %s", ticket.Ticket)
+}
+
+func (m *SPHandler) TransferTicket(w http.ResponseWriter, r *http.Request) {
+	log.Print("transfer ticket handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	code := vars["code"]
+
+	var transfer models.TicketTransfer
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "TicketTransfer", &transfer); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ticket, err := m.service.TransferTicket(ctx, code, transfer)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error transferring ticket: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ticket); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - ticket %s to service point %d", ticket.Ticket, ticket.ServicePointID)
+}
@@ -0,0 +1,1982 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/snnus/mainservice/config"
+	"github.com/snnus/mainservice/internal/client"
+	"github.com/snnus/mainservice/internal/handlers"
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/services/spservice"
+	"github.com/snnus/mainservice/internal/tenant"
+	"github.com/snnus/mainservice/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type env struct {
+	qe       *testutil.QueueEngine
+	storage  *testutil.Storage
+	producer *testutil.Producer
+	service  *spservice.SPService
+	server   *httptest.Server
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+
+	qe := testutil.NewQueueEngine()
+	t.Cleanup(qe.Close)
+
+	storage := testutil.NewStorage()
+	producer := testutil.NewProducer()
+
+	cfg := &config.Config{Queueengine: qe.Config()}
+	service := spservice.NewSPService(storage, client.NewClient(cfg), producer)
+	service.SetAdminHost(adminHost)
+	server := httptest.NewServer(handlers.NewRouter(handlers.NewSPHandler(service)))
+	t.Cleanup(server.Close)
+
+	return &env{qe: qe, storage: storage, producer: producer, service: service, server: server}
+}
+
+func (e *env) do(t *testing.T, method, path, body string) (int, string) {
+	t.Helper()
+
+	status, _, respBody := e.request(t, method, path, body, nil)
+	return status, respBody
+}
+
+func (e *env) request(t *testing.T, method, path, body string, headers map[string]string) (int, http.Header, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, e.server.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if host := headers["Host"]; host != "" {
+		req.Host = host
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, resp.Header, string(data)
+}
+
+// adminHost is the host test requests managing organisations are sent to.
+const adminHost = "admin.example.org"
+
+// admin sends a request to adminHost, as administrators do.
+var admin = map[string]string{"Host": adminHost}
+
+// createOrganisation creates an organisation as an administrator.
+func (e *env) createOrganisation(t *testing.T, body string) {
+	t.Helper()
+
+	status, _, respBody := e.request(t, http.MethodPost, "/api/v1/organisations", body, admin)
+	require.Equal(t, http.StatusCreated, status, respBody)
+}
+
+func (e *env) mustDo(t *testing.T, method, path, body string, wantStatus int) string {
+	t.Helper()
+
+	status, respBody := e.do(t, method, path, body)
+	require.Equal(t, wantStatus, status, respBody)
+	return respBody
+}
+
+// assertFieldErrors checks a validation error response lists exactly the
+// given field messages.
+func assertFieldErrors(t *testing.T, body string, want map[string]string) {
+	t.Helper()
+
+	var resp struct {
+		Error  string              `json:"error"`
+		Fields []models.FieldError `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &resp), body)
+	assert.Equal(t, models.ErrInvalidArgument.Error(), resp.Error)
+
+	got := make(map[string]string, len(resp.Fields))
+	for _, f := range resp.Fields {
+		got[f.Field] = f.Message
+	}
+	assert.Equal(t, want, got)
+}
+
+const cashDesk = `{"name":"Cash desk","shortName":"C","officeNumber":"101"}`
+
+func TestEndToEnd(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, e *env)
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		check      func(t *testing.T, e *env, body string)
+	}{
+		{
+			name:       "upsert creates service point",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       cashDesk,
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, e *env, body string) {
+				var sp models.ServicePoint
+				require.NoError(t, json.Unmarshal([]byte(body), &sp))
+				assert.Equal(t, int64(1), sp.ID)
+				assert.Equal(t, "Cash desk", sp.Name)
+				assert.Equal(t, "C", sp.ShortName)
+				assert.Equal(t, "101", sp.OfficeNumber)
+			},
+		},
+		{
+			name: "upsert overwrites existing service point",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+			},
+			method:     http.MethodPost,
+			path:       "/api/v1/servicepoint/1",
+			body:       `{"name":"Accounts","shortName":"A","officeNumber":"202"}`,
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, e *env, body string) {
+				var sp models.ServicePoint
+				require.NoError(t, json.Unmarshal([]byte(body), &sp))
+				assert.Equal(t, "Accounts", sp.Name)
+				assert.Equal(t, "202", sp.OfficeNumber)
+				assert.False(t, sp.UpdatedAt.Before(sp.CreatedAt))
+			},
+		},
+		{
+			name:       "upsert rejects malformed json",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       `{"name":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "upsert rejects missing fields",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       `{"name":"Cash desk"}`,
+			wantStatus: http.StatusBadRequest,
+			check: func(t *testing.T, e *env, body string) {
+				assertFieldErrors(t, body, map[string]string{"shortName": "is required"})
+			},
+		},
+		{
+			name:       "upsert rejects values longer than their columns",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       `{"name":"Cash desk","shortName":"CASHDESK123","officeNumber":"12345678901"}`,
+			wantStatus: http.StatusBadRequest,
+			check: func(t *testing.T, e *env, body string) {
+				assertFieldErrors(t, body, map[string]string{
+					"shortName":    "must be at most 10 characters",
+					"officeNumber": "must be at most 10 characters",
+				})
+			},
+		},
+		{
+			name:       "upsert counts characters not bytes",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       `{"name":"Касса","shortName":"КАССАКАССА","officeNumber":"101"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "upsert rejects unknown fields",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       `{"name":"Cash desk","shortName":"C","officeNumber":"101","floor":2}`,
+			wantStatus: http.StatusBadRequest,
+			check: func(t *testing.T, e *env, body string) {
+				assertFieldErrors(t, body, map[string]string{"floor": "unknown field"})
+			},
+		},
+		{
+			name:       "upsert rejects wrong types",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       `{"name":"Cash desk","shortName":"C","officeNumber":101}`,
+			wantStatus: http.StatusBadRequest,
+			check: func(t *testing.T, e *env, body string) {
+				assertFieldErrors(t, body, map[string]string{"officeNumber": "must be a string"})
+			},
+		},
+		{
+			name:       "upsert checks number bounds",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       `{"name":"Cash desk","shortName":"C","officeId":0,"maxQueueLength":-1}`,
+			wantStatus: http.StatusBadRequest,
+			check: func(t *testing.T, e *env, body string) {
+				assertFieldErrors(t, body, map[string]string{
+					"officeId":       "must be at least 1",
+					"maxQueueLength": "must not be negative",
+				})
+			},
+		},
+		{
+			name:       "slot schedule checks number bounds",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1/slots/schedule",
+			body:       `{"slotMinutes":1441,"capacity":0}`,
+			wantStatus: http.StatusBadRequest,
+			check: func(t *testing.T, e *env, body string) {
+				assertFieldErrors(t, body, map[string]string{
+					"slotMinutes": "must be at most 1440",
+					"capacity":    "must be at least 1",
+				})
+			},
+		},
+		{
+			name:       "upsert reports every invalid field at once",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       `{"name":"   ","shortName":"C-1","officeNumber":"1\u0007"}`,
+			wantStatus: http.StatusBadRequest,
+			check: func(t *testing.T, e *env, body string) {
+				assertFieldErrors(t, body, map[string]string{
+					"name":         "is required",
+					"shortName":    "must contain only letters and digits",
+					"officeNumber": "must not contain control characters",
+				})
+			},
+		},
+		{
+			name:       "upsert trims whitespace",
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       `{"name":"  Cash desk ","shortName":" C ","officeNumber":"101\t"}`,
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, e *env, body string) {
+				var sp models.ServicePoint
+				require.NoError(t, json.Unmarshal([]byte(body), &sp))
+				assert.Equal(t, "Cash desk", sp.Name)
+				assert.Equal(t, "C", sp.ShortName)
+				assert.Equal(t, "101", sp.OfficeNumber)
+			},
+		},
+		{
+			name: "upsert surfaces storage failure",
+			setup: func(t *testing.T, e *env) {
+				e.storage.SetFailing(true)
+			},
+			method:     http.MethodPut,
+			path:       "/api/v1/servicepoint/1",
+			body:       cashDesk,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "get returns service point",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/7", cashDesk, http.StatusCreated)
+			},
+			method:     http.MethodGet,
+			path:       "/api/v1/servicepoint/7",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, e *env, body string) {
+				var sp models.ServicePoint
+				require.NoError(t, json.Unmarshal([]byte(body), &sp))
+				assert.Equal(t, int64(7), sp.ID)
+				assert.Equal(t, "Cash desk", sp.Name)
+			},
+		},
+		{
+			name:       "get unknown service point",
+			method:     http.MethodGet,
+			path:       "/api/v1/servicepoint/404",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "list returns service points ordered by id",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/9", cashDesk, http.StatusCreated)
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/2", cashDesk, http.StatusCreated)
+			},
+			method:     http.MethodGet,
+			path:       "/api/v1/servicepoint",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, e *env, body string) {
+				var sps []models.ServicePoint
+				require.NoError(t, json.Unmarshal([]byte(body), &sps))
+				require.Len(t, sps, 2)
+				assert.Equal(t, int64(2), sps[0].ID)
+				assert.Equal(t, int64(9), sps[1].ID)
+			},
+		},
+		{
+			name: "delete removes service point",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/3", cashDesk, http.StatusCreated)
+			},
+			method:     http.MethodDelete,
+			path:       "/api/v1/servicepoint/3",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, e *env, body string) {
+				var sp models.ServicePoint
+				require.NoError(t, json.Unmarshal([]byte(body), &sp))
+				assert.Equal(t, int64(3), sp.ID)
+				e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/3", "", http.StatusNotFound)
+			},
+		},
+		{
+			name:       "delete unknown service point",
+			method:     http.MethodDelete,
+			path:       "/api/v1/servicepoint/404",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "enqueue issues ticket with short name prefix",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+				e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+			},
+			method:     http.MethodPost,
+			path:       "/api/v1/enqueue/1",
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, e *env, body string) {
+				var ticket models.Ticket
+				require.NoError(t, json.Unmarshal([]byte(body), &ticket))
+				assert.Equal(t, "C002", ticket.Ticket)
+				assert.Equal(t, 2, e.qe.Len("1"))
+			},
+		},
+		{
+			name:       "enqueue unknown service point",
+			method:     http.MethodPost,
+			path:       "/api/v1/enqueue/404",
+			wantStatus: http.StatusNotFound,
+			check: func(t *testing.T, e *env, body string) {
+				assert.Equal(t, 0, e.qe.Len("404"))
+			},
+		},
+		{
+			name: "enqueue surfaces queue engine failure",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+				e.qe.SetFailing(true)
+			},
+			method:     http.MethodPost,
+			path:       "/api/v1/enqueue/1",
+			wantStatus: http.StatusInternalServerError,
+			check: func(t *testing.T, e *env, body string) {
+				assert.Contains(t, body, "500")
+			},
+		},
+		{
+			name: "dequeue calls ticket and publishes it",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+				e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+				e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+			},
+			method:     http.MethodPost,
+			path:       "/api/v1/dequeue/1",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, e *env, body string) {
+				var ticket models.Ticket
+				require.NoError(t, json.Unmarshal([]byte(body), &ticket))
+				assert.Equal(t, "C001", ticket.Ticket)
+				assert.Equal(t, 1, e.qe.Len("1"))
+
+				messages := e.producer.Messages()
+				require.Len(t, messages, 1)
+				assert.Equal(t, "C001", messages[0].Ticket)
+				assert.Equal(t, "101", messages[0].OfficeNumber)
+			},
+		},
+		{
+			name: "dequeue empty queue",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+			},
+			method:     http.MethodPost,
+			path:       "/api/v1/dequeue/1",
+			wantStatus: http.StatusInternalServerError,
+			check: func(t *testing.T, e *env, body string) {
+				assert.Contains(t, body, "queue is empty")
+				assert.Empty(t, e.producer.Messages())
+			},
+		},
+		{
+			name: "dequeue for service point deleted after enqueue",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+				e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+				e.mustDo(t, http.MethodDelete, "/api/v1/servicepoint/1", "", http.StatusOK)
+			},
+			method:     http.MethodPost,
+			path:       "/api/v1/dequeue/1",
+			wantStatus: http.StatusNotFound,
+			check: func(t *testing.T, e *env, body string) {
+				assert.Empty(t, e.producer.Messages())
+			},
+		},
+		{
+			name: "dequeue still succeeds when publishing fails",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+				e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+				e.producer.SetFailing(true)
+			},
+			method:     http.MethodPost,
+			path:       "/api/v1/dequeue/1",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, e *env, body string) {
+				var ticket models.Ticket
+				require.NoError(t, json.Unmarshal([]byte(body), &ticket))
+				assert.Equal(t, "C001", ticket.Ticket)
+			},
+		},
+		{
+			name: "dequeue surfaces queue engine failure",
+			setup: func(t *testing.T, e *env) {
+				e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+				e.qe.SetFailing(true)
+			},
+			method:     http.MethodPost,
+			path:       "/api/v1/dequeue/1",
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "openapi document is served",
+			method:     http.MethodGet,
+			path:       "/api/v1/openapi.json",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, e *env, body string) {
+				var doc map[string]any
+				require.NoError(t, json.Unmarshal([]byte(body), &doc))
+				assert.Equal(t, "3.0.3", doc["openapi"])
+			},
+		},
+		{
+			name:       "unversioned routes are gone",
+			method:     http.MethodGet,
+			path:       "/servicepoint/1",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "router rejects non-numeric id",
+			method:     http.MethodGet,
+			path:       "/api/v1/servicepoint/abc",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "router rejects unsupported method",
+			method:     http.MethodGet,
+			path:       "/api/v1/enqueue/1",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnv(t)
+			if tt.setup != nil {
+				tt.setup(t, e)
+			}
+
+			status, body := e.do(t, tt.method, tt.path, tt.body)
+			require.Equal(t, tt.wantStatus, status, body)
+
+			if tt.check != nil {
+				tt.check(t, e, body)
+			}
+		})
+	}
+}
+
+func TestOptimisticConcurrency(t *testing.T) {
+	const path = "/api/v1/servicepoint/1"
+	mergePatch := map[string]string{"Content-Type": "application/merge-patch+json"}
+
+	e := newEnv(t)
+
+	status, header, body := e.request(t, http.MethodPut, path, cashDesk, nil)
+	require.Equal(t, http.StatusCreated, status, body)
+	assert.Equal(t, `"1"`, header.Get("ETag"))
+
+	status, header, body = e.request(t, http.MethodGet, path, "", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	etag := header.Get("ETag")
+	assert.Equal(t, `"1"`, etag)
+
+	t.Run("patch updates only supplied fields", func(t *testing.T) {
+		status, header, body := e.request(t, http.MethodPatch, path, `{"officeNumber":"205"}`, map[string]string{
+			"Content-Type": "application/merge-patch+json",
+			"If-Match":     etag,
+		})
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Equal(t, `"2"`, header.Get("ETag"))
+
+		var sp models.ServicePoint
+		require.NoError(t, json.Unmarshal([]byte(body), &sp))
+		assert.Equal(t, "Cash desk", sp.Name)
+		assert.Equal(t, "C", sp.ShortName)
+		assert.Equal(t, "205", sp.OfficeNumber)
+		assert.Equal(t, int64(2), sp.Version)
+	})
+
+	t.Run("stale if-match is rejected", func(t *testing.T) {
+		for _, method := range []string{http.MethodPut, http.MethodPatch, http.MethodDelete} {
+			status, _, body := e.request(t, method, path, cashDesk, map[string]string{
+				"Content-Type": "application/json",
+				"If-Match":     etag,
+			})
+			assert.Equal(t, http.StatusPreconditionFailed, status, "%s: %s", method, body)
+		}
+	})
+
+	t.Run("weak if-match never matches", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodDelete, path, "", map[string]string{"If-Match": `W/"2"`})
+		assert.Equal(t, http.StatusPreconditionFailed, status, body)
+	})
+
+	t.Run("if-match on missing service point", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPut, "/api/v1/servicepoint/2", cashDesk, map[string]string{"If-Match": `"1"`})
+		assert.Equal(t, http.StatusPreconditionFailed, status, body)
+	})
+
+	t.Run("patch rejects null and unknown fields", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPatch, path, `{"name":null,"floor":2}`, mergePatch)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{
+			"name":  "must be a string",
+			"floor": "unknown field",
+		})
+	})
+
+	t.Run("patch validates the merged result", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPatch, path, `{"shortName":"C1"}`, mergePatch)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"shortName": "must not end with a digit"})
+	})
+
+	t.Run("patch requires merge patch content type", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPatch, path, `{"name":"x"}`, map[string]string{"Content-Type": "text/plain"})
+		assert.Equal(t, http.StatusUnsupportedMediaType, status, body)
+	})
+
+	t.Run("patch unknown service point", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPatch, "/api/v1/servicepoint/404", `{"name":"x"}`, mergePatch)
+		assert.Equal(t, http.StatusNotFound, status, body)
+	})
+
+	t.Run("delete with current if-match", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodDelete, path, "", map[string]string{"If-Match": `"2"`})
+		assert.Equal(t, http.StatusOK, status, body)
+	})
+}
+
+func TestCreateServicePoint(t *testing.T) {
+	e := newEnv(t)
+
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+
+	status, header, body := e.request(t, http.MethodPost, "/api/v1/servicepoint", cashDesk, nil)
+	require.Equal(t, http.StatusCreated, status, body)
+
+	var created models.ServicePoint
+	require.NoError(t, json.Unmarshal([]byte(body), &created))
+	assert.Equal(t, int64(2), created.ID, "allocated id skips the one taken by PUT")
+	assert.Equal(t, "/api/v1/servicepoint/2", header.Get("Location"))
+	assert.Equal(t, `"1"`, header.Get("ETag"))
+
+	got := e.mustDo(t, http.MethodGet, header.Get("Location"), "", http.StatusOK)
+	var sp models.ServicePoint
+	require.NoError(t, json.Unmarshal([]byte(got), &sp))
+	assert.Equal(t, created.ShardID, sp.ShardID)
+
+	status, header, body = e.request(t, http.MethodPost, "/api/v1/servicepoint", cashDesk, nil)
+	require.Equal(t, http.StatusCreated, status, body)
+	assert.Equal(t, "/api/v1/servicepoint/3", header.Get("Location"))
+
+	t.Run("rejects invalid body", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPost, "/api/v1/servicepoint", `{"name":"Cash desk"}`, nil)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"shortName": "is required"})
+	})
+
+	t.Run("requires an office number without an office", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPost, "/api/v1/servicepoint", `{"name":"Cash desk","shortName":"C"}`, nil)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"officeNumber": "is required"})
+	})
+}
+
+func TestSoftDelete(t *testing.T) {
+	const path = "/api/v1/servicepoint/1"
+
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, path, cashDesk, http.StatusCreated)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/2", cashDesk, http.StatusCreated)
+
+	status, header, body := e.request(t, http.MethodDelete, path, "", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, `"2"`, header.Get("ETag"))
+
+	var deleted models.ServicePoint
+	require.NoError(t, json.Unmarshal([]byte(body), &deleted))
+	require.NotNil(t, deleted.DeletedAt)
+
+	t.Run("hidden from reads", func(t *testing.T) {
+		e.mustDo(t, http.MethodGet, path, "", http.StatusNotFound)
+
+		var sps []models.ServicePoint
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint", "", http.StatusOK)), &sps))
+		require.Len(t, sps, 1)
+		assert.Equal(t, int64(2), sps[0].ID)
+	})
+
+	t.Run("include deleted", func(t *testing.T) {
+		var sp models.ServicePoint
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, path+"?includeDeleted=true", "", http.StatusOK)), &sp))
+		assert.NotNil(t, sp.DeletedAt)
+
+		var sps []models.ServicePoint
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint?includeDeleted=true", "", http.StatusOK)), &sps))
+		assert.Len(t, sps, 2)
+	})
+
+	t.Run("include deleted must be a boolean", func(t *testing.T) {
+		status, body := e.do(t, http.MethodGet, "/api/v1/servicepoint?includeDeleted=maybe", "")
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"includeDeleted": "must be a boolean"})
+	})
+
+	t.Run("enqueue is blocked", func(t *testing.T) {
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusNotFound)
+		assert.Zero(t, e.qe.Len("1"))
+	})
+
+	t.Run("id cannot be reused", func(t *testing.T) {
+		e.mustDo(t, http.MethodPut, path, cashDesk, http.StatusConflict)
+	})
+
+	t.Run("restore with stale if-match", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPost, path+"/restore", "", map[string]string{"If-Match": `"1"`})
+		assert.Equal(t, http.StatusPreconditionFailed, status, body)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		status, header, body := e.request(t, http.MethodPost, path+"/restore", "", map[string]string{"If-Match": `"2"`})
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Equal(t, `"3"`, header.Get("ETag"))
+
+		e.mustDo(t, http.MethodGet, path, "", http.StatusOK)
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	})
+
+	t.Run("restore unknown service point", func(t *testing.T) {
+		e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/404/restore", "", http.StatusNotFound)
+	})
+}
+
+func TestHistory(t *testing.T) {
+	const path = "/api/v1/servicepoint/1"
+
+	e := newEnv(t)
+
+	status, header, body := e.request(t, http.MethodPut, path, cashDesk, map[string]string{
+		"X-Caller":     "alice",
+		"X-Request-ID": "req-1",
+	})
+	require.Equal(t, http.StatusCreated, status, body)
+	assert.Equal(t, "req-1", header.Get("X-Request-ID"))
+
+	_, header, _ = e.request(t, http.MethodPut, path, `{"name":"Cash desk","shortName":"C","officeNumber":"205"}`, map[string]string{"X-Caller": "bob"})
+	generatedID := header.Get("X-Request-ID")
+	assert.NotEmpty(t, generatedID)
+
+	e.mustDo(t, http.MethodDelete, path, "", http.StatusOK)
+
+	var page models.HistoryPage
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, path+"/history?limit=2", "", http.StatusOK)), &page))
+	require.Len(t, page.Records, 2)
+	assert.NotZero(t, page.NextCursor)
+
+	assert.Equal(t, models.AuditActionCreate, page.Records[0].Action)
+	assert.Equal(t, "alice", page.Records[0].Caller)
+	assert.Equal(t, "req-1", page.Records[0].RequestID)
+
+	update := page.Records[1]
+	assert.Equal(t, models.AuditActionUpdate, update.Action)
+	assert.Equal(t, "bob", update.Caller)
+	assert.Equal(t, generatedID, update.RequestID)
+	var before, after models.ServicePoint
+	require.NoError(t, json.Unmarshal(update.Before, &before))
+	require.NoError(t, json.Unmarshal(update.After, &after))
+	assert.Equal(t, "101", before.OfficeNumber)
+	assert.Equal(t, "205", after.OfficeNumber)
+
+	var next models.HistoryPage
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, fmt.Sprintf("%s/history?limit=2&cursor=%d", path, page.NextCursor), "", http.StatusOK)), &next))
+	require.Len(t, next.Records, 1)
+	assert.Equal(t, models.AuditActionDelete, next.Records[0].Action)
+	assert.Zero(t, next.NextCursor)
+
+	t.Run("invalid paging", func(t *testing.T) {
+		status, body := e.do(t, http.MethodGet, path+"/history?limit=0&cursor=x", "")
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{
+			"cursor": "must be a non-negative integer",
+			"limit":  "must be an integer between 1 and 100",
+		})
+	})
+
+	t.Run("unknown service point", func(t *testing.T) {
+		e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/404/history", "", http.StatusNotFound)
+	})
+}
+
+func TestTickets(t *testing.T) {
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+
+	var tickets []models.TicketRecord
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/tickets", "", http.StatusOK)), &tickets))
+	require.Len(t, tickets, 2)
+	assert.Equal(t, "C001", tickets[0].Code)
+	assert.Equal(t, models.TicketStatusCalled, tickets[0].Status)
+	assert.NotNil(t, tickets[0].CalledAt)
+	assert.Equal(t, "C002", tickets[1].Code)
+	assert.Equal(t, models.TicketStatusWaiting, tickets[1].Status)
+
+	t.Run("filter by code and time", func(t *testing.T) {
+		from := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		var tickets []models.TicketRecord
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/tickets?code=C002&from="+from, "", http.StatusOK)), &tickets))
+		require.Len(t, tickets, 1)
+		assert.Equal(t, "C002", tickets[0].Code)
+	})
+
+	t.Run("kept after delete", func(t *testing.T) {
+		e.mustDo(t, http.MethodDelete, "/api/v1/servicepoint/1", "", http.StatusOK)
+		var tickets []models.TicketRecord
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/tickets", "", http.StatusOK)), &tickets))
+		assert.Len(t, tickets, 2)
+	})
+
+	t.Run("invalid time", func(t *testing.T) {
+		status, body := e.do(t, http.MethodGet, "/api/v1/servicepoint/1/tickets?from=yesterday", "")
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"from": "must be an RFC 3339 time"})
+	})
+
+	t.Run("unknown service point", func(t *testing.T) {
+		e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/404/tickets", "", http.StatusNotFound)
+	})
+}
+
+func TestStats(t *testing.T) {
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/2", `{"name":"Accounts","shortName":"A","officeNumber":"202"}`, http.StatusCreated)
+
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/2", "", http.StatusCreated)
+	// The first ticket is served once the second is called; the second is
+	// only called.
+	e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+	e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+
+	t.Run("service point", func(t *testing.T) {
+		var stats models.QueueStats
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/stats/servicepoint/1", "", http.StatusOK)), &stats))
+		assert.Equal(t, int64(1), stats.ServicePointID)
+		assert.Equal(t, 2, stats.Issued)
+		assert.Equal(t, 1, stats.Served)
+		require.Len(t, stats.PeakHours, 1)
+		assert.Equal(t, 2, stats.PeakHours[0].Issued)
+	})
+
+	t.Run("offices", func(t *testing.T) {
+		var stats []models.QueueStats
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/stats/offices", "", http.StatusOK)), &stats))
+		require.Len(t, stats, 2)
+		assert.Equal(t, "101", stats[0].OfficeNumber)
+		assert.Equal(t, 2, stats[0].Issued)
+		assert.Equal(t, "202", stats[1].OfficeNumber)
+		assert.Equal(t, 1, stats[1].Issued)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		status, header, body := e.request(t, http.MethodGet, "/api/v1/stats/offices?format=csv", "", nil)
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Equal(t, "text/csv", header.Get("Content-Type"))
+
+		today := time.Now().UTC().Format("2006-01-02")
+		lines := strings.Split(strings.TrimSpace(body), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, "office_number,service_point_id,from,to,issued,served,avg_wait_seconds,p90_wait_seconds,peak_hours", lines[0])
+		assert.True(t, strings.HasPrefix(lines[1], "101,,"+today+","+today+",2,1,"), lines[1])
+	})
+
+	t.Run("range outside tickets", func(t *testing.T) {
+		var stats models.QueueStats
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/stats/servicepoint/1?from=2020-01-01&to=2020-01-31", "", http.StatusOK)), &stats))
+		assert.Zero(t, stats.Issued)
+		assert.Empty(t, stats.PeakHours)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		status, body := e.do(t, http.MethodGet, "/api/v1/stats/offices?from=2024-02-01&to=2024-01-01", "")
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{
+			"to": "must not be before from",
+		})
+	})
+
+	t.Run("unknown service point", func(t *testing.T) {
+		e.mustDo(t, http.MethodGet, "/api/v1/stats/servicepoint/404", "", http.StatusNotFound)
+	})
+
+	t.Run("closing serves the last ticket", func(t *testing.T) {
+		e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/1/close", "", http.StatusOK)
+
+		var stats models.QueueStats
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/stats/servicepoint/1", "", http.StatusOK)), &stats))
+		assert.Equal(t, 2, stats.Served)
+	})
+}
+
+func TestTicketStatus(t *testing.T) {
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+
+	var first, second models.Ticket
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)), &first))
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)), &second))
+	assert.Equal(t, 1, first.Position)
+	assert.Equal(t, 2, second.Position)
+	assert.Equal(t, models.TicketStatusWaiting, second.Status)
+	assert.Nil(t, second.EstimatedWaitSeconds, "nothing called yet")
+
+	e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+
+	var status models.Ticket
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/ticket/C002", "", http.StatusOK)), &status))
+	assert.Equal(t, int64(1), status.ServicePointID)
+	assert.Equal(t, 1, status.Position, "moved up")
+
+	var called models.Ticket
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/ticket/C001", "", http.StatusOK)), &called))
+	assert.Equal(t, models.TicketStatusCalled, called.Status)
+	assert.Zero(t, called.Position)
+
+	e.mustDo(t, http.MethodGet, "/api/v1/ticket/Z999", "", http.StatusNotFound)
+
+	t.Run("codes in other scripts", func(t *testing.T) {
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/2", `{"name":"Касса","shortName":"К","officeNumber":"102"}`, http.StatusCreated)
+
+		var ticket models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPost, "/api/v1/enqueue/2", "", http.StatusCreated)), &ticket))
+		require.Equal(t, "К001", ticket.Ticket)
+
+		path := "/api/v1/ticket/" + url.PathEscape(ticket.Ticket)
+		e.mustDo(t, http.MethodGet, path, "", http.StatusOK)
+		e.mustDo(t, http.MethodGet, "/api/v1/ticket/2/"+url.PathEscape(ticket.Ticket), "", http.StatusOK)
+		e.mustDo(t, http.MethodPost, "/api/v1/ticket/2/"+url.PathEscape(ticket.Ticket)+"/cancel", "", http.StatusOK)
+	})
+
+	t.Run("codes of several service points", func(t *testing.T) {
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/3", cashDesk, http.StatusCreated)
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/3", "", http.StatusCreated)
+
+		e.mustDo(t, http.MethodGet, "/api/v1/ticket/C001", "", http.StatusConflict)
+		e.mustDo(t, http.MethodGet, "/api/v1/ticket/3/C001", "", http.StatusOK)
+
+		e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+		var ticket models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/ticket/C001", "", http.StatusOK)), &ticket))
+		assert.Equal(t, int64(3), ticket.ServicePointID, "the ticket still waiting")
+	})
+}
+
+func TestServicePointTicketStatus(t *testing.T) {
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/2", `{"name":"Accounts","shortName":"A","officeNumber":"202"}`, http.StatusCreated)
+
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+	e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+
+	status := func(path string) models.Ticket {
+		var ticket models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, path, "", http.StatusOK)), &ticket))
+		return ticket
+	}
+
+	served := status("/api/v1/ticket/1/C001")
+	assert.Equal(t, models.TicketStatusServed, served.Status)
+	assert.Equal(t, "101", served.OfficeNumber)
+
+	called := status("/api/v1/ticket/1/C002")
+	assert.Equal(t, models.TicketStatusCalled, called.Status)
+	assert.Equal(t, "101", called.OfficeNumber)
+	assert.Zero(t, called.Position)
+
+	waiting := status("/api/v1/ticket/1/C003")
+	assert.Equal(t, models.TicketStatusWaiting, waiting.Status)
+	assert.Equal(t, 1, waiting.Position)
+	assert.Empty(t, waiting.OfficeNumber)
+
+	e.mustDo(t, http.MethodGet, "/api/v1/ticket/2/C003", "", http.StatusNotFound)
+	e.mustDo(t, http.MethodGet, "/api/v1/ticket/9/C001", "", http.StatusNotFound)
+}
+
+func TestDeskOperations(t *testing.T) {
+	e := newEnv(t)
+	e.service.SetMaxRecalls(1)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+	for range 4 {
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	}
+
+	decode := func(body string) models.Ticket {
+		var ticket models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(body), &ticket))
+		return ticket
+	}
+	events := func() []string {
+		var events []string
+		for _, msg := range e.producer.Messages() {
+			events = append(events, msg.Event+" "+msg.Ticket)
+		}
+		return events
+	}
+
+	e.mustDo(t, http.MethodPost, "/api/v1/recall/1", "", http.StatusNotFound)
+
+	t.Run("cancel", func(t *testing.T) {
+		cancelled := decode(e.mustDo(t, http.MethodPost, "/api/v1/ticket/1/C002/cancel", "", http.StatusOK))
+		assert.Equal(t, models.TicketStatusCancelled, cancelled.Status)
+		assert.Equal(t, 3, e.qe.Len("1"), "taken out of the queue engine")
+
+		e.mustDo(t, http.MethodPost, "/api/v1/ticket/1/C002/cancel", "", http.StatusNotFound)
+		e.mustDo(t, http.MethodPost, "/api/v1/ticket/1/C009/cancel", "", http.StatusNotFound)
+	})
+
+	t.Run("recall until no-show", func(t *testing.T) {
+		e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+
+		recalled := decode(e.mustDo(t, http.MethodPost, "/api/v1/recall/1", "", http.StatusOK))
+		assert.Equal(t, "C001", recalled.Ticket)
+		assert.Equal(t, models.TicketStatusCalled, recalled.Status)
+		assert.Equal(t, "101", recalled.OfficeNumber)
+
+		noShow := decode(e.mustDo(t, http.MethodPost, "/api/v1/recall/1", "", http.StatusOK))
+		assert.Equal(t, models.TicketStatusNoShow, noShow.Status)
+
+		e.mustDo(t, http.MethodPost, "/api/v1/recall/1", "", http.StatusNotFound)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+
+		next := decode(e.mustDo(t, http.MethodPost, "/api/v1/skip/1", "", http.StatusOK))
+		assert.Equal(t, "C004", next.Ticket)
+
+		skipped := decode(e.mustDo(t, http.MethodGet, "/api/v1/ticket/1/C003", "", http.StatusOK))
+		assert.Equal(t, models.TicketStatusNoShow, skipped.Status)
+	})
+
+	assert.Equal(t, []string{
+		"cancelled C002",
+		"called C001",
+		"recalled C001",
+		"no-show C001",
+		"called C003",
+		"skipped C003",
+		"called C004",
+	}, events())
+
+	var tickets []models.TicketRecord
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/tickets?status=no-show", "", http.StatusOK)), &tickets))
+	require.Len(t, tickets, 2)
+	assert.Equal(t, 1, tickets[0].Recalls)
+	e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/tickets?status=lost", "", http.StatusBadRequest)
+}
+
+func TestTransferTicket(t *testing.T) {
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/2", `{"name":"Accounts","shortName":"A","officeNumber":"202"}`, http.StatusCreated)
+
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/2", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/2", "", http.StatusCreated)
+
+	decode := func(body string) models.Ticket {
+		var ticket models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(body), &ticket))
+		return ticket
+	}
+
+	e.mustDo(t, http.MethodPost, "/api/v1/ticket/C001/transfer", `{"servicePointId":2}`, http.StatusNotFound)
+
+	e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+	e.mustDo(t, http.MethodPost, "/api/v1/ticket/C001/transfer", `{"servicePointId":1}`, http.StatusBadRequest)
+	e.mustDo(t, http.MethodPost, "/api/v1/ticket/C001/transfer", `{"servicePointId":2,"position":"middle"}`, http.StatusBadRequest)
+	e.mustDo(t, http.MethodPost, "/api/v1/ticket/C001/transfer", `{"servicePointId":9}`, http.StatusNotFound)
+
+	// The target admits the ticket only as it would admit a new one.
+	e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/2/pause", "", http.StatusOK)
+	e.mustDo(t, http.MethodPost, "/api/v1/ticket/C001/transfer", `{"servicePointId":2}`, http.StatusConflict)
+	e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/2/resume", "", http.StatusOK)
+	mergePatch := map[string]string{"Content-Type": "application/merge-patch+json"}
+	status, _, body := e.request(t, http.MethodPatch, "/api/v1/servicepoint/2", `{"maxQueueLength":2}`, mergePatch)
+	require.Equal(t, http.StatusOK, status, body)
+	e.mustDo(t, http.MethodPost, "/api/v1/ticket/C001/transfer", `{"servicePointId":2}`, http.StatusTooManyRequests)
+	status, _, body = e.request(t, http.MethodPatch, "/api/v1/servicepoint/2", `{"maxQueueLength":null}`, mergePatch)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, []string{"A001", "A002"}, e.qe.Queue("2"))
+
+	front := decode(e.mustDo(t, http.MethodPost, "/api/v1/ticket/C001/transfer", `{"servicePointId":2,"position":"front"}`, http.StatusOK))
+	assert.Equal(t, "C001", front.Ticket)
+	assert.Equal(t, int64(2), front.ServicePointID)
+	assert.Equal(t, models.TicketStatusWaiting, front.Status)
+	assert.Equal(t, 1, front.Position)
+	assert.Equal(t, []string{"C001", "A001", "A002"}, e.qe.Queue("2"))
+
+	original := decode(e.mustDo(t, http.MethodGet, "/api/v1/ticket/1/C001", "", http.StatusOK))
+	assert.Equal(t, models.TicketStatusTransferred, original.Status)
+
+	var transferred []models.TicketRecord
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/tickets?status=transferred", "", http.StatusOK)), &transferred))
+	require.Len(t, transferred, 1)
+	assert.Equal(t, "C001", transferred[0].Code)
+
+	last := e.producer.Messages()[len(e.producer.Messages())-1]
+	assert.Equal(t, models.TicketEventTransferred, last.Event)
+	assert.Equal(t, "C001", last.Ticket)
+	assert.Equal(t, "202", last.OfficeNumber)
+
+	called := decode(e.mustDo(t, http.MethodPost, "/api/v1/dequeue/2", "", http.StatusOK))
+	assert.Equal(t, "C001", called.Ticket)
+
+	e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+	back := decode(e.mustDo(t, http.MethodPost, "/api/v1/ticket/C002/transfer", `{"servicePointId":2}`, http.StatusOK))
+	assert.Equal(t, 3, back.Position)
+	assert.Equal(t, []string{"A001", "A002", "C002"}, e.qe.Queue("2"))
+
+	var tickets []models.TicketRecord
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/2/tickets?code=C001", "", http.StatusOK)), &tickets))
+	require.Len(t, tickets, 1)
+	assert.Equal(t, int64(1), tickets[0].TransferredFrom)
+}
+
+func TestTransferTicketSharedCode(t *testing.T) {
+	e := newEnv(t)
+	for _, id := range []string{"1", "3"} {
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/"+id, cashDesk, http.StatusCreated)
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/"+id, "", http.StatusCreated)
+		e.mustDo(t, http.MethodPost, "/api/v1/dequeue/"+id, "", http.StatusOK)
+	}
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/2", `{"name":"Accounts","shortName":"A","officeNumber":"202"}`, http.StatusCreated)
+
+	e.mustDo(t, http.MethodPost, "/api/v1/ticket/C001/transfer", `{"servicePointId":2}`, http.StatusConflict)
+
+	var moved models.Ticket
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPost, "/api/v1/ticket/C001/transfer", `{"servicePointId":2,"fromServicePointId":3}`, http.StatusOK)), &moved))
+	assert.Equal(t, int64(2), moved.ServicePointID)
+
+	var kept models.Ticket
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/ticket/1/C001", "", http.StatusOK)), &kept))
+	assert.Equal(t, models.TicketStatusCalled, kept.Status)
+	var left models.Ticket
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/ticket/3/C001", "", http.StatusOK)), &left))
+	assert.Equal(t, models.TicketStatusTransferred, left.Status)
+}
+
+func TestTicketClasses(t *testing.T) {
+	decode := func(t *testing.T, body string) models.Ticket {
+		var ticket models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(body), &ticket))
+		return ticket
+	}
+	dequeueAll := func(t *testing.T, e *env) []string {
+		var codes []string
+		for {
+			status, body := e.do(t, http.MethodPost, "/api/v1/dequeue/1", "")
+			if status != http.StatusOK {
+				return codes
+			}
+			codes = append(codes, decode(t, body).Ticket)
+		}
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		e := newEnv(t)
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+
+		var positions []int
+		for _, class := range []string{"", "regular", "priority", "appointment", "priority"} {
+			ticket := decode(t, e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1?class="+class, "", http.StatusCreated))
+			positions = append(positions, ticket.Position)
+		}
+		assert.Equal(t, []int{1, 2, 1, 1, 3}, positions, "counting the lines that go first")
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1?class=vip", "", http.StatusBadRequest)
+
+		assert.Equal(t, 5, decode(t, e.mustDo(t, http.MethodGet, "/api/v1/ticket/1/C002", "", http.StatusOK)).Position)
+		assert.Equal(t, 2, decode(t, e.mustDo(t, http.MethodGet, "/api/v1/ticket/1/PC001", "", http.StatusOK)).Position)
+
+		assert.Equal(t, []string{"AC001", "PC001", "PC002", "C001", "C002"}, dequeueAll(t, e))
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		e := newEnv(t)
+		require.NoError(t, e.service.SetDequeuePolicy(spservice.PolicyWeighted, map[string]int{
+			models.TicketClassPriority: 2,
+		}))
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+
+		for range 4 {
+			e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1?class=priority", "", http.StatusCreated)
+		}
+		ticket := decode(t, e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated))
+		assert.Equal(t, models.TicketClassRegular, ticket.Class)
+		assert.Equal(t, 2, ticket.Position)
+		ticket = decode(t, e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated))
+		assert.Equal(t, 5, ticket.Position)
+
+		assert.Equal(t, 6, decode(t, e.mustDo(t, http.MethodGet, "/api/v1/ticket/1/PC004", "", http.StatusOK)).Position)
+
+		assert.Equal(t, []string{"PC001", "C001", "PC002", "PC003", "C002", "PC004"}, dequeueAll(t, e))
+	})
+}
+
+func TestAppointments(t *testing.T) {
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+
+	decode := func(body string) models.Appointment {
+		var appointment models.Appointment
+		require.NoError(t, json.Unmarshal([]byte(body), &appointment))
+		return appointment
+	}
+	book := func(slot time.Time, status int) string {
+		return e.mustDo(t, http.MethodPost, "/api/v1/appointments",
+			fmt.Sprintf(`{"servicePointId":1,"slotStart":%q,"name":"Ann"}`, slot.Format(time.RFC3339)), status)
+	}
+
+	next := time.Now().UTC().Truncate(30 * time.Minute).Add(30 * time.Minute)
+	later := next.Add(2 * time.Hour)
+
+	book(next, http.StatusNotFound)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1/slots/schedule", `{"slotMinutes":7,"capacity":1}`, http.StatusBadRequest)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/9/slots/schedule", `{"slotMinutes":30,"capacity":1}`, http.StatusNotFound)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1/slots/schedule", `{"slotMinutes":30,"capacity":1}`, http.StatusOK)
+
+	book(next.Add(-time.Hour), http.StatusBadRequest)
+	book(next.Add(time.Minute), http.StatusBadRequest)
+	e.mustDo(t, http.MethodPost, "/api/v1/appointments", `{"servicePointId":1,"slotStart":"tomorrow","name":"Ann"}`, http.StatusBadRequest)
+
+	booked := decode(book(next, http.StatusCreated))
+	assert.Equal(t, models.AppointmentStatusBooked, booked.Status)
+	assert.True(t, next.Add(30*time.Minute).Equal(booked.SlotEnd))
+	book(next, http.StatusConflict)
+
+	var slots []models.Slot
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/slots?date="+next.Format("2006-01-02"), "", http.StatusOK)), &slots))
+	require.Len(t, slots, 48)
+	for _, slot := range slots {
+		if slot.Start.Equal(next) {
+			assert.Equal(t, 1, slot.Booked)
+		}
+	}
+
+	tooEarly := decode(book(later, http.StatusCreated))
+	e.mustDo(t, http.MethodPost, fmt.Sprintf("/api/v1/appointments/%d/checkin", tooEarly.ID), "", http.StatusBadRequest)
+	cancelled := decode(e.mustDo(t, http.MethodPost, fmt.Sprintf("/api/v1/appointments/%d/cancel", tooEarly.ID), "", http.StatusOK))
+	assert.Equal(t, models.AppointmentStatusCancelled, cancelled.Status)
+	e.mustDo(t, http.MethodPost, fmt.Sprintf("/api/v1/appointments/%d/cancel", tooEarly.ID), "", http.StatusConflict)
+
+	checkedIn := decode(e.mustDo(t, http.MethodPost, fmt.Sprintf("/api/v1/appointments/%d/checkin", booked.ID), "", http.StatusOK))
+	assert.Equal(t, models.AppointmentStatusCheckedIn, checkedIn.Status)
+	assert.Equal(t, "AC001", checkedIn.Ticket)
+	e.mustDo(t, http.MethodPost, fmt.Sprintf("/api/v1/appointments/%d/checkin", booked.ID), "", http.StatusConflict)
+
+	got := decode(e.mustDo(t, http.MethodGet, fmt.Sprintf("/api/v1/appointments/%d", booked.ID), "", http.StatusOK))
+	assert.Equal(t, "AC001", got.Ticket)
+	e.mustDo(t, http.MethodGet, "/api/v1/appointments/999", "", http.StatusNotFound)
+
+	var ticket models.Ticket
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)), &ticket))
+	assert.Equal(t, "AC001", ticket.Ticket, "appointments go ahead of walk-ins")
+}
+
+func TestWorkingHours(t *testing.T) {
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+
+	monday := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	e.service.SetClock(func() time.Time { return monday })
+
+	decode := func(body string) models.WorkingHours {
+		var hours models.WorkingHours
+		require.NoError(t, json.Unmarshal([]byte(body), &hours))
+		return hours
+	}
+
+	e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/schedule", "", http.StatusNotFound)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+
+	office := decode(e.mustDo(t, http.MethodPut, "/api/v1/office/101/schedule", `{"week":{"monday":{"open":"09:00","close":"18:00"}}}`, http.StatusOK))
+	assert.Equal(t, models.WorkingHoursSourceOffice, office.Source)
+	assert.Equal(t, "UTC", office.Timezone)
+
+	inherited := decode(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/schedule", "", http.StatusOK))
+	assert.Equal(t, models.WorkingHoursSourceOffice, inherited.Source)
+	assert.Equal(t, "101", inherited.OfficeNumber)
+
+	closed := e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusConflict)
+	assert.Contains(t, closed, "opens at 09:00")
+
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1/schedule", `{"week":{"monday":{"open":"9:00","close":"18:00"}}}`, http.StatusBadRequest)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1/schedule", `{"week":{"monday":{"open":"09:00","close":"08:00"}}}`, http.StatusBadRequest)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1/schedule", `{"week":{},"timezone":"Nowhere"}`, http.StatusBadRequest)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/9/schedule", `{"week":{}}`, http.StatusNotFound)
+
+	own := decode(e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1/schedule", `{"week":{"monday":{"open":"07:30","close":"18:00"}},"holidays":[{"date":"2026-10-20","name":"Staff training"}]}`, http.StatusOK))
+	assert.Equal(t, models.WorkingHoursSourceServicePoint, own.Source)
+	assert.Equal(t, int64(1), own.ServicePointID)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+
+	e.service.SetClock(func() time.Time { return monday.AddDate(0, 0, 1) })
+	assert.Contains(t, e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusConflict), "Staff training")
+
+	e.mustDo(t, http.MethodDelete, "/api/v1/servicepoint/1/schedule", "", http.StatusNoContent)
+	e.mustDo(t, http.MethodDelete, "/api/v1/servicepoint/1/schedule", "", http.StatusNotFound)
+	assert.Equal(t, models.WorkingHoursSourceOffice, decode(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/schedule", "", http.StatusOK)).Source)
+	assert.Equal(t, models.WorkingHoursSourceOffice, decode(e.mustDo(t, http.MethodGet, "/api/v1/office/101/schedule", "", http.StatusOK)).Source)
+	e.mustDo(t, http.MethodGet, "/api/v1/office/999/schedule", "", http.StatusNotFound)
+}
+
+func TestCapacityLimits(t *testing.T) {
+	e := newEnv(t)
+	mergePatch := map[string]string{"Content-Type": "application/merge-patch+json"}
+
+	status, _, body := e.request(t, http.MethodPut, "/api/v1/servicepoint/1", `{"name":"Cash desk","shortName":"C","officeNumber":"101","maxQueueLength":-1}`, nil)
+	require.Equal(t, http.StatusBadRequest, status, body)
+	assertFieldErrors(t, body, map[string]string{"maxQueueLength": "must not be negative"})
+
+	var sp models.ServicePoint
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1",
+		`{"name":"Cash desk","shortName":"C","officeNumber":"101","maxQueueLength":2}`, http.StatusCreated)), &sp))
+	assert.Equal(t, 2, sp.MaxQueueLength)
+	assert.Zero(t, sp.DailyTicketQuota)
+
+	events := func() []string {
+		var events []string
+		for _, msg := range e.producer.Messages() {
+			if msg.Event == models.TicketEventQueueFull || msg.Event == models.TicketEventQuotaReached {
+				events = append(events, msg.Event+" "+msg.Ticket)
+			}
+		}
+		return events
+	}
+
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1?class=priority", "", http.StatusCreated)
+	assert.Equal(t, []string{"queue-full PC001"}, events())
+	assert.Contains(t, e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusTooManyRequests), "2 tickets are waiting")
+
+	e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+
+	status, _, body = e.request(t, http.MethodPatch, "/api/v1/servicepoint/1", `{"maxQueueLength":0,"dailyTicketQuota":4}`, mergePatch)
+	require.Equal(t, http.StatusOK, status, body)
+	require.NoError(t, json.Unmarshal([]byte(body), &sp))
+	assert.Zero(t, sp.MaxQueueLength)
+	assert.Equal(t, 4, sp.DailyTicketQuota)
+
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	assert.Equal(t, []string{"queue-full PC001", "queue-full C002", "quota-reached C003"}, events())
+	assert.Contains(t, e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusConflict), "all 4 tickets of the day are issued")
+
+	status, _, body = e.request(t, http.MethodPatch, "/api/v1/servicepoint/1", `{"maxQueueLength":null,"dailyTicketQuota":null}`, mergePatch)
+	require.Equal(t, http.StatusOK, status, body)
+	require.NoError(t, json.Unmarshal([]byte(body), &sp))
+	assert.Zero(t, sp.DailyTicketQuota, "null lifts the limit")
+	e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+}
+
+func TestServicePointStatus(t *testing.T) {
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+	for range 2 {
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	}
+
+	decode := func(body string) models.ServicePoint {
+		var sp models.ServicePoint
+		require.NoError(t, json.Unmarshal([]byte(body), &sp))
+		return sp
+	}
+	returnAt := time.Now().Add(30 * time.Minute).UTC().Truncate(time.Second)
+
+	t.Run("pause validates its body", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPost, "/api/v1/servicepoint/1/pause", `{"returnAt":"2020-01-01T12:00:00Z","until":"later"}`, nil)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"until": "unknown field"})
+
+		status, _, body = e.request(t, http.MethodPost, "/api/v1/servicepoint/1/pause", `{"returnAt":"2020-01-01T12:00:00Z"}`, nil)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"returnAt": "must be in the future"})
+
+		e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/9/pause", "", http.StatusNotFound)
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		paused := decode(e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/1/pause",
+			`{"reason":" Lunch break ","returnAt":"`+returnAt.Format(time.RFC3339)+`"}`, http.StatusOK))
+		assert.Equal(t, models.ServicePointStatusPaused, paused.Status)
+		assert.Equal(t, "Lunch break", paused.StatusReason)
+		require.NotNil(t, paused.ReturnAt)
+		assert.True(t, returnAt.Equal(*paused.ReturnAt))
+
+		refused := e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusConflict)
+		assert.Contains(t, refused, "Lunch break, back at "+returnAt.Format(time.RFC3339))
+		e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusConflict)
+
+		var ticket models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/ticket/1/C001", "", http.StatusOK)), &ticket))
+		assert.Equal(t, models.ServicePointStatusPaused, ticket.ServicePointStatus)
+		assert.Equal(t, "Lunch break", ticket.StatusReason)
+		require.NotNil(t, ticket.ReturnAt)
+		assert.True(t, returnAt.Equal(*ticket.ReturnAt))
+	})
+
+	t.Run("resumed", func(t *testing.T) {
+		resumed := decode(e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/1/resume", "", http.StatusOK))
+		assert.Equal(t, models.ServicePointStatusOpen, resumed.Status)
+		assert.Empty(t, resumed.StatusReason)
+		assert.Nil(t, resumed.ReturnAt)
+
+		e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+
+		var ticket models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/ticket/1/C002", "", http.StatusOK)), &ticket))
+		assert.Empty(t, ticket.ServicePointStatus)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		closed := decode(e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/1/close", "", http.StatusOK))
+		assert.Equal(t, models.ServicePointStatusClosed, closed.Status)
+
+		assert.Equal(t, "service point is closed", e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusConflict))
+		e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+	})
+
+	t.Run("changes are published and audited", func(t *testing.T) {
+		var statuses []string
+		for _, msg := range e.producer.StatusMessages() {
+			assert.Equal(t, models.ServicePointEventStatus, msg.Event)
+			assert.Equal(t, "101", msg.OfficeNumber)
+			statuses = append(statuses, msg.Status)
+		}
+		assert.Equal(t, []string{"paused", "open", "closed"}, statuses)
+
+		var page models.HistoryPage
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/history", "", http.StatusOK)), &page))
+		var actions []string
+		for _, record := range page.Records {
+			actions = append(actions, record.Action)
+		}
+		assert.Equal(t, []string{"create", "pause", "resume", "close"}, actions)
+	})
+}
+
+func TestOperators(t *testing.T) {
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+	for range 3 {
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+	}
+
+	var alice, bob models.Operator
+	status, header, body := e.request(t, http.MethodPost, "/api/v1/operators", `{"name":" Alice "}`, nil)
+	require.Equal(t, http.StatusCreated, status, body)
+	require.NoError(t, json.Unmarshal([]byte(body), &alice))
+	assert.Equal(t, "Alice", alice.Name)
+	assert.Equal(t, fmt.Sprintf("/api/v1/operators/%d", alice.ID), header.Get("Location"))
+	require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPost, "/api/v1/operators", `{"name":"Bob"}`, http.StatusCreated)), &bob))
+
+	t.Run("operators are listed", func(t *testing.T) {
+		var operators []models.Operator
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/operators", "", http.StatusOK)), &operators))
+		assert.Len(t, operators, 2)
+
+		e.mustDo(t, http.MethodGet, fmt.Sprintf("/api/v1/operators/%d", bob.ID), "", http.StatusOK)
+		e.mustDo(t, http.MethodGet, "/api/v1/operators/99", "", http.StatusNotFound)
+	})
+
+	t.Run("login validates its body", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPost, "/api/v1/servicepoint/1/session", `{"operatorId":1,"deskNumber":"12345678901"}`, nil)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"deskNumber": "must be at most 10 characters"})
+
+		e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/1/session", `{"operatorId":99,"deskNumber":"7"}`, http.StatusNotFound)
+		e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/9/session", fmt.Sprintf(`{"operatorId":%d,"deskNumber":"7"}`, alice.ID), http.StatusNotFound)
+	})
+
+	t.Run("dequeue without an operator records no desk", func(t *testing.T) {
+		var ticket models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)), &ticket))
+		assert.Empty(t, ticket.DeskNumber)
+	})
+
+	t.Run("dequeue records the desk of the logged in operator", func(t *testing.T) {
+		e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/session", "", http.StatusNotFound)
+		e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/1/session", fmt.Sprintf(`{"operatorId":%d,"deskNumber":"7"}`, alice.ID), http.StatusCreated)
+		e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/1/session", fmt.Sprintf(`{"operatorId":%d,"deskNumber":"8"}`, bob.ID), http.StatusConflict)
+
+		var ticket models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)), &ticket))
+		assert.Equal(t, "7", ticket.DeskNumber)
+
+		messages := e.producer.Messages()
+		last := messages[len(messages)-1]
+		assert.Equal(t, ticket.Ticket, last.Ticket)
+		assert.Equal(t, "101", last.OfficeNumber)
+		assert.Equal(t, "7", last.DeskNumber)
+
+		var records []models.TicketRecord
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/tickets?code="+ticket.Ticket, "", http.StatusOK)), &records))
+		require.Len(t, records, 1)
+		assert.Equal(t, alice.ID, records[0].OperatorID)
+		assert.Equal(t, "7", records[0].DeskNumber)
+	})
+
+	t.Run("logout", func(t *testing.T) {
+		var session models.Session
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodDelete, "/api/v1/servicepoint/1/session", "", http.StatusOK)), &session))
+		assert.Equal(t, alice.ID, session.OperatorID)
+		assert.NotNil(t, session.EndedAt)
+
+		var called []models.TicketRecord
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/tickets?status=called", "", http.StatusOK)), &called))
+		assert.Empty(t, called, "the ticket being served is served")
+
+		e.mustDo(t, http.MethodDelete, "/api/v1/servicepoint/1/session", "", http.StatusNotFound)
+		e.mustDo(t, http.MethodPost, "/api/v1/servicepoint/1/session", fmt.Sprintf(`{"operatorId":%d,"deskNumber":"8"}`, bob.ID), http.StatusCreated)
+	})
+
+	t.Run("stats count the tickets each operator called", func(t *testing.T) {
+		var stats []models.OperatorStats
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/stats/operators", "", http.StatusOK)), &stats))
+		require.Len(t, stats, 2)
+		assert.Equal(t, alice.ID, stats[0].OperatorID)
+		assert.Equal(t, 1, stats[0].Called)
+		assert.Greater(t, stats[0].LoggedInSeconds, 0.0)
+		assert.Equal(t, 0, stats[1].Called)
+	})
+}
+
+func TestCategories(t *testing.T) {
+	e := newEnv(t)
+	for _, id := range []string{"1", "2"} {
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/"+id, cashDesk, http.StatusCreated)
+	}
+
+	var category models.Category
+	status, header, body := e.request(t, http.MethodPost, "/api/v1/categories", `{"name":"Payments","shortName":"K"}`, nil)
+	require.Equal(t, http.StatusCreated, status, body)
+	require.NoError(t, json.Unmarshal([]byte(body), &category))
+	assert.Equal(t, fmt.Sprintf("/api/v1/categories/%d", category.ID), header.Get("Location"))
+	assert.Empty(t, category.ServicePointIDs)
+	enqueue := fmt.Sprintf("/api/v1/enqueue/category/%d", category.ID)
+
+	t.Run("create validates short names", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPost, "/api/v1/categories", `{"name":"Loans","shortName":"K1"}`, nil)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"shortName": "must not end with a digit"})
+
+		e.mustDo(t, http.MethodPost, "/api/v1/categories", `{"name":"Loans","shortName":"K"}`, http.StatusConflict)
+	})
+
+	t.Run("enqueue needs a service point serving the category", func(t *testing.T) {
+		e.mustDo(t, http.MethodPost, enqueue, "", http.StatusConflict)
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/category/99", "", http.StatusNotFound)
+	})
+
+	t.Run("service points are assigned", func(t *testing.T) {
+		assignment := fmt.Sprintf(`{"categoryIds":[%d]}`, category.ID)
+		for _, id := range []string{"1", "2"} {
+			var categories []models.Category
+			require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/"+id+"/categories", assignment, http.StatusOK)), &categories))
+			require.Len(t, categories, 1)
+		}
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1/categories", `{"categoryIds":[99]}`, http.StatusNotFound)
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/9/categories", assignment, http.StatusNotFound)
+
+		var got models.Category
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, fmt.Sprintf("/api/v1/categories/%d", category.ID), "", http.StatusOK)), &got))
+		assert.Equal(t, []int64{1, 2}, got.ServicePointIDs)
+	})
+
+	t.Run("any service point calls the oldest ticket", func(t *testing.T) {
+		decode := func(body string) models.Ticket {
+			var ticket models.Ticket
+			require.NoError(t, json.Unmarshal([]byte(body), &ticket))
+			return ticket
+		}
+
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+		first := decode(e.mustDo(t, http.MethodPost, enqueue, "", http.StatusCreated))
+		assert.Equal(t, "K001", first.Ticket)
+		assert.Equal(t, category.ID, first.CategoryID)
+		assert.Equal(t, 1, first.Position)
+		e.mustDo(t, http.MethodPost, enqueue, "", http.StatusCreated)
+
+		waiting := decode(e.mustDo(t, http.MethodGet, "/api/v1/ticket/K002", "", http.StatusOK))
+		assert.Equal(t, models.TicketStatusWaiting, waiting.Status)
+		assert.Equal(t, 2, waiting.Position)
+
+		assert.Equal(t, "K001", decode(e.mustDo(t, http.MethodPost, "/api/v1/dequeue/2", "", http.StatusOK)).Ticket)
+		assert.Equal(t, "C001", decode(e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)).Ticket, "issued before K002")
+		assert.Equal(t, "K002", decode(e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)).Ticket)
+
+		called := decode(e.mustDo(t, http.MethodGet, "/api/v1/ticket/K001", "", http.StatusOK))
+		assert.Equal(t, models.TicketStatusCalled, called.Status)
+		assert.Equal(t, int64(2), called.ServicePointID)
+
+		var records []models.TicketRecord
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/1/tickets?code=K002", "", http.StatusOK)), &records))
+		require.Len(t, records, 1)
+		assert.Equal(t, category.ID, records[0].CategoryID)
+		assert.Equal(t, models.TicketStatusCalled, records[0].Status)
+	})
+
+	t.Run("waiting tickets are cancelled", func(t *testing.T) {
+		cancelPath := func(code string) string {
+			return fmt.Sprintf("/api/v1/ticket/category/%d/%s/cancel", category.ID, code)
+		}
+
+		e.mustDo(t, http.MethodPost, enqueue, "", http.StatusCreated)
+		var cancelled models.Ticket
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPost, cancelPath("K003"), "", http.StatusOK)), &cancelled))
+		assert.Equal(t, models.TicketStatusCancelled, cancelled.Status)
+		assert.Equal(t, category.ID, cancelled.CategoryID)
+		assert.NotContains(t, e.qe.Queue(fmt.Sprintf("category-%d", category.ID)), "K003")
+
+		e.mustDo(t, http.MethodPost, cancelPath("K003"), "", http.StatusNotFound)
+		e.mustDo(t, http.MethodPost, cancelPath("K001"), "", http.StatusNotFound)
+		e.mustDo(t, http.MethodPost, "/api/v1/ticket/category/99/K003/cancel", "", http.StatusNotFound)
+	})
+
+	t.Run("category tickets only go before regular tickets", func(t *testing.T) {
+		called := func() string {
+			var ticket models.Ticket
+			require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)), &ticket))
+			return ticket.Ticket
+		}
+
+		e.mustDo(t, http.MethodPost, enqueue, "", http.StatusCreated)
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1?class=priority", "", http.StatusCreated)
+
+		assert.Equal(t, "PC001", called(), "issued after K004")
+		assert.Equal(t, "K004", called())
+	})
+
+	t.Run("short names differ from those of service points", func(t *testing.T) {
+		for _, shortName := range []string{"C", "AC"} {
+			status, _, body := e.request(t, http.MethodPost, "/api/v1/categories", `{"name":"Loans","shortName":"`+shortName+`"}`, nil)
+			require.Equal(t, http.StatusBadRequest, status, body)
+			assertFieldErrors(t, body, map[string]string{"shortName": "would number tickets like service point 1"})
+		}
+
+		status, _, body := e.request(t, http.MethodPut, "/api/v1/servicepoint/3", `{"name":"Payments","shortName":"K","officeNumber":"101"}`, nil)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"shortName": fmt.Sprintf("would number tickets like category %d", category.ID)})
+	})
+}
+
+func TestOrganisations(t *testing.T) {
+	e := newEnv(t)
+
+	status, header, body := e.request(t, http.MethodPost, "/api/v1/organisations", `{"id":"city","name":"City hall","host":"queue.city.example.org"}`, admin)
+	require.Equal(t, http.StatusCreated, status, body)
+	assert.Equal(t, "/api/v1/organisations/city", header.Get("Location"))
+	status, _, body = e.request(t, http.MethodPost, "/api/v1/organisations", `{"id":"city","name":"Again"}`, admin)
+	assert.Equal(t, http.StatusConflict, status, body)
+	status, _, body = e.request(t, http.MethodGet, "/api/v1/organisations/city", "", admin)
+	assert.Equal(t, http.StatusOK, status, body)
+	status, _, body = e.request(t, http.MethodGet, "/api/v1/organisations/nobody", "", admin)
+	assert.Equal(t, http.StatusNotFound, status, body)
+
+	t.Run("create validates ids", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPost, "/api/v1/organisations", `{"id":"City Hall","name":"City hall"}`, admin)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"id": "must match ^[a-z0-9]+(-[a-z0-9]+)*$"})
+	})
+
+	var organisations []models.Organisation
+	_, _, body = e.request(t, http.MethodGet, "/api/v1/organisations", "", admin)
+	require.NoError(t, json.Unmarshal([]byte(body), &organisations))
+	require.Len(t, organisations, 2)
+
+	t.Run("only administrators manage organisations", func(t *testing.T) {
+		e.mustDo(t, http.MethodGet, "/api/v1/organisations", "", http.StatusForbidden)
+		e.mustDo(t, http.MethodPost, "/api/v1/organisations", `{"id":"rogue","name":"Rogue"}`, http.StatusForbidden)
+
+		defaultTenant := map[string]string{handlers.TenantHeader: tenant.Default}
+		status, _, body := e.request(t, http.MethodGet, "/api/v1/organisations", "", defaultTenant)
+		assert.Equal(t, http.StatusForbidden, status, "the default organisation is no administrator: %s", body)
+	})
+
+	t.Run("the host's organisation cannot be overridden", func(t *testing.T) {
+		for _, id := range []string{"county", tenant.Default} {
+			status, _, body := e.request(t, http.MethodGet, "/api/v1/servicepoint", "", map[string]string{
+				"Host":                "queue.city.example.org",
+				handlers.TenantHeader: id,
+			})
+			assert.Equal(t, http.StatusForbidden, status, "%s: %s", id, body)
+		}
+	})
+
+	city := map[string]string{handlers.TenantHeader: "city"}
+
+	t.Run("organisations only see themselves", func(t *testing.T) {
+		e.createOrganisation(t, `{"id":"county","name":"County council"}`)
+
+		status, _, body := e.request(t, http.MethodGet, "/api/v1/organisations/city", "", city)
+		assert.Equal(t, http.StatusOK, status, body)
+		status, _, body = e.request(t, http.MethodGet, "/api/v1/organisations/county", "", city)
+		assert.Equal(t, http.StatusNotFound, status, body)
+		status, _, body = e.request(t, http.MethodGet, "/api/v1/organisations/default", "", city)
+		assert.Equal(t, http.StatusNotFound, status, body)
+
+		status, _, body = e.request(t, http.MethodGet, "/api/v1/organisations", "", city)
+		assert.Equal(t, http.StatusForbidden, status, body)
+		status, _, body = e.request(t, http.MethodPost, "/api/v1/organisations", `{"id":"rogue","name":"Rogue"}`, city)
+		assert.Equal(t, http.StatusForbidden, status, body)
+		e.mustDo(t, http.MethodGet, "/api/v1/organisations/rogue", "", http.StatusNotFound)
+	})
+
+	t.Run("service points are only seen by their organisation", func(t *testing.T) {
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+		status, _, body := e.request(t, http.MethodPut, "/api/v1/servicepoint/2", cashDesk, city)
+		require.Equal(t, http.StatusCreated, status, body)
+
+		status, _, body = e.request(t, http.MethodGet, "/api/v1/servicepoint/1", "", city)
+		assert.Equal(t, http.StatusNotFound, status, body)
+		status, _, body = e.request(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, city)
+		assert.Equal(t, http.StatusConflict, status, "ids are unique across organisations: %s", body)
+		e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/2", "", http.StatusNotFound)
+
+		var list []models.ServicePoint
+		_, _, body = e.request(t, http.MethodGet, "/api/v1/servicepoint", "", city)
+		require.NoError(t, json.Unmarshal([]byte(body), &list))
+		require.Len(t, list, 1)
+		assert.Equal(t, int64(2), list[0].ID)
+	})
+
+	t.Run("the host name picks the organisation", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodGet, "/api/v1/servicepoint/2", "", map[string]string{"Host": "queue.city.example.org:8080"})
+		assert.Equal(t, http.StatusOK, status, body)
+		status, _, body = e.request(t, http.MethodGet, "/api/v1/servicepoint/2", "", map[string]string{"Host": "elsewhere.example.org"})
+		assert.Equal(t, http.StatusNotFound, status, "unknown hosts act for the default organisation: %s", body)
+	})
+
+	t.Run("unknown organisations are rejected", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodGet, "/api/v1/servicepoint", "", map[string]string{handlers.TenantHeader: "nobody"})
+		assert.Equal(t, http.StatusNotFound, status, body)
+	})
+
+	t.Run("tickets are published with their organisation", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPost, "/api/v1/enqueue/2", "", city)
+		require.Equal(t, http.StatusCreated, status, body)
+		status, _, body = e.request(t, http.MethodPost, "/api/v1/dequeue/2", "", city)
+		require.Equal(t, http.StatusOK, status, body)
+
+		messages := e.producer.Messages()
+		require.NotEmpty(t, messages)
+		assert.Equal(t, "city", messages[len(messages)-1].Tenant)
+
+		status, _, body = e.request(t, http.MethodGet, "/api/v1/ticket/C001", "", nil)
+		assert.Equal(t, http.StatusNotFound, status, body)
+	})
+}
+
+func TestLocations(t *testing.T) {
+	e := newEnv(t)
+
+	decode := func(body string, v any) {
+		t.Helper()
+		require.NoError(t, json.Unmarshal([]byte(body), v))
+	}
+
+	var building models.Building
+	status, header, body := e.request(t, http.MethodPost, "/api/v1/buildings", `{"name":"Main building","address":"1 Lenin St","directions":"Entrance from the courtyard"}`, nil)
+	require.Equal(t, http.StatusCreated, status, body)
+	decode(body, &building)
+	assert.Equal(t, fmt.Sprintf("/api/v1/buildings/%d", building.ID), header.Get("Location"))
+
+	var floor models.Floor
+	status, header, body = e.request(t, http.MethodPost, fmt.Sprintf("/api/v1/buildings/%d/floors", building.ID), `{"name":"Second floor","level":2,"directions":"Lift on the left"}`, nil)
+	require.Equal(t, http.StatusCreated, status, body)
+	decode(body, &floor)
+	assert.Equal(t, fmt.Sprintf("/api/v1/floors/%d", floor.ID), header.Get("Location"))
+	assert.Equal(t, building.ID, floor.BuildingID)
+
+	var office models.Office
+	status, header, body = e.request(t, http.MethodPost, fmt.Sprintf("/api/v1/floors/%d/offices", floor.ID), `{"number":"201","directions":"Second door on the right"}`, nil)
+	require.Equal(t, http.StatusCreated, status, body)
+	decode(body, &office)
+	assert.Equal(t, fmt.Sprintf("/api/v1/offices/%d", office.ID), header.Get("Location"))
+
+	t.Run("floors and offices are unique", func(t *testing.T) {
+		e.mustDo(t, http.MethodPost, fmt.Sprintf("/api/v1/buildings/%d/floors", building.ID), `{"name":"Also second","level":2}`, http.StatusConflict)
+		e.mustDo(t, http.MethodPost, fmt.Sprintf("/api/v1/floors/%d/offices", floor.ID), `{"number":"201"}`, http.StatusConflict)
+		e.mustDo(t, http.MethodPost, "/api/v1/buildings/99/floors", `{"name":"Ground floor","level":0}`, http.StatusNotFound)
+		e.mustDo(t, http.MethodPost, "/api/v1/floors/99/offices", `{"number":"1"}`, http.StatusNotFound)
+	})
+
+	t.Run("lists follow the hierarchy", func(t *testing.T) {
+		e.mustDo(t, http.MethodPost, fmt.Sprintf("/api/v1/buildings/%d/floors", building.ID), `{"name":"Ground floor","level":0}`, http.StatusCreated)
+
+		var floors []models.Floor
+		decode(e.mustDo(t, http.MethodGet, fmt.Sprintf("/api/v1/buildings/%d/floors", building.ID), "", http.StatusOK), &floors)
+		require.Len(t, floors, 2)
+		assert.Equal(t, 0, floors[0].Level, "lowest level first")
+
+		var offices []models.Office
+		decode(e.mustDo(t, http.MethodGet, fmt.Sprintf("/api/v1/floors/%d/offices", floor.ID), "", http.StatusOK), &offices)
+		require.Len(t, offices, 1)
+		assert.Equal(t, "201", offices[0].Number)
+
+		e.mustDo(t, http.MethodGet, "/api/v1/buildings/99/floors", "", http.StatusNotFound)
+	})
+
+	t.Run("an office is located by its floor and building", func(t *testing.T) {
+		var location models.Location
+		decode(e.mustDo(t, http.MethodGet, fmt.Sprintf("/api/v1/offices/%d", office.ID), "", http.StatusOK), &location)
+		assert.Equal(t, "201", location.Office.Number)
+		assert.Equal(t, "Second floor", location.Floor.Name)
+		assert.Equal(t, "Main building", location.Building.Name)
+
+		e.mustDo(t, http.MethodGet, "/api/v1/offices/99", "", http.StatusNotFound)
+	})
+
+	t.Run("service points take the number of their office", func(t *testing.T) {
+		var sp models.ServicePoint
+		decode(e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", fmt.Sprintf(`{"name":"Cash desk","shortName":"C","officeId":%d}`, office.ID), http.StatusCreated), &sp)
+		assert.Equal(t, office.ID, sp.OfficeID)
+		assert.Equal(t, "201", sp.OfficeNumber)
+
+		status, _, body := e.request(t, http.MethodPut, "/api/v1/servicepoint/2", fmt.Sprintf(`{"name":"Cash desk","shortName":"C","officeNumber":"101","officeId":%d}`, office.ID), nil)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"officeNumber": fmt.Sprintf(`must be "201", the number of office %d`, office.ID)})
+
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/2", `{"name":"Cash desk","shortName":"C","officeId":99}`, http.StatusNotFound)
+
+		mergePatch := map[string]string{"Content-Type": "application/merge-patch+json"}
+		status, _, body = e.request(t, http.MethodPatch, "/api/v1/servicepoint/1", `{"officeId":0,"officeNumber":"101"}`, mergePatch)
+		require.Equal(t, http.StatusOK, status, body)
+		var moved models.ServicePoint
+		decode(body, &moved)
+		assert.Zero(t, moved.OfficeID)
+		assert.Equal(t, "101", moved.OfficeNumber)
+		status, _, body = e.request(t, http.MethodPatch, "/api/v1/servicepoint/1", fmt.Sprintf(`{"officeId":%d}`, office.ID), mergePatch)
+		require.Equal(t, http.StatusOK, status, body)
+		decode(body, &sp)
+		assert.Equal(t, "201", sp.OfficeNumber, "moving to an office takes its number")
+
+		status, _, body = e.request(t, http.MethodPatch, "/api/v1/servicepoint/1", `{"officeId":null}`, mergePatch)
+		require.Equal(t, http.StatusOK, status, body)
+		var out models.ServicePoint
+		decode(body, &out)
+		assert.Zero(t, out.OfficeID, "null takes the service point out of its office")
+		status, _, body = e.request(t, http.MethodPatch, "/api/v1/servicepoint/1", fmt.Sprintf(`{"officeId":%d}`, office.ID), mergePatch)
+		require.Equal(t, http.StatusOK, status, body)
+	})
+
+	t.Run("called tickets are published with the location", func(t *testing.T) {
+		e.mustDo(t, http.MethodPost, "/api/v1/enqueue/1", "", http.StatusCreated)
+		e.mustDo(t, http.MethodPost, "/api/v1/dequeue/1", "", http.StatusOK)
+
+		messages := e.producer.Messages()
+		require.NotEmpty(t, messages)
+		location := messages[len(messages)-1].Location
+		require.NotNil(t, location)
+		assert.Equal(t, "Main building", location.Building.Name)
+		assert.Equal(t, 2, location.Floor.Level)
+		assert.Equal(t, "Second door on the right", location.Office.Directions)
+	})
+
+	t.Run("locations are only seen by their organisation", func(t *testing.T) {
+		e.createOrganisation(t, `{"id":"city","name":"City hall"}`)
+		city := map[string]string{handlers.TenantHeader: "city"}
+
+		status, _, body := e.request(t, http.MethodGet, fmt.Sprintf("/api/v1/offices/%d", office.ID), "", city)
+		assert.Equal(t, http.StatusNotFound, status, body)
+		status, _, body = e.request(t, http.MethodPut, "/api/v1/servicepoint/3", fmt.Sprintf(`{"name":"Cash desk","shortName":"C","officeId":%d}`, office.ID), city)
+		assert.Equal(t, http.StatusNotFound, status, body)
+	})
+}
+
+func TestImportExport(t *testing.T) {
+	e := newEnv(t)
+	e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/1", cashDesk, http.StatusCreated)
+
+	csvType := map[string]string{"Content-Type": "text/csv"}
+	jsonType := map[string]string{"Content-Type": "application/json"}
+
+	importRows := func(t *testing.T, query, body string, headers map[string]string, wantStatus int) models.ImportResult {
+		t.Helper()
+
+		status, _, respBody := e.request(t, http.MethodPost, "/api/v1/servicepoint:import"+query, body, headers)
+		require.Equal(t, wantStatus, status, respBody)
+		var result models.ImportResult
+		require.NoError(t, json.Unmarshal([]byte(respBody), &result), respBody)
+		return result
+	}
+
+	list := func(t *testing.T) []models.ServicePoint {
+		t.Helper()
+
+		var sps []models.ServicePoint
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint", "", http.StatusOK)), &sps))
+		return sps
+	}
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		result := importRows(t, "?dryRun=true", "id,name,short_name,office_number\n1,Cash desk,C,102\n,Accounts,A,202\n", csvType, http.StatusOK)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, models.ImportModeTransactional, result.Mode)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Updated)
+		assert.Len(t, list(t), 1)
+	})
+
+	t.Run("transactional import rolls back every row", func(t *testing.T) {
+		result := importRows(t, "", `[{"id":1,"name":"Cash desk","shortName":"C","officeNumber":"102"},{"name":"Accounts","shortName":"A1"}]`, jsonType, http.StatusBadRequest)
+		assert.Equal(t, 1, result.Failed)
+		assert.Zero(t, result.Created+result.Updated)
+		require.Len(t, result.Rows, 2)
+		assert.Empty(t, result.Rows[0].Action)
+		assert.Equal(t, 2, result.Rows[1].Row)
+		assert.ElementsMatch(t, []models.FieldError{
+			{Field: "shortName", Message: "must not end with a digit"},
+			{Field: "officeNumber", Message: "is required"},
+		}, result.Rows[1].Fields)
+
+		sps := list(t)
+		require.Len(t, sps, 1)
+		assert.Equal(t, "101", sps[0].OfficeNumber)
+	})
+
+	t.Run("transactional import writes every row", func(t *testing.T) {
+		result := importRows(t, "", "short_name,name,office_number,id,max_queue_length\nC,Cash desk,102,1,\nA,Accounts,202,,20\n", csvType, http.StatusOK)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Updated)
+		require.Len(t, result.Rows, 2)
+		assert.Equal(t, models.AuditActionUpdate, result.Rows[0].Action)
+		assert.Equal(t, models.AuditActionCreate, result.Rows[1].Action)
+		assert.NotZero(t, result.Rows[1].ID)
+
+		sps := list(t)
+		require.Len(t, sps, 2)
+		assert.Equal(t, "102", sps[0].OfficeNumber)
+		assert.Equal(t, 20, sps[1].MaxQueueLength)
+	})
+
+	t.Run("best-effort import writes the valid rows", func(t *testing.T) {
+		e.mustDo(t, http.MethodPut, "/api/v1/servicepoint/7", `{"name":"Archive","shortName":"R","officeNumber":"7"}`, http.StatusCreated)
+		e.mustDo(t, http.MethodDelete, "/api/v1/servicepoint/7", "", http.StatusOK)
+
+		body := `[{"id":5,"name":"Passports","shortName":"P","officeNumber":"305"},{"id":7,"name":"Archive","shortName":"R","officeNumber":"7"},{"id":5,"name":"Visas","shortName":"V","officeNumber":"306"}]`
+		result := importRows(t, "?mode=best-effort", body, jsonType, http.StatusOK)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 2, result.Failed)
+		require.Len(t, result.Rows, 3)
+		assert.Equal(t, models.AuditActionCreate, result.Rows[0].Action)
+		assert.Contains(t, result.Rows[1].Error, models.ErrDeleted.Error())
+		assert.Equal(t, []models.FieldError{{Field: "id", Message: "repeats row 1"}}, result.Rows[2].Fields)
+
+		sp := e.mustDo(t, http.MethodGet, "/api/v1/servicepoint/5", "", http.StatusOK)
+		assert.Contains(t, sp, "Passports")
+	})
+
+	t.Run("malformed files are rejected", func(t *testing.T) {
+		status, _, body := e.request(t, http.MethodPost, "/api/v1/servicepoint:import", "id,name,colour\n1,Cash desk,red\n", csvType)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"": `unknown column "colour"`})
+
+		status, _, body = e.request(t, http.MethodPost, "/api/v1/servicepoint:import", "id,name,max_queue_length\n1,Cash desk,many\n", csvType)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"[0].max_queue_length": "must be an integer"})
+
+		status, _, body = e.request(t, http.MethodPost, "/api/v1/servicepoint:import", `[{"name":"Cash desk","colour":"red"}]`, jsonType)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{"[0].colour": "unknown field"})
+
+		status, _, body = e.request(t, http.MethodPost, "/api/v1/servicepoint:import?mode=eventually", `[]`, jsonType)
+		require.Equal(t, http.StatusBadRequest, status, body)
+		assertFieldErrors(t, body, map[string]string{
+			"mode": `must be "transactional" or "best-effort"`,
+			"":     "import has no rows",
+		})
+
+		status, _, body = e.request(t, http.MethodPost, "/api/v1/servicepoint:import", "<rows/>", map[string]string{"Content-Type": "application/xml"})
+		assert.Equal(t, http.StatusUnsupportedMediaType, status, body)
+	})
+
+	t.Run("export round-trips through import", func(t *testing.T) {
+		status, header, body := e.request(t, http.MethodGet, "/api/v1/servicepoint:export?format=csv", "", nil)
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Equal(t, "text/csv", header.Get("Content-Type"))
+		assert.Contains(t, header.Get("Content-Disposition"), "servicepoints.csv")
+
+		lines := strings.Split(strings.TrimSpace(body), "\n")
+		require.Len(t, lines, 4, "deleted service points are not exported")
+		assert.Equal(t, "id,name,short_name,office_number,office_id,max_queue_length,daily_ticket_quota", lines[0])
+		assert.Equal(t, "1,Cash desk,C,102,,,", lines[1])
+
+		result := importRows(t, "", body, csvType, http.StatusOK)
+		assert.Equal(t, 3, result.Updated)
+
+		var rows []models.ImportRow
+		require.NoError(t, json.Unmarshal([]byte(e.mustDo(t, http.MethodGet, "/api/v1/servicepoint:export", "", http.StatusOK)), &rows))
+		require.Len(t, rows, 3)
+		assert.Equal(t, "Passports", rows[2].Name)
+	})
+
+	t.Run("imports only touch their organisation", func(t *testing.T) {
+		e.createOrganisation(t, `{"id":"city","name":"City hall"}`)
+		city := map[string]string{handlers.TenantHeader: "city", "Content-Type": "text/csv"}
+
+		status, _, body := e.request(t, http.MethodPost, "/api/v1/servicepoint:import", "id,name,short_name,office_number\n1,Cash desk,C,1\n", city)
+		require.Equal(t, http.StatusConflict, status, body)
+		assert.Equal(t, "102", list(t)[0].OfficeNumber)
+	})
+}
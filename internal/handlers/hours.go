@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (m *SPHandler) SetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	log.Print("set working hours handler called")
+
+	m.setHours(w, r, "id", m.service.SetWorkingHours)
+}
+
+func (m *SPHandler) GetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	log.Print("get working hours handler called")
+
+	m.getHours(w, r, "id", m.service.GetWorkingHours)
+}
+
+func (m *SPHandler) DeleteWorkingHours(w http.ResponseWriter, r *http.Request) {
+	log.Print("delete working hours handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	if err := m.service.DeleteWorkingHours(ctx, id); err != nil {
+		writeError(w, err)
+		log.Printf("error deleting working hours: %s", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	log.Printf("204 no content - working hours of service point %s", id)
+}
+
+func (m *SPHandler) SetOfficeHours(w http.ResponseWriter, r *http.Request) {
+	log.Print("set office hours handler called")
+
+	m.setHours(w, r, "officeNumber", m.service.SetOfficeHours)
+}
+
+func (m *SPHandler) GetOfficeHours(w http.ResponseWriter, r *http.Request) {
+	log.Print("get office hours handler called")
+
+	m.getHours(w, r, "officeNumber", m.service.GetOfficeHours)
+}
+
+// setHours decodes working hours and stores them with set for the service
+// point or office named by the path variable key.
+func (m *SPHandler) setHours(w http.ResponseWriter, r *http.Request, key string, set func(context.Context, string, models.WorkingHoursRequest) (*models.WorkingHours, error)) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	owner := vars[key]
+
+	var req models.WorkingHoursRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "WorkingHoursRequest", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	hours, err := set(ctx, owner, req)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error setting working hours: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(hours); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - working hours of %s %s", hours.Source, owner)
+}
+
+// getHours writes the working hours get returns for the service point or
+// office named by the path variable key.
+func (m *SPHandler) getHours(w http.ResponseWriter, r *http.Request, key string, get func(context.Context, string) (*models.WorkingHours, error)) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	owner := vars[key]
+
+	hours, err := get(ctx, owner)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting working hours: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(hours); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %s working hours of %s", hours.Source, owner)
+}
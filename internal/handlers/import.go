@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// importColumns is the CSV header of an export, the columns an import may
+// have in any order. Each is a field of models.ImportRow.
+var importColumns = []string{
+	"id", "name", "short_name", "office_number", "office_id", "max_queue_length", "daily_ticket_quota",
+}
+
+// ImportSP creates and replaces service points from a CSV file or a JSON
+// array. The response reports every row; a transactional import that fails
+// answers with the status of its first failing row.
+func (m *SPHandler) ImportSP(w http.ResponseWriter, r *http.Request) {
+	log.Print("import service points handler called")
+
+	// An import writes up to a thousand service points, more than the
+	// other handlers' five seconds allow for.
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+
+	var dryRun bool
+	if value := query.Get("dryRun"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			var verr models.ValidationError
+			verr.Add("dryRun", "must be a boolean")
+			writeError(w, &verr)
+			return
+		}
+	}
+
+	var rows []models.ImportRow
+
+	defer r.Body.Close()
+	mediaType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";")
+	switch strings.TrimSpace(mediaType) {
+	case "application/json":
+		if err := decodeBody(r, "ImportRows", &rows); err != nil {
+			writeError(w, err)
+			return
+		}
+	case "text/csv":
+		var err error
+		if rows, err = decodeCSV(r); err != nil {
+			writeError(w, err)
+			return
+		}
+	default:
+		http.Error(w, "content type must be text/csv or application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	result, err := m.service.ImportSP(ctx, rows, query.Get("mode"), dryRun)
+	if result == nil {
+		writeError(w, err)
+		log.Printf("error importing service points: %s", err)
+		return
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		status = errorStatus(err)
+		log.Printf("error importing service points: %s", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("%d - imported %d created, %d updated, %d failed", status, result.Created, result.Updated, result.Failed)
+}
+
+// decodeCSV reads import rows from CSV whose header row names the columns.
+// Errors are reported with the paths decodeBody uses for a JSON array, [0]
+// being the first row after the header.
+func decodeCSV(r *http.Request) ([]models.ImportRow, error) {
+	var verr models.ValidationError
+
+	records, err := csv.NewReader(io.LimitReader(r.Body, maxBodySize)).ReadAll()
+	if err != nil {
+		verr.Add("", "malformed csv: %s", err)
+		return nil, &verr
+	}
+	if len(records) == 0 {
+		verr.Add("", "csv has no header row")
+		return nil, &verr
+	}
+
+	header := records[0]
+	// Spreadsheets often save CSV with a byte order mark.
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	for i, column := range header {
+		switch {
+		case !slices.Contains(importColumns, column):
+			verr.Add("", "unknown column %q", column)
+		case slices.Index(header, column) < i:
+			verr.Add("", "column %q is repeated", column)
+		}
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	rows := make([]models.ImportRow, len(records)-1)
+	for i, record := range records[1:] {
+		row := &rows[i]
+		for j, value := range record {
+			path := fmt.Sprintf("[%d].%s", i, header[j])
+			switch header[j] {
+			case "id":
+				row.ID = csvInt(&verr, path, value)
+			case "name":
+				row.Name = value
+			case "short_name":
+				row.ShortName = value
+			case "office_number":
+				row.OfficeNumber = value
+			case "office_id":
+				row.OfficeID = csvInt(&verr, path, value)
+			case "max_queue_length":
+				row.MaxQueueLength = int(csvInt(&verr, path, value))
+			case "daily_ticket_quota":
+				row.DailyTicketQuota = int(csvInt(&verr, path, value))
+			}
+		}
+	}
+	return rows, verr.Err()
+}
+
+// csvInt reads an integer cell; an empty cell is 0, like an omitted field.
+func csvInt(verr *models.ValidationError, path, value string) int64 {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		verr.Add(path, "must be an integer")
+	}
+	return n
+}
+
+// ExportSP writes the service points of the organisation as JSON import rows
+// or, with format=csv, as a CSV file that can be imported again.
+func (m *SPHandler) ExportSP(w http.ResponseWriter, r *http.Request) {
+	log.Print("export service points handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	asCSV, err := wantsCSV(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	rows, err := m.service.ExportSP(ctx)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error exporting service points: %s", err)
+		return
+	}
+
+	if asCSV {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="servicepoints.csv"`)
+		w.WriteHeader(http.StatusOK)
+		if err := writeImportCSV(w, rows); err != nil {
+			log.Printf("failed to encode response: %s", err)
+		}
+		log.Printf("200 ok - exported %d service points", len(rows))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(rows); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - exported %d service points", len(rows))
+}
+
+// writeImportCSV writes one row per service point under importColumns. Unset
+// numbers are left empty, as an import reads them.
+func writeImportCSV(w http.ResponseWriter, rows []models.ImportRow) error {
+	optional := func(n int64) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.FormatInt(n, 10)
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write(importColumns)
+
+	for _, row := range rows {
+		cw.Write([]string{
+			strconv.FormatInt(row.ID, 10),
+			row.Name,
+			row.ShortName,
+			row.OfficeNumber,
+			optional(row.OfficeID),
+			optional(int64(row.MaxQueueLength)),
+			optional(int64(row.DailyTicketQuota)),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
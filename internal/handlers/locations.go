@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (m *SPHandler) CreateBuilding(w http.ResponseWriter, r *http.Request) {
+	log.Print("create building handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var req models.NewBuildingRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "NewBuildingRequest", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	building, err := m.service.CreateBuilding(ctx, req)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error creating building: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("%s/buildings/%d", APIPrefix, building.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(building); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("201 created - building ID: %d", building.ID)
+}
+
+func (m *SPHandler) GetBuilding(w http.ResponseWriter, r *http.Request) {
+	log.Print("get building handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	building, err := m.service.GetBuilding(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting building: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(building); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - building ID: %d", building.ID)
+}
+
+func (m *SPHandler) ListBuildings(w http.ResponseWriter, r *http.Request) {
+	log.Print("list buildings handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	buildings, err := m.service.ListBuildings(ctx)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error listing buildings: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(buildings); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %d buildings", len(buildings))
+}
+
+func (m *SPHandler) CreateFloor(w http.ResponseWriter, r *http.Request) {
+	log.Print("create floor handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var req models.NewFloorRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "NewFloorRequest", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	floor, err := m.service.CreateFloor(ctx, id, req)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error creating floor: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("%s/floors/%d", APIPrefix, floor.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(floor); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("201 created - floor ID: %d", floor.ID)
+}
+
+func (m *SPHandler) GetFloor(w http.ResponseWriter, r *http.Request) {
+	log.Print("get floor handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	floor, err := m.service.GetFloor(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting floor: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(floor); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - floor ID: %d", floor.ID)
+}
+
+func (m *SPHandler) ListFloors(w http.ResponseWriter, r *http.Request) {
+	log.Print("list floors handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	floors, err := m.service.ListFloors(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error listing floors: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(floors); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - building ID: %s, %d floors", id, len(floors))
+}
+
+func (m *SPHandler) CreateOffice(w http.ResponseWriter, r *http.Request) {
+	log.Print("create office handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var req models.NewOfficeRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "NewOfficeRequest", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	office, err := m.service.CreateOffice(ctx, id, req)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error creating office: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("%s/offices/%d", APIPrefix, office.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(office); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("201 created - office ID: %d", office.ID)
+}
+
+func (m *SPHandler) ListOffices(w http.ResponseWriter, r *http.Request) {
+	log.Print("list offices handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	offices, err := m.service.ListOffices(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error listing offices: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(offices); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - floor ID: %s, %d offices", id, len(offices))
+}
+
+// GetLocation returns an office with the floor and building it is on.
+func (m *SPHandler) GetLocation(w http.ResponseWriter, r *http.Request) {
+	log.Print("get location handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	location, err := m.service.GetLocation(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting location: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(location); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - office ID: %d", location.Office.ID)
+}
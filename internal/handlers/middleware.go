@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/snnus/mainservice/internal/audit"
+	"github.com/snnus/mainservice/internal/tenant"
+)
+
+const (
+	// CallerHeader names who is making the request; it is recorded in the
+	// audit log as is.
+	CallerHeader = "X-Caller"
+	// RequestIDHeader correlates a request across services. One is generated
+	// when the client sends none, and it is echoed in the response.
+	RequestIDHeader = "X-Request-ID"
+	// TenantHeader names the organisation a request acts for when the host
+	// it was sent to belongs to none. A host that belongs to an organisation
+	// rejects a header naming another one.
+	TenantHeader = "X-Tenant"
+)
+
+// withAuditMeta puts the caller and request id of every request into its
+// context for the audit log.
+func withAuditMeta(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := audit.NewContext(r.Context(), audit.Meta{
+			Caller:    r.Header.Get(CallerHeader),
+			RequestID: requestID,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// withTenant puts the organisation every request acts for into its context
+// so that it only sees the data of that organisation.
+func (m *SPHandler) withTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := m.service.ResolveTenant(ctx, r.Header.Get(TenantHeader), r.Host)
+		if err != nil {
+			writeError(w, err)
+			log.Printf("error resolving tenant: %s", err)
+			return
+		}
+		ctx = tenant.NewContext(r.Context(), id)
+		if m.service.IsAdminHost(r.Host) {
+			ctx = tenant.NewAdminContext(ctx)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// maxBodySize caps request bodies before they are validated.
+const maxBodySize = 1 << 20
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	Format               string             `json:"format"`
+	Nullable             bool               `json:"nullable"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+
+	// pattern is Pattern compiled when the document is loaded.
+	pattern *regexp.Regexp
+}
+
+type openAPISpec struct {
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+var spec = mustLoadSpec()
+
+func mustLoadSpec() *openAPISpec {
+	var s openAPISpec
+	if err := json.Unmarshal(openAPIDocument, &s); err != nil {
+		panic(fmt.Sprintf("invalid embedded openapi document: %s", err))
+	}
+	for _, sc := range s.Components.Schemas {
+		compilePatterns(sc)
+	}
+	return &s
+}
+
+// compilePatterns compiles the patterns of a schema and the schemas nested
+// in it, so validation does not compile them on every request.
+func compilePatterns(s *schema) {
+	if s == nil {
+		return
+	}
+	if s.Pattern != "" {
+		s.pattern = regexp.MustCompile(s.Pattern)
+	}
+	for _, prop := range s.Properties {
+		compilePatterns(prop)
+	}
+	compilePatterns(s.Items)
+}
+
+// OpenAPI serves the OpenAPI document describing this API.
+func (m *SPHandler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(openAPIDocument); err != nil {
+		log.Printf("failed to write openapi document: %s", err)
+	}
+}
+
+// decodeBody validates the request body against the named schema from the
+// OpenAPI document and then decodes it into v. Every failure is reported as
+// a *models.ValidationError.
+func decodeBody(r *http.Request, schemaName string, v any) error {
+	var verr models.ValidationError
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		verr.Add("", "failed to read body: %s", err)
+		return &verr
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		verr.Add("", "malformed json: %s", err)
+		return &verr
+	}
+
+	validate(spec.Components.Schemas[schemaName], doc, "", &verr)
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// join builds the dotted path of a field nested in an object.
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func resolve(s *schema) *schema {
+	for s != nil && s.Ref != "" {
+		s = spec.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+func validate(s *schema, value any, path string, verr *models.ValidationError) {
+	s = resolve(s)
+	if s == nil || value == nil && s.Nullable {
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			verr.Add(path, "must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				verr.Add(join(path, name), "is required")
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					verr.Add(join(path, name), "unknown field")
+				}
+				continue
+			}
+			validate(prop, obj[name], join(path, name), verr)
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			verr.Add(path, "must be an array")
+			return
+		}
+		for i, item := range arr {
+			validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i), verr)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			verr.Add(path, "must be a string")
+			return
+		}
+		n := utf8.RuneCountInString(str)
+		if s.MinLength != nil && n < *s.MinLength {
+			verr.Add(path, "must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			verr.Add(path, "must be at most %d characters", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(str) {
+			verr.Add(path, "must match %s", s.Pattern)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				verr.Add(path, "must be an RFC 3339 date-time")
+			}
+		}
+	case "integer", "number":
+		num, ok := value.(float64)
+		if !ok {
+			verr.Add(path, "must be a number")
+			return
+		}
+		if s.Type == "integer" && num != float64(int64(num)) {
+			verr.Add(path, "must be an integer")
+		}
+		switch {
+		case s.Minimum != nil && num < *s.Minimum && *s.Minimum == 0:
+			verr.Add(path, "must not be negative")
+		case s.Minimum != nil && num < *s.Minimum:
+			verr.Add(path, "must be at least %g", *s.Minimum)
+		case s.Maximum != nil && num > *s.Maximum:
+			verr.Add(path, "must be at most %g", *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			verr.Add(path, "must be a boolean")
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSchemasMatchModels keeps the OpenAPI schemas in step with the json
+// tags of the models they describe.
+func TestSchemasMatchModels(t *testing.T) {
+	tests := []struct {
+		schema string
+		model  any
+	}{
+		{"NewServicePointRequest", models.NewServicePointRequest{}},
+		{"ServicePoint", models.ServicePoint{}},
+		{"ServicePointPatch", models.ServicePointPatch{}},
+		{"Ticket", models.Ticket{}},
+		{"ErrorResponse", errorResponse{}},
+		{"FieldError", models.FieldError{}},
+		{"AuditRecord", models.AuditRecord{}},
+		{"HistoryPage", models.HistoryPage{}},
+		{"TicketRecord", models.TicketRecord{}},
+		{"TicketTransfer", models.TicketTransfer{}},
+		{"StatusChange", models.StatusChange{}},
+		{"QueueStats", models.QueueStats{}},
+		{"HourCount", models.HourCount{}},
+		{"SlotScheduleRequest", models.SlotScheduleRequest{}},
+		{"SlotSchedule", models.SlotSchedule{}},
+		{"Slot", models.Slot{}},
+		{"NewAppointmentRequest", models.NewAppointmentRequest{}},
+		{"Appointment", models.Appointment{}},
+		{"DayHours", models.DayHours{}},
+		{"WeekHours", models.WeekHours{}},
+		{"Holiday", models.Holiday{}},
+		{"WorkingHoursRequest", models.WorkingHoursRequest{}},
+		{"WorkingHours", models.WorkingHours{}},
+		{"NewOperatorRequest", models.NewOperatorRequest{}},
+		{"Operator", models.Operator{}},
+		{"NewSessionRequest", models.NewSessionRequest{}},
+		{"Session", models.Session{}},
+		{"OperatorStats", models.OperatorStats{}},
+		{"NewCategoryRequest", models.NewCategoryRequest{}},
+		{"Category", models.Category{}},
+		{"CategoryAssignment", models.CategoryAssignment{}},
+		{"NewOrganisationRequest", models.NewOrganisationRequest{}},
+		{"Organisation", models.Organisation{}},
+		{"NewBuildingRequest", models.NewBuildingRequest{}},
+		{"Building", models.Building{}},
+		{"NewFloorRequest", models.NewFloorRequest{}},
+		{"Floor", models.Floor{}},
+		{"NewOfficeRequest", models.NewOfficeRequest{}},
+		{"Office", models.Office{}},
+		{"Location", models.Location{}},
+		{"ImportRow", models.ImportRow{}},
+		{"ImportResult", models.ImportResult{}},
+		{"ImportRowResult", models.ImportRowResult{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			s := spec.Components.Schemas[tt.schema]
+			require.NotNil(t, s)
+
+			var want []string
+			typ := reflect.TypeOf(tt.model)
+			for i := 0; i < typ.NumField(); i++ {
+				name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+				want = append(want, name)
+			}
+
+			var got []string
+			for name := range s.Properties {
+				got = append(got, name)
+			}
+
+			sort.Strings(want)
+			sort.Strings(got)
+			assert.Equal(t, want, got)
+		})
+	}
+}
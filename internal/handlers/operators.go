@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (m *SPHandler) CreateOperator(w http.ResponseWriter, r *http.Request) {
+	log.Print("create operator handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var req models.NewOperatorRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "NewOperatorRequest", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	operator, err := m.service.CreateOperator(ctx, req)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error creating operator: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("%s/operators/%d", APIPrefix, operator.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(operator); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("201 created - operator ID: %d", operator.ID)
+}
+
+func (m *SPHandler) GetOperator(w http.ResponseWriter, r *http.Request) {
+	log.Print("get operator handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	operator, err := m.service.GetOperator(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting operator: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(operator); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - operator ID: %d", operator.ID)
+}
+
+func (m *SPHandler) ListOperators(w http.ResponseWriter, r *http.Request) {
+	log.Print("list operators handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	operators, err := m.service.ListOperators(ctx)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error listing operators: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(operators); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %d operators", len(operators))
+}
+
+func (m *SPHandler) StartSession(w http.ResponseWriter, r *http.Request) {
+	log.Print("start session handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var req models.NewSessionRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "NewSessionRequest", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	session, err := m.service.StartSession(ctx, id, req)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error starting session: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("%s/servicepoint/%s/session", APIPrefix, id))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(session); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("201 created - operator %d at desk %s", session.OperatorID, session.DeskNumber)
+}
+
+func (m *SPHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	log.Print("get session handler called")
+
+	m.serveSession(w, r, m.service.GetSession, "getting")
+}
+
+func (m *SPHandler) EndSession(w http.ResponseWriter, r *http.Request) {
+	log.Print("end session handler called")
+
+	m.serveSession(w, r, m.service.EndSession, "ending")
+}
+
+// serveSession writes the session op returns for the service point in the
+// path.
+func (m *SPHandler) serveSession(w http.ResponseWriter, r *http.Request, op func(context.Context, string) (*models.Session, error), verb string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	session, err := op(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error %s session: %s", verb, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(session); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - session %d of operator %d", session.ID, session.OperatorID)
+}
+
+func (m *SPHandler) OperatorStats(w http.ResponseWriter, r *http.Request) {
+	log.Print("operator stats handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	from, to, err := dateRange(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	stats, err := m.service.OperatorStats(ctx, from, to)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error computing operator stats: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %d operator stats rows", len(stats))
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (m *SPHandler) CreateOrganisation(w http.ResponseWriter, r *http.Request) {
+	log.Print("create organisation handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var req models.NewOrganisationRequest
+
+	defer r.Body.Close()
+	if err := decodeBody(r, "NewOrganisationRequest", &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	organisation, err := m.service.CreateOrganisation(ctx, req)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error creating organisation: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("%s/organisations/%s", APIPrefix, organisation.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(organisation); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("201 created - organisation ID: %s", organisation.ID)
+}
+
+func (m *SPHandler) GetOrganisation(w http.ResponseWriter, r *http.Request) {
+	log.Print("get organisation handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	organisation, err := m.service.GetOrganisation(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error getting organisation: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(organisation); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - organisation ID: %s", organisation.ID)
+}
+
+func (m *SPHandler) ListOrganisations(w http.ResponseWriter, r *http.Request) {
+	log.Print("list organisations handler called")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	organisations, err := m.service.ListOrganisations(ctx)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error listing organisations: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(organisations); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %d organisations", len(organisations))
+}
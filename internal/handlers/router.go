@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"github.com/gorilla/mux"
+)
+
+// APIPrefix is the path every versioned route is served under.
+const APIPrefix = "/api/v1"
+
+// ticketCode matches the ticket codes of any short name checkShortName
+// accepts, which may be in a non-Latin script.
+const ticketCode = `{code:[\p{L}\p{Nd}]+}`
+
+func NewRouter(spHandler *SPHandler) *mux.Router {
+	r := mux.NewRouter()
+	r.Use(withAuditMeta)
+	r.Use(spHandler.withTenant)
+
+	// Routes are registered with the full path rather than on a PathPrefix
+	// subrouter so that a wrong method still answers 405 instead of 404.
+	r.HandleFunc(APIPrefix+"/openapi.json", spHandler.OpenAPI).Methods("GET")
+	r.HandleFunc(APIPrefix+"/organisations", spHandler.ListOrganisations).Methods("GET")
+	r.HandleFunc(APIPrefix+"/organisations", spHandler.CreateOrganisation).Methods("POST")
+	r.HandleFunc(APIPrefix+"/organisations/{id:[a-z0-9-]+}", spHandler.GetOrganisation).Methods("GET")
+	r.HandleFunc(APIPrefix+"/servicepoint", spHandler.ListSP).Methods("GET")
+	r.HandleFunc(APIPrefix+"/servicepoint", spHandler.CreateSP).Methods("POST")
+	r.HandleFunc(APIPrefix+"/servicepoint:import", spHandler.ImportSP).Methods("POST")
+	r.HandleFunc(APIPrefix+"/servicepoint:export", spHandler.ExportSP).Methods("GET")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}", spHandler.UpsertSP).Methods("PUT", "POST")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}", spHandler.PatchSP).Methods("PATCH")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}", spHandler.GetSP).Methods("GET")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}", spHandler.DeleteSP).Methods("DELETE")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/history", spHandler.GetSPHistory).Methods("GET")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/tickets", spHandler.ListTickets).Methods("GET")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/restore", spHandler.RestoreSP).Methods("POST")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/pause", spHandler.PauseSP).Methods("POST")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/resume", spHandler.ResumeSP).Methods("POST")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/close", spHandler.CloseSP).Methods("POST")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/session", spHandler.StartSession).Methods("POST")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/session", spHandler.GetSession).Methods("GET")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/session", spHandler.EndSession).Methods("DELETE")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/categories", spHandler.SetSPCategories).Methods("PUT")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/categories", spHandler.GetSPCategories).Methods("GET")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/schedule", spHandler.SetWorkingHours).Methods("PUT")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/schedule", spHandler.GetWorkingHours).Methods("GET")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/schedule", spHandler.DeleteWorkingHours).Methods("DELETE")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/slots", spHandler.ListSlots).Methods("GET")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/slots/schedule", spHandler.SetSlotSchedule).Methods("PUT")
+	r.HandleFunc(APIPrefix+"/servicepoint/{id:[0-9]+}/slots/schedule", spHandler.GetSlotSchedule).Methods("GET")
+	r.HandleFunc(APIPrefix+"/office/{officeNumber}/schedule", spHandler.SetOfficeHours).Methods("PUT")
+	r.HandleFunc(APIPrefix+"/office/{officeNumber}/schedule", spHandler.GetOfficeHours).Methods("GET")
+	r.HandleFunc(APIPrefix+"/operators", spHandler.ListOperators).Methods("GET")
+	r.HandleFunc(APIPrefix+"/operators", spHandler.CreateOperator).Methods("POST")
+	r.HandleFunc(APIPrefix+"/operators/{id:[0-9]+}", spHandler.GetOperator).Methods("GET")
+	r.HandleFunc(APIPrefix+"/categories", spHandler.ListCategories).Methods("GET")
+	r.HandleFunc(APIPrefix+"/categories", spHandler.CreateCategory).Methods("POST")
+	r.HandleFunc(APIPrefix+"/categories/{id:[0-9]+}", spHandler.GetCategory).Methods("GET")
+	r.HandleFunc(APIPrefix+"/buildings", spHandler.ListBuildings).Methods("GET")
+	r.HandleFunc(APIPrefix+"/buildings", spHandler.CreateBuilding).Methods("POST")
+	r.HandleFunc(APIPrefix+"/buildings/{id:[0-9]+}", spHandler.GetBuilding).Methods("GET")
+	r.HandleFunc(APIPrefix+"/buildings/{id:[0-9]+}/floors", spHandler.ListFloors).Methods("GET")
+	r.HandleFunc(APIPrefix+"/buildings/{id:[0-9]+}/floors", spHandler.CreateFloor).Methods("POST")
+	r.HandleFunc(APIPrefix+"/floors/{id:[0-9]+}", spHandler.GetFloor).Methods("GET")
+	r.HandleFunc(APIPrefix+"/floors/{id:[0-9]+}/offices", spHandler.ListOffices).Methods("GET")
+	r.HandleFunc(APIPrefix+"/floors/{id:[0-9]+}/offices", spHandler.CreateOffice).Methods("POST")
+	r.HandleFunc(APIPrefix+"/offices/{id:[0-9]+}", spHandler.GetLocation).Methods("GET")
+	r.HandleFunc(APIPrefix+"/appointments", spHandler.BookAppointment).Methods("POST")
+	r.HandleFunc(APIPrefix+"/appointments/{id:[0-9]+}", spHandler.GetAppointment).Methods("GET")
+	r.HandleFunc(APIPrefix+"/appointments/{id:[0-9]+}/cancel", spHandler.CancelAppointment).Methods("POST")
+	r.HandleFunc(APIPrefix+"/appointments/{id:[0-9]+}/checkin", spHandler.CheckIn).Methods("POST")
+	r.HandleFunc(APIPrefix+"/ticket/"+ticketCode, spHandler.TicketStatus).Methods("GET")
+	r.HandleFunc(APIPrefix+"/ticket/"+ticketCode+"/transfer", spHandler.TransferTicket).Methods("POST")
+	r.HandleFunc(APIPrefix+"/ticket/{id:[0-9]+}/"+ticketCode, spHandler.ServicePointTicketStatus).Methods("GET")
+	r.HandleFunc(APIPrefix+"/ticket/{id:[0-9]+}/"+ticketCode+"/cancel", spHandler.CancelTicket).Methods("POST")
+	r.HandleFunc(APIPrefix+"/ticket/category/{id:[0-9]+}/"+ticketCode+"/cancel", spHandler.CancelCategoryTicket).Methods("POST")
+	r.HandleFunc(APIPrefix+"/stats/servicepoint/{id:[0-9]+}", spHandler.ServicePointStats).Methods("GET")
+	r.HandleFunc(APIPrefix+"/stats/offices", spHandler.OfficeStats).Methods("GET")
+	r.HandleFunc(APIPrefix+"/stats/operators", spHandler.OperatorStats).Methods("GET")
+	r.HandleFunc(APIPrefix+"/enqueue/{id:[0-9]+}", spHandler.Enqueue).Methods("POST")
+	r.HandleFunc(APIPrefix+"/enqueue/category/{id:[0-9]+}", spHandler.EnqueueCategory).Methods("POST")
+	r.HandleFunc(APIPrefix+"/dequeue/{id:[0-9]+}", spHandler.Dequeue).Methods("POST")
+	r.HandleFunc(APIPrefix+"/recall/{id:[0-9]+}", spHandler.Recall).Methods("POST")
+	r.HandleFunc(APIPrefix+"/skip/{id:[0-9]+}", spHandler.Skip).Methods("POST")
+
+	return r
+}
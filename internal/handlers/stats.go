@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/snnus/mainservice/internal/models"
+)
+
+const dateLayout = "2006-01-02"
+
+// dateRange reads the from and to query parameters, both inclusive UTC
+// dates defaulting to today, and returns them as the half-open interval of
+// times they cover.
+func dateRange(r *http.Request) (time.Time, time.Time, error) {
+	var verr models.ValidationError
+	query := r.URL.Query()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to := today, today
+
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"from", &from},
+		{"to", &to},
+	} {
+		value := query.Get(p.name)
+		if value == "" {
+			continue
+		}
+		d, err := time.Parse(dateLayout, value)
+		if err != nil {
+			verr.Add(p.name, "must be a date formatted as YYYY-MM-DD")
+			continue
+		}
+		*p.dst = d
+	}
+	if len(verr.Fields) == 0 && to.Before(from) {
+		verr.Add("to", "must not be before from")
+	}
+
+	return from, to.AddDate(0, 0, 1), verr.Err()
+}
+
+// wantsCSV reports whether the client asked for CSV with format=csv.
+func wantsCSV(r *http.Request) (bool, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		return false, nil
+	case "csv":
+		return true, nil
+	default:
+		var verr models.ValidationError
+		verr.Add("format", "must be json or csv")
+		return false, &verr
+	}
+}
+
+func (m *SPHandler) ServicePointStats(w http.ResponseWriter, r *http.Request) {
+	log.Print("service point stats handler called")
+
+	m.serveStats(w, r, func(ctx context.Context, from, to time.Time) ([]models.QueueStats, error) {
+		stats, err := m.service.ServicePointStats(ctx, mux.Vars(r)["id"], from, to)
+		if err != nil {
+			return nil, err
+		}
+		return []models.QueueStats{*stats}, nil
+	}, false)
+}
+
+func (m *SPHandler) OfficeStats(w http.ResponseWriter, r *http.Request) {
+	log.Print("office stats handler called")
+
+	m.serveStats(w, r, m.service.OfficeStats, true)
+}
+
+// serveStats writes the stats get returns for the requested date range as
+// JSON, a single object unless list is set, or as CSV.
+func (m *SPHandler) serveStats(w http.ResponseWriter, r *http.Request, get func(context.Context, time.Time, time.Time) ([]models.QueueStats, error), list bool) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	from, to, err := dateRange(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	asCSV, err := wantsCSV(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	stats, err := get(ctx, from, to)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error computing stats: %s", err)
+		return
+	}
+
+	if asCSV {
+		w.Header().Set("Content-Type", "text/csv")
+		w.WriteHeader(http.StatusOK)
+		if err := writeStatsCSV(w, stats); err != nil {
+			log.Printf("failed to encode response: %s", err)
+		}
+		log.Printf("200 ok - %d stats rows", len(stats))
+		return
+	}
+
+	var body any = stats
+	if !list {
+		body = stats[0]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - %d stats rows", len(stats))
+}
+
+// writeStatsCSV writes one row per stats entry. to is written as the last
+// day included, like the query parameter, and peak hours as hour:issued
+// pairs separated by spaces.
+func writeStatsCSV(w http.ResponseWriter, stats []models.QueueStats) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"office_number", "service_point_id", "from", "to",
+		"issued", "served", "avg_wait_seconds", "p90_wait_seconds", "peak_hours",
+	})
+
+	for _, s := range stats {
+		var spID string
+		if s.ServicePointID != 0 {
+			spID = strconv.FormatInt(s.ServicePointID, 10)
+		}
+		peaks := make([]string, 0, len(s.PeakHours))
+		for _, p := range s.PeakHours {
+			peaks = append(peaks, fmt.Sprintf("%d:%d", p.Hour, p.Issued))
+		}
+		cw.Write([]string{
+			s.OfficeNumber,
+			spID,
+			s.From.Format(dateLayout),
+			s.To.AddDate(0, 0, -1).Format(dateLayout),
+			strconv.Itoa(s.Issued),
+			strconv.Itoa(s.Served),
+			strconv.FormatFloat(s.AvgWaitSeconds, 'f', 1, 64),
+			strconv.FormatFloat(s.P90WaitSeconds, 'f', 1, 64),
+			strings.Join(peaks, " "),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
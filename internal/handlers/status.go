@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (m *SPHandler) PauseSP(w http.ResponseWriter, r *http.Request) {
+	log.Print("pause service point handler called")
+
+	m.serveStatus(w, r, m.service.PauseSP, "pausing")
+}
+
+func (m *SPHandler) ResumeSP(w http.ResponseWriter, r *http.Request) {
+	log.Print("resume service point handler called")
+
+	m.serveStatus(w, r, func(ctx context.Context, id string, _ models.StatusChange) (*models.ServicePoint, error) {
+		return m.service.ResumeSP(ctx, id)
+	}, "resuming")
+}
+
+func (m *SPHandler) CloseSP(w http.ResponseWriter, r *http.Request) {
+	log.Print("close service point handler called")
+
+	m.serveStatus(w, r, m.service.CloseSP, "closing")
+}
+
+// serveStatus applies the status change op makes to the service point in the
+// path. The StatusChange body is optional.
+func (m *SPHandler) serveStatus(w http.ResponseWriter, r *http.Request, op func(context.Context, string, models.StatusChange) (*models.ServicePoint, error), verb string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var change models.StatusChange
+
+	defer r.Body.Close()
+	if r.ContentLength != 0 {
+		if err := decodeBody(r, "StatusChange", &change); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	sp, err := op(ctx, id, change)
+	if err != nil {
+		writeError(w, err)
+		log.Printf("error %s service point: %s", verb, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(sp.Version))
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(sp); err != nil {
+		log.Printf("failed to encode response: %s", err)
+	}
+
+	log.Printf("200 ok - service point ID: %d is %s", sp.ID, sp.Status)
+}
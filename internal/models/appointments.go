@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// SlotScheduleRequest sets how a service point takes appointments: the day
+// is cut into slots of SlotMinutes from midnight UTC, each taking up to
+// Capacity bookings.
+type SlotScheduleRequest struct {
+	SlotMinutes int `json:"slotMinutes"`
+	Capacity    int `json:"capacity"`
+}
+
+type SlotSchedule struct {
+	ServicePointID int64     `json:"servicePointId"`
+	SlotMinutes    int       `json:"slotMinutes"`
+	Capacity       int       `json:"capacity"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// Slot is one bookable interval of a service point and how much of it is
+// taken.
+type Slot struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Capacity int       `json:"capacity"`
+	Booked   int       `json:"booked"`
+}
+
+type NewAppointmentRequest struct {
+	ServicePointID int64     `json:"servicePointId"`
+	SlotStart      time.Time `json:"slotStart"`
+	Name           string    `json:"name"`
+}
+
+// An appointment is booked until the visitor checks in, cancels it or lets
+// its slot pass, which expires it. Booked and checked-in appointments take up
+// their slot.
+const (
+	AppointmentStatusBooked    = "booked"
+	AppointmentStatusCheckedIn = "checked-in"
+	AppointmentStatusCancelled = "cancelled"
+	AppointmentStatusExpired   = "expired"
+)
+
+// Appointment is a booked slot. Ticket is the code issued at check-in.
+type Appointment struct {
+	ID             int64     `json:"id"`
+	ServicePointID int64     `json:"servicePointId"`
+	SlotStart      time.Time `json:"slotStart"`
+	SlotEnd        time.Time `json:"slotEnd"`
+	Name           string    `json:"name"`
+	Status         string    `json:"status"`
+	Ticket         string    `json:"ticket,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Category is a kind of request that several service points serve. Tickets
+// issued for a category wait in one shared line and are called by whichever
+// of its service points gets to them first.
+type Category struct {
+	ID              int64     `json:"id"`
+	Name            string    `json:"name"`
+	ShortName       string    `json:"shortName"`
+	ServicePointIDs []int64   `json:"servicePointIds"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+type NewCategoryRequest struct {
+	Name      string `json:"name"`
+	ShortName string `json:"shortName"`
+}
+
+// CategoryAssignment replaces the categories a service point serves.
+type CategoryAssignment struct {
+	CategoryIDs []int64 `json:"categoryIds"`
+}
+
+// CategoryTicket is a ticket waiting in the line of a category. Once a
+// service point calls it, it continues as a ticket of that service point and
+// ServicePointID is set.
+type CategoryTicket struct {
+	ID             int64      `json:"id"`
+	CategoryID     int64      `json:"categoryId"`
+	Code           string     `json:"code"`
+	Status         string     `json:"status"`
+	IssuedAt       time.Time  `json:"issuedAt"`
+	CalledAt       *time.Time `json:"calledAt,omitempty"`
+	ServicePointID int64      `json:"servicePointId,omitempty"`
+}
+
+// CategoryTicketFilter narrows a category ticket listing. Zero fields match
+// everything.
+type CategoryTicketFilter struct {
+	CategoryIDs []int64
+	Code        string
+	Status      string
+}
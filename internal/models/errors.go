@@ -0,0 +1,86 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrNotFound        = errors.New("service point not found")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrVersionMismatch = errors.New("service point version mismatch")
+	ErrAlreadyExists   = errors.New("service point already exists")
+	ErrDeleted         = errors.New("service point is deleted")
+	ErrTicketNotFound  = errors.New("ticket not found")
+	ErrAmbiguousTicket = errors.New("ticket code was issued by several service points")
+
+	ErrScheduleNotFound    = errors.New("slot schedule not found")
+	ErrAppointmentNotFound = errors.New("appointment not found")
+	ErrSlotFull            = errors.New("slot is fully booked")
+	ErrAppointmentState    = errors.New("appointment is not booked")
+
+	ErrHoursNotFound = errors.New("working hours not found")
+	ErrClosed        = errors.New("service point is closed")
+	ErrPaused        = errors.New("service point is paused")
+
+	ErrQueueFull    = errors.New("queue is full")
+	ErrQuotaReached = errors.New("daily ticket quota is reached")
+
+	ErrOperatorNotFound = errors.New("operator not found")
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrSessionActive    = errors.New("another operator is logged in to the service point")
+
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrNotServed        = errors.New("no open service point serves the category")
+
+	ErrOrganisationNotFound = errors.New("organisation not found")
+	ErrOrganisationExists   = errors.New("organisation already exists")
+	ErrNotAdmin             = errors.New("only administrators may manage organisations")
+	ErrTenantMismatch       = errors.New("organisation does not match the host")
+
+	ErrBuildingNotFound = errors.New("building not found")
+	ErrFloorNotFound    = errors.New("floor not found")
+	ErrFloorExists      = errors.New("building already has a floor at that level")
+	ErrOfficeNotFound   = errors.New("office not found")
+	ErrOfficeExists     = errors.New("floor already has an office with that number")
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every invalid field of a request so callers can
+// fix them all at once. It matches ErrInvalidArgument with errors.Is.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Add(field, format string, args ...any) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns e if any field was added and nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		if f.Field == "" {
+			msgs = append(msgs, f.Message)
+			continue
+		}
+		msgs = append(msgs, f.Field+": "+f.Message)
+	}
+	return ErrInvalidArgument.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidArgument
+}
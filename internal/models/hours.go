@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// DayHours are the opening hours of one day as HH:MM local times. The
+// break, if set, lies within them and takes no tickets.
+type DayHours struct {
+	Open       string `json:"open"`
+	Close      string `json:"close"`
+	BreakStart string `json:"breakStart,omitempty"`
+	BreakEnd   string `json:"breakEnd,omitempty"`
+}
+
+// WeekHours are the regular opening hours per weekday. A day without hours
+// is closed.
+type WeekHours struct {
+	Monday    *DayHours `json:"monday,omitempty"`
+	Tuesday   *DayHours `json:"tuesday,omitempty"`
+	Wednesday *DayHours `json:"wednesday,omitempty"`
+	Thursday  *DayHours `json:"thursday,omitempty"`
+	Friday    *DayHours `json:"friday,omitempty"`
+	Saturday  *DayHours `json:"saturday,omitempty"`
+	Sunday    *DayHours `json:"sunday,omitempty"`
+}
+
+// Day returns the hours of a weekday, nil if it is closed.
+func (w WeekHours) Day(d time.Weekday) *DayHours {
+	return [...]*DayHours{w.Sunday, w.Monday, w.Tuesday, w.Wednesday, w.Thursday, w.Friday, w.Saturday}[d]
+}
+
+// Holiday replaces the weekly hours on one date, formatted as YYYY-MM-DD.
+// It is closed all day unless Hours is set.
+type Holiday struct {
+	Date  string    `json:"date"`
+	Name  string    `json:"name,omitempty"`
+	Hours *DayHours `json:"hours,omitempty"`
+}
+
+// WorkingHoursRequest sets when tickets are issued. Times are in Timezone,
+// UTC if empty. No tickets are issued in the last StopBeforeCloseMinutes
+// before closing so that the queue empties by then.
+type WorkingHoursRequest struct {
+	Timezone               string    `json:"timezone,omitempty"`
+	Week                   WeekHours `json:"week"`
+	Holidays               []Holiday `json:"holidays,omitempty"`
+	StopBeforeCloseMinutes int       `json:"stopBeforeCloseMinutes,omitempty"`
+}
+
+// Working hours are set per office and may be overridden per service point.
+const (
+	WorkingHoursSourceServicePoint = "servicepoint"
+	WorkingHoursSourceOffice       = "office"
+)
+
+// WorkingHours are the stored hours of a service point or of an office,
+// with Source telling which.
+type WorkingHours struct {
+	Source                 string    `json:"source"`
+	ServicePointID         int64     `json:"servicePointId,omitempty"`
+	OfficeNumber           string    `json:"officeNumber,omitempty"`
+	Timezone               string    `json:"timezone"`
+	Week                   WeekHours `json:"week"`
+	Holidays               []Holiday `json:"holidays,omitempty"`
+	StopBeforeCloseMinutes int       `json:"stopBeforeCloseMinutes"`
+	UpdatedAt              time.Time `json:"updatedAt"`
+}
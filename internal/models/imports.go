@@ -0,0 +1,67 @@
+package models
+
+import "fmt"
+
+// Import modes. A transactional import writes every row or none of them; a
+// best-effort import writes the rows that are valid and reports the others.
+const (
+	ImportModeTransactional = "transactional"
+	ImportModeBestEffort    = "best-effort"
+)
+
+// ImportRow is a service point in an import or export file. A row without an
+// ID creates a service point under a server-allocated id; one with an ID
+// creates or replaces the service point with that id.
+type ImportRow struct {
+	ID               int64  `json:"id,omitempty"`
+	Name             string `json:"name"`
+	ShortName        string `json:"shortName"`
+	OfficeNumber     string `json:"officeNumber"`
+	OfficeID         int64  `json:"officeId,omitempty"`
+	MaxQueueLength   int    `json:"maxQueueLength,omitempty"`
+	DailyTicketQuota int    `json:"dailyTicketQuota,omitempty"`
+}
+
+// ImportResult reports an import row by row. Rows are numbered from 1 in
+// the order of the file. A dry run reports what would be written without
+// writing it.
+type ImportResult struct {
+	DryRun  bool              `json:"dryRun"`
+	Mode    string            `json:"mode"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// ImportRowResult is the outcome of one row: the service point it created or
+// updated, or why it was rejected.
+type ImportRowResult struct {
+	Row    int          `json:"row"`
+	ID     int64        `json:"id,omitempty"`
+	Action string       `json:"action,omitempty"`
+	Error  string       `json:"error,omitempty"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// ServicePointWrite is one write of a batch passed to storage: a create of a
+// new service point or a replace of any existing one.
+type ServicePointWrite struct {
+	ID           string
+	Create       bool
+	ServicePoint NewServicePointRequest
+}
+
+// RowError is the failure of one write of a batch, numbered from 1.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
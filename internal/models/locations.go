@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Building is a site of the organisation. Directions, here and on floors and
+// offices, is a wayfinding hint for visitors such as "Entrance from the
+// courtyard".
+type Building struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Address    string    `json:"address,omitempty"`
+	Directions string    `json:"directions,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type NewBuildingRequest struct {
+	Name       string `json:"name"`
+	Address    string `json:"address,omitempty"`
+	Directions string `json:"directions,omitempty"`
+}
+
+// Floor is a storey of a building. Level orders the floors of a building and
+// is what lifts show; 0 is the ground floor.
+type Floor struct {
+	ID         int64     `json:"id"`
+	BuildingID int64     `json:"buildingId"`
+	Name       string    `json:"name"`
+	Level      int       `json:"level"`
+	Directions string    `json:"directions,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type NewFloorRequest struct {
+	Name       string `json:"name"`
+	Level      int    `json:"level"`
+	Directions string `json:"directions,omitempty"`
+}
+
+// Office is a room on a floor. Service points in it take its Number as their
+// office number.
+type Office struct {
+	ID         int64     `json:"id"`
+	FloorID    int64     `json:"floorId"`
+	Number     string    `json:"number"`
+	Name       string    `json:"name,omitempty"`
+	Directions string    `json:"directions,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type NewOfficeRequest struct {
+	Number     string `json:"number"`
+	Name       string `json:"name,omitempty"`
+	Directions string `json:"directions,omitempty"`
+}
+
+// Location is an office together with the floor and building it is on, as
+// display boards and announcements direct visitors to it.
+type Location struct {
+	Office   Office   `json:"office"`
+	Floor    Floor    `json:"floor"`
+	Building Building `json:"building"`
+}
@@ -1,23 +1,260 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
+// NewServicePointRequest is a service point as clients write it.
+// MaxQueueLength caps the tickets waiting at once and DailyTicketQuota the
+// tickets issued per day; zero means no limit. OfficeID places the service
+// point in an office, whose number then becomes its OfficeNumber.
 type NewServicePointRequest struct {
-	Name         string `json:"name"`
-	ShortName    string `json:"shortName"`
-	OfficeNumber string `json:"officeNumber"`
+	Name             string `json:"name"`
+	ShortName        string `json:"shortName"`
+	OfficeNumber     string `json:"officeNumber"`
+	OfficeID         int64  `json:"officeId,omitempty"`
+	MaxQueueLength   int    `json:"maxQueueLength,omitempty"`
+	DailyTicketQuota int    `json:"dailyTicketQuota,omitempty"`
 }
 
+// ServicePoint is a stored service point. Status is one of the
+// ServicePointStatus constants; StatusReason and ReturnAt explain a pause or
+// closure to visitors.
 type ServicePoint struct {
-	ID           int64     `json:"id"`
-	Name         string    `json:"name"`
-	ShortName    string    `json:"shortName"`
-	OfficeNumber string    `json:"officeNumber"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
-	ShardID      int       `json:"shard_id"`
+	ID               int64      `json:"id"`
+	Name             string     `json:"name"`
+	ShortName        string     `json:"shortName"`
+	OfficeNumber     string     `json:"officeNumber"`
+	OfficeID         int64      `json:"officeId,omitempty"`
+	MaxQueueLength   int        `json:"maxQueueLength"`
+	DailyTicketQuota int        `json:"dailyTicketQuota"`
+	Status           string     `json:"status"`
+	StatusReason     string     `json:"statusReason,omitempty"`
+	ReturnAt         *time.Time `json:"returnAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	ShardID          int        `json:"shard_id"`
+	Version          int64      `json:"version"`
+	// DeletedAt is set once the service point is soft-deleted.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
+// A service point is open unless an operator pauses or closes it. A paused
+// service point neither issues nor calls tickets until it resumes; a closed
+// one issues none but still calls the tickets already waiting.
+const (
+	ServicePointStatusOpen   = "open"
+	ServicePointStatusPaused = "paused"
+	ServicePointStatusClosed = "closed"
+)
+
+// StatusChange is the reason for pausing or closing a service point and, for
+// a pause, when it is expected to resume.
+type StatusChange struct {
+	Reason   string     `json:"reason,omitempty"`
+	ReturnAt *time.Time `json:"returnAt,omitempty"`
+}
+
+// ServicePointPatch is a JSON Merge Patch of a service point. Nil fields are
+// left unchanged; an OfficeID of zero takes the service point out of its
+// office.
+type ServicePointPatch struct {
+	Name             *string `json:"name,omitempty"`
+	ShortName        *string `json:"shortName,omitempty"`
+	OfficeNumber     *string `json:"officeNumber,omitempty"`
+	OfficeID         *int64  `json:"officeId,omitempty"`
+	MaxQueueLength   *int    `json:"maxQueueLength,omitempty"`
+	DailyTicketQuota *int    `json:"dailyTicketQuota,omitempty"`
+}
+
+// UnmarshalJSON reads null for an optional field as a reset to zero, the
+// way a merge patch removes a member: "officeId": null takes the service
+// point out of its office.
+func (p *ServicePointPatch) UnmarshalJSON(data []byte) error {
+	type patch ServicePointPatch
+	if err := json.Unmarshal(data, (*patch)(p)); err != nil {
+		return err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	if string(members["officeId"]) == "null" {
+		p.OfficeID = new(int64)
+	}
+	if string(members["maxQueueLength"]) == "null" {
+		p.MaxQueueLength = new(int)
+	}
+	if string(members["dailyTicketQuota"]) == "null" {
+		p.DailyTicketQuota = new(int)
+	}
+	return nil
+}
+
+// Ticket is what visitors are handed. Position and EstimatedWaitSeconds are
+// only known for recorded tickets still waiting. Position is 1-based and
+// counts the ticket itself: the ticket called next is at 1 and has nobody
+// ahead. The estimate is Position service times, the last one being the wait
+// for the visitor at the desk; it is missing until the service point has
+// called enough tickets to measure its pace.
+// OfficeNumber is set once the ticket is called, and DeskNumber if an
+// operator called it. CategoryID is set for tickets issued for a category.
 type Ticket struct {
-	Ticket string `json:"ticket"`
+	Ticket               string `json:"ticket"`
+	ServicePointID       int64  `json:"servicePointId,omitempty"`
+	Status               string `json:"status,omitempty"`
+	Position             int    `json:"position,omitempty"`
+	EstimatedWaitSeconds *int64 `json:"estimatedWaitSeconds,omitempty"`
+	OfficeNumber         string `json:"officeNumber,omitempty"`
+	DeskNumber           string `json:"deskNumber,omitempty"`
+	Class                string `json:"class,omitempty"`
+	CategoryID           int64  `json:"categoryId,omitempty"`
+	// ServicePointStatus, StatusReason and ReturnAt tell a waiting visitor
+	// that their service point is paused or closed.
+	ServicePointStatus string     `json:"servicePointStatus,omitempty"`
+	StatusReason       string     `json:"statusReason,omitempty"`
+	ReturnAt           *time.Time `json:"returnAt,omitempty"`
+}
+
+// A ticket waits until it is called and is served once its service point
+// calls the next one. No-show and cancelled close a ticket without serving it;
+// a transferred ticket continues as a new waiting ticket at another service
+// point.
+const (
+	TicketStatusWaiting     = "waiting"
+	TicketStatusCalled      = "called"
+	TicketStatusServed      = "served"
+	TicketStatusNoShow      = "no-show"
+	TicketStatusCancelled   = "cancelled"
+	TicketStatusTransferred = "transferred"
+)
+
+// Ticket classes, in order of precedence. Each class is a separate line of a
+// service point; regular is the line tickets join by default.
+const (
+	TicketClassAppointment = "appointment"
+	TicketClassPriority    = "priority"
+	TicketClassRegular     = "regular"
+)
+
+// TicketClasses lists every ticket class, highest precedence first.
+var TicketClasses = []string{TicketClassAppointment, TicketClassPriority, TicketClassRegular}
+
+// Ticket events published to Kafka, one per change a display should show.
+const (
+	TicketEventCalled      = "called"
+	TicketEventRecalled    = "recalled"
+	TicketEventNoShow      = "no-show"
+	TicketEventSkipped     = "skipped"
+	TicketEventCancelled   = "cancelled"
+	TicketEventTransferred = "transferred"
+
+	// TicketEventQueueFull and TicketEventQuotaReached are published with
+	// the ticket that filled the queue or used up the daily quota.
+	TicketEventQueueFull    = "queue-full"
+	TicketEventQuotaReached = "quota-reached"
+)
+
+// ServicePointEventStatus is published whenever a service point is paused,
+// resumed or closed.
+const ServicePointEventStatus = "status-changed"
+
+// TicketTransfer sends a called ticket to the queue of another service point,
+// at the back unless Position is TransferFront. FromServicePointID names the
+// service point that called it, which is needed when several service points
+// share its code.
+type TicketTransfer struct {
+	ServicePointID     int64  `json:"servicePointId"`
+	FromServicePointID int64  `json:"fromServicePointId,omitempty"`
+	Position           string `json:"position,omitempty"`
+}
+
+const (
+	TransferFront = "front"
+	TransferBack  = "back"
+)
+
+// TicketRecord is a ticket as issued and called through mainservice. Codes
+// are reused by the queue engine, so a code only identifies a ticket together
+// with its service point and issue time. QueuedAt orders the waiting tickets
+// of a service point; it is the issue time unless the ticket was transferred
+// to the front of the queue from TransferredFrom. OperatorID and DeskNumber
+// are set if an operator was logged in when the ticket was called, and
+// CategoryID if the ticket waited in the line of a category.
+type TicketRecord struct {
+	ID              int64      `json:"id"`
+	ServicePointID  int64      `json:"servicePointId"`
+	Code            string     `json:"code"`
+	Status          string     `json:"status"`
+	IssuedAt        time.Time  `json:"issuedAt"`
+	CalledAt        *time.Time `json:"calledAt,omitempty"`
+	OfficeNumber    string     `json:"officeNumber,omitempty"`
+	Recalls         int        `json:"recalls,omitempty"`
+	QueuedAt        time.Time  `json:"queuedAt"`
+	TransferredFrom int64      `json:"transferredFrom,omitempty"`
+	Class           string     `json:"class"`
+	OperatorID      int64      `json:"operatorId,omitempty"`
+	DeskNumber      string     `json:"deskNumber,omitempty"`
+	CategoryID      int64      `json:"categoryId,omitempty"`
+}
+
+// TicketFilter narrows a ticket listing. Zero fields match everything;
+// IssuedTo is exclusive.
+type TicketFilter struct {
+	Code       string
+	Status     string
+	IssuedFrom time.Time
+	IssuedTo   time.Time
+}
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPause   = "pause"
+	AuditActionResume  = "resume"
+	AuditActionClose   = "close"
+)
+
+// AuditRecord is one change of a service point. Before is empty for
+// creations; both hold the service point as JSON.
+type AuditRecord struct {
+	ID             int64           `json:"id"`
+	ServicePointID int64           `json:"servicePointId"`
+	Action         string          `json:"action"`
+	Before         json.RawMessage `json:"before,omitempty"`
+	After          json.RawMessage `json:"after,omitempty"`
+	Caller         string          `json:"caller"`
+	RequestID      string          `json:"requestId"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+// HistoryPage is a page of audit records, oldest first. NextCursor is set
+// when there are more records after this page.
+type HistoryPage struct {
+	Records    []AuditRecord `json:"records"`
+	NextCursor int64         `json:"nextCursor,omitempty"`
+}
+
+// QueueStats summarises the tickets issued in a period, either for one
+// service point or for every service point of an office.
+type QueueStats struct {
+	ServicePointID int64       `json:"servicePointId,omitempty"`
+	OfficeNumber   string      `json:"officeNumber,omitempty"`
+	From           time.Time   `json:"from"`
+	To             time.Time   `json:"to"`
+	Issued         int         `json:"issued"`
+	Served         int         `json:"served"`
+	AvgWaitSeconds float64     `json:"avgWaitSeconds"`
+	P90WaitSeconds float64     `json:"p90WaitSeconds"`
+	PeakHours      []HourCount `json:"peakHours"`
+}
+
+// HourCount is how many tickets were issued in an hour of the day, UTC.
+type HourCount struct {
+	Hour   int `json:"hour"`
+	Issued int `json:"issued"`
 }
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Operator is a member of staff who calls tickets at a desk.
+type Operator struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type NewOperatorRequest struct {
+	Name string `json:"name"`
+}
+
+// Session is an operator logged in to a service point at a desk. A service
+// point has at most one active session and so does an operator; EndedAt is
+// set once the operator logs out.
+type Session struct {
+	ID             int64      `json:"id"`
+	OperatorID     int64      `json:"operatorId"`
+	ServicePointID int64      `json:"servicePointId"`
+	DeskNumber     string     `json:"deskNumber"`
+	StartedAt      time.Time  `json:"startedAt"`
+	EndedAt        *time.Time `json:"endedAt,omitempty"`
+}
+
+// NewSessionRequest logs an operator in to a service point at a desk.
+type NewSessionRequest struct {
+	OperatorID int64  `json:"operatorId"`
+	DeskNumber string `json:"deskNumber"`
+}
+
+// TicketCall is where a ticket is called to: its office and, when an
+// operator is logged in to the service point, their desk.
+type TicketCall struct {
+	OfficeNumber string
+	OperatorID   int64
+	DeskNumber   string
+}
+
+// OperatorStats summarises the tickets an operator called among those issued
+// in [From, To), and how long they were logged in during that time.
+type OperatorStats struct {
+	OperatorID      int64     `json:"operatorId"`
+	Name            string    `json:"name"`
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	Called          int       `json:"called"`
+	Served          int       `json:"served"`
+	NoShows         int       `json:"noShows"`
+	LoggedInSeconds float64   `json:"loggedInSeconds"`
+	TicketsPerHour  float64   `json:"ticketsPerHour"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Organisation is an institution served by the deployment. It owns its
+// service points and everything about them; requests act for one
+// organisation, named by its ID or found by the host name they were sent
+// to.
+type Organisation struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Host      string    `json:"host,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type NewOrganisationRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Host string `json:"host,omitempty"`
+}
@@ -8,22 +8,57 @@ import (
 
 	"github.com/segmentio/kafka-go"
 	"github.com/snnus/mainservice/config"
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/tenant"
 )
 
+// TicketMessage is published for every ticket event; Event is one of the
+// models.TicketEvent constants. DeskNumber is set for tickets called at the
+// desk of a logged in operator, and Location for tickets called at a service
+// point placed in an office.
 type TicketMessage struct {
-	Ticket       string `json:"ticket"`
-	OfficeNumber string `json:"officeNumber"`
-	Timestamp    string `json:"timestamp"`
+	Tenant       string           `json:"tenant"`
+	Event        string           `json:"event"`
+	Ticket       string           `json:"ticket"`
+	OfficeNumber string           `json:"officeNumber"`
+	DeskNumber   string           `json:"deskNumber,omitempty"`
+	Location     *models.Location `json:"location,omitempty"`
+	Timestamp    string           `json:"timestamp"`
 }
 
+// StatusMessage is published whenever a service point is paused, resumed or
+// closed; Event is models.ServicePointEventStatus. ReturnAt is only set for a
+// pause with an expected return time.
+type StatusMessage struct {
+	Tenant         string `json:"tenant"`
+	Event          string `json:"event"`
+	ServicePointID int64  `json:"servicePointId"`
+	OfficeNumber   string `json:"officeNumber"`
+	Status         string `json:"status"`
+	Reason         string `json:"reason,omitempty"`
+	ReturnAt       string `json:"returnAt,omitempty"`
+	Timestamp      string `json:"timestamp"`
+}
+
+// defaultEventsTopic receives the events other than called tickets when no
+// events topic is configured.
+const defaultEventsTopic = "ticket-events"
+
 type SPProducer struct {
 	writer *kafka.Writer
+	// topic only carries called tickets, so consumers that announce them
+	// never see other events.
+	topic       string
+	eventsTopic string
 }
 
 func NewSPProducer(cfg *config.Config) *SPProducer {
+	eventsTopic := cfg.Kafka.EventsTopic
+	if eventsTopic == "" {
+		eventsTopic = defaultEventsTopic
+	}
 	writer := &kafka.Writer{
 		Addr:         kafka.TCP(cfg.Kafka.Broker),
-		Topic:        cfg.Kafka.Topic,
 		Balancer:     &kafka.LeastBytes{},
 		BatchSize:    cfg.Kafka.BatchSize,
 		BatchTimeout: 10 * time.Millisecond,
@@ -31,22 +66,64 @@ func NewSPProducer(cfg *config.Config) *SPProducer {
 		Async:        false,
 	}
 	return &SPProducer{
-		writer: writer,
+		writer:      writer,
+		topic:       cfg.Kafka.Topic,
+		eventsTopic: eventsTopic,
 	}
 }
 
-func (kp *SPProducer) PublishTicket(ctx context.Context, ticket, officeNumber string) error {
-	msg := TicketMessage{
+func (kp *SPProducer) PublishTicket(ctx context.Context, ticket, officeNumber, deskNumber string, location *models.Location) error {
+	return kp.publishTicket(ctx, models.TicketEventCalled, ticket, officeNumber, deskNumber, location)
+}
+
+func (kp *SPProducer) PublishTicketEvent(ctx context.Context, event, ticket, officeNumber, deskNumber string) error {
+	return kp.publishTicket(ctx, event, ticket, officeNumber, deskNumber, nil)
+}
+
+func (kp *SPProducer) publishTicket(ctx context.Context, event, ticket, officeNumber, deskNumber string, location *models.Location) error {
+	topic := kp.eventsTopic
+	if event == models.TicketEventCalled {
+		topic = kp.topic
+	}
+	return kp.write(ctx, topic, officeNumber, TicketMessage{
+		Tenant:       tenant.FromContext(ctx),
+		Event:        event,
 		Ticket:       ticket,
 		OfficeNumber: officeNumber,
+		DeskNumber:   deskNumber,
+		Location:     location,
 		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (kp *SPProducer) PublishStatus(ctx context.Context, sp models.ServicePoint) error {
+	msg := StatusMessage{
+		Tenant:         tenant.FromContext(ctx),
+		Event:          models.ServicePointEventStatus,
+		ServicePointID: sp.ID,
+		OfficeNumber:   sp.OfficeNumber,
+		Status:         sp.Status,
+		Reason:         sp.StatusReason,
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
 	}
+	if sp.ReturnAt != nil {
+		msg.ReturnAt = sp.ReturnAt.UTC().Format(time.RFC3339)
+	}
+	return kp.write(ctx, kp.eventsTopic, sp.OfficeNumber, msg)
+}
+
+// write publishes msg to topic keyed by the organisation in ctx and the
+// office, as "tenant/office", so consumers can pick the messages of their
+// organisation by key prefix without decoding them.
+func (kp *SPProducer) write(ctx context.Context, topic, officeNumber string, msg any) error {
 	jsonData, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
 	err = kp.writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(tenant.FromContext(ctx) + "/" + officeNumber),
 		Value: jsonData,
 	})
 
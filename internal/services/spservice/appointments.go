@@ -0,0 +1,198 @@
+package spservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// checkInEarly is how long before its slot an appointment can be checked in.
+const checkInEarly = 30 * time.Minute
+
+// maxAppointmentNameLength is the size of the name column of
+// shard_N.appointments.
+const maxAppointmentNameLength = 255
+
+// SetSlotSchedule sets how an existing service point takes appointments.
+// Slots must divide the day evenly so that every day has the same slots.
+func (m *SPService) SetSlotSchedule(ctx context.Context, id string, schedule models.SlotScheduleRequest) (*models.SlotSchedule, error) {
+	var verr models.ValidationError
+	if schedule.SlotMinutes <= 0 || (24*60)%schedule.SlotMinutes != 0 {
+		verr.Add("slotMinutes", "must be a positive divisor of 1440")
+	}
+	if schedule.Capacity <= 0 {
+		verr.Add("capacity", "must be positive")
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.storage.GetServicePointByID(ctx, id, false); err != nil {
+		return nil, err
+	}
+	return m.storage.UpsertSlotSchedule(ctx, id, schedule)
+}
+
+func (m *SPService) GetSlotSchedule(ctx context.Context, id string) (*models.SlotSchedule, error) {
+	return m.storage.GetSlotSchedule(ctx, id)
+}
+
+// ListSlots returns every slot of a service point on the UTC day of date
+// with how many of its places are taken.
+func (m *SPService) ListSlots(ctx context.Context, id string, date time.Time) ([]models.Slot, error) {
+	schedule, err := m.storage.GetSlotSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	day := date.UTC().Truncate(24 * time.Hour)
+	length := time.Duration(schedule.SlotMinutes) * time.Minute
+
+	appointments, err := m.storage.ListAppointments(ctx, id, day, day.Add(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	booked := make(map[time.Time]int)
+	for _, a := range appointments {
+		if a.Status == models.AppointmentStatusBooked || a.Status == models.AppointmentStatusCheckedIn {
+			booked[a.SlotStart.UTC()]++
+		}
+	}
+
+	slots := make([]models.Slot, 0, 24*60/schedule.SlotMinutes)
+	for start := day; start.Before(day.Add(24 * time.Hour)); start = start.Add(length) {
+		slots = append(slots, models.Slot{
+			Start:    start,
+			End:      start.Add(length),
+			Capacity: schedule.Capacity,
+			Booked:   booked[start],
+		})
+	}
+	return slots, nil
+}
+
+// BookAppointment books a future slot of a service point for a visitor.
+func (m *SPService) BookAppointment(ctx context.Context, req models.NewAppointmentRequest) (*models.Appointment, error) {
+	req.Name = strings.TrimSpace(req.Name)
+
+	var verr models.ValidationError
+	checkLength(&verr, "name", req.Name, maxAppointmentNameLength)
+	checkPrintable(&verr, "name", req.Name)
+	if req.ServicePointID <= 0 {
+		verr.Add("servicePointId", "must be positive")
+	}
+	if !req.SlotStart.After(m.now()) {
+		verr.Add("slotStart", "must be in the future")
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	id := strconv.FormatInt(req.ServicePointID, 10)
+	if _, err := m.storage.GetServicePointByID(ctx, id, false); err != nil {
+		return nil, err
+	}
+	schedule, err := m.storage.GetSlotSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	length := time.Duration(schedule.SlotMinutes) * time.Minute
+	start := req.SlotStart.UTC()
+	if !start.Truncate(length).Equal(start) {
+		verr.Add("slotStart", "must be the start of a %d minute slot", schedule.SlotMinutes)
+		return nil, verr.Err()
+	}
+
+	return m.storage.BookAppointment(ctx, id, models.Appointment{
+		SlotStart: start,
+		SlotEnd:   start.Add(length),
+		Name:      req.Name,
+	})
+}
+
+func (m *SPService) GetAppointment(ctx context.Context, id string) (*models.Appointment, error) {
+	return m.storage.GetAppointment(ctx, id)
+}
+
+// CancelAppointment frees the slot of a booked appointment.
+func (m *SPService) CancelAppointment(ctx context.Context, id string) (*models.Appointment, error) {
+	appointment, err := m.storage.GetAppointment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	spID := strconv.FormatInt(appointment.ServicePointID, 10)
+	return m.storage.SetAppointmentStatus(ctx, spID, appointment.ID, models.AppointmentStatusBooked, models.AppointmentStatusCancelled, "")
+}
+
+// CheckIn turns a booked appointment into an appointment class ticket, which
+// is called ahead of walk-in visitors. Appointments can be checked in from
+// checkInEarly before their slot until it ends.
+func (m *SPService) CheckIn(ctx context.Context, id string) (*models.Appointment, error) {
+	appointment, err := m.storage.GetAppointment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.Status != models.AppointmentStatusBooked {
+		return nil, fmt.Errorf("failed to check in appointment %d: %w", appointment.ID, models.ErrAppointmentState)
+	}
+
+	now := m.now()
+	if now.Before(appointment.SlotStart.Add(-checkInEarly)) || !now.Before(appointment.SlotEnd) {
+		var verr models.ValidationError
+		verr.Add("", "appointment can be checked in from %s until %s",
+			appointment.SlotStart.Add(-checkInEarly).UTC().Format(time.RFC3339), appointment.SlotEnd.UTC().Format(time.RFC3339))
+		return nil, verr.Err()
+	}
+
+	spID := strconv.FormatInt(appointment.ServicePointID, 10)
+	ticket, err := m.Enqueue(ctx, spID, models.TicketClassAppointment)
+	if err != nil {
+		return nil, err
+	}
+
+	checkedIn, err := m.storage.SetAppointmentStatus(ctx, spID, appointment.ID, models.AppointmentStatusBooked, models.AppointmentStatusCheckedIn, ticket.Ticket)
+	if err != nil {
+		// The appointment changed since it was read; do not leave a ticket
+		// in the queue that nobody holds.
+		if _, cancelErr := m.CancelTicket(ctx, spID, ticket.Ticket); cancelErr != nil {
+			log.Printf("failed to cancel ticket %s of appointment %d: %s", ticket.Ticket, appointment.ID, cancelErr)
+		}
+		return nil, err
+	}
+	return checkedIn, nil
+}
+
+// ExpireAppointments marks booked appointments whose slot has passed as
+// expired.
+func (m *SPService) ExpireAppointments(ctx context.Context) (int64, error) {
+	return m.storage.ExpireAppointments(ctx, m.now())
+}
+
+// RunAppointmentExpiry calls ExpireAppointments every interval until ctx is
+// done.
+func (m *SPService) RunAppointmentExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := m.ExpireAppointments(ctx)
+			if err != nil {
+				log.Printf("failed to expire appointments: %s", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("expired %d appointments", expired)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package spservice
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// load is how full a service point was just before a ticket was admitted.
+type load struct {
+	waiting int
+	issued  int
+}
+
+// checkCapacity reports models.ErrQueueFull if as many tickets wait in all
+// lines of a service point as it allows, and models.ErrQuotaReached if it has
+// issued its daily quota. The day is the one of now, in its time zone.
+//
+// Concurrent enqueues may both pass the check: the limits are there to turn
+// crowds away, not to be exact.
+func (m *SPService) checkCapacity(ctx context.Context, sp *models.ServicePoint, now time.Time) (load, error) {
+	var l load
+	id := strconv.FormatInt(sp.ID, 10)
+
+	if sp.MaxQueueLength > 0 {
+		for _, class := range models.TicketClasses {
+			n, err := m.httpClient.Length(ctx, queueID(id, class))
+			if err != nil {
+				return l, err
+			}
+			l.waiting += n
+		}
+		if l.waiting >= sp.MaxQueueLength {
+			return l, fmt.Errorf("%w: %d tickets are waiting", models.ErrQueueFull, l.waiting)
+		}
+	}
+
+	if sp.DailyTicketQuota > 0 {
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		issued, err := m.storage.CountIssuedTickets(ctx, id, day)
+		if err != nil {
+			return l, err
+		}
+		l.issued = issued
+		if l.issued >= sp.DailyTicketQuota {
+			return l, fmt.Errorf("%w: all %d tickets of the day are issued", models.ErrQuotaReached, sp.DailyTicketQuota)
+		}
+	}
+
+	return l, nil
+}
+
+// publishCapacity announces that the ticket just admitted filled the queue
+// or used up the daily quota, so that kiosks can stop offering tickets.
+func (m *SPService) publishCapacity(ctx context.Context, sp *models.ServicePoint, l load, ticket string) {
+	if sp.MaxQueueLength > 0 && l.waiting+1 >= sp.MaxQueueLength {
+		m.publish(ctx, models.TicketEventQueueFull, ticket, sp.OfficeNumber, "")
+	}
+	if sp.DailyTicketQuota > 0 && l.issued+1 >= sp.DailyTicketQuota {
+		m.publish(ctx, models.TicketEventQuotaReached, ticket, sp.OfficeNumber, "")
+	}
+}
@@ -0,0 +1,271 @@
+package spservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (m *SPService) CreateCategory(ctx context.Context, category models.NewCategoryRequest) (*models.Category, error) {
+	category.Name = strings.TrimSpace(category.Name)
+	category.ShortName = strings.TrimSpace(category.ShortName)
+
+	var verr models.ValidationError
+	checkLength(&verr, "name", category.Name, maxNameLength)
+	checkPrintable(&verr, "name", category.Name)
+	checkShortName(&verr, "shortName", category.ShortName)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	// Deleted service points count too, as they may be restored.
+	servicePoints, err := m.storage.ListServicePoints(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, sp := range servicePoints {
+		if slices.Contains(codePrefixes(sp.ShortName), category.ShortName) {
+			verr.Add("shortName", "would number tickets like service point %d", sp.ID)
+			return nil, verr.Err()
+		}
+	}
+
+	return m.storage.CreateCategory(ctx, category)
+}
+
+// checkCategoryShortNames reports a service point short name whose tickets
+// would be numbered like those of a category, so that a ticket code always
+// names one line.
+func (m *SPService) checkCategoryShortNames(ctx context.Context, shortName string) error {
+	categories, err := m.storage.ListCategories(ctx)
+	if err != nil {
+		return err
+	}
+	prefixes := codePrefixes(shortName)
+	for _, category := range categories {
+		if slices.Contains(prefixes, category.ShortName) {
+			var verr models.ValidationError
+			verr.Add("shortName", "would number tickets like category %d", category.ID)
+			return verr.Err()
+		}
+	}
+	return nil
+}
+
+// codePrefixes returns the prefixes of the ticket codes a service point with
+// the given short name issues, one per line.
+func codePrefixes(shortName string) []string {
+	prefixes := make([]string, 0, len(classPrefixes))
+	for _, class := range models.TicketClasses {
+		prefixes = append(prefixes, classPrefixes[class]+shortName)
+	}
+	return prefixes
+}
+
+func (m *SPService) GetCategory(ctx context.Context, id string) (*models.Category, error) {
+	return m.storage.GetCategory(ctx, id)
+}
+
+func (m *SPService) ListCategories(ctx context.Context) ([]models.Category, error) {
+	return m.storage.ListCategories(ctx)
+}
+
+// SetSPCategories replaces the categories a service point serves.
+func (m *SPService) SetSPCategories(ctx context.Context, id string, assignment models.CategoryAssignment) ([]models.Category, error) {
+	var verr models.ValidationError
+	ids := []int64{}
+	for _, categoryID := range assignment.CategoryIDs {
+		if categoryID <= 0 {
+			verr.Add("categoryIds", "must be positive")
+			break
+		}
+		if !slices.Contains(ids, categoryID) {
+			ids = append(ids, categoryID)
+		}
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.storage.GetServicePointByID(ctx, id, false); err != nil {
+		return nil, err
+	}
+	return m.storage.SetServicePointCategories(ctx, id, ids)
+}
+
+// GetSPCategories returns the categories a service point serves.
+func (m *SPService) GetSPCategories(ctx context.Context, id string) ([]models.Category, error) {
+	if _, err := m.storage.GetServicePointByID(ctx, id, false); err != nil {
+		return nil, err
+	}
+	return m.storage.ListServicePointCategories(ctx, id)
+}
+
+// EnqueueCategory issues a ticket in the line of a category, to be called by
+// whichever of its service points gets to it first. It reports
+// models.ErrNotServed unless one of them is open and issuing tickets now;
+// the limits of the service points do not apply.
+func (m *SPService) EnqueueCategory(ctx context.Context, id string) (*models.Ticket, error) {
+	category, err := m.storage.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkServed(ctx, category); err != nil {
+		return nil, err
+	}
+
+	ticket, err := m.httpClient.Enqueue(ctx, categoryQueueID(category.ID), category.ShortName)
+	if err != nil {
+		return nil, err
+	}
+	ticket.Class = models.TicketClassRegular
+	ticket.CategoryID = category.ID
+
+	// As for service points, the ticket is issued by now and failing to
+	// record it must not fail the request.
+	record, err := m.storage.CreateCategoryTicket(ctx, category.ID, ticket.Ticket)
+	if err != nil {
+		log.Printf("failed to record ticket %s: %s", ticket.Ticket, err)
+		return ticket, nil
+	}
+
+	withPosition, err := m.categoryTicketStatus(ctx, record)
+	if err != nil {
+		log.Printf("failed to find position of ticket %s: %s", ticket.Ticket, err)
+		return ticket, nil
+	}
+	return withPosition, nil
+}
+
+// CancelCategoryTicket takes a ticket waiting in the line of a category out
+// of it at the visitor's request.
+func (m *SPService) CancelCategoryTicket(ctx context.Context, id string, code string) (*models.Ticket, error) {
+	category, err := m.storage.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := m.storage.ListCategoryTickets(ctx, models.CategoryTicketFilter{
+		CategoryIDs: []int64{category.ID},
+		Code:        code,
+		Status:      models.TicketStatusWaiting,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tickets) == 0 {
+		return nil, models.ErrTicketNotFound
+	}
+	waiting := tickets[len(tickets)-1]
+
+	if err := m.httpClient.Cancel(ctx, categoryQueueID(category.ID), code); err != nil {
+		return nil, err
+	}
+
+	record, err := m.storage.SetCategoryTicketStatus(ctx, waiting.ID, models.TicketStatusWaiting, models.TicketStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	m.publish(ctx, models.TicketEventCancelled, record.Code, "", "")
+	return &models.Ticket{
+		Ticket:     record.Code,
+		Status:     record.Status,
+		Class:      models.TicketClassRegular,
+		CategoryID: record.CategoryID,
+	}, nil
+}
+
+// checkServed reports models.ErrNotServed unless a service point of the
+// category would issue a ticket now.
+func (m *SPService) checkServed(ctx context.Context, category *models.Category) error {
+	for _, spID := range category.ServicePointIDs {
+		sp, err := m.storage.GetServicePointByID(ctx, strconv.FormatInt(spID, 10), false)
+		if errors.Is(err, models.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if checkStatus(sp, true) != nil {
+			continue
+		}
+		_, err = m.checkOpen(ctx, sp)
+		if errors.Is(err, models.ErrClosed) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	return fmt.Errorf("%w %d", models.ErrNotServed, category.ID)
+}
+
+// categoryTicketStatus turns a waiting category ticket into what the visitor
+// sees, with their place in the line of the category.
+func (m *SPService) categoryTicketStatus(ctx context.Context, record *models.CategoryTicket) (*models.Ticket, error) {
+	waiting, err := m.storage.ListCategoryTickets(ctx, models.CategoryTicketFilter{
+		CategoryIDs: []int64{record.CategoryID},
+		Status:      models.TicketStatusWaiting,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ticket := &models.Ticket{
+		Ticket:     record.Code,
+		Status:     record.Status,
+		Class:      models.TicketClassRegular,
+		CategoryID: record.CategoryID,
+	}
+	for i, other := range waiting {
+		if other.ID == record.ID {
+			ticket.Position = i + 1
+		}
+	}
+	return ticket, nil
+}
+
+// categoryTicket returns the oldest ticket waiting in the lines of the
+// categories a service point serves if it has waited longer than the head of
+// the service point's regular line, which goes first otherwise. Category
+// tickets are regular tickets, so they only compete when the dequeue policy
+// picked class, the line to call from, as regular.
+func (m *SPService) categoryTicket(ctx context.Context, id string, class string, waiting []models.TicketRecord) (*models.CategoryTicket, error) {
+	if class != models.TicketClassRegular {
+		return nil, nil
+	}
+	categories, err := m.storage.ListServicePointCategories(ctx, id)
+	if err != nil || len(categories) == 0 {
+		return nil, err
+	}
+
+	ids := make([]int64, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+	tickets, err := m.storage.ListCategoryTickets(ctx, models.CategoryTicketFilter{CategoryIDs: ids, Status: models.TicketStatusWaiting})
+	if err != nil || len(tickets) == 0 {
+		return nil, err
+	}
+
+	oldest := tickets[0]
+	for _, ticket := range waiting {
+		if ticket.Class == class && !ticket.QueuedAt.After(oldest.IssuedAt) {
+			return nil, nil
+		}
+	}
+	return &oldest, nil
+}
+
+// categoryQueueID names the queue engine queue of the line of a category.
+func categoryQueueID(id int64) string {
+	return "category-" + strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,233 @@
+package spservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// defaultMaxRecalls is how often a called ticket is recalled before the next
+// recall gives up on it as a no-show.
+const defaultMaxRecalls = 3
+
+// SetMaxRecalls changes how often a called ticket is recalled before it is
+// marked as a no-show. Values below 0 are treated as 0.
+func (m *SPService) SetMaxRecalls(n int) {
+	m.maxRecalls = max(n, 0)
+}
+
+// Recall calls the current ticket of a service point again. Once it has been
+// recalled maxRecalls times, the next recall marks it as a no-show instead.
+func (m *SPService) Recall(ctx context.Context, id string) (*models.Ticket, error) {
+	current, err := m.currentTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *models.TicketRecord
+	var event string
+	if current.Recalls >= m.maxRecalls {
+		record, err = m.storage.SetTicketStatus(ctx, id, current.ID, models.TicketStatusCalled, models.TicketStatusNoShow)
+		event = models.TicketEventNoShow
+	} else {
+		record, err = m.storage.RecallTicket(ctx, id, current.ID)
+		event = models.TicketEventRecalled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m.publish(ctx, event, record.Code, record.OfficeNumber, record.DeskNumber)
+	return m.ticketStatus(ctx, record, nil)
+}
+
+// Skip gives up on the current ticket of a service point as a no-show and
+// calls the next one.
+func (m *SPService) Skip(ctx context.Context, id string) (*models.Ticket, error) {
+	current, err := m.currentTicket(ctx, id)
+	switch {
+	case err == nil:
+		skipped, err := m.storage.SetTicketStatus(ctx, id, current.ID, models.TicketStatusCalled, models.TicketStatusNoShow)
+		if err != nil {
+			return nil, err
+		}
+		m.publish(ctx, models.TicketEventSkipped, skipped.Code, skipped.OfficeNumber, skipped.DeskNumber)
+	case !errors.Is(err, models.ErrTicketNotFound):
+		return nil, err
+	}
+
+	return m.Dequeue(ctx, id)
+}
+
+// CancelTicket takes a waiting ticket out of the queue at the visitor's
+// request.
+func (m *SPService) CancelTicket(ctx context.Context, id string, code string) (*models.Ticket, error) {
+	tickets, err := m.storage.ListTickets(ctx, id, models.TicketFilter{Code: code, Status: models.TicketStatusWaiting})
+	if err != nil {
+		return nil, err
+	}
+	if len(tickets) == 0 {
+		return nil, models.ErrTicketNotFound
+	}
+	waiting := tickets[len(tickets)-1]
+
+	officeNumber, err := m.storage.GetOfficeNumberById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.httpClient.Cancel(ctx, queueID(id, waiting.Class), code); err != nil {
+		return nil, err
+	}
+
+	record, err := m.storage.SetTicketStatus(ctx, id, waiting.ID, models.TicketStatusWaiting, models.TicketStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	m.publish(ctx, models.TicketEventCancelled, record.Code, officeNumber, "")
+	return m.ticketStatus(ctx, record, nil)
+}
+
+// TransferTicket sends the called ticket with the given code to the queue of
+// another service point under the same code. The ticket is closed as
+// transferred at its service point and recorded anew at the other one. Like
+// Enqueue, it fails if the other service point is paused, closed, outside its
+// working hours or at capacity. Without the service point the ticket was
+// called at, a code called at several of them is reported as
+// models.ErrAmbiguousTicket.
+func (m *SPService) TransferTicket(ctx context.Context, code string, transfer models.TicketTransfer) (*models.Ticket, error) {
+	var verr models.ValidationError
+	if transfer.ServicePointID <= 0 {
+		verr.Add("servicePointId", "must be positive")
+	}
+	if transfer.FromServicePointID < 0 {
+		verr.Add("fromServicePointId", "must be positive")
+	}
+	switch transfer.Position {
+	case "", models.TransferFront, models.TransferBack:
+	default:
+		verr.Add("position", "must be front or back")
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	filter := models.TicketFilter{Code: code, Status: models.TicketStatusCalled}
+	var tickets []models.TicketRecord
+	var err error
+	if transfer.FromServicePointID > 0 {
+		tickets, err = m.storage.ListTickets(ctx, strconv.FormatInt(transfer.FromServicePointID, 10), filter)
+	} else {
+		tickets, err = m.storage.ListAllTickets(ctx, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(tickets) == 0 {
+		return nil, fmt.Errorf("no called ticket %s: %w", code, models.ErrTicketNotFound)
+	}
+	called := tickets[len(tickets)-1]
+	for _, other := range tickets {
+		if other.ServicePointID != called.ServicePointID {
+			return nil, fmt.Errorf("%w: %s is called at service points %d and %d, name one in fromServicePointId",
+				models.ErrAmbiguousTicket, code, other.ServicePointID, called.ServicePointID)
+		}
+	}
+	if called.ServicePointID == transfer.ServicePointID {
+		verr.Add("servicePointId", "must differ from the service point of the ticket")
+		return nil, verr.Err()
+	}
+
+	from := strconv.FormatInt(called.ServicePointID, 10)
+	to := strconv.FormatInt(transfer.ServicePointID, 10)
+	front := transfer.Position == models.TransferFront
+
+	sp, err := m.storage.GetServicePointByID(ctx, to, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(sp, true); err != nil {
+		return nil, err
+	}
+	now, err := m.checkOpen(ctx, sp)
+	if err != nil {
+		return nil, err
+	}
+	load, err := m.checkCapacity(ctx, sp, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// The ticket is closed at its service point before it enters the other
+	// queue, and reopened if that fails, so it is never waiting at one while
+	// called at the other.
+	if _, err := m.storage.SetTicketStatus(ctx, from, called.ID, models.TicketStatusCalled, models.TicketStatusTransferred); err != nil {
+		return nil, err
+	}
+	if err := m.httpClient.Insert(ctx, queueID(to, called.Class), code, front); err != nil {
+		m.reopenTicket(ctx, from, called.ID)
+		return nil, err
+	}
+	record, err := m.storage.CreateTransferredTicket(ctx, to, code, called.Class, called.ServicePointID, front)
+	if err != nil {
+		if cancelErr := m.httpClient.Cancel(ctx, queueID(to, called.Class), code); cancelErr != nil {
+			log.Printf("failed to take transferred ticket %s out of queue %s: %s", code, to, cancelErr)
+		}
+		m.reopenTicket(ctx, from, called.ID)
+		return nil, err
+	}
+	m.publishCapacity(ctx, sp, load, code)
+
+	m.publish(ctx, models.TicketEventTransferred, code, sp.OfficeNumber, "")
+	return m.ticketStatus(ctx, record, sp)
+}
+
+// reopenTicket marks a ticket whose transfer failed as called again.
+func (m *SPService) reopenTicket(ctx context.Context, spID string, ticketID int64) {
+	if _, err := m.storage.SetTicketStatus(ctx, spID, ticketID, models.TicketStatusTransferred, models.TicketStatusCalled); err != nil {
+		log.Printf("failed to reopen ticket %d of service point %s: %s", ticketID, spID, err)
+	}
+}
+
+// currentTicket is the ticket a service point called last and is still
+// serving. It reports models.ErrTicketNotFound if there is none.
+func (m *SPService) currentTicket(ctx context.Context, id string) (*models.TicketRecord, error) {
+	tickets, err := m.storage.ListTickets(ctx, id, models.TicketFilter{Status: models.TicketStatusCalled})
+	if err != nil {
+		return nil, err
+	}
+	if len(tickets) == 0 {
+		return nil, fmt.Errorf("no ticket is called: %w", models.ErrTicketNotFound)
+	}
+	return &tickets[len(tickets)-1], nil
+}
+
+// serveCurrent marks the tickets a service point is still serving as served.
+// Calling the next ticket does that too, but the last ticket of a day has no
+// next one. A failure leaves the ticket called and does not fail the request.
+func (m *SPService) serveCurrent(ctx context.Context, id string) {
+	tickets, err := m.storage.ListTickets(ctx, id, models.TicketFilter{Status: models.TicketStatusCalled})
+	if err != nil {
+		log.Printf("failed to serve the called tickets of service point %s: %s", id, err)
+		return
+	}
+	for _, ticket := range tickets {
+		if _, err := m.storage.SetTicketStatus(ctx, id, ticket.ID, models.TicketStatusCalled, models.TicketStatusServed); err != nil {
+			log.Printf("failed to serve ticket %d of service point %s: %s", ticket.ID, id, err)
+		}
+	}
+}
+
+// publish sends a ticket event. The desk number is empty unless the ticket
+// was called at a desk. Like for calls, a failed publish does not undo the
+// change.
+func (m *SPService) publish(ctx context.Context, event, ticket, officeNumber, deskNumber string) {
+	if err := m.producer.PublishTicketEvent(ctx, event, ticket, officeNumber, deskNumber); err != nil {
+		log.Printf("%s", err.Error())
+	}
+}
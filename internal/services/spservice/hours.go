@@ -0,0 +1,240 @@
+package spservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+const (
+	clockLayout = "15:04"
+	dateLayout  = "2006-01-02"
+
+	maxHolidayNameLength = 255
+)
+
+// SetClock replaces the clock of the service: opening hours and the daily
+// ticket quota, appointment booking, check-in and expiry, the retention of
+// deleted service points, the return time of a pause and the time operators
+// of open sessions have been logged in are all measured against it.
+func (m *SPService) SetClock(now func() time.Time) {
+	m.now = now
+}
+
+// SetWorkingHours sets the hours of an existing service point, overriding
+// those of its office.
+func (m *SPService) SetWorkingHours(ctx context.Context, id string, hours models.WorkingHoursRequest) (*models.WorkingHours, error) {
+	hours, err := normalizeWorkingHours(hours)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := m.storage.GetServicePointByID(ctx, id, false); err != nil {
+		return nil, err
+	}
+	return m.storage.UpsertWorkingHours(ctx, id, hours)
+}
+
+// DeleteWorkingHours makes a service point follow the hours of its office
+// again.
+func (m *SPService) DeleteWorkingHours(ctx context.Context, id string) error {
+	return m.storage.DeleteWorkingHours(ctx, id)
+}
+
+// GetWorkingHours returns the hours a service point keeps: its own if set,
+// the default of its office otherwise. A service point with neither is
+// reported as models.ErrHoursNotFound and takes tickets at any time.
+func (m *SPService) GetWorkingHours(ctx context.Context, id string) (*models.WorkingHours, error) {
+	hours, err := m.storage.GetWorkingHours(ctx, id)
+	if !errors.Is(err, models.ErrHoursNotFound) {
+		return hours, err
+	}
+
+	officeNumber, err := m.storage.GetOfficeNumberById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return m.storage.GetOfficeHours(ctx, officeNumber)
+}
+
+// SetOfficeHours sets the default hours of every service point in an
+// office.
+func (m *SPService) SetOfficeHours(ctx context.Context, officeNumber string, hours models.WorkingHoursRequest) (*models.WorkingHours, error) {
+	officeNumber = strings.TrimSpace(officeNumber)
+
+	var verr models.ValidationError
+	checkLength(&verr, "officeNumber", officeNumber, maxOfficeNumberLength)
+	checkPrintable(&verr, "officeNumber", officeNumber)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	hours, err := normalizeWorkingHours(hours)
+	if err != nil {
+		return nil, err
+	}
+	return m.storage.UpsertOfficeHours(ctx, officeNumber, hours)
+}
+
+func (m *SPService) GetOfficeHours(ctx context.Context, officeNumber string) (*models.WorkingHours, error) {
+	return m.storage.GetOfficeHours(ctx, officeNumber)
+}
+
+// checkOpen reports models.ErrClosed, with the reason, if a service point
+// does not issue tickets now. It returns the current time in the time zone
+// of the working hours, UTC if there are none.
+func (m *SPService) checkOpen(ctx context.Context, sp *models.ServicePoint) (time.Time, error) {
+	hours, err := m.storage.GetWorkingHours(ctx, strconv.FormatInt(sp.ID, 10))
+	if errors.Is(err, models.ErrHoursNotFound) {
+		hours, err = m.storage.GetOfficeHours(ctx, sp.OfficeNumber)
+	}
+	if errors.Is(err, models.ErrHoursNotFound) {
+		return m.now().UTC(), nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc, err := time.LoadLocation(hours.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load time zone of working hours: %w", err)
+	}
+	now := m.now().In(loc)
+
+	day := hours.Week.Day(now.Weekday())
+	date := now.Format(dateLayout)
+	for _, holiday := range hours.Holidays {
+		if holiday.Date != date {
+			continue
+		}
+		if holiday.Hours == nil {
+			name := holiday.Name
+			if name == "" {
+				name = "holiday"
+			}
+			return now, fmt.Errorf("%w: closed on %s for %s", models.ErrClosed, date, name)
+		}
+		day = holiday.Hours
+	}
+	if day == nil {
+		return now, fmt.Errorf("%w: closed on %s", models.ErrClosed, now.Weekday())
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	closing := clockMinutes(day.Close)
+	switch {
+	case minute < clockMinutes(day.Open):
+		return now, fmt.Errorf("%w: opens at %s %s", models.ErrClosed, day.Open, hours.Timezone)
+	case minute >= closing:
+		return now, fmt.Errorf("%w: closed at %s %s", models.ErrClosed, day.Close, hours.Timezone)
+	case minute >= closing-hours.StopBeforeCloseMinutes:
+		return now, fmt.Errorf("%w: no new tickets in the last %d minutes before closing at %s %s",
+			models.ErrClosed, hours.StopBeforeCloseMinutes, day.Close, hours.Timezone)
+	case day.BreakStart != "" && minute >= clockMinutes(day.BreakStart) && minute < clockMinutes(day.BreakEnd):
+		return now, fmt.Errorf("%w: on break until %s %s", models.ErrClosed, day.BreakEnd, hours.Timezone)
+	}
+	return now, nil
+}
+
+// normalizeWorkingHours defaults the time zone to UTC and reports every
+// field that does not describe valid hours.
+func normalizeWorkingHours(hours models.WorkingHoursRequest) (models.WorkingHoursRequest, error) {
+	var verr models.ValidationError
+
+	hours.Timezone = strings.TrimSpace(hours.Timezone)
+	if hours.Timezone == "" {
+		hours.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(hours.Timezone); err != nil {
+		verr.Add("timezone", "must be an IANA time zone name")
+	}
+
+	for _, d := range []struct {
+		name  string
+		hours *models.DayHours
+	}{
+		{"monday", hours.Week.Monday},
+		{"tuesday", hours.Week.Tuesday},
+		{"wednesday", hours.Week.Wednesday},
+		{"thursday", hours.Week.Thursday},
+		{"friday", hours.Week.Friday},
+		{"saturday", hours.Week.Saturday},
+		{"sunday", hours.Week.Sunday},
+	} {
+		checkDayHours(&verr, "week."+d.name, d.hours)
+	}
+
+	seen := make(map[string]bool)
+	for i, holiday := range hours.Holidays {
+		field := fmt.Sprintf("holidays[%d]", i)
+		if _, err := time.Parse(dateLayout, holiday.Date); err != nil {
+			verr.Add(field+".date", "must be a date formatted as YYYY-MM-DD")
+		} else if seen[holiday.Date] {
+			verr.Add(field+".date", "must not repeat %s", holiday.Date)
+		}
+		seen[holiday.Date] = true
+
+		hours.Holidays[i].Name = strings.TrimSpace(holiday.Name)
+		if len(hours.Holidays[i].Name) > maxHolidayNameLength {
+			verr.Add(field+".name", "must be at most %d characters", maxHolidayNameLength)
+		}
+		checkPrintable(&verr, field+".name", hours.Holidays[i].Name)
+		checkDayHours(&verr, field+".hours", holiday.Hours)
+	}
+
+	if hours.StopBeforeCloseMinutes < 0 || hours.StopBeforeCloseMinutes >= 24*60 {
+		verr.Add("stopBeforeCloseMinutes", "must be between 0 and 1439")
+	}
+
+	return hours, verr.Err()
+}
+
+// checkDayHours reports hours that are not ordered as open, break start,
+// break end, close. Nil hours are a closed day.
+func checkDayHours(verr *models.ValidationError, field string, day *models.DayHours) {
+	if day == nil {
+		return
+	}
+
+	times := []struct {
+		name  string
+		value string
+	}{
+		{"open", day.Open},
+		{"breakStart", day.BreakStart},
+		{"breakEnd", day.BreakEnd},
+		{"close", day.Close},
+	}
+	if (day.BreakStart == "") != (day.BreakEnd == "") {
+		verr.Add(field, "must set both breakStart and breakEnd or neither")
+		return
+	}
+
+	last := -1
+	for _, t := range times {
+		if t.value == "" && (t.name == "breakStart" || t.name == "breakEnd") {
+			continue
+		}
+		if _, err := time.Parse(clockLayout, t.value); err != nil {
+			verr.Add(field+"."+t.name, "must be a time formatted as HH:MM")
+			return
+		}
+		minute := clockMinutes(t.value)
+		if minute <= last {
+			verr.Add(field+"."+t.name, "must be later than the times before it")
+			return
+		}
+		last = minute
+	}
+}
+
+// clockMinutes returns the minutes after midnight of a valid HH:MM time.
+func clockMinutes(value string) int {
+	t, _ := time.Parse(clockLayout, value)
+	return t.Hour()*60 + t.Minute()
+}
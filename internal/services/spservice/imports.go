@@ -0,0 +1,245 @@
+package spservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// maxImportRows bounds the rows of one import, all of which a transactional
+// import keeps locked until it commits.
+const maxImportRows = 1000
+
+// ImportSP creates and replaces service points in bulk, checking every row as
+// CreateSP or UpsertSP would. A transactional import writes nothing if any
+// row fails and returns the error of the first failing row along with the
+// result, whose rows then have no action. A best-effort import writes the
+// rows that pass and only reports the others. A dry run checks the rows
+// without writing them.
+func (m *SPService) ImportSP(ctx context.Context, rows []models.ImportRow, mode string, dryRun bool) (*models.ImportResult, error) {
+	if mode == "" {
+		mode = models.ImportModeTransactional
+	}
+
+	var verr models.ValidationError
+	if mode != models.ImportModeTransactional && mode != models.ImportModeBestEffort {
+		verr.Add("mode", "must be %q or %q", models.ImportModeTransactional, models.ImportModeBestEffort)
+	}
+	switch {
+	case len(rows) == 0:
+		verr.Add("", "import has no rows")
+	case len(rows) > maxImportRows:
+		verr.Add("", "import has more than %d rows", maxImportRows)
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.ImportResult{DryRun: dryRun, Mode: mode, Rows: make([]models.ImportRowResult, len(rows))}
+	writes := make([]*models.ServicePointWrite, len(rows))
+	seen := make(map[int64]int, len(rows))
+
+	var firstErr error
+	for i, row := range rows {
+		result.Rows[i] = models.ImportRowResult{Row: i + 1, ID: row.ID}
+
+		w, action, err := m.checkImportRow(ctx, row, seen)
+		if _, ok := seen[row.ID]; !ok && row.ID > 0 {
+			seen[row.ID] = i + 1
+		}
+		if err != nil {
+			failRow(&result.Rows[i], err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		writes[i] = w
+		result.Rows[i].Action = action
+	}
+
+	switch {
+	case dryRun:
+	case mode == models.ImportModeBestEffort:
+		m.importEach(ctx, result, writes)
+	case firstErr == nil:
+		firstErr = m.importAll(ctx, result, writes)
+	}
+
+	if mode == models.ImportModeBestEffort {
+		firstErr = nil
+	}
+	for i := range result.Rows {
+		row := &result.Rows[i]
+		if firstErr != nil && !dryRun {
+			row.Action = ""
+		}
+		switch {
+		case row.Error != "":
+			result.Failed++
+		case row.Action == models.AuditActionCreate:
+			result.Created++
+		case row.Action == models.AuditActionUpdate:
+			result.Updated++
+		}
+	}
+	return result, firstErr
+}
+
+// checkImportRow checks a row and returns the write it makes and whether
+// that creates or updates a service point. seen maps the ids of earlier rows
+// to their row numbers.
+func (m *SPService) checkImportRow(ctx context.Context, row models.ImportRow, seen map[int64]int) (*models.ServicePointWrite, string, error) {
+	var verr models.ValidationError
+	if row.ID < 0 {
+		verr.Add("id", "must be positive")
+	}
+	if n, ok := seen[row.ID]; ok {
+		verr.Add("id", "repeats row %d", n)
+	}
+
+	sp, err := m.placeServicePoint(ctx, models.NewServicePointRequest{
+		Name:             row.Name,
+		ShortName:        row.ShortName,
+		OfficeNumber:     row.OfficeNumber,
+		OfficeID:         row.OfficeID,
+		MaxQueueLength:   row.MaxQueueLength,
+		DailyTicketQuota: row.DailyTicketQuota,
+	})
+	if err == nil {
+		sp, err = normalizeServicePoint(sp)
+	}
+	if err == nil {
+		err = m.checkCategoryShortNames(ctx, sp.ShortName)
+	}
+	var spErr *models.ValidationError
+	if errors.As(err, &spErr) {
+		verr.Fields = append(verr.Fields, spErr.Fields...)
+	} else if err != nil {
+		return nil, "", err
+	}
+	if err := verr.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if row.ID == 0 {
+		return &models.ServicePointWrite{Create: true, ServicePoint: sp}, models.AuditActionCreate, nil
+	}
+
+	id := strconv.FormatInt(row.ID, 10)
+	existing, err := m.storage.GetServicePointByID(ctx, id, true)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return &models.ServicePointWrite{ID: id, ServicePoint: sp}, models.AuditActionCreate, nil
+	case err != nil:
+		return nil, "", err
+	case existing.DeletedAt != nil:
+		return nil, "", fmt.Errorf("failed to import service point: %w", models.ErrDeleted)
+	}
+	return &models.ServicePointWrite{ID: id, ServicePoint: sp}, models.AuditActionUpdate, nil
+}
+
+// importAll makes every write in one storage transaction. New service points
+// get fresh ids on every attempt: like in CreateSP, a caller-chosen id may
+// have taken an allocated one.
+func (m *SPService) importAll(ctx context.Context, result *models.ImportResult, writes []*models.ServicePointWrite) error {
+	for attempt := 0; ; attempt++ {
+		batch := make([]models.ServicePointWrite, len(writes))
+		for i, w := range writes {
+			batch[i] = *w
+			if !w.Create {
+				continue
+			}
+			id, err := m.storage.NextServicePointID(ctx)
+			if err != nil {
+				return err
+			}
+			batch[i].ID = strconv.FormatInt(id, 10)
+		}
+
+		servicePoints, err := m.storage.ImportServicePoints(ctx, batch)
+		var rowErr *models.RowError
+		if errors.As(err, &rowErr) {
+			if batch[rowErr.Row-1].Create && errors.Is(err, models.ErrAlreadyExists) && attempt < createRetries {
+				continue
+			}
+			failRow(&result.Rows[rowErr.Row-1], rowErr.Err)
+		}
+		if err != nil {
+			return err
+		}
+
+		for i := range servicePoints {
+			doneRow(&result.Rows[i], &servicePoints[i])
+		}
+		return nil
+	}
+}
+
+// importEach makes the writes one by one, reporting the failing ones.
+func (m *SPService) importEach(ctx context.Context, result *models.ImportResult, writes []*models.ServicePointWrite) {
+	for i, w := range writes {
+		if w == nil {
+			continue
+		}
+
+		var sp *models.ServicePoint
+		var err error
+		if w.Create {
+			sp, err = m.create(ctx, w.ServicePoint)
+		} else {
+			sp, err = m.storage.UpsertServicePoint(ctx, w.ID, w.ServicePoint, 0)
+		}
+		if err != nil {
+			failRow(&result.Rows[i], err)
+			continue
+		}
+		doneRow(&result.Rows[i], sp)
+	}
+}
+
+func failRow(row *models.ImportRowResult, err error) {
+	row.Action = ""
+	row.Error = err.Error()
+
+	var verr *models.ValidationError
+	if errors.As(err, &verr) {
+		row.Fields = verr.Fields
+	}
+}
+
+// doneRow records the service point a row wrote. Only a new service point is
+// at its first version.
+func doneRow(row *models.ImportRowResult, sp *models.ServicePoint) {
+	row.ID = sp.ID
+	row.Action = models.AuditActionUpdate
+	if sp.Version == 1 {
+		row.Action = models.AuditActionCreate
+	}
+}
+
+// ExportSP returns the service points of the organisation as import rows, so
+// an export can be edited and imported again.
+func (m *SPService) ExportSP(ctx context.Context) ([]models.ImportRow, error) {
+	servicePoints, err := m.storage.ListServicePoints(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]models.ImportRow, 0, len(servicePoints))
+	for _, sp := range servicePoints {
+		rows = append(rows, models.ImportRow{
+			ID:               sp.ID,
+			Name:             sp.Name,
+			ShortName:        sp.ShortName,
+			OfficeNumber:     sp.OfficeNumber,
+			OfficeID:         sp.OfficeID,
+			MaxQueueLength:   sp.MaxQueueLength,
+			DailyTicketQuota: sp.DailyTicketQuota,
+		})
+	}
+	return rows, nil
+}
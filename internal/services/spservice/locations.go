@@ -0,0 +1,156 @@
+package spservice
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// Column sizes of the location tables.
+const (
+	maxAddressLength    = 255
+	maxDirectionsLength = 500
+)
+
+func (m *SPService) CreateBuilding(ctx context.Context, building models.NewBuildingRequest) (*models.Building, error) {
+	building.Name = strings.TrimSpace(building.Name)
+	building.Address = strings.TrimSpace(building.Address)
+	building.Directions = strings.TrimSpace(building.Directions)
+
+	var verr models.ValidationError
+	checkLength(&verr, "name", building.Name, maxNameLength)
+	checkPrintable(&verr, "name", building.Name)
+	checkOptional(&verr, "address", building.Address, maxAddressLength)
+	checkOptional(&verr, "directions", building.Directions, maxDirectionsLength)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	return m.storage.CreateBuilding(ctx, building)
+}
+
+func (m *SPService) GetBuilding(ctx context.Context, id string) (*models.Building, error) {
+	return m.storage.GetBuilding(ctx, id)
+}
+
+func (m *SPService) ListBuildings(ctx context.Context) ([]models.Building, error) {
+	return m.storage.ListBuildings(ctx)
+}
+
+// CreateFloor adds a floor to a building. Levels are unique per building.
+func (m *SPService) CreateFloor(ctx context.Context, buildingID string, floor models.NewFloorRequest) (*models.Floor, error) {
+	floor.Name = strings.TrimSpace(floor.Name)
+	floor.Directions = strings.TrimSpace(floor.Directions)
+
+	var verr models.ValidationError
+	checkLength(&verr, "name", floor.Name, maxNameLength)
+	checkPrintable(&verr, "name", floor.Name)
+	checkOptional(&verr, "directions", floor.Directions, maxDirectionsLength)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.storage.GetBuilding(ctx, buildingID); err != nil {
+		return nil, err
+	}
+	return m.storage.CreateFloor(ctx, buildingID, floor)
+}
+
+func (m *SPService) GetFloor(ctx context.Context, id string) (*models.Floor, error) {
+	return m.storage.GetFloor(ctx, id)
+}
+
+// ListFloors returns the floors of a building from the lowest level up.
+func (m *SPService) ListFloors(ctx context.Context, buildingID string) ([]models.Floor, error) {
+	if _, err := m.storage.GetBuilding(ctx, buildingID); err != nil {
+		return nil, err
+	}
+	return m.storage.ListFloors(ctx, buildingID)
+}
+
+// CreateOffice adds an office to a floor. Numbers are unique per floor and
+// must fit the office number of a service point.
+func (m *SPService) CreateOffice(ctx context.Context, floorID string, office models.NewOfficeRequest) (*models.Office, error) {
+	office.Number = strings.TrimSpace(office.Number)
+	office.Name = strings.TrimSpace(office.Name)
+	office.Directions = strings.TrimSpace(office.Directions)
+
+	var verr models.ValidationError
+	checkLength(&verr, "number", office.Number, maxOfficeNumberLength)
+	checkPrintable(&verr, "number", office.Number)
+	checkOptional(&verr, "name", office.Name, maxNameLength)
+	checkOptional(&verr, "directions", office.Directions, maxDirectionsLength)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.storage.GetFloor(ctx, floorID); err != nil {
+		return nil, err
+	}
+	return m.storage.CreateOffice(ctx, floorID, office)
+}
+
+// ListOffices returns the offices on a floor ordered by number.
+func (m *SPService) ListOffices(ctx context.Context, floorID string) ([]models.Office, error) {
+	if _, err := m.storage.GetFloor(ctx, floorID); err != nil {
+		return nil, err
+	}
+	return m.storage.ListOffices(ctx, floorID)
+}
+
+// GetLocation returns an office with the floor and building it is on.
+func (m *SPService) GetLocation(ctx context.Context, officeID string) (*models.Location, error) {
+	return m.storage.GetLocation(ctx, officeID)
+}
+
+// placeServicePoint takes the office number of a service point from the
+// office it is placed in. An office number that names another office is
+// reported rather than silently replaced.
+func (m *SPService) placeServicePoint(ctx context.Context, sp models.NewServicePointRequest) (models.NewServicePointRequest, error) {
+	if sp.OfficeID <= 0 {
+		return sp, nil
+	}
+
+	location, err := m.storage.GetLocation(ctx, strconv.FormatInt(sp.OfficeID, 10))
+	if err != nil {
+		return sp, err
+	}
+
+	number := strings.TrimSpace(sp.OfficeNumber)
+	if number != "" && number != location.Office.Number {
+		var verr models.ValidationError
+		verr.Add("officeNumber", "must be %q, the number of office %d", location.Office.Number, sp.OfficeID)
+		return sp, verr.Err()
+	}
+	sp.OfficeNumber = location.Office.Number
+	return sp, nil
+}
+
+// checkOptional is checkLength and checkPrintable for a value that may be
+// left empty.
+func checkOptional(verr *models.ValidationError, field, value string, max int) {
+	if value == "" {
+		return
+	}
+	if checkLength(verr, field, value, max) {
+		checkPrintable(verr, field, value)
+	}
+}
+
+// location returns where the office of a service point is, for the message
+// directing visitors to it. It is nil if the service point is in no office
+// or the office cannot be read; the call goes ahead either way.
+func (m *SPService) location(ctx context.Context, sp *models.ServicePoint) *models.Location {
+	if sp.OfficeID == 0 {
+		return nil
+	}
+	location, err := m.storage.GetLocation(ctx, strconv.FormatInt(sp.OfficeID, 10))
+	if err != nil {
+		log.Printf("failed to get location of office %d: %s", sp.OfficeID, err)
+		return nil
+	}
+	return location
+}
@@ -22,6 +22,54 @@ func (_m *MockSPClient) EXPECT() *MockSPClient_Expecter {
 	return &MockSPClient_Expecter{mock: &_m.Mock}
 }
 
+// Cancel provides a mock function with given fields: ctx, id, ticket
+func (_m *MockSPClient) Cancel(ctx context.Context, id string, ticket string) error {
+	ret := _m.Called(ctx, id, ticket)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cancel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, ticket)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSPClient_Cancel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Cancel'
+type MockSPClient_Cancel_Call struct {
+	*mock.Call
+}
+
+// Cancel is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - ticket string
+func (_e *MockSPClient_Expecter) Cancel(ctx interface{}, id interface{}, ticket interface{}) *MockSPClient_Cancel_Call {
+	return &MockSPClient_Cancel_Call{Call: _e.mock.On("Cancel", ctx, id, ticket)}
+}
+
+func (_c *MockSPClient_Cancel_Call) Run(run func(ctx context.Context, id string, ticket string)) *MockSPClient_Cancel_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPClient_Cancel_Call) Return(_a0 error) *MockSPClient_Cancel_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSPClient_Cancel_Call) RunAndReturn(run func(context.Context, string, string) error) *MockSPClient_Cancel_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Dequeue provides a mock function with given fields: ctx, id
 func (_m *MockSPClient) Dequeue(ctx context.Context, id string) (*models.Ticket, error) {
 	ret := _m.Called(ctx, id)
@@ -141,6 +189,112 @@ func (_c *MockSPClient_Enqueue_Call) RunAndReturn(run func(context.Context, stri
 	return _c
 }
 
+// Insert provides a mock function with given fields: ctx, id, ticket, front
+func (_m *MockSPClient) Insert(ctx context.Context, id string, ticket string, front bool) error {
+	ret := _m.Called(ctx, id, ticket, front)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Insert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) error); ok {
+		r0 = rf(ctx, id, ticket, front)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSPClient_Insert_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Insert'
+type MockSPClient_Insert_Call struct {
+	*mock.Call
+}
+
+// Insert is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - ticket string
+//   - front bool
+func (_e *MockSPClient_Expecter) Insert(ctx interface{}, id interface{}, ticket interface{}, front interface{}) *MockSPClient_Insert_Call {
+	return &MockSPClient_Insert_Call{Call: _e.mock.On("Insert", ctx, id, ticket, front)}
+}
+
+func (_c *MockSPClient_Insert_Call) Run(run func(ctx context.Context, id string, ticket string, front bool)) *MockSPClient_Insert_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *MockSPClient_Insert_Call) Return(_a0 error) *MockSPClient_Insert_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSPClient_Insert_Call) RunAndReturn(run func(context.Context, string, string, bool) error) *MockSPClient_Insert_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Length provides a mock function with given fields: ctx, id
+func (_m *MockSPClient) Length(ctx context.Context, id string) (int, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Length")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPClient_Length_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Length'
+type MockSPClient_Length_Call struct {
+	*mock.Call
+}
+
+// Length is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockSPClient_Expecter) Length(ctx interface{}, id interface{}) *MockSPClient_Length_Call {
+	return &MockSPClient_Length_Call{Call: _e.mock.On("Length", ctx, id)}
+}
+
+func (_c *MockSPClient_Length_Call) Run(run func(ctx context.Context, id string)) *MockSPClient_Length_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPClient_Length_Call) Return(_a0 int, _a1 error) *MockSPClient_Length_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPClient_Length_Call) RunAndReturn(run func(context.Context, string) (int, error)) *MockSPClient_Length_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockSPClient creates a new instance of MockSPClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockSPClient(t interface {
@@ -5,6 +5,7 @@ package spservice
 import (
 	context "context"
 
+	models "github.com/snnus/mainservice/internal/models"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -21,17 +22,64 @@ func (_m *MockSPProducer) EXPECT() *MockSPProducer_Expecter {
 	return &MockSPProducer_Expecter{mock: &_m.Mock}
 }
 
-// PublishTicket provides a mock function with given fields: ctx, ticket, officeNumber
-func (_m *MockSPProducer) PublishTicket(ctx context.Context, ticket string, officeNumber string) error {
-	ret := _m.Called(ctx, ticket, officeNumber)
+// PublishStatus provides a mock function with given fields: ctx, sp
+func (_m *MockSPProducer) PublishStatus(ctx context.Context, sp models.ServicePoint) error {
+	ret := _m.Called(ctx, sp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ServicePoint) error); ok {
+		r0 = rf(ctx, sp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSPProducer_PublishStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishStatus'
+type MockSPProducer_PublishStatus_Call struct {
+	*mock.Call
+}
+
+// PublishStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sp models.ServicePoint
+func (_e *MockSPProducer_Expecter) PublishStatus(ctx interface{}, sp interface{}) *MockSPProducer_PublishStatus_Call {
+	return &MockSPProducer_PublishStatus_Call{Call: _e.mock.On("PublishStatus", ctx, sp)}
+}
+
+func (_c *MockSPProducer_PublishStatus_Call) Run(run func(ctx context.Context, sp models.ServicePoint)) *MockSPProducer_PublishStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.ServicePoint))
+	})
+	return _c
+}
+
+func (_c *MockSPProducer_PublishStatus_Call) Return(_a0 error) *MockSPProducer_PublishStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSPProducer_PublishStatus_Call) RunAndReturn(run func(context.Context, models.ServicePoint) error) *MockSPProducer_PublishStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PublishTicket provides a mock function with given fields: ctx, ticket, officeNumber, deskNumber, location
+func (_m *MockSPProducer) PublishTicket(ctx context.Context, ticket string, officeNumber string, deskNumber string, location *models.Location) error {
+	ret := _m.Called(ctx, ticket, officeNumber, deskNumber, location)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PublishTicket")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = rf(ctx, ticket, officeNumber)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *models.Location) error); ok {
+		r0 = rf(ctx, ticket, officeNumber, deskNumber, location)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -48,13 +96,15 @@ type MockSPProducer_PublishTicket_Call struct {
 //   - ctx context.Context
 //   - ticket string
 //   - officeNumber string
-func (_e *MockSPProducer_Expecter) PublishTicket(ctx interface{}, ticket interface{}, officeNumber interface{}) *MockSPProducer_PublishTicket_Call {
-	return &MockSPProducer_PublishTicket_Call{Call: _e.mock.On("PublishTicket", ctx, ticket, officeNumber)}
+//   - deskNumber string
+//   - location *models.Location
+func (_e *MockSPProducer_Expecter) PublishTicket(ctx interface{}, ticket interface{}, officeNumber interface{}, deskNumber interface{}, location interface{}) *MockSPProducer_PublishTicket_Call {
+	return &MockSPProducer_PublishTicket_Call{Call: _e.mock.On("PublishTicket", ctx, ticket, officeNumber, deskNumber, location)}
 }
 
-func (_c *MockSPProducer_PublishTicket_Call) Run(run func(ctx context.Context, ticket string, officeNumber string)) *MockSPProducer_PublishTicket_Call {
+func (_c *MockSPProducer_PublishTicket_Call) Run(run func(ctx context.Context, ticket string, officeNumber string, deskNumber string, location *models.Location)) *MockSPProducer_PublishTicket_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(*models.Location))
 	})
 	return _c
 }
@@ -64,7 +114,57 @@ func (_c *MockSPProducer_PublishTicket_Call) Return(_a0 error) *MockSPProducer_P
 	return _c
 }
 
-func (_c *MockSPProducer_PublishTicket_Call) RunAndReturn(run func(context.Context, string, string) error) *MockSPProducer_PublishTicket_Call {
+func (_c *MockSPProducer_PublishTicket_Call) RunAndReturn(run func(context.Context, string, string, string, *models.Location) error) *MockSPProducer_PublishTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PublishTicketEvent provides a mock function with given fields: ctx, event, ticket, officeNumber, deskNumber
+func (_m *MockSPProducer) PublishTicketEvent(ctx context.Context, event string, ticket string, officeNumber string, deskNumber string) error {
+	ret := _m.Called(ctx, event, ticket, officeNumber, deskNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishTicketEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, event, ticket, officeNumber, deskNumber)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSPProducer_PublishTicketEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishTicketEvent'
+type MockSPProducer_PublishTicketEvent_Call struct {
+	*mock.Call
+}
+
+// PublishTicketEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event string
+//   - ticket string
+//   - officeNumber string
+//   - deskNumber string
+func (_e *MockSPProducer_Expecter) PublishTicketEvent(ctx interface{}, event interface{}, ticket interface{}, officeNumber interface{}, deskNumber interface{}) *MockSPProducer_PublishTicketEvent_Call {
+	return &MockSPProducer_PublishTicketEvent_Call{Call: _e.mock.On("PublishTicketEvent", ctx, event, ticket, officeNumber, deskNumber)}
+}
+
+func (_c *MockSPProducer_PublishTicketEvent_Call) Run(run func(ctx context.Context, event string, ticket string, officeNumber string, deskNumber string)) *MockSPProducer_PublishTicketEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPProducer_PublishTicketEvent_Call) Return(_a0 error) *MockSPProducer_PublishTicketEvent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSPProducer_PublishTicketEvent_Call) RunAndReturn(run func(context.Context, string, string, string, string) error) *MockSPProducer_PublishTicketEvent_Call {
 	_c.Call.Return(run)
 	return _c
 }
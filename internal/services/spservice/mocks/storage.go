@@ -7,44 +7,3235 @@ import (
 
 	models "github.com/snnus/mainservice/internal/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
-// MockSPStorage is an autogenerated mock type for the SPStorage type
-type MockSPStorage struct {
-	mock.Mock
+// MockSPStorage is an autogenerated mock type for the SPStorage type
+type MockSPStorage struct {
+	mock.Mock
+}
+
+type MockSPStorage_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSPStorage) EXPECT() *MockSPStorage_Expecter {
+	return &MockSPStorage_Expecter{mock: &_m.Mock}
+}
+
+// BookAppointment provides a mock function with given fields: ctx, spID, appointment
+func (_m *MockSPStorage) BookAppointment(ctx context.Context, spID string, appointment models.Appointment) (*models.Appointment, error) {
+	ret := _m.Called(ctx, spID, appointment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BookAppointment")
+	}
+
+	var r0 *models.Appointment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.Appointment) (*models.Appointment, error)); ok {
+		return rf(ctx, spID, appointment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.Appointment) *models.Appointment); ok {
+		r0 = rf(ctx, spID, appointment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Appointment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.Appointment) error); ok {
+		r1 = rf(ctx, spID, appointment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_BookAppointment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BookAppointment'
+type MockSPStorage_BookAppointment_Call struct {
+	*mock.Call
+}
+
+// BookAppointment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - appointment models.Appointment
+func (_e *MockSPStorage_Expecter) BookAppointment(ctx interface{}, spID interface{}, appointment interface{}) *MockSPStorage_BookAppointment_Call {
+	return &MockSPStorage_BookAppointment_Call{Call: _e.mock.On("BookAppointment", ctx, spID, appointment)}
+}
+
+func (_c *MockSPStorage_BookAppointment_Call) Run(run func(ctx context.Context, spID string, appointment models.Appointment)) *MockSPStorage_BookAppointment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.Appointment))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_BookAppointment_Call) Return(_a0 *models.Appointment, _a1 error) *MockSPStorage_BookAppointment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_BookAppointment_Call) RunAndReturn(run func(context.Context, string, models.Appointment) (*models.Appointment, error)) *MockSPStorage_BookAppointment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CallTicket provides a mock function with given fields: ctx, spID, code, call
+func (_m *MockSPStorage) CallTicket(ctx context.Context, spID string, code string, call models.TicketCall) (*models.TicketRecord, error) {
+	ret := _m.Called(ctx, spID, code, call)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CallTicket")
+	}
+
+	var r0 *models.TicketRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.TicketCall) (*models.TicketRecord, error)); ok {
+		return rf(ctx, spID, code, call)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.TicketCall) *models.TicketRecord); ok {
+		r0 = rf(ctx, spID, code, call)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TicketRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, models.TicketCall) error); ok {
+		r1 = rf(ctx, spID, code, call)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CallTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CallTicket'
+type MockSPStorage_CallTicket_Call struct {
+	*mock.Call
+}
+
+// CallTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - code string
+//   - call models.TicketCall
+func (_e *MockSPStorage_Expecter) CallTicket(ctx interface{}, spID interface{}, code interface{}, call interface{}) *MockSPStorage_CallTicket_Call {
+	return &MockSPStorage_CallTicket_Call{Call: _e.mock.On("CallTicket", ctx, spID, code, call)}
+}
+
+func (_c *MockSPStorage_CallTicket_Call) Run(run func(ctx context.Context, spID string, code string, call models.TicketCall)) *MockSPStorage_CallTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(models.TicketCall))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CallTicket_Call) Return(_a0 *models.TicketRecord, _a1 error) *MockSPStorage_CallTicket_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CallTicket_Call) RunAndReturn(run func(context.Context, string, string, models.TicketCall) (*models.TicketRecord, error)) *MockSPStorage_CallTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClaimCategoryTicket provides a mock function with given fields: ctx, categoryID, code, spID, call
+func (_m *MockSPStorage) ClaimCategoryTicket(ctx context.Context, categoryID int64, code string, spID string, call models.TicketCall) (*models.TicketRecord, error) {
+	ret := _m.Called(ctx, categoryID, code, spID, call)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimCategoryTicket")
+	}
+
+	var r0 *models.TicketRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, models.TicketCall) (*models.TicketRecord, error)); ok {
+		return rf(ctx, categoryID, code, spID, call)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, models.TicketCall) *models.TicketRecord); ok {
+		r0 = rf(ctx, categoryID, code, spID, call)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TicketRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string, models.TicketCall) error); ok {
+		r1 = rf(ctx, categoryID, code, spID, call)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ClaimCategoryTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimCategoryTicket'
+type MockSPStorage_ClaimCategoryTicket_Call struct {
+	*mock.Call
+}
+
+// ClaimCategoryTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - categoryID int64
+//   - code string
+//   - spID string
+//   - call models.TicketCall
+func (_e *MockSPStorage_Expecter) ClaimCategoryTicket(ctx interface{}, categoryID interface{}, code interface{}, spID interface{}, call interface{}) *MockSPStorage_ClaimCategoryTicket_Call {
+	return &MockSPStorage_ClaimCategoryTicket_Call{Call: _e.mock.On("ClaimCategoryTicket", ctx, categoryID, code, spID, call)}
+}
+
+func (_c *MockSPStorage_ClaimCategoryTicket_Call) Run(run func(ctx context.Context, categoryID int64, code string, spID string, call models.TicketCall)) *MockSPStorage_ClaimCategoryTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(string), args[4].(models.TicketCall))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ClaimCategoryTicket_Call) Return(_a0 *models.TicketRecord, _a1 error) *MockSPStorage_ClaimCategoryTicket_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ClaimCategoryTicket_Call) RunAndReturn(run func(context.Context, int64, string, string, models.TicketCall) (*models.TicketRecord, error)) *MockSPStorage_ClaimCategoryTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountIssuedTickets provides a mock function with given fields: ctx, spID, since
+func (_m *MockSPStorage) CountIssuedTickets(ctx context.Context, spID string, since time.Time) (int, error) {
+	ret := _m.Called(ctx, spID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountIssuedTickets")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int, error)); ok {
+		return rf(ctx, spID, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int); ok {
+		r0 = rf(ctx, spID, since)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, spID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CountIssuedTickets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountIssuedTickets'
+type MockSPStorage_CountIssuedTickets_Call struct {
+	*mock.Call
+}
+
+// CountIssuedTickets is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - since time.Time
+func (_e *MockSPStorage_Expecter) CountIssuedTickets(ctx interface{}, spID interface{}, since interface{}) *MockSPStorage_CountIssuedTickets_Call {
+	return &MockSPStorage_CountIssuedTickets_Call{Call: _e.mock.On("CountIssuedTickets", ctx, spID, since)}
+}
+
+func (_c *MockSPStorage_CountIssuedTickets_Call) Run(run func(ctx context.Context, spID string, since time.Time)) *MockSPStorage_CountIssuedTickets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CountIssuedTickets_Call) Return(_a0 int, _a1 error) *MockSPStorage_CountIssuedTickets_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CountIssuedTickets_Call) RunAndReturn(run func(context.Context, string, time.Time) (int, error)) *MockSPStorage_CountIssuedTickets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateBuilding provides a mock function with given fields: ctx, building
+func (_m *MockSPStorage) CreateBuilding(ctx context.Context, building models.NewBuildingRequest) (*models.Building, error) {
+	ret := _m.Called(ctx, building)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBuilding")
+	}
+
+	var r0 *models.Building
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.NewBuildingRequest) (*models.Building, error)); ok {
+		return rf(ctx, building)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.NewBuildingRequest) *models.Building); ok {
+		r0 = rf(ctx, building)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Building)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.NewBuildingRequest) error); ok {
+		r1 = rf(ctx, building)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CreateBuilding_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBuilding'
+type MockSPStorage_CreateBuilding_Call struct {
+	*mock.Call
+}
+
+// CreateBuilding is a helper method to define mock.On call
+//   - ctx context.Context
+//   - building models.NewBuildingRequest
+func (_e *MockSPStorage_Expecter) CreateBuilding(ctx interface{}, building interface{}) *MockSPStorage_CreateBuilding_Call {
+	return &MockSPStorage_CreateBuilding_Call{Call: _e.mock.On("CreateBuilding", ctx, building)}
+}
+
+func (_c *MockSPStorage_CreateBuilding_Call) Run(run func(ctx context.Context, building models.NewBuildingRequest)) *MockSPStorage_CreateBuilding_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.NewBuildingRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CreateBuilding_Call) Return(_a0 *models.Building, _a1 error) *MockSPStorage_CreateBuilding_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CreateBuilding_Call) RunAndReturn(run func(context.Context, models.NewBuildingRequest) (*models.Building, error)) *MockSPStorage_CreateBuilding_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateCategory provides a mock function with given fields: ctx, category
+func (_m *MockSPStorage) CreateCategory(ctx context.Context, category models.NewCategoryRequest) (*models.Category, error) {
+	ret := _m.Called(ctx, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCategory")
+	}
+
+	var r0 *models.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.NewCategoryRequest) (*models.Category, error)); ok {
+		return rf(ctx, category)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.NewCategoryRequest) *models.Category); ok {
+		r0 = rf(ctx, category)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.NewCategoryRequest) error); ok {
+		r1 = rf(ctx, category)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CreateCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCategory'
+type MockSPStorage_CreateCategory_Call struct {
+	*mock.Call
+}
+
+// CreateCategory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - category models.NewCategoryRequest
+func (_e *MockSPStorage_Expecter) CreateCategory(ctx interface{}, category interface{}) *MockSPStorage_CreateCategory_Call {
+	return &MockSPStorage_CreateCategory_Call{Call: _e.mock.On("CreateCategory", ctx, category)}
+}
+
+func (_c *MockSPStorage_CreateCategory_Call) Run(run func(ctx context.Context, category models.NewCategoryRequest)) *MockSPStorage_CreateCategory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.NewCategoryRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CreateCategory_Call) Return(_a0 *models.Category, _a1 error) *MockSPStorage_CreateCategory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CreateCategory_Call) RunAndReturn(run func(context.Context, models.NewCategoryRequest) (*models.Category, error)) *MockSPStorage_CreateCategory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateCategoryTicket provides a mock function with given fields: ctx, categoryID, code
+func (_m *MockSPStorage) CreateCategoryTicket(ctx context.Context, categoryID int64, code string) (*models.CategoryTicket, error) {
+	ret := _m.Called(ctx, categoryID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCategoryTicket")
+	}
+
+	var r0 *models.CategoryTicket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (*models.CategoryTicket, error)); ok {
+		return rf(ctx, categoryID, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *models.CategoryTicket); ok {
+		r0 = rf(ctx, categoryID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CategoryTicket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, categoryID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CreateCategoryTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCategoryTicket'
+type MockSPStorage_CreateCategoryTicket_Call struct {
+	*mock.Call
+}
+
+// CreateCategoryTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - categoryID int64
+//   - code string
+func (_e *MockSPStorage_Expecter) CreateCategoryTicket(ctx interface{}, categoryID interface{}, code interface{}) *MockSPStorage_CreateCategoryTicket_Call {
+	return &MockSPStorage_CreateCategoryTicket_Call{Call: _e.mock.On("CreateCategoryTicket", ctx, categoryID, code)}
+}
+
+func (_c *MockSPStorage_CreateCategoryTicket_Call) Run(run func(ctx context.Context, categoryID int64, code string)) *MockSPStorage_CreateCategoryTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CreateCategoryTicket_Call) Return(_a0 *models.CategoryTicket, _a1 error) *MockSPStorage_CreateCategoryTicket_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CreateCategoryTicket_Call) RunAndReturn(run func(context.Context, int64, string) (*models.CategoryTicket, error)) *MockSPStorage_CreateCategoryTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateFloor provides a mock function with given fields: ctx, buildingID, floor
+func (_m *MockSPStorage) CreateFloor(ctx context.Context, buildingID string, floor models.NewFloorRequest) (*models.Floor, error) {
+	ret := _m.Called(ctx, buildingID, floor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFloor")
+	}
+
+	var r0 *models.Floor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewFloorRequest) (*models.Floor, error)); ok {
+		return rf(ctx, buildingID, floor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewFloorRequest) *models.Floor); ok {
+		r0 = rf(ctx, buildingID, floor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Floor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.NewFloorRequest) error); ok {
+		r1 = rf(ctx, buildingID, floor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CreateFloor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateFloor'
+type MockSPStorage_CreateFloor_Call struct {
+	*mock.Call
+}
+
+// CreateFloor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - buildingID string
+//   - floor models.NewFloorRequest
+func (_e *MockSPStorage_Expecter) CreateFloor(ctx interface{}, buildingID interface{}, floor interface{}) *MockSPStorage_CreateFloor_Call {
+	return &MockSPStorage_CreateFloor_Call{Call: _e.mock.On("CreateFloor", ctx, buildingID, floor)}
+}
+
+func (_c *MockSPStorage_CreateFloor_Call) Run(run func(ctx context.Context, buildingID string, floor models.NewFloorRequest)) *MockSPStorage_CreateFloor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.NewFloorRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CreateFloor_Call) Return(_a0 *models.Floor, _a1 error) *MockSPStorage_CreateFloor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CreateFloor_Call) RunAndReturn(run func(context.Context, string, models.NewFloorRequest) (*models.Floor, error)) *MockSPStorage_CreateFloor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateOffice provides a mock function with given fields: ctx, floorID, office
+func (_m *MockSPStorage) CreateOffice(ctx context.Context, floorID string, office models.NewOfficeRequest) (*models.Office, error) {
+	ret := _m.Called(ctx, floorID, office)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOffice")
+	}
+
+	var r0 *models.Office
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewOfficeRequest) (*models.Office, error)); ok {
+		return rf(ctx, floorID, office)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewOfficeRequest) *models.Office); ok {
+		r0 = rf(ctx, floorID, office)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Office)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.NewOfficeRequest) error); ok {
+		r1 = rf(ctx, floorID, office)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CreateOffice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOffice'
+type MockSPStorage_CreateOffice_Call struct {
+	*mock.Call
+}
+
+// CreateOffice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - floorID string
+//   - office models.NewOfficeRequest
+func (_e *MockSPStorage_Expecter) CreateOffice(ctx interface{}, floorID interface{}, office interface{}) *MockSPStorage_CreateOffice_Call {
+	return &MockSPStorage_CreateOffice_Call{Call: _e.mock.On("CreateOffice", ctx, floorID, office)}
+}
+
+func (_c *MockSPStorage_CreateOffice_Call) Run(run func(ctx context.Context, floorID string, office models.NewOfficeRequest)) *MockSPStorage_CreateOffice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.NewOfficeRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CreateOffice_Call) Return(_a0 *models.Office, _a1 error) *MockSPStorage_CreateOffice_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CreateOffice_Call) RunAndReturn(run func(context.Context, string, models.NewOfficeRequest) (*models.Office, error)) *MockSPStorage_CreateOffice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateOperator provides a mock function with given fields: ctx, operator
+func (_m *MockSPStorage) CreateOperator(ctx context.Context, operator models.NewOperatorRequest) (*models.Operator, error) {
+	ret := _m.Called(ctx, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOperator")
+	}
+
+	var r0 *models.Operator
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.NewOperatorRequest) (*models.Operator, error)); ok {
+		return rf(ctx, operator)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.NewOperatorRequest) *models.Operator); ok {
+		r0 = rf(ctx, operator)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Operator)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.NewOperatorRequest) error); ok {
+		r1 = rf(ctx, operator)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CreateOperator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOperator'
+type MockSPStorage_CreateOperator_Call struct {
+	*mock.Call
+}
+
+// CreateOperator is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator models.NewOperatorRequest
+func (_e *MockSPStorage_Expecter) CreateOperator(ctx interface{}, operator interface{}) *MockSPStorage_CreateOperator_Call {
+	return &MockSPStorage_CreateOperator_Call{Call: _e.mock.On("CreateOperator", ctx, operator)}
+}
+
+func (_c *MockSPStorage_CreateOperator_Call) Run(run func(ctx context.Context, operator models.NewOperatorRequest)) *MockSPStorage_CreateOperator_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.NewOperatorRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CreateOperator_Call) Return(_a0 *models.Operator, _a1 error) *MockSPStorage_CreateOperator_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CreateOperator_Call) RunAndReturn(run func(context.Context, models.NewOperatorRequest) (*models.Operator, error)) *MockSPStorage_CreateOperator_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateOrganisation provides a mock function with given fields: ctx, organisation
+func (_m *MockSPStorage) CreateOrganisation(ctx context.Context, organisation models.NewOrganisationRequest) (*models.Organisation, error) {
+	ret := _m.Called(ctx, organisation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrganisation")
+	}
+
+	var r0 *models.Organisation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.NewOrganisationRequest) (*models.Organisation, error)); ok {
+		return rf(ctx, organisation)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.NewOrganisationRequest) *models.Organisation); ok {
+		r0 = rf(ctx, organisation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Organisation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.NewOrganisationRequest) error); ok {
+		r1 = rf(ctx, organisation)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CreateOrganisation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOrganisation'
+type MockSPStorage_CreateOrganisation_Call struct {
+	*mock.Call
+}
+
+// CreateOrganisation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - organisation models.NewOrganisationRequest
+func (_e *MockSPStorage_Expecter) CreateOrganisation(ctx interface{}, organisation interface{}) *MockSPStorage_CreateOrganisation_Call {
+	return &MockSPStorage_CreateOrganisation_Call{Call: _e.mock.On("CreateOrganisation", ctx, organisation)}
+}
+
+func (_c *MockSPStorage_CreateOrganisation_Call) Run(run func(ctx context.Context, organisation models.NewOrganisationRequest)) *MockSPStorage_CreateOrganisation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.NewOrganisationRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CreateOrganisation_Call) Return(_a0 *models.Organisation, _a1 error) *MockSPStorage_CreateOrganisation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CreateOrganisation_Call) RunAndReturn(run func(context.Context, models.NewOrganisationRequest) (*models.Organisation, error)) *MockSPStorage_CreateOrganisation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateServicePoint provides a mock function with given fields: ctx, id, sp
+func (_m *MockSPStorage) CreateServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest) (*models.ServicePoint, error) {
+	ret := _m.Called(ctx, id, sp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateServicePoint")
+	}
+
+	var r0 *models.ServicePoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewServicePointRequest) (*models.ServicePoint, error)); ok {
+		return rf(ctx, id, sp)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewServicePointRequest) *models.ServicePoint); ok {
+		r0 = rf(ctx, id, sp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ServicePoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.NewServicePointRequest) error); ok {
+		r1 = rf(ctx, id, sp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CreateServicePoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateServicePoint'
+type MockSPStorage_CreateServicePoint_Call struct {
+	*mock.Call
+}
+
+// CreateServicePoint is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - sp models.NewServicePointRequest
+func (_e *MockSPStorage_Expecter) CreateServicePoint(ctx interface{}, id interface{}, sp interface{}) *MockSPStorage_CreateServicePoint_Call {
+	return &MockSPStorage_CreateServicePoint_Call{Call: _e.mock.On("CreateServicePoint", ctx, id, sp)}
+}
+
+func (_c *MockSPStorage_CreateServicePoint_Call) Run(run func(ctx context.Context, id string, sp models.NewServicePointRequest)) *MockSPStorage_CreateServicePoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.NewServicePointRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CreateServicePoint_Call) Return(_a0 *models.ServicePoint, _a1 error) *MockSPStorage_CreateServicePoint_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CreateServicePoint_Call) RunAndReturn(run func(context.Context, string, models.NewServicePointRequest) (*models.ServicePoint, error)) *MockSPStorage_CreateServicePoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTicket provides a mock function with given fields: ctx, spID, code, class
+func (_m *MockSPStorage) CreateTicket(ctx context.Context, spID string, code string, class string) (*models.TicketRecord, error) {
+	ret := _m.Called(ctx, spID, code, class)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTicket")
+	}
+
+	var r0 *models.TicketRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*models.TicketRecord, error)); ok {
+		return rf(ctx, spID, code, class)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *models.TicketRecord); ok {
+		r0 = rf(ctx, spID, code, class)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TicketRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, spID, code, class)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CreateTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTicket'
+type MockSPStorage_CreateTicket_Call struct {
+	*mock.Call
+}
+
+// CreateTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - code string
+//   - class string
+func (_e *MockSPStorage_Expecter) CreateTicket(ctx interface{}, spID interface{}, code interface{}, class interface{}) *MockSPStorage_CreateTicket_Call {
+	return &MockSPStorage_CreateTicket_Call{Call: _e.mock.On("CreateTicket", ctx, spID, code, class)}
+}
+
+func (_c *MockSPStorage_CreateTicket_Call) Run(run func(ctx context.Context, spID string, code string, class string)) *MockSPStorage_CreateTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CreateTicket_Call) Return(_a0 *models.TicketRecord, _a1 error) *MockSPStorage_CreateTicket_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CreateTicket_Call) RunAndReturn(run func(context.Context, string, string, string) (*models.TicketRecord, error)) *MockSPStorage_CreateTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTransferredTicket provides a mock function with given fields: ctx, spID, code, class, from, front
+func (_m *MockSPStorage) CreateTransferredTicket(ctx context.Context, spID string, code string, class string, from int64, front bool) (*models.TicketRecord, error) {
+	ret := _m.Called(ctx, spID, code, class, from, front)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTransferredTicket")
+	}
+
+	var r0 *models.TicketRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int64, bool) (*models.TicketRecord, error)); ok {
+		return rf(ctx, spID, code, class, from, front)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int64, bool) *models.TicketRecord); ok {
+		r0 = rf(ctx, spID, code, class, from, front)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TicketRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, int64, bool) error); ok {
+		r1 = rf(ctx, spID, code, class, from, front)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_CreateTransferredTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTransferredTicket'
+type MockSPStorage_CreateTransferredTicket_Call struct {
+	*mock.Call
+}
+
+// CreateTransferredTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - code string
+//   - class string
+//   - from int64
+//   - front bool
+func (_e *MockSPStorage_Expecter) CreateTransferredTicket(ctx interface{}, spID interface{}, code interface{}, class interface{}, from interface{}, front interface{}) *MockSPStorage_CreateTransferredTicket_Call {
+	return &MockSPStorage_CreateTransferredTicket_Call{Call: _e.mock.On("CreateTransferredTicket", ctx, spID, code, class, from, front)}
+}
+
+func (_c *MockSPStorage_CreateTransferredTicket_Call) Run(run func(ctx context.Context, spID string, code string, class string, from int64, front bool)) *MockSPStorage_CreateTransferredTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(int64), args[5].(bool))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_CreateTransferredTicket_Call) Return(_a0 *models.TicketRecord, _a1 error) *MockSPStorage_CreateTransferredTicket_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_CreateTransferredTicket_Call) RunAndReturn(run func(context.Context, string, string, string, int64, bool) (*models.TicketRecord, error)) *MockSPStorage_CreateTransferredTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteServicePoint provides a mock function with given fields: ctx, id, ifVersion
+func (_m *MockSPStorage) DeleteServicePoint(ctx context.Context, id string, ifVersion int64) (*models.ServicePoint, error) {
+	ret := _m.Called(ctx, id, ifVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteServicePoint")
+	}
+
+	var r0 *models.ServicePoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (*models.ServicePoint, error)); ok {
+		return rf(ctx, id, ifVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *models.ServicePoint); ok {
+		r0 = rf(ctx, id, ifVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ServicePoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, id, ifVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_DeleteServicePoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteServicePoint'
+type MockSPStorage_DeleteServicePoint_Call struct {
+	*mock.Call
+}
+
+// DeleteServicePoint is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - ifVersion int64
+func (_e *MockSPStorage_Expecter) DeleteServicePoint(ctx interface{}, id interface{}, ifVersion interface{}) *MockSPStorage_DeleteServicePoint_Call {
+	return &MockSPStorage_DeleteServicePoint_Call{Call: _e.mock.On("DeleteServicePoint", ctx, id, ifVersion)}
+}
+
+func (_c *MockSPStorage_DeleteServicePoint_Call) Run(run func(ctx context.Context, id string, ifVersion int64)) *MockSPStorage_DeleteServicePoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_DeleteServicePoint_Call) Return(_a0 *models.ServicePoint, _a1 error) *MockSPStorage_DeleteServicePoint_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_DeleteServicePoint_Call) RunAndReturn(run func(context.Context, string, int64) (*models.ServicePoint, error)) *MockSPStorage_DeleteServicePoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWorkingHours provides a mock function with given fields: ctx, spID
+func (_m *MockSPStorage) DeleteWorkingHours(ctx context.Context, spID string) error {
+	ret := _m.Called(ctx, spID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWorkingHours")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, spID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSPStorage_DeleteWorkingHours_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWorkingHours'
+type MockSPStorage_DeleteWorkingHours_Call struct {
+	*mock.Call
+}
+
+// DeleteWorkingHours is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+func (_e *MockSPStorage_Expecter) DeleteWorkingHours(ctx interface{}, spID interface{}) *MockSPStorage_DeleteWorkingHours_Call {
+	return &MockSPStorage_DeleteWorkingHours_Call{Call: _e.mock.On("DeleteWorkingHours", ctx, spID)}
+}
+
+func (_c *MockSPStorage_DeleteWorkingHours_Call) Run(run func(ctx context.Context, spID string)) *MockSPStorage_DeleteWorkingHours_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_DeleteWorkingHours_Call) Return(_a0 error) *MockSPStorage_DeleteWorkingHours_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSPStorage_DeleteWorkingHours_Call) RunAndReturn(run func(context.Context, string) error) *MockSPStorage_DeleteWorkingHours_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EndSession provides a mock function with given fields: ctx, spID
+func (_m *MockSPStorage) EndSession(ctx context.Context, spID string) (*models.Session, error) {
+	ret := _m.Called(ctx, spID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EndSession")
+	}
+
+	var r0 *models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Session, error)); ok {
+		return rf(ctx, spID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Session); ok {
+		r0 = rf(ctx, spID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, spID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_EndSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EndSession'
+type MockSPStorage_EndSession_Call struct {
+	*mock.Call
+}
+
+// EndSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+func (_e *MockSPStorage_Expecter) EndSession(ctx interface{}, spID interface{}) *MockSPStorage_EndSession_Call {
+	return &MockSPStorage_EndSession_Call{Call: _e.mock.On("EndSession", ctx, spID)}
+}
+
+func (_c *MockSPStorage_EndSession_Call) Run(run func(ctx context.Context, spID string)) *MockSPStorage_EndSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_EndSession_Call) Return(_a0 *models.Session, _a1 error) *MockSPStorage_EndSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_EndSession_Call) RunAndReturn(run func(context.Context, string) (*models.Session, error)) *MockSPStorage_EndSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExpireAppointments provides a mock function with given fields: ctx, before
+func (_m *MockSPStorage) ExpireAppointments(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireAppointments")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ExpireAppointments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExpireAppointments'
+type MockSPStorage_ExpireAppointments_Call struct {
+	*mock.Call
+}
+
+// ExpireAppointments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *MockSPStorage_Expecter) ExpireAppointments(ctx interface{}, before interface{}) *MockSPStorage_ExpireAppointments_Call {
+	return &MockSPStorage_ExpireAppointments_Call{Call: _e.mock.On("ExpireAppointments", ctx, before)}
+}
+
+func (_c *MockSPStorage_ExpireAppointments_Call) Run(run func(ctx context.Context, before time.Time)) *MockSPStorage_ExpireAppointments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ExpireAppointments_Call) Return(_a0 int64, _a1 error) *MockSPStorage_ExpireAppointments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ExpireAppointments_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *MockSPStorage_ExpireAppointments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetActiveSession provides a mock function with given fields: ctx, spID
+func (_m *MockSPStorage) GetActiveSession(ctx context.Context, spID string) (*models.Session, error) {
+	ret := _m.Called(ctx, spID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveSession")
+	}
+
+	var r0 *models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Session, error)); ok {
+		return rf(ctx, spID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Session); ok {
+		r0 = rf(ctx, spID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, spID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetActiveSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetActiveSession'
+type MockSPStorage_GetActiveSession_Call struct {
+	*mock.Call
+}
+
+// GetActiveSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+func (_e *MockSPStorage_Expecter) GetActiveSession(ctx interface{}, spID interface{}) *MockSPStorage_GetActiveSession_Call {
+	return &MockSPStorage_GetActiveSession_Call{Call: _e.mock.On("GetActiveSession", ctx, spID)}
+}
+
+func (_c *MockSPStorage_GetActiveSession_Call) Run(run func(ctx context.Context, spID string)) *MockSPStorage_GetActiveSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetActiveSession_Call) Return(_a0 *models.Session, _a1 error) *MockSPStorage_GetActiveSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetActiveSession_Call) RunAndReturn(run func(context.Context, string) (*models.Session, error)) *MockSPStorage_GetActiveSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAppointment provides a mock function with given fields: ctx, id
+func (_m *MockSPStorage) GetAppointment(ctx context.Context, id string) (*models.Appointment, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAppointment")
+	}
+
+	var r0 *models.Appointment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Appointment, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Appointment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Appointment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetAppointment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAppointment'
+type MockSPStorage_GetAppointment_Call struct {
+	*mock.Call
+}
+
+// GetAppointment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockSPStorage_Expecter) GetAppointment(ctx interface{}, id interface{}) *MockSPStorage_GetAppointment_Call {
+	return &MockSPStorage_GetAppointment_Call{Call: _e.mock.On("GetAppointment", ctx, id)}
+}
+
+func (_c *MockSPStorage_GetAppointment_Call) Run(run func(ctx context.Context, id string)) *MockSPStorage_GetAppointment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetAppointment_Call) Return(_a0 *models.Appointment, _a1 error) *MockSPStorage_GetAppointment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetAppointment_Call) RunAndReturn(run func(context.Context, string) (*models.Appointment, error)) *MockSPStorage_GetAppointment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBuilding provides a mock function with given fields: ctx, id
+func (_m *MockSPStorage) GetBuilding(ctx context.Context, id string) (*models.Building, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBuilding")
+	}
+
+	var r0 *models.Building
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Building, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Building); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Building)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetBuilding_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBuilding'
+type MockSPStorage_GetBuilding_Call struct {
+	*mock.Call
+}
+
+// GetBuilding is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockSPStorage_Expecter) GetBuilding(ctx interface{}, id interface{}) *MockSPStorage_GetBuilding_Call {
+	return &MockSPStorage_GetBuilding_Call{Call: _e.mock.On("GetBuilding", ctx, id)}
+}
+
+func (_c *MockSPStorage_GetBuilding_Call) Run(run func(ctx context.Context, id string)) *MockSPStorage_GetBuilding_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetBuilding_Call) Return(_a0 *models.Building, _a1 error) *MockSPStorage_GetBuilding_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetBuilding_Call) RunAndReturn(run func(context.Context, string) (*models.Building, error)) *MockSPStorage_GetBuilding_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCategory provides a mock function with given fields: ctx, id
+func (_m *MockSPStorage) GetCategory(ctx context.Context, id string) (*models.Category, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategory")
+	}
+
+	var r0 *models.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Category, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Category); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCategory'
+type MockSPStorage_GetCategory_Call struct {
+	*mock.Call
+}
+
+// GetCategory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockSPStorage_Expecter) GetCategory(ctx interface{}, id interface{}) *MockSPStorage_GetCategory_Call {
+	return &MockSPStorage_GetCategory_Call{Call: _e.mock.On("GetCategory", ctx, id)}
+}
+
+func (_c *MockSPStorage_GetCategory_Call) Run(run func(ctx context.Context, id string)) *MockSPStorage_GetCategory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetCategory_Call) Return(_a0 *models.Category, _a1 error) *MockSPStorage_GetCategory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetCategory_Call) RunAndReturn(run func(context.Context, string) (*models.Category, error)) *MockSPStorage_GetCategory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFloor provides a mock function with given fields: ctx, id
+func (_m *MockSPStorage) GetFloor(ctx context.Context, id string) (*models.Floor, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFloor")
+	}
+
+	var r0 *models.Floor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Floor, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Floor); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Floor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetFloor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFloor'
+type MockSPStorage_GetFloor_Call struct {
+	*mock.Call
+}
+
+// GetFloor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockSPStorage_Expecter) GetFloor(ctx interface{}, id interface{}) *MockSPStorage_GetFloor_Call {
+	return &MockSPStorage_GetFloor_Call{Call: _e.mock.On("GetFloor", ctx, id)}
+}
+
+func (_c *MockSPStorage_GetFloor_Call) Run(run func(ctx context.Context, id string)) *MockSPStorage_GetFloor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetFloor_Call) Return(_a0 *models.Floor, _a1 error) *MockSPStorage_GetFloor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetFloor_Call) RunAndReturn(run func(context.Context, string) (*models.Floor, error)) *MockSPStorage_GetFloor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLocation provides a mock function with given fields: ctx, officeID
+func (_m *MockSPStorage) GetLocation(ctx context.Context, officeID string) (*models.Location, error) {
+	ret := _m.Called(ctx, officeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLocation")
+	}
+
+	var r0 *models.Location
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Location, error)); ok {
+		return rf(ctx, officeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Location); ok {
+		r0 = rf(ctx, officeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Location)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, officeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetLocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLocation'
+type MockSPStorage_GetLocation_Call struct {
+	*mock.Call
+}
+
+// GetLocation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - officeID string
+func (_e *MockSPStorage_Expecter) GetLocation(ctx interface{}, officeID interface{}) *MockSPStorage_GetLocation_Call {
+	return &MockSPStorage_GetLocation_Call{Call: _e.mock.On("GetLocation", ctx, officeID)}
+}
+
+func (_c *MockSPStorage_GetLocation_Call) Run(run func(ctx context.Context, officeID string)) *MockSPStorage_GetLocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetLocation_Call) Return(_a0 *models.Location, _a1 error) *MockSPStorage_GetLocation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetLocation_Call) RunAndReturn(run func(context.Context, string) (*models.Location, error)) *MockSPStorage_GetLocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOfficeHours provides a mock function with given fields: ctx, officeNumber
+func (_m *MockSPStorage) GetOfficeHours(ctx context.Context, officeNumber string) (*models.WorkingHours, error) {
+	ret := _m.Called(ctx, officeNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOfficeHours")
+	}
+
+	var r0 *models.WorkingHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.WorkingHours, error)); ok {
+		return rf(ctx, officeNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.WorkingHours); ok {
+		r0 = rf(ctx, officeNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WorkingHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, officeNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetOfficeHours_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOfficeHours'
+type MockSPStorage_GetOfficeHours_Call struct {
+	*mock.Call
+}
+
+// GetOfficeHours is a helper method to define mock.On call
+//   - ctx context.Context
+//   - officeNumber string
+func (_e *MockSPStorage_Expecter) GetOfficeHours(ctx interface{}, officeNumber interface{}) *MockSPStorage_GetOfficeHours_Call {
+	return &MockSPStorage_GetOfficeHours_Call{Call: _e.mock.On("GetOfficeHours", ctx, officeNumber)}
+}
+
+func (_c *MockSPStorage_GetOfficeHours_Call) Run(run func(ctx context.Context, officeNumber string)) *MockSPStorage_GetOfficeHours_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetOfficeHours_Call) Return(_a0 *models.WorkingHours, _a1 error) *MockSPStorage_GetOfficeHours_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetOfficeHours_Call) RunAndReturn(run func(context.Context, string) (*models.WorkingHours, error)) *MockSPStorage_GetOfficeHours_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOfficeNumberById provides a mock function with given fields: ctx, is
+func (_m *MockSPStorage) GetOfficeNumberById(ctx context.Context, is string) (string, error) {
+	ret := _m.Called(ctx, is)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOfficeNumberById")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, is)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, is)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, is)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetOfficeNumberById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOfficeNumberById'
+type MockSPStorage_GetOfficeNumberById_Call struct {
+	*mock.Call
+}
+
+// GetOfficeNumberById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - is string
+func (_e *MockSPStorage_Expecter) GetOfficeNumberById(ctx interface{}, is interface{}) *MockSPStorage_GetOfficeNumberById_Call {
+	return &MockSPStorage_GetOfficeNumberById_Call{Call: _e.mock.On("GetOfficeNumberById", ctx, is)}
+}
+
+func (_c *MockSPStorage_GetOfficeNumberById_Call) Run(run func(ctx context.Context, is string)) *MockSPStorage_GetOfficeNumberById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetOfficeNumberById_Call) Return(_a0 string, _a1 error) *MockSPStorage_GetOfficeNumberById_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetOfficeNumberById_Call) RunAndReturn(run func(context.Context, string) (string, error)) *MockSPStorage_GetOfficeNumberById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOperator provides a mock function with given fields: ctx, id
+func (_m *MockSPStorage) GetOperator(ctx context.Context, id string) (*models.Operator, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOperator")
+	}
+
+	var r0 *models.Operator
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Operator, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Operator); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Operator)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetOperator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOperator'
+type MockSPStorage_GetOperator_Call struct {
+	*mock.Call
+}
+
+// GetOperator is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockSPStorage_Expecter) GetOperator(ctx interface{}, id interface{}) *MockSPStorage_GetOperator_Call {
+	return &MockSPStorage_GetOperator_Call{Call: _e.mock.On("GetOperator", ctx, id)}
+}
+
+func (_c *MockSPStorage_GetOperator_Call) Run(run func(ctx context.Context, id string)) *MockSPStorage_GetOperator_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetOperator_Call) Return(_a0 *models.Operator, _a1 error) *MockSPStorage_GetOperator_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetOperator_Call) RunAndReturn(run func(context.Context, string) (*models.Operator, error)) *MockSPStorage_GetOperator_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrganisation provides a mock function with given fields: ctx, id
+func (_m *MockSPStorage) GetOrganisation(ctx context.Context, id string) (*models.Organisation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganisation")
+	}
+
+	var r0 *models.Organisation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Organisation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Organisation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Organisation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetOrganisation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganisation'
+type MockSPStorage_GetOrganisation_Call struct {
+	*mock.Call
+}
+
+// GetOrganisation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockSPStorage_Expecter) GetOrganisation(ctx interface{}, id interface{}) *MockSPStorage_GetOrganisation_Call {
+	return &MockSPStorage_GetOrganisation_Call{Call: _e.mock.On("GetOrganisation", ctx, id)}
+}
+
+func (_c *MockSPStorage_GetOrganisation_Call) Run(run func(ctx context.Context, id string)) *MockSPStorage_GetOrganisation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetOrganisation_Call) Return(_a0 *models.Organisation, _a1 error) *MockSPStorage_GetOrganisation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetOrganisation_Call) RunAndReturn(run func(context.Context, string) (*models.Organisation, error)) *MockSPStorage_GetOrganisation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrganisationByHost provides a mock function with given fields: ctx, host
+func (_m *MockSPStorage) GetOrganisationByHost(ctx context.Context, host string) (*models.Organisation, error) {
+	ret := _m.Called(ctx, host)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganisationByHost")
+	}
+
+	var r0 *models.Organisation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Organisation, error)); ok {
+		return rf(ctx, host)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Organisation); ok {
+		r0 = rf(ctx, host)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Organisation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, host)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetOrganisationByHost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganisationByHost'
+type MockSPStorage_GetOrganisationByHost_Call struct {
+	*mock.Call
+}
+
+// GetOrganisationByHost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - host string
+func (_e *MockSPStorage_Expecter) GetOrganisationByHost(ctx interface{}, host interface{}) *MockSPStorage_GetOrganisationByHost_Call {
+	return &MockSPStorage_GetOrganisationByHost_Call{Call: _e.mock.On("GetOrganisationByHost", ctx, host)}
+}
+
+func (_c *MockSPStorage_GetOrganisationByHost_Call) Run(run func(ctx context.Context, host string)) *MockSPStorage_GetOrganisationByHost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetOrganisationByHost_Call) Return(_a0 *models.Organisation, _a1 error) *MockSPStorage_GetOrganisationByHost_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetOrganisationByHost_Call) RunAndReturn(run func(context.Context, string) (*models.Organisation, error)) *MockSPStorage_GetOrganisationByHost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetServicePointByID provides a mock function with given fields: ctx, id, includeDeleted
+func (_m *MockSPStorage) GetServicePointByID(ctx context.Context, id string, includeDeleted bool) (*models.ServicePoint, error) {
+	ret := _m.Called(ctx, id, includeDeleted)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetServicePointByID")
+	}
+
+	var r0 *models.ServicePoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (*models.ServicePoint, error)); ok {
+		return rf(ctx, id, includeDeleted)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *models.ServicePoint); ok {
+		r0 = rf(ctx, id, includeDeleted)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ServicePoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, id, includeDeleted)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetServicePointByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetServicePointByID'
+type MockSPStorage_GetServicePointByID_Call struct {
+	*mock.Call
+}
+
+// GetServicePointByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - includeDeleted bool
+func (_e *MockSPStorage_Expecter) GetServicePointByID(ctx interface{}, id interface{}, includeDeleted interface{}) *MockSPStorage_GetServicePointByID_Call {
+	return &MockSPStorage_GetServicePointByID_Call{Call: _e.mock.On("GetServicePointByID", ctx, id, includeDeleted)}
+}
+
+func (_c *MockSPStorage_GetServicePointByID_Call) Run(run func(ctx context.Context, id string, includeDeleted bool)) *MockSPStorage_GetServicePointByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetServicePointByID_Call) Return(_a0 *models.ServicePoint, _a1 error) *MockSPStorage_GetServicePointByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetServicePointByID_Call) RunAndReturn(run func(context.Context, string, bool) (*models.ServicePoint, error)) *MockSPStorage_GetServicePointByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetServicePointHistory provides a mock function with given fields: ctx, id, cursor, limit
+func (_m *MockSPStorage) GetServicePointHistory(ctx context.Context, id string, cursor int64, limit int) ([]models.AuditRecord, error) {
+	ret := _m.Called(ctx, id, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetServicePointHistory")
+	}
+
+	var r0 []models.AuditRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int) ([]models.AuditRecord, error)); ok {
+		return rf(ctx, id, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int) []models.AuditRecord); ok {
+		r0 = rf(ctx, id, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AuditRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int) error); ok {
+		r1 = rf(ctx, id, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetServicePointHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetServicePointHistory'
+type MockSPStorage_GetServicePointHistory_Call struct {
+	*mock.Call
+}
+
+// GetServicePointHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - cursor int64
+//   - limit int
+func (_e *MockSPStorage_Expecter) GetServicePointHistory(ctx interface{}, id interface{}, cursor interface{}, limit interface{}) *MockSPStorage_GetServicePointHistory_Call {
+	return &MockSPStorage_GetServicePointHistory_Call{Call: _e.mock.On("GetServicePointHistory", ctx, id, cursor, limit)}
+}
+
+func (_c *MockSPStorage_GetServicePointHistory_Call) Run(run func(ctx context.Context, id string, cursor int64, limit int)) *MockSPStorage_GetServicePointHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetServicePointHistory_Call) Return(_a0 []models.AuditRecord, _a1 error) *MockSPStorage_GetServicePointHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetServicePointHistory_Call) RunAndReturn(run func(context.Context, string, int64, int) ([]models.AuditRecord, error)) *MockSPStorage_GetServicePointHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetShortNameById provides a mock function with given fields: ctx, is
+func (_m *MockSPStorage) GetShortNameById(ctx context.Context, is string) (string, error) {
+	ret := _m.Called(ctx, is)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShortNameById")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, is)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, is)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, is)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetShortNameById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetShortNameById'
+type MockSPStorage_GetShortNameById_Call struct {
+	*mock.Call
+}
+
+// GetShortNameById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - is string
+func (_e *MockSPStorage_Expecter) GetShortNameById(ctx interface{}, is interface{}) *MockSPStorage_GetShortNameById_Call {
+	return &MockSPStorage_GetShortNameById_Call{Call: _e.mock.On("GetShortNameById", ctx, is)}
+}
+
+func (_c *MockSPStorage_GetShortNameById_Call) Run(run func(ctx context.Context, is string)) *MockSPStorage_GetShortNameById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetShortNameById_Call) Return(_a0 string, _a1 error) *MockSPStorage_GetShortNameById_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetShortNameById_Call) RunAndReturn(run func(context.Context, string) (string, error)) *MockSPStorage_GetShortNameById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSlotSchedule provides a mock function with given fields: ctx, spID
+func (_m *MockSPStorage) GetSlotSchedule(ctx context.Context, spID string) (*models.SlotSchedule, error) {
+	ret := _m.Called(ctx, spID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSlotSchedule")
+	}
+
+	var r0 *models.SlotSchedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.SlotSchedule, error)); ok {
+		return rf(ctx, spID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.SlotSchedule); ok {
+		r0 = rf(ctx, spID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SlotSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, spID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetSlotSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSlotSchedule'
+type MockSPStorage_GetSlotSchedule_Call struct {
+	*mock.Call
+}
+
+// GetSlotSchedule is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+func (_e *MockSPStorage_Expecter) GetSlotSchedule(ctx interface{}, spID interface{}) *MockSPStorage_GetSlotSchedule_Call {
+	return &MockSPStorage_GetSlotSchedule_Call{Call: _e.mock.On("GetSlotSchedule", ctx, spID)}
+}
+
+func (_c *MockSPStorage_GetSlotSchedule_Call) Run(run func(ctx context.Context, spID string)) *MockSPStorage_GetSlotSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetSlotSchedule_Call) Return(_a0 *models.SlotSchedule, _a1 error) *MockSPStorage_GetSlotSchedule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetSlotSchedule_Call) RunAndReturn(run func(context.Context, string) (*models.SlotSchedule, error)) *MockSPStorage_GetSlotSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWorkingHours provides a mock function with given fields: ctx, spID
+func (_m *MockSPStorage) GetWorkingHours(ctx context.Context, spID string) (*models.WorkingHours, error) {
+	ret := _m.Called(ctx, spID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkingHours")
+	}
+
+	var r0 *models.WorkingHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.WorkingHours, error)); ok {
+		return rf(ctx, spID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.WorkingHours); ok {
+		r0 = rf(ctx, spID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WorkingHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, spID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_GetWorkingHours_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWorkingHours'
+type MockSPStorage_GetWorkingHours_Call struct {
+	*mock.Call
+}
+
+// GetWorkingHours is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+func (_e *MockSPStorage_Expecter) GetWorkingHours(ctx interface{}, spID interface{}) *MockSPStorage_GetWorkingHours_Call {
+	return &MockSPStorage_GetWorkingHours_Call{Call: _e.mock.On("GetWorkingHours", ctx, spID)}
+}
+
+func (_c *MockSPStorage_GetWorkingHours_Call) Run(run func(ctx context.Context, spID string)) *MockSPStorage_GetWorkingHours_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_GetWorkingHours_Call) Return(_a0 *models.WorkingHours, _a1 error) *MockSPStorage_GetWorkingHours_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_GetWorkingHours_Call) RunAndReturn(run func(context.Context, string) (*models.WorkingHours, error)) *MockSPStorage_GetWorkingHours_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ImportServicePoints provides a mock function with given fields: ctx, writes
+func (_m *MockSPStorage) ImportServicePoints(ctx context.Context, writes []models.ServicePointWrite) ([]models.ServicePoint, error) {
+	ret := _m.Called(ctx, writes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportServicePoints")
+	}
+
+	var r0 []models.ServicePoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.ServicePointWrite) ([]models.ServicePoint, error)); ok {
+		return rf(ctx, writes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.ServicePointWrite) []models.ServicePoint); ok {
+		r0 = rf(ctx, writes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ServicePoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.ServicePointWrite) error); ok {
+		r1 = rf(ctx, writes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ImportServicePoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportServicePoints'
+type MockSPStorage_ImportServicePoints_Call struct {
+	*mock.Call
+}
+
+// ImportServicePoints is a helper method to define mock.On call
+//   - ctx context.Context
+//   - writes []models.ServicePointWrite
+func (_e *MockSPStorage_Expecter) ImportServicePoints(ctx interface{}, writes interface{}) *MockSPStorage_ImportServicePoints_Call {
+	return &MockSPStorage_ImportServicePoints_Call{Call: _e.mock.On("ImportServicePoints", ctx, writes)}
+}
+
+func (_c *MockSPStorage_ImportServicePoints_Call) Run(run func(ctx context.Context, writes []models.ServicePointWrite)) *MockSPStorage_ImportServicePoints_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.ServicePointWrite))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ImportServicePoints_Call) Return(_a0 []models.ServicePoint, _a1 error) *MockSPStorage_ImportServicePoints_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ImportServicePoints_Call) RunAndReturn(run func(context.Context, []models.ServicePointWrite) ([]models.ServicePoint, error)) *MockSPStorage_ImportServicePoints_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAllTickets provides a mock function with given fields: ctx, filter
+func (_m *MockSPStorage) ListAllTickets(ctx context.Context, filter models.TicketFilter) ([]models.TicketRecord, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAllTickets")
+	}
+
+	var r0 []models.TicketRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.TicketFilter) ([]models.TicketRecord, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.TicketFilter) []models.TicketRecord); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TicketRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.TicketFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListAllTickets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAllTickets'
+type MockSPStorage_ListAllTickets_Call struct {
+	*mock.Call
+}
+
+// ListAllTickets is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.TicketFilter
+func (_e *MockSPStorage_Expecter) ListAllTickets(ctx interface{}, filter interface{}) *MockSPStorage_ListAllTickets_Call {
+	return &MockSPStorage_ListAllTickets_Call{Call: _e.mock.On("ListAllTickets", ctx, filter)}
+}
+
+func (_c *MockSPStorage_ListAllTickets_Call) Run(run func(ctx context.Context, filter models.TicketFilter)) *MockSPStorage_ListAllTickets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.TicketFilter))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListAllTickets_Call) Return(_a0 []models.TicketRecord, _a1 error) *MockSPStorage_ListAllTickets_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListAllTickets_Call) RunAndReturn(run func(context.Context, models.TicketFilter) ([]models.TicketRecord, error)) *MockSPStorage_ListAllTickets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAppointments provides a mock function with given fields: ctx, spID, from, to
+func (_m *MockSPStorage) ListAppointments(ctx context.Context, spID string, from time.Time, to time.Time) ([]models.Appointment, error) {
+	ret := _m.Called(ctx, spID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAppointments")
+	}
+
+	var r0 []models.Appointment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) ([]models.Appointment, error)); ok {
+		return rf(ctx, spID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) []models.Appointment); ok {
+		r0 = rf(ctx, spID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Appointment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, spID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListAppointments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAppointments'
+type MockSPStorage_ListAppointments_Call struct {
+	*mock.Call
+}
+
+// ListAppointments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - from time.Time
+//   - to time.Time
+func (_e *MockSPStorage_Expecter) ListAppointments(ctx interface{}, spID interface{}, from interface{}, to interface{}) *MockSPStorage_ListAppointments_Call {
+	return &MockSPStorage_ListAppointments_Call{Call: _e.mock.On("ListAppointments", ctx, spID, from, to)}
+}
+
+func (_c *MockSPStorage_ListAppointments_Call) Run(run func(ctx context.Context, spID string, from time.Time, to time.Time)) *MockSPStorage_ListAppointments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListAppointments_Call) Return(_a0 []models.Appointment, _a1 error) *MockSPStorage_ListAppointments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListAppointments_Call) RunAndReturn(run func(context.Context, string, time.Time, time.Time) ([]models.Appointment, error)) *MockSPStorage_ListAppointments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListBuildings provides a mock function with given fields: ctx
+func (_m *MockSPStorage) ListBuildings(ctx context.Context) ([]models.Building, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBuildings")
+	}
+
+	var r0 []models.Building
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Building, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Building); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Building)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListBuildings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBuildings'
+type MockSPStorage_ListBuildings_Call struct {
+	*mock.Call
+}
+
+// ListBuildings is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockSPStorage_Expecter) ListBuildings(ctx interface{}) *MockSPStorage_ListBuildings_Call {
+	return &MockSPStorage_ListBuildings_Call{Call: _e.mock.On("ListBuildings", ctx)}
+}
+
+func (_c *MockSPStorage_ListBuildings_Call) Run(run func(ctx context.Context)) *MockSPStorage_ListBuildings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListBuildings_Call) Return(_a0 []models.Building, _a1 error) *MockSPStorage_ListBuildings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListBuildings_Call) RunAndReturn(run func(context.Context) ([]models.Building, error)) *MockSPStorage_ListBuildings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCategories provides a mock function with given fields: ctx
+func (_m *MockSPStorage) ListCategories(ctx context.Context) ([]models.Category, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCategories")
+	}
+
+	var r0 []models.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Category, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Category); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListCategories_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCategories'
+type MockSPStorage_ListCategories_Call struct {
+	*mock.Call
+}
+
+// ListCategories is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockSPStorage_Expecter) ListCategories(ctx interface{}) *MockSPStorage_ListCategories_Call {
+	return &MockSPStorage_ListCategories_Call{Call: _e.mock.On("ListCategories", ctx)}
+}
+
+func (_c *MockSPStorage_ListCategories_Call) Run(run func(ctx context.Context)) *MockSPStorage_ListCategories_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListCategories_Call) Return(_a0 []models.Category, _a1 error) *MockSPStorage_ListCategories_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListCategories_Call) RunAndReturn(run func(context.Context) ([]models.Category, error)) *MockSPStorage_ListCategories_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCategoryTickets provides a mock function with given fields: ctx, filter
+func (_m *MockSPStorage) ListCategoryTickets(ctx context.Context, filter models.CategoryTicketFilter) ([]models.CategoryTicket, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCategoryTickets")
+	}
+
+	var r0 []models.CategoryTicket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.CategoryTicketFilter) ([]models.CategoryTicket, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.CategoryTicketFilter) []models.CategoryTicket); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.CategoryTicket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.CategoryTicketFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListCategoryTickets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCategoryTickets'
+type MockSPStorage_ListCategoryTickets_Call struct {
+	*mock.Call
+}
+
+// ListCategoryTickets is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.CategoryTicketFilter
+func (_e *MockSPStorage_Expecter) ListCategoryTickets(ctx interface{}, filter interface{}) *MockSPStorage_ListCategoryTickets_Call {
+	return &MockSPStorage_ListCategoryTickets_Call{Call: _e.mock.On("ListCategoryTickets", ctx, filter)}
+}
+
+func (_c *MockSPStorage_ListCategoryTickets_Call) Run(run func(ctx context.Context, filter models.CategoryTicketFilter)) *MockSPStorage_ListCategoryTickets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.CategoryTicketFilter))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListCategoryTickets_Call) Return(_a0 []models.CategoryTicket, _a1 error) *MockSPStorage_ListCategoryTickets_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListCategoryTickets_Call) RunAndReturn(run func(context.Context, models.CategoryTicketFilter) ([]models.CategoryTicket, error)) *MockSPStorage_ListCategoryTickets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListFloors provides a mock function with given fields: ctx, buildingID
+func (_m *MockSPStorage) ListFloors(ctx context.Context, buildingID string) ([]models.Floor, error) {
+	ret := _m.Called(ctx, buildingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFloors")
+	}
+
+	var r0 []models.Floor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.Floor, error)); ok {
+		return rf(ctx, buildingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.Floor); ok {
+		r0 = rf(ctx, buildingID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Floor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, buildingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListFloors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFloors'
+type MockSPStorage_ListFloors_Call struct {
+	*mock.Call
+}
+
+// ListFloors is a helper method to define mock.On call
+//   - ctx context.Context
+//   - buildingID string
+func (_e *MockSPStorage_Expecter) ListFloors(ctx interface{}, buildingID interface{}) *MockSPStorage_ListFloors_Call {
+	return &MockSPStorage_ListFloors_Call{Call: _e.mock.On("ListFloors", ctx, buildingID)}
+}
+
+func (_c *MockSPStorage_ListFloors_Call) Run(run func(ctx context.Context, buildingID string)) *MockSPStorage_ListFloors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListFloors_Call) Return(_a0 []models.Floor, _a1 error) *MockSPStorage_ListFloors_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListFloors_Call) RunAndReturn(run func(context.Context, string) ([]models.Floor, error)) *MockSPStorage_ListFloors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOffices provides a mock function with given fields: ctx, floorID
+func (_m *MockSPStorage) ListOffices(ctx context.Context, floorID string) ([]models.Office, error) {
+	ret := _m.Called(ctx, floorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOffices")
+	}
+
+	var r0 []models.Office
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.Office, error)); ok {
+		return rf(ctx, floorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.Office); ok {
+		r0 = rf(ctx, floorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Office)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, floorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListOffices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOffices'
+type MockSPStorage_ListOffices_Call struct {
+	*mock.Call
+}
+
+// ListOffices is a helper method to define mock.On call
+//   - ctx context.Context
+//   - floorID string
+func (_e *MockSPStorage_Expecter) ListOffices(ctx interface{}, floorID interface{}) *MockSPStorage_ListOffices_Call {
+	return &MockSPStorage_ListOffices_Call{Call: _e.mock.On("ListOffices", ctx, floorID)}
+}
+
+func (_c *MockSPStorage_ListOffices_Call) Run(run func(ctx context.Context, floorID string)) *MockSPStorage_ListOffices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListOffices_Call) Return(_a0 []models.Office, _a1 error) *MockSPStorage_ListOffices_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListOffices_Call) RunAndReturn(run func(context.Context, string) ([]models.Office, error)) *MockSPStorage_ListOffices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOperators provides a mock function with given fields: ctx
+func (_m *MockSPStorage) ListOperators(ctx context.Context) ([]models.Operator, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOperators")
+	}
+
+	var r0 []models.Operator
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Operator, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Operator); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Operator)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListOperators_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOperators'
+type MockSPStorage_ListOperators_Call struct {
+	*mock.Call
+}
+
+// ListOperators is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockSPStorage_Expecter) ListOperators(ctx interface{}) *MockSPStorage_ListOperators_Call {
+	return &MockSPStorage_ListOperators_Call{Call: _e.mock.On("ListOperators", ctx)}
+}
+
+func (_c *MockSPStorage_ListOperators_Call) Run(run func(ctx context.Context)) *MockSPStorage_ListOperators_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListOperators_Call) Return(_a0 []models.Operator, _a1 error) *MockSPStorage_ListOperators_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListOperators_Call) RunAndReturn(run func(context.Context) ([]models.Operator, error)) *MockSPStorage_ListOperators_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOrganisations provides a mock function with given fields: ctx
+func (_m *MockSPStorage) ListOrganisations(ctx context.Context) ([]models.Organisation, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrganisations")
+	}
+
+	var r0 []models.Organisation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Organisation, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Organisation); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Organisation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListOrganisations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOrganisations'
+type MockSPStorage_ListOrganisations_Call struct {
+	*mock.Call
+}
+
+// ListOrganisations is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockSPStorage_Expecter) ListOrganisations(ctx interface{}) *MockSPStorage_ListOrganisations_Call {
+	return &MockSPStorage_ListOrganisations_Call{Call: _e.mock.On("ListOrganisations", ctx)}
+}
+
+func (_c *MockSPStorage_ListOrganisations_Call) Run(run func(ctx context.Context)) *MockSPStorage_ListOrganisations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListOrganisations_Call) Return(_a0 []models.Organisation, _a1 error) *MockSPStorage_ListOrganisations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListOrganisations_Call) RunAndReturn(run func(context.Context) ([]models.Organisation, error)) *MockSPStorage_ListOrganisations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListServicePointCategories provides a mock function with given fields: ctx, spID
+func (_m *MockSPStorage) ListServicePointCategories(ctx context.Context, spID string) ([]models.Category, error) {
+	ret := _m.Called(ctx, spID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListServicePointCategories")
+	}
+
+	var r0 []models.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.Category, error)); ok {
+		return rf(ctx, spID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.Category); ok {
+		r0 = rf(ctx, spID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, spID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListServicePointCategories_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListServicePointCategories'
+type MockSPStorage_ListServicePointCategories_Call struct {
+	*mock.Call
+}
+
+// ListServicePointCategories is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+func (_e *MockSPStorage_Expecter) ListServicePointCategories(ctx interface{}, spID interface{}) *MockSPStorage_ListServicePointCategories_Call {
+	return &MockSPStorage_ListServicePointCategories_Call{Call: _e.mock.On("ListServicePointCategories", ctx, spID)}
+}
+
+func (_c *MockSPStorage_ListServicePointCategories_Call) Run(run func(ctx context.Context, spID string)) *MockSPStorage_ListServicePointCategories_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListServicePointCategories_Call) Return(_a0 []models.Category, _a1 error) *MockSPStorage_ListServicePointCategories_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListServicePointCategories_Call) RunAndReturn(run func(context.Context, string) ([]models.Category, error)) *MockSPStorage_ListServicePointCategories_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListServicePoints provides a mock function with given fields: ctx, includeDeleted
+func (_m *MockSPStorage) ListServicePoints(ctx context.Context, includeDeleted bool) ([]models.ServicePoint, error) {
+	ret := _m.Called(ctx, includeDeleted)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListServicePoints")
+	}
+
+	var r0 []models.ServicePoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) ([]models.ServicePoint, error)); ok {
+		return rf(ctx, includeDeleted)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool) []models.ServicePoint); ok {
+		r0 = rf(ctx, includeDeleted)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ServicePoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, includeDeleted)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListServicePoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListServicePoints'
+type MockSPStorage_ListServicePoints_Call struct {
+	*mock.Call
+}
+
+// ListServicePoints is a helper method to define mock.On call
+//   - ctx context.Context
+//   - includeDeleted bool
+func (_e *MockSPStorage_Expecter) ListServicePoints(ctx interface{}, includeDeleted interface{}) *MockSPStorage_ListServicePoints_Call {
+	return &MockSPStorage_ListServicePoints_Call{Call: _e.mock.On("ListServicePoints", ctx, includeDeleted)}
+}
+
+func (_c *MockSPStorage_ListServicePoints_Call) Run(run func(ctx context.Context, includeDeleted bool)) *MockSPStorage_ListServicePoints_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(bool))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListServicePoints_Call) Return(_a0 []models.ServicePoint, _a1 error) *MockSPStorage_ListServicePoints_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListServicePoints_Call) RunAndReturn(run func(context.Context, bool) ([]models.ServicePoint, error)) *MockSPStorage_ListServicePoints_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSessions provides a mock function with given fields: ctx, from, to
+func (_m *MockSPStorage) ListSessions(ctx context.Context, from time.Time, to time.Time) ([]models.Session, error) {
+	ret := _m.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessions")
+	}
+
+	var r0 []models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]models.Session, error)); ok {
+		return rf(ctx, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []models.Session); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSessions'
+type MockSPStorage_ListSessions_Call struct {
+	*mock.Call
+}
+
+// ListSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from time.Time
+//   - to time.Time
+func (_e *MockSPStorage_Expecter) ListSessions(ctx interface{}, from interface{}, to interface{}) *MockSPStorage_ListSessions_Call {
+	return &MockSPStorage_ListSessions_Call{Call: _e.mock.On("ListSessions", ctx, from, to)}
+}
+
+func (_c *MockSPStorage_ListSessions_Call) Run(run func(ctx context.Context, from time.Time, to time.Time)) *MockSPStorage_ListSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListSessions_Call) Return(_a0 []models.Session, _a1 error) *MockSPStorage_ListSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListSessions_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) ([]models.Session, error)) *MockSPStorage_ListSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTickets provides a mock function with given fields: ctx, spID, filter
+func (_m *MockSPStorage) ListTickets(ctx context.Context, spID string, filter models.TicketFilter) ([]models.TicketRecord, error) {
+	ret := _m.Called(ctx, spID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTickets")
+	}
+
+	var r0 []models.TicketRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.TicketFilter) ([]models.TicketRecord, error)); ok {
+		return rf(ctx, spID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.TicketFilter) []models.TicketRecord); ok {
+		r0 = rf(ctx, spID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TicketRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.TicketFilter) error); ok {
+		r1 = rf(ctx, spID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_ListTickets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTickets'
+type MockSPStorage_ListTickets_Call struct {
+	*mock.Call
+}
+
+// ListTickets is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - filter models.TicketFilter
+func (_e *MockSPStorage_Expecter) ListTickets(ctx interface{}, spID interface{}, filter interface{}) *MockSPStorage_ListTickets_Call {
+	return &MockSPStorage_ListTickets_Call{Call: _e.mock.On("ListTickets", ctx, spID, filter)}
+}
+
+func (_c *MockSPStorage_ListTickets_Call) Run(run func(ctx context.Context, spID string, filter models.TicketFilter)) *MockSPStorage_ListTickets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.TicketFilter))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_ListTickets_Call) Return(_a0 []models.TicketRecord, _a1 error) *MockSPStorage_ListTickets_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_ListTickets_Call) RunAndReturn(run func(context.Context, string, models.TicketFilter) ([]models.TicketRecord, error)) *MockSPStorage_ListTickets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NextServicePointID provides a mock function with given fields: ctx
+func (_m *MockSPStorage) NextServicePointID(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextServicePointID")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_NextServicePointID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NextServicePointID'
+type MockSPStorage_NextServicePointID_Call struct {
+	*mock.Call
+}
+
+// NextServicePointID is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockSPStorage_Expecter) NextServicePointID(ctx interface{}) *MockSPStorage_NextServicePointID_Call {
+	return &MockSPStorage_NextServicePointID_Call{Call: _e.mock.On("NextServicePointID", ctx)}
+}
+
+func (_c *MockSPStorage_NextServicePointID_Call) Run(run func(ctx context.Context)) *MockSPStorage_NextServicePointID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_NextServicePointID_Call) Return(_a0 int64, _a1 error) *MockSPStorage_NextServicePointID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_NextServicePointID_Call) RunAndReturn(run func(context.Context) (int64, error)) *MockSPStorage_NextServicePointID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeServicePoints provides a mock function with given fields: ctx, before
+func (_m *MockSPStorage) PurgeServicePoints(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeServicePoints")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_PurgeServicePoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeServicePoints'
+type MockSPStorage_PurgeServicePoints_Call struct {
+	*mock.Call
+}
+
+// PurgeServicePoints is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *MockSPStorage_Expecter) PurgeServicePoints(ctx interface{}, before interface{}) *MockSPStorage_PurgeServicePoints_Call {
+	return &MockSPStorage_PurgeServicePoints_Call{Call: _e.mock.On("PurgeServicePoints", ctx, before)}
+}
+
+func (_c *MockSPStorage_PurgeServicePoints_Call) Run(run func(ctx context.Context, before time.Time)) *MockSPStorage_PurgeServicePoints_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_PurgeServicePoints_Call) Return(_a0 int64, _a1 error) *MockSPStorage_PurgeServicePoints_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_PurgeServicePoints_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *MockSPStorage_PurgeServicePoints_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecallTicket provides a mock function with given fields: ctx, spID, ticketID
+func (_m *MockSPStorage) RecallTicket(ctx context.Context, spID string, ticketID int64) (*models.TicketRecord, error) {
+	ret := _m.Called(ctx, spID, ticketID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecallTicket")
+	}
+
+	var r0 *models.TicketRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (*models.TicketRecord, error)); ok {
+		return rf(ctx, spID, ticketID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *models.TicketRecord); ok {
+		r0 = rf(ctx, spID, ticketID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TicketRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, spID, ticketID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_RecallTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecallTicket'
+type MockSPStorage_RecallTicket_Call struct {
+	*mock.Call
+}
+
+// RecallTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - ticketID int64
+func (_e *MockSPStorage_Expecter) RecallTicket(ctx interface{}, spID interface{}, ticketID interface{}) *MockSPStorage_RecallTicket_Call {
+	return &MockSPStorage_RecallTicket_Call{Call: _e.mock.On("RecallTicket", ctx, spID, ticketID)}
+}
+
+func (_c *MockSPStorage_RecallTicket_Call) Run(run func(ctx context.Context, spID string, ticketID int64)) *MockSPStorage_RecallTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_RecallTicket_Call) Return(_a0 *models.TicketRecord, _a1 error) *MockSPStorage_RecallTicket_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_RecallTicket_Call) RunAndReturn(run func(context.Context, string, int64) (*models.TicketRecord, error)) *MockSPStorage_RecallTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecentCallTimes provides a mock function with given fields: ctx, spID, limit
+func (_m *MockSPStorage) RecentCallTimes(ctx context.Context, spID string, limit int) ([]time.Time, error) {
+	ret := _m.Called(ctx, spID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecentCallTimes")
+	}
+
+	var r0 []time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]time.Time, error)); ok {
+		return rf(ctx, spID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []time.Time); ok {
+		r0 = rf(ctx, spID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Time)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, spID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_RecentCallTimes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecentCallTimes'
+type MockSPStorage_RecentCallTimes_Call struct {
+	*mock.Call
+}
+
+// RecentCallTimes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - limit int
+func (_e *MockSPStorage_Expecter) RecentCallTimes(ctx interface{}, spID interface{}, limit interface{}) *MockSPStorage_RecentCallTimes_Call {
+	return &MockSPStorage_RecentCallTimes_Call{Call: _e.mock.On("RecentCallTimes", ctx, spID, limit)}
+}
+
+func (_c *MockSPStorage_RecentCallTimes_Call) Run(run func(ctx context.Context, spID string, limit int)) *MockSPStorage_RecentCallTimes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_RecentCallTimes_Call) Return(_a0 []time.Time, _a1 error) *MockSPStorage_RecentCallTimes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_RecentCallTimes_Call) RunAndReturn(run func(context.Context, string, int) ([]time.Time, error)) *MockSPStorage_RecentCallTimes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreServicePoint provides a mock function with given fields: ctx, id, ifVersion
+func (_m *MockSPStorage) RestoreServicePoint(ctx context.Context, id string, ifVersion int64) (*models.ServicePoint, error) {
+	ret := _m.Called(ctx, id, ifVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreServicePoint")
+	}
+
+	var r0 *models.ServicePoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (*models.ServicePoint, error)); ok {
+		return rf(ctx, id, ifVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *models.ServicePoint); ok {
+		r0 = rf(ctx, id, ifVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ServicePoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, id, ifVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_RestoreServicePoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreServicePoint'
+type MockSPStorage_RestoreServicePoint_Call struct {
+	*mock.Call
+}
+
+// RestoreServicePoint is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - ifVersion int64
+func (_e *MockSPStorage_Expecter) RestoreServicePoint(ctx interface{}, id interface{}, ifVersion interface{}) *MockSPStorage_RestoreServicePoint_Call {
+	return &MockSPStorage_RestoreServicePoint_Call{Call: _e.mock.On("RestoreServicePoint", ctx, id, ifVersion)}
+}
+
+func (_c *MockSPStorage_RestoreServicePoint_Call) Run(run func(ctx context.Context, id string, ifVersion int64)) *MockSPStorage_RestoreServicePoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_RestoreServicePoint_Call) Return(_a0 *models.ServicePoint, _a1 error) *MockSPStorage_RestoreServicePoint_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_RestoreServicePoint_Call) RunAndReturn(run func(context.Context, string, int64) (*models.ServicePoint, error)) *MockSPStorage_RestoreServicePoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetAppointmentStatus provides a mock function with given fields: ctx, spID, id, from, to, ticket
+func (_m *MockSPStorage) SetAppointmentStatus(ctx context.Context, spID string, id int64, from string, to string, ticket string) (*models.Appointment, error) {
+	ret := _m.Called(ctx, spID, id, from, to, ticket)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAppointmentStatus")
+	}
+
+	var r0 *models.Appointment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, string, string) (*models.Appointment, error)); ok {
+		return rf(ctx, spID, id, from, to, ticket)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, string, string) *models.Appointment); ok {
+		r0 = rf(ctx, spID, id, from, to, ticket)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Appointment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, string, string) error); ok {
+		r1 = rf(ctx, spID, id, from, to, ticket)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-type MockSPStorage_Expecter struct {
-	mock *mock.Mock
+// MockSPStorage_SetAppointmentStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAppointmentStatus'
+type MockSPStorage_SetAppointmentStatus_Call struct {
+	*mock.Call
 }
 
-func (_m *MockSPStorage) EXPECT() *MockSPStorage_Expecter {
-	return &MockSPStorage_Expecter{mock: &_m.Mock}
+// SetAppointmentStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - id int64
+//   - from string
+//   - to string
+//   - ticket string
+func (_e *MockSPStorage_Expecter) SetAppointmentStatus(ctx interface{}, spID interface{}, id interface{}, from interface{}, to interface{}, ticket interface{}) *MockSPStorage_SetAppointmentStatus_Call {
+	return &MockSPStorage_SetAppointmentStatus_Call{Call: _e.mock.On("SetAppointmentStatus", ctx, spID, id, from, to, ticket)}
 }
 
-// DeleteServicePoint provides a mock function with given fields: ctx, id
-func (_m *MockSPStorage) DeleteServicePoint(ctx context.Context, id string) (*models.ServicePoint, error) {
-	ret := _m.Called(ctx, id)
+func (_c *MockSPStorage_SetAppointmentStatus_Call) Run(run func(ctx context.Context, spID string, id int64, from string, to string, ticket string)) *MockSPStorage_SetAppointmentStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(string), args[4].(string), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_SetAppointmentStatus_Call) Return(_a0 *models.Appointment, _a1 error) *MockSPStorage_SetAppointmentStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_SetAppointmentStatus_Call) RunAndReturn(run func(context.Context, string, int64, string, string, string) (*models.Appointment, error)) *MockSPStorage_SetAppointmentStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetCategoryTicketStatus provides a mock function with given fields: ctx, ticketID, from, to
+func (_m *MockSPStorage) SetCategoryTicketStatus(ctx context.Context, ticketID int64, from string, to string) (*models.CategoryTicket, error) {
+	ret := _m.Called(ctx, ticketID, from, to)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteServicePoint")
+		panic("no return value specified for SetCategoryTicketStatus")
 	}
 
-	var r0 *models.ServicePoint
+	var r0 *models.CategoryTicket
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.ServicePoint, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) (*models.CategoryTicket, error)); ok {
+		return rf(ctx, ticketID, from, to)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.ServicePoint); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *models.CategoryTicket); ok {
+		r0 = rf(ctx, ticketID, from, to)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.ServicePoint)
+			r0 = ret.Get(0).(*models.CategoryTicket)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, ticketID, from, to)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -52,56 +3243,60 @@ func (_m *MockSPStorage) DeleteServicePoint(ctx context.Context, id string) (*mo
 	return r0, r1
 }
 
-// MockSPStorage_DeleteServicePoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteServicePoint'
-type MockSPStorage_DeleteServicePoint_Call struct {
+// MockSPStorage_SetCategoryTicketStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetCategoryTicketStatus'
+type MockSPStorage_SetCategoryTicketStatus_Call struct {
 	*mock.Call
 }
 
-// DeleteServicePoint is a helper method to define mock.On call
+// SetCategoryTicketStatus is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id string
-func (_e *MockSPStorage_Expecter) DeleteServicePoint(ctx interface{}, id interface{}) *MockSPStorage_DeleteServicePoint_Call {
-	return &MockSPStorage_DeleteServicePoint_Call{Call: _e.mock.On("DeleteServicePoint", ctx, id)}
+//   - ticketID int64
+//   - from string
+//   - to string
+func (_e *MockSPStorage_Expecter) SetCategoryTicketStatus(ctx interface{}, ticketID interface{}, from interface{}, to interface{}) *MockSPStorage_SetCategoryTicketStatus_Call {
+	return &MockSPStorage_SetCategoryTicketStatus_Call{Call: _e.mock.On("SetCategoryTicketStatus", ctx, ticketID, from, to)}
 }
 
-func (_c *MockSPStorage_DeleteServicePoint_Call) Run(run func(ctx context.Context, id string)) *MockSPStorage_DeleteServicePoint_Call {
+func (_c *MockSPStorage_SetCategoryTicketStatus_Call) Run(run func(ctx context.Context, ticketID int64, from string, to string)) *MockSPStorage_SetCategoryTicketStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *MockSPStorage_DeleteServicePoint_Call) Return(_a0 *models.ServicePoint, _a1 error) *MockSPStorage_DeleteServicePoint_Call {
+func (_c *MockSPStorage_SetCategoryTicketStatus_Call) Return(_a0 *models.CategoryTicket, _a1 error) *MockSPStorage_SetCategoryTicketStatus_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockSPStorage_DeleteServicePoint_Call) RunAndReturn(run func(context.Context, string) (*models.ServicePoint, error)) *MockSPStorage_DeleteServicePoint_Call {
+func (_c *MockSPStorage_SetCategoryTicketStatus_Call) RunAndReturn(run func(context.Context, int64, string, string) (*models.CategoryTicket, error)) *MockSPStorage_SetCategoryTicketStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOfficeNumberById provides a mock function with given fields: ctx, is
-func (_m *MockSPStorage) GetOfficeNumberById(ctx context.Context, is string) (string, error) {
-	ret := _m.Called(ctx, is)
+// SetServicePointCategories provides a mock function with given fields: ctx, spID, categoryIDs
+func (_m *MockSPStorage) SetServicePointCategories(ctx context.Context, spID string, categoryIDs []int64) ([]models.Category, error) {
+	ret := _m.Called(ctx, spID, categoryIDs)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOfficeNumberById")
+		panic("no return value specified for SetServicePointCategories")
 	}
 
-	var r0 string
+	var r0 []models.Category
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, is)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int64) ([]models.Category, error)); ok {
+		return rf(ctx, spID, categoryIDs)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, is)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int64) []models.Category); ok {
+		r0 = rf(ctx, spID, categoryIDs)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Category)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, is)
+	if rf, ok := ret.Get(1).(func(context.Context, string, []int64) error); ok {
+		r1 = rf(ctx, spID, categoryIDs)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -109,58 +3304,59 @@ func (_m *MockSPStorage) GetOfficeNumberById(ctx context.Context, is string) (st
 	return r0, r1
 }
 
-// MockSPStorage_GetOfficeNumberById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOfficeNumberById'
-type MockSPStorage_GetOfficeNumberById_Call struct {
+// MockSPStorage_SetServicePointCategories_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetServicePointCategories'
+type MockSPStorage_SetServicePointCategories_Call struct {
 	*mock.Call
 }
 
-// GetOfficeNumberById is a helper method to define mock.On call
+// SetServicePointCategories is a helper method to define mock.On call
 //   - ctx context.Context
-//   - is string
-func (_e *MockSPStorage_Expecter) GetOfficeNumberById(ctx interface{}, is interface{}) *MockSPStorage_GetOfficeNumberById_Call {
-	return &MockSPStorage_GetOfficeNumberById_Call{Call: _e.mock.On("GetOfficeNumberById", ctx, is)}
+//   - spID string
+//   - categoryIDs []int64
+func (_e *MockSPStorage_Expecter) SetServicePointCategories(ctx interface{}, spID interface{}, categoryIDs interface{}) *MockSPStorage_SetServicePointCategories_Call {
+	return &MockSPStorage_SetServicePointCategories_Call{Call: _e.mock.On("SetServicePointCategories", ctx, spID, categoryIDs)}
 }
 
-func (_c *MockSPStorage_GetOfficeNumberById_Call) Run(run func(ctx context.Context, is string)) *MockSPStorage_GetOfficeNumberById_Call {
+func (_c *MockSPStorage_SetServicePointCategories_Call) Run(run func(ctx context.Context, spID string, categoryIDs []int64)) *MockSPStorage_SetServicePointCategories_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].([]int64))
 	})
 	return _c
 }
 
-func (_c *MockSPStorage_GetOfficeNumberById_Call) Return(_a0 string, _a1 error) *MockSPStorage_GetOfficeNumberById_Call {
+func (_c *MockSPStorage_SetServicePointCategories_Call) Return(_a0 []models.Category, _a1 error) *MockSPStorage_SetServicePointCategories_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockSPStorage_GetOfficeNumberById_Call) RunAndReturn(run func(context.Context, string) (string, error)) *MockSPStorage_GetOfficeNumberById_Call {
+func (_c *MockSPStorage_SetServicePointCategories_Call) RunAndReturn(run func(context.Context, string, []int64) ([]models.Category, error)) *MockSPStorage_SetServicePointCategories_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetServicePointByID provides a mock function with given fields: ctx, id
-func (_m *MockSPStorage) GetServicePointByID(ctx context.Context, id string) (*models.ServicePoint, error) {
-	ret := _m.Called(ctx, id)
+// SetServicePointStatus provides a mock function with given fields: ctx, id, status, change
+func (_m *MockSPStorage) SetServicePointStatus(ctx context.Context, id string, status string, change models.StatusChange) (*models.ServicePoint, error) {
+	ret := _m.Called(ctx, id, status, change)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetServicePointByID")
+		panic("no return value specified for SetServicePointStatus")
 	}
 
 	var r0 *models.ServicePoint
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.ServicePoint, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.StatusChange) (*models.ServicePoint, error)); ok {
+		return rf(ctx, id, status, change)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.ServicePoint); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.StatusChange) *models.ServicePoint); ok {
+		r0 = rf(ctx, id, status, change)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.ServicePoint)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, models.StatusChange) error); ok {
+		r1 = rf(ctx, id, status, change)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -168,56 +3364,60 @@ func (_m *MockSPStorage) GetServicePointByID(ctx context.Context, id string) (*m
 	return r0, r1
 }
 
-// MockSPStorage_GetServicePointByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetServicePointByID'
-type MockSPStorage_GetServicePointByID_Call struct {
+// MockSPStorage_SetServicePointStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetServicePointStatus'
+type MockSPStorage_SetServicePointStatus_Call struct {
 	*mock.Call
 }
 
-// GetServicePointByID is a helper method to define mock.On call
+// SetServicePointStatus is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id string
-func (_e *MockSPStorage_Expecter) GetServicePointByID(ctx interface{}, id interface{}) *MockSPStorage_GetServicePointByID_Call {
-	return &MockSPStorage_GetServicePointByID_Call{Call: _e.mock.On("GetServicePointByID", ctx, id)}
+//   - status string
+//   - change models.StatusChange
+func (_e *MockSPStorage_Expecter) SetServicePointStatus(ctx interface{}, id interface{}, status interface{}, change interface{}) *MockSPStorage_SetServicePointStatus_Call {
+	return &MockSPStorage_SetServicePointStatus_Call{Call: _e.mock.On("SetServicePointStatus", ctx, id, status, change)}
 }
 
-func (_c *MockSPStorage_GetServicePointByID_Call) Run(run func(ctx context.Context, id string)) *MockSPStorage_GetServicePointByID_Call {
+func (_c *MockSPStorage_SetServicePointStatus_Call) Run(run func(ctx context.Context, id string, status string, change models.StatusChange)) *MockSPStorage_SetServicePointStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(models.StatusChange))
 	})
 	return _c
 }
 
-func (_c *MockSPStorage_GetServicePointByID_Call) Return(_a0 *models.ServicePoint, _a1 error) *MockSPStorage_GetServicePointByID_Call {
+func (_c *MockSPStorage_SetServicePointStatus_Call) Return(_a0 *models.ServicePoint, _a1 error) *MockSPStorage_SetServicePointStatus_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockSPStorage_GetServicePointByID_Call) RunAndReturn(run func(context.Context, string) (*models.ServicePoint, error)) *MockSPStorage_GetServicePointByID_Call {
+func (_c *MockSPStorage_SetServicePointStatus_Call) RunAndReturn(run func(context.Context, string, string, models.StatusChange) (*models.ServicePoint, error)) *MockSPStorage_SetServicePointStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetShortNameById provides a mock function with given fields: ctx, is
-func (_m *MockSPStorage) GetShortNameById(ctx context.Context, is string) (string, error) {
-	ret := _m.Called(ctx, is)
+// SetTicketStatus provides a mock function with given fields: ctx, spID, ticketID, from, to
+func (_m *MockSPStorage) SetTicketStatus(ctx context.Context, spID string, ticketID int64, from string, to string) (*models.TicketRecord, error) {
+	ret := _m.Called(ctx, spID, ticketID, from, to)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetShortNameById")
+		panic("no return value specified for SetTicketStatus")
 	}
 
-	var r0 string
+	var r0 *models.TicketRecord
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, is)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, string) (*models.TicketRecord, error)); ok {
+		return rf(ctx, spID, ticketID, from, to)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, is)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, string) *models.TicketRecord); ok {
+		r0 = rf(ctx, spID, ticketID, from, to)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TicketRecord)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, is)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, string) error); ok {
+		r1 = rf(ctx, spID, ticketID, from, to)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -225,38 +3425,161 @@ func (_m *MockSPStorage) GetShortNameById(ctx context.Context, is string) (strin
 	return r0, r1
 }
 
-// MockSPStorage_GetShortNameById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetShortNameById'
-type MockSPStorage_GetShortNameById_Call struct {
+// MockSPStorage_SetTicketStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTicketStatus'
+type MockSPStorage_SetTicketStatus_Call struct {
 	*mock.Call
 }
 
-// GetShortNameById is a helper method to define mock.On call
+// SetTicketStatus is a helper method to define mock.On call
 //   - ctx context.Context
-//   - is string
-func (_e *MockSPStorage_Expecter) GetShortNameById(ctx interface{}, is interface{}) *MockSPStorage_GetShortNameById_Call {
-	return &MockSPStorage_GetShortNameById_Call{Call: _e.mock.On("GetShortNameById", ctx, is)}
+//   - spID string
+//   - ticketID int64
+//   - from string
+//   - to string
+func (_e *MockSPStorage_Expecter) SetTicketStatus(ctx interface{}, spID interface{}, ticketID interface{}, from interface{}, to interface{}) *MockSPStorage_SetTicketStatus_Call {
+	return &MockSPStorage_SetTicketStatus_Call{Call: _e.mock.On("SetTicketStatus", ctx, spID, ticketID, from, to)}
 }
 
-func (_c *MockSPStorage_GetShortNameById_Call) Run(run func(ctx context.Context, is string)) *MockSPStorage_GetShortNameById_Call {
+func (_c *MockSPStorage_SetTicketStatus_Call) Run(run func(ctx context.Context, spID string, ticketID int64, from string, to string)) *MockSPStorage_SetTicketStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(string), args[4].(string))
 	})
 	return _c
 }
 
-func (_c *MockSPStorage_GetShortNameById_Call) Return(_a0 string, _a1 error) *MockSPStorage_GetShortNameById_Call {
+func (_c *MockSPStorage_SetTicketStatus_Call) Return(_a0 *models.TicketRecord, _a1 error) *MockSPStorage_SetTicketStatus_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockSPStorage_GetShortNameById_Call) RunAndReturn(run func(context.Context, string) (string, error)) *MockSPStorage_GetShortNameById_Call {
+func (_c *MockSPStorage_SetTicketStatus_Call) RunAndReturn(run func(context.Context, string, int64, string, string) (*models.TicketRecord, error)) *MockSPStorage_SetTicketStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpsertServicePoint provides a mock function with given fields: ctx, id, sp
-func (_m *MockSPStorage) UpsertServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest) (*models.ServicePoint, error) {
-	ret := _m.Called(ctx, id, sp)
+// StartSession provides a mock function with given fields: ctx, spID, session
+func (_m *MockSPStorage) StartSession(ctx context.Context, spID string, session models.NewSessionRequest) (*models.Session, error) {
+	ret := _m.Called(ctx, spID, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartSession")
+	}
+
+	var r0 *models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewSessionRequest) (*models.Session, error)); ok {
+		return rf(ctx, spID, session)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewSessionRequest) *models.Session); ok {
+		r0 = rf(ctx, spID, session)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.NewSessionRequest) error); ok {
+		r1 = rf(ctx, spID, session)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_StartSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartSession'
+type MockSPStorage_StartSession_Call struct {
+	*mock.Call
+}
+
+// StartSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - session models.NewSessionRequest
+func (_e *MockSPStorage_Expecter) StartSession(ctx interface{}, spID interface{}, session interface{}) *MockSPStorage_StartSession_Call {
+	return &MockSPStorage_StartSession_Call{Call: _e.mock.On("StartSession", ctx, spID, session)}
+}
+
+func (_c *MockSPStorage_StartSession_Call) Run(run func(ctx context.Context, spID string, session models.NewSessionRequest)) *MockSPStorage_StartSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.NewSessionRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_StartSession_Call) Return(_a0 *models.Session, _a1 error) *MockSPStorage_StartSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_StartSession_Call) RunAndReturn(run func(context.Context, string, models.NewSessionRequest) (*models.Session, error)) *MockSPStorage_StartSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertOfficeHours provides a mock function with given fields: ctx, officeNumber, hours
+func (_m *MockSPStorage) UpsertOfficeHours(ctx context.Context, officeNumber string, hours models.WorkingHoursRequest) (*models.WorkingHours, error) {
+	ret := _m.Called(ctx, officeNumber, hours)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertOfficeHours")
+	}
+
+	var r0 *models.WorkingHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.WorkingHoursRequest) (*models.WorkingHours, error)); ok {
+		return rf(ctx, officeNumber, hours)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.WorkingHoursRequest) *models.WorkingHours); ok {
+		r0 = rf(ctx, officeNumber, hours)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WorkingHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.WorkingHoursRequest) error); ok {
+		r1 = rf(ctx, officeNumber, hours)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_UpsertOfficeHours_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertOfficeHours'
+type MockSPStorage_UpsertOfficeHours_Call struct {
+	*mock.Call
+}
+
+// UpsertOfficeHours is a helper method to define mock.On call
+//   - ctx context.Context
+//   - officeNumber string
+//   - hours models.WorkingHoursRequest
+func (_e *MockSPStorage_Expecter) UpsertOfficeHours(ctx interface{}, officeNumber interface{}, hours interface{}) *MockSPStorage_UpsertOfficeHours_Call {
+	return &MockSPStorage_UpsertOfficeHours_Call{Call: _e.mock.On("UpsertOfficeHours", ctx, officeNumber, hours)}
+}
+
+func (_c *MockSPStorage_UpsertOfficeHours_Call) Run(run func(ctx context.Context, officeNumber string, hours models.WorkingHoursRequest)) *MockSPStorage_UpsertOfficeHours_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.WorkingHoursRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_UpsertOfficeHours_Call) Return(_a0 *models.WorkingHours, _a1 error) *MockSPStorage_UpsertOfficeHours_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_UpsertOfficeHours_Call) RunAndReturn(run func(context.Context, string, models.WorkingHoursRequest) (*models.WorkingHours, error)) *MockSPStorage_UpsertOfficeHours_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertServicePoint provides a mock function with given fields: ctx, id, sp, ifVersion
+func (_m *MockSPStorage) UpsertServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest, ifVersion int64) (*models.ServicePoint, error) {
+	ret := _m.Called(ctx, id, sp, ifVersion)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpsertServicePoint")
@@ -264,19 +3587,19 @@ func (_m *MockSPStorage) UpsertServicePoint(ctx context.Context, id string, sp m
 
 	var r0 *models.ServicePoint
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewServicePointRequest) (*models.ServicePoint, error)); ok {
-		return rf(ctx, id, sp)
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewServicePointRequest, int64) (*models.ServicePoint, error)); ok {
+		return rf(ctx, id, sp, ifVersion)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewServicePointRequest) *models.ServicePoint); ok {
-		r0 = rf(ctx, id, sp)
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.NewServicePointRequest, int64) *models.ServicePoint); ok {
+		r0 = rf(ctx, id, sp, ifVersion)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.ServicePoint)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, models.NewServicePointRequest) error); ok {
-		r1 = rf(ctx, id, sp)
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.NewServicePointRequest, int64) error); ok {
+		r1 = rf(ctx, id, sp, ifVersion)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -293,13 +3616,14 @@ type MockSPStorage_UpsertServicePoint_Call struct {
 //   - ctx context.Context
 //   - id string
 //   - sp models.NewServicePointRequest
-func (_e *MockSPStorage_Expecter) UpsertServicePoint(ctx interface{}, id interface{}, sp interface{}) *MockSPStorage_UpsertServicePoint_Call {
-	return &MockSPStorage_UpsertServicePoint_Call{Call: _e.mock.On("UpsertServicePoint", ctx, id, sp)}
+//   - ifVersion int64
+func (_e *MockSPStorage_Expecter) UpsertServicePoint(ctx interface{}, id interface{}, sp interface{}, ifVersion interface{}) *MockSPStorage_UpsertServicePoint_Call {
+	return &MockSPStorage_UpsertServicePoint_Call{Call: _e.mock.On("UpsertServicePoint", ctx, id, sp, ifVersion)}
 }
 
-func (_c *MockSPStorage_UpsertServicePoint_Call) Run(run func(ctx context.Context, id string, sp models.NewServicePointRequest)) *MockSPStorage_UpsertServicePoint_Call {
+func (_c *MockSPStorage_UpsertServicePoint_Call) Run(run func(ctx context.Context, id string, sp models.NewServicePointRequest, ifVersion int64)) *MockSPStorage_UpsertServicePoint_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(models.NewServicePointRequest))
+		run(args[0].(context.Context), args[1].(string), args[2].(models.NewServicePointRequest), args[3].(int64))
 	})
 	return _c
 }
@@ -309,7 +3633,127 @@ func (_c *MockSPStorage_UpsertServicePoint_Call) Return(_a0 *models.ServicePoint
 	return _c
 }
 
-func (_c *MockSPStorage_UpsertServicePoint_Call) RunAndReturn(run func(context.Context, string, models.NewServicePointRequest) (*models.ServicePoint, error)) *MockSPStorage_UpsertServicePoint_Call {
+func (_c *MockSPStorage_UpsertServicePoint_Call) RunAndReturn(run func(context.Context, string, models.NewServicePointRequest, int64) (*models.ServicePoint, error)) *MockSPStorage_UpsertServicePoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertSlotSchedule provides a mock function with given fields: ctx, spID, schedule
+func (_m *MockSPStorage) UpsertSlotSchedule(ctx context.Context, spID string, schedule models.SlotScheduleRequest) (*models.SlotSchedule, error) {
+	ret := _m.Called(ctx, spID, schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertSlotSchedule")
+	}
+
+	var r0 *models.SlotSchedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.SlotScheduleRequest) (*models.SlotSchedule, error)); ok {
+		return rf(ctx, spID, schedule)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.SlotScheduleRequest) *models.SlotSchedule); ok {
+		r0 = rf(ctx, spID, schedule)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SlotSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.SlotScheduleRequest) error); ok {
+		r1 = rf(ctx, spID, schedule)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_UpsertSlotSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertSlotSchedule'
+type MockSPStorage_UpsertSlotSchedule_Call struct {
+	*mock.Call
+}
+
+// UpsertSlotSchedule is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - schedule models.SlotScheduleRequest
+func (_e *MockSPStorage_Expecter) UpsertSlotSchedule(ctx interface{}, spID interface{}, schedule interface{}) *MockSPStorage_UpsertSlotSchedule_Call {
+	return &MockSPStorage_UpsertSlotSchedule_Call{Call: _e.mock.On("UpsertSlotSchedule", ctx, spID, schedule)}
+}
+
+func (_c *MockSPStorage_UpsertSlotSchedule_Call) Run(run func(ctx context.Context, spID string, schedule models.SlotScheduleRequest)) *MockSPStorage_UpsertSlotSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.SlotScheduleRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_UpsertSlotSchedule_Call) Return(_a0 *models.SlotSchedule, _a1 error) *MockSPStorage_UpsertSlotSchedule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_UpsertSlotSchedule_Call) RunAndReturn(run func(context.Context, string, models.SlotScheduleRequest) (*models.SlotSchedule, error)) *MockSPStorage_UpsertSlotSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertWorkingHours provides a mock function with given fields: ctx, spID, hours
+func (_m *MockSPStorage) UpsertWorkingHours(ctx context.Context, spID string, hours models.WorkingHoursRequest) (*models.WorkingHours, error) {
+	ret := _m.Called(ctx, spID, hours)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertWorkingHours")
+	}
+
+	var r0 *models.WorkingHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.WorkingHoursRequest) (*models.WorkingHours, error)); ok {
+		return rf(ctx, spID, hours)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.WorkingHoursRequest) *models.WorkingHours); ok {
+		r0 = rf(ctx, spID, hours)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WorkingHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.WorkingHoursRequest) error); ok {
+		r1 = rf(ctx, spID, hours)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSPStorage_UpsertWorkingHours_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertWorkingHours'
+type MockSPStorage_UpsertWorkingHours_Call struct {
+	*mock.Call
+}
+
+// UpsertWorkingHours is a helper method to define mock.On call
+//   - ctx context.Context
+//   - spID string
+//   - hours models.WorkingHoursRequest
+func (_e *MockSPStorage_Expecter) UpsertWorkingHours(ctx interface{}, spID interface{}, hours interface{}) *MockSPStorage_UpsertWorkingHours_Call {
+	return &MockSPStorage_UpsertWorkingHours_Call{Call: _e.mock.On("UpsertWorkingHours", ctx, spID, hours)}
+}
+
+func (_c *MockSPStorage_UpsertWorkingHours_Call) Run(run func(ctx context.Context, spID string, hours models.WorkingHoursRequest)) *MockSPStorage_UpsertWorkingHours_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.WorkingHoursRequest))
+	})
+	return _c
+}
+
+func (_c *MockSPStorage_UpsertWorkingHours_Call) Return(_a0 *models.WorkingHours, _a1 error) *MockSPStorage_UpsertWorkingHours_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSPStorage_UpsertWorkingHours_Call) RunAndReturn(run func(context.Context, string, models.WorkingHoursRequest) (*models.WorkingHours, error)) *MockSPStorage_UpsertWorkingHours_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,144 @@
+package spservice
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// maxDeskNumberLength is the size of the desk_number columns.
+const maxDeskNumberLength = 10
+
+func (m *SPService) CreateOperator(ctx context.Context, operator models.NewOperatorRequest) (*models.Operator, error) {
+	operator.Name = strings.TrimSpace(operator.Name)
+
+	var verr models.ValidationError
+	checkLength(&verr, "name", operator.Name, maxNameLength)
+	checkPrintable(&verr, "name", operator.Name)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	return m.storage.CreateOperator(ctx, operator)
+}
+
+func (m *SPService) GetOperator(ctx context.Context, id string) (*models.Operator, error) {
+	return m.storage.GetOperator(ctx, id)
+}
+
+func (m *SPService) ListOperators(ctx context.Context) ([]models.Operator, error) {
+	return m.storage.ListOperators(ctx)
+}
+
+// StartSession logs an operator in to a service point at a desk. Logging in
+// at another desk ends the session at the previous one.
+func (m *SPService) StartSession(ctx context.Context, id string, session models.NewSessionRequest) (*models.Session, error) {
+	session.DeskNumber = strings.TrimSpace(session.DeskNumber)
+
+	var verr models.ValidationError
+	if session.OperatorID <= 0 {
+		verr.Add("operatorId", "must be positive")
+	}
+	checkLength(&verr, "deskNumber", session.DeskNumber, maxDeskNumberLength)
+	checkPrintable(&verr, "deskNumber", session.DeskNumber)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.storage.GetServicePointByID(ctx, id, false); err != nil {
+		return nil, err
+	}
+	if _, err := m.storage.GetOperator(ctx, strconv.FormatInt(session.OperatorID, 10)); err != nil {
+		return nil, err
+	}
+
+	return m.storage.StartSession(ctx, id, session)
+}
+
+// EndSession logs out the operator of a service point. The ticket they were
+// serving is served, as no later call will close it.
+func (m *SPService) EndSession(ctx context.Context, id string) (*models.Session, error) {
+	session, err := m.storage.EndSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	m.serveCurrent(ctx, id)
+	return session, nil
+}
+
+// GetSession returns the session of the operator logged in to a service
+// point.
+func (m *SPService) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	return m.storage.GetActiveSession(ctx, id)
+}
+
+// OperatorStats summarises, for every operator, the tickets issued in
+// [from, to) that they called, and the time they were logged in during it.
+// Throughput is the tickets called per hour logged in.
+func (m *SPService) OperatorStats(ctx context.Context, from, to time.Time) ([]models.OperatorStats, error) {
+	operators, err := m.storage.ListOperators(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tickets, err := m.storage.ListAllTickets(ctx, models.TicketFilter{IssuedFrom: from, IssuedTo: to})
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := m.storage.ListSessions(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byOperator := make(map[int64]*models.OperatorStats, len(operators))
+	stats := make([]models.OperatorStats, len(operators))
+	for i, o := range operators {
+		stats[i] = models.OperatorStats{OperatorID: o.ID, Name: o.Name, From: from, To: to}
+		byOperator[o.ID] = &stats[i]
+	}
+
+	for _, ticket := range tickets {
+		s, ok := byOperator[ticket.OperatorID]
+		if !ok || ticket.CalledAt == nil {
+			continue
+		}
+		s.Called++
+		switch ticket.Status {
+		case models.TicketStatusServed:
+			s.Served++
+		case models.TicketStatusNoShow:
+			s.NoShows++
+		}
+	}
+
+	now := m.now()
+	for _, session := range sessions {
+		s, ok := byOperator[session.OperatorID]
+		if !ok {
+			continue
+		}
+		end := now
+		if session.EndedAt != nil {
+			end = *session.EndedAt
+		}
+		start := session.StartedAt
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			s.LoggedInSeconds += end.Sub(start).Seconds()
+		}
+	}
+
+	for i := range stats {
+		if stats[i].LoggedInSeconds > 0 {
+			stats[i].TicketsPerHour = float64(stats[i].Called) / (stats[i].LoggedInSeconds / 3600)
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,123 @@
+package spservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/tenant"
+)
+
+// Column sizes of public.organisations.
+const (
+	maxOrganisationIDLength = 64
+	maxHostLength           = 255
+)
+
+// SetAdminHost names the host whose requests may manage every organisation.
+// An empty host, the default, leaves no request able to.
+func (m *SPService) SetAdminHost(host string) {
+	m.adminHost = strings.ToLower(host)
+}
+
+// IsAdminHost reports whether a request sent to host comes from an
+// administrator.
+func (m *SPService) IsAdminHost(host string) bool {
+	return m.adminHost != "" && hostName(host) == m.adminHost
+}
+
+func (m *SPService) CreateOrganisation(ctx context.Context, organisation models.NewOrganisationRequest) (*models.Organisation, error) {
+	if !tenant.IsAdmin(ctx) {
+		return nil, models.ErrNotAdmin
+	}
+
+	organisation.ID = strings.TrimSpace(organisation.ID)
+	organisation.Name = strings.TrimSpace(organisation.Name)
+	organisation.Host = strings.ToLower(strings.TrimSpace(organisation.Host))
+
+	var verr models.ValidationError
+	if checkLength(&verr, "id", organisation.ID, maxOrganisationIDLength) && !isSlug(organisation.ID) {
+		verr.Add("id", "must contain only lowercase letters, digits and hyphens")
+	}
+	checkLength(&verr, "name", organisation.Name, maxNameLength)
+	checkPrintable(&verr, "name", organisation.Name)
+	if organisation.Host != "" {
+		if len(organisation.Host) > maxHostLength {
+			verr.Add("host", "must be at most %d characters", maxHostLength)
+		} else if !isSlug(strings.ReplaceAll(organisation.Host, ".", "")) {
+			verr.Add("host", "must be a host name without a port")
+		}
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	return m.storage.CreateOrganisation(ctx, organisation)
+}
+
+// GetOrganisation returns the organisation the request acts for; only
+// administrators see the others. Any other one is reported as not found
+// rather than forbidden, so its existence is not revealed.
+func (m *SPService) GetOrganisation(ctx context.Context, id string) (*models.Organisation, error) {
+	if !tenant.IsAdmin(ctx) && id != tenant.FromContext(ctx) {
+		return nil, models.ErrOrganisationNotFound
+	}
+	return m.storage.GetOrganisation(ctx, id)
+}
+
+func (m *SPService) ListOrganisations(ctx context.Context) ([]models.Organisation, error) {
+	if !tenant.IsAdmin(ctx) {
+		return nil, models.ErrNotAdmin
+	}
+	return m.storage.ListOrganisations(ctx)
+}
+
+// ResolveTenant returns the organisation a request acts for: the one whose
+// host the request was sent to, otherwise the one named by id if the caller
+// gave one, otherwise tenant.Default. An id naming another organisation than
+// the host is reported as models.ErrTenantMismatch, and one naming no
+// organisation as models.ErrOrganisationNotFound.
+func (m *SPService) ResolveTenant(ctx context.Context, id, host string) (string, error) {
+	if host = hostName(host); host != "" {
+		organisation, err := m.storage.GetOrganisationByHost(ctx, host)
+		switch {
+		case err == nil && id != "" && id != organisation.ID:
+			return "", fmt.Errorf("%w: %s is served at %s", models.ErrTenantMismatch, organisation.ID, host)
+		case err == nil:
+			return organisation.ID, nil
+		case !errors.Is(err, models.ErrOrganisationNotFound):
+			return "", err
+		}
+	}
+
+	if id == "" {
+		return tenant.Default, nil
+	}
+	organisation, err := m.storage.GetOrganisation(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return organisation.ID, nil
+}
+
+// hostName strips the port from a Host header and lowercases it.
+func hostName(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// isSlug reports whether s is made of lowercase ASCII letters, digits and
+// inner hyphens.
+func isSlug(s string) bool {
+	if s == "" || strings.HasPrefix(s, "-") || strings.HasSuffix(s, "-") {
+		return false
+	}
+	return strings.IndexFunc(s, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-'
+	}) < 0
+}
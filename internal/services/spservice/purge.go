@@ -0,0 +1,29 @@
+package spservice
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunPurge calls PurgeSP every interval until ctx is done.
+func (m *SPService) RunPurge(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := m.PurgeSP(ctx, retention)
+			if err != nil {
+				log.Printf("failed to purge service points: %s", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("purged %d deleted service points", purged)
+			}
+		}
+	}
+}
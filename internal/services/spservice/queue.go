@@ -0,0 +1,188 @@
+package spservice
+
+import (
+	"fmt"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// Dequeue policies choose the line of a service point the next ticket is
+// called from.
+const (
+	// PolicyStrict always calls from the waiting line of highest precedence.
+	PolicyStrict = "strict"
+	// PolicyWeighted interleaves the waiting lines in proportion to their
+	// weights, spreading the calls of each line evenly over a round.
+	PolicyWeighted = "weighted"
+)
+
+// classPrefixes are prepended to the short name of a service point in the
+// codes of its tickets, so every line numbers its tickets apart.
+var classPrefixes = map[string]string{
+	models.TicketClassAppointment: "A",
+	models.TicketClassPriority:    "P",
+	models.TicketClassRegular:     "",
+}
+
+// SetDequeuePolicy changes how Dequeue picks among the lines of a service
+// point. Weights only apply to PolicyWeighted; classes without a weight get 1.
+func (m *SPService) SetDequeuePolicy(policy string, weights map[string]int) error {
+	switch policy {
+	case PolicyStrict, PolicyWeighted:
+	default:
+		return fmt.Errorf("unknown dequeue policy %q", policy)
+	}
+
+	classWeights := make(map[string]int, len(models.TicketClasses))
+	for _, class := range models.TicketClasses {
+		classWeights[class] = 1
+	}
+	for class, weight := range weights {
+		if _, ok := classPrefixes[class]; !ok {
+			return fmt.Errorf("unknown ticket class %q", class)
+		}
+		if weight < 1 {
+			return fmt.Errorf("weight of %s must be positive", class)
+		}
+		classWeights[class] = weight
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.policy = policy
+	m.rounds = make(map[string]map[string]int)
+	m.weights = classWeights
+	return nil
+}
+
+// normalizeClass defaults an empty class to regular and rejects unknown ones.
+func normalizeClass(class string) (string, error) {
+	if class == "" {
+		return models.TicketClassRegular, nil
+	}
+	if _, ok := classPrefixes[class]; !ok {
+		var verr models.ValidationError
+		verr.Add("class", "must be one of appointment, priority or regular")
+		return "", verr.Err()
+	}
+	return class, nil
+}
+
+// queueID names the queue engine queue of a line. The regular line keeps the
+// plain service point id it had before lines existed.
+func queueID(id string, class string) string {
+	if class == "" || class == models.TicketClassRegular {
+		return id
+	}
+	return id + "-" + class
+}
+
+// nextClass picks the line Dequeue calls from among the waiting tickets of a
+// service point. With nothing recorded as waiting it falls back to the
+// regular line, which may still hold tickets whose recording failed.
+func (m *SPService) nextClass(id string, tickets []models.TicketRecord) string {
+	waiting := waitingLines(tickets)
+	if len(waiting) == 0 {
+		return models.TicketClassRegular
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	credit := m.rounds[id]
+	if credit == nil {
+		credit = make(map[string]int)
+		m.rounds[id] = credit
+	}
+	return m.pickClass(credit, waiting)
+}
+
+// linePosition is how many calls of a service point it takes to reach ticket
+// under the dequeue policy, counting the call of ticket itself. It replays
+// the policy on a copy of the weighted credit, so tickets of other lines that
+// go first are counted and those that go later are not. It is 0 if ticket is
+// not among the waiting tickets.
+func (m *SPService) linePosition(id string, tickets []models.TicketRecord, ticket *models.TicketRecord) int {
+	var mine int
+	for _, other := range tickets {
+		if other.Class != ticket.Class || other.QueuedAt.After(ticket.QueuedAt) {
+			continue
+		}
+		if other.QueuedAt.Equal(ticket.QueuedAt) && other.ID > ticket.ID {
+			continue
+		}
+		mine++
+	}
+	if mine == 0 {
+		return 0
+	}
+	waiting := waitingLines(tickets)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	credit := make(map[string]int, len(m.rounds[id]))
+	for class, c := range m.rounds[id] {
+		credit[class] = c
+	}
+
+	for calls := 1; calls <= len(tickets); calls++ {
+		class := m.pickClass(credit, waiting)
+		if class == "" {
+			break
+		}
+		waiting[class]--
+		if class != ticket.Class {
+			continue
+		}
+		if mine--; mine == 0 {
+			return calls
+		}
+	}
+	return len(tickets)
+}
+
+// waitingLines counts the waiting tickets of each line.
+func waitingLines(tickets []models.TicketRecord) map[string]int {
+	waiting := make(map[string]int)
+	for _, ticket := range tickets {
+		waiting[ticket.Class]++
+	}
+	return waiting
+}
+
+// pickClass applies the dequeue policy to the number of tickets waiting in
+// each line, paying for the pick from credit. It is empty if no line has
+// tickets waiting. The caller holds m.mu.
+func (m *SPService) pickClass(credit map[string]int, waiting map[string]int) string {
+	if m.policy != PolicyWeighted {
+		for _, class := range models.TicketClasses {
+			if waiting[class] > 0 {
+				return class
+			}
+		}
+		return ""
+	}
+
+	// Smooth weighted round-robin: every waiting line earns its weight, the
+	// line with most credit goes next and pays for it with the weights of all
+	// waiting lines. The credit lives in this process only.
+
+	best := ""
+	var total int
+	for _, class := range models.TicketClasses {
+		if waiting[class] <= 0 {
+			continue
+		}
+		credit[class] += m.weights[class]
+		total += m.weights[class]
+		if best == "" || credit[class] > credit[best] {
+			best = class
+		}
+	}
+	if best != "" {
+		credit[best] -= total
+	}
+	return best
+}
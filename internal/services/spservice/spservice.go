@@ -2,16 +2,78 @@ package spservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/snnus/mainservice/internal/models"
 )
 
 type SPStorage interface {
-	UpsertServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest) (*models.ServicePoint, error)
-	DeleteServicePoint(ctx context.Context, id string) (*models.ServicePoint, error)
-	GetServicePointByID(ctx context.Context, id string) (*models.ServicePoint, error)
+	NextServicePointID(ctx context.Context) (int64, error)
+	CreateServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest) (*models.ServicePoint, error)
+	UpsertServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest, ifVersion int64) (*models.ServicePoint, error)
+	ImportServicePoints(ctx context.Context, writes []models.ServicePointWrite) ([]models.ServicePoint, error)
+	DeleteServicePoint(ctx context.Context, id string, ifVersion int64) (*models.ServicePoint, error)
+	RestoreServicePoint(ctx context.Context, id string, ifVersion int64) (*models.ServicePoint, error)
+	SetServicePointStatus(ctx context.Context, id string, status string, change models.StatusChange) (*models.ServicePoint, error)
+	PurgeServicePoints(ctx context.Context, before time.Time) (int64, error)
+	GetServicePointHistory(ctx context.Context, id string, cursor int64, limit int) ([]models.AuditRecord, error)
+	GetServicePointByID(ctx context.Context, id string, includeDeleted bool) (*models.ServicePoint, error)
+	ListServicePoints(ctx context.Context, includeDeleted bool) ([]models.ServicePoint, error)
+	CreateTicket(ctx context.Context, spID string, code string, class string) (*models.TicketRecord, error)
+	CreateTransferredTicket(ctx context.Context, spID string, code string, class string, from int64, front bool) (*models.TicketRecord, error)
+	CallTicket(ctx context.Context, spID string, code string, call models.TicketCall) (*models.TicketRecord, error)
+	RecallTicket(ctx context.Context, spID string, ticketID int64) (*models.TicketRecord, error)
+	SetTicketStatus(ctx context.Context, spID string, ticketID int64, from string, to string) (*models.TicketRecord, error)
+	ListTickets(ctx context.Context, spID string, filter models.TicketFilter) ([]models.TicketRecord, error)
+	ListAllTickets(ctx context.Context, filter models.TicketFilter) ([]models.TicketRecord, error)
+	CountIssuedTickets(ctx context.Context, spID string, since time.Time) (int, error)
+	RecentCallTimes(ctx context.Context, spID string, limit int) ([]time.Time, error)
+	UpsertSlotSchedule(ctx context.Context, spID string, schedule models.SlotScheduleRequest) (*models.SlotSchedule, error)
+	GetSlotSchedule(ctx context.Context, spID string) (*models.SlotSchedule, error)
+	BookAppointment(ctx context.Context, spID string, appointment models.Appointment) (*models.Appointment, error)
+	GetAppointment(ctx context.Context, id string) (*models.Appointment, error)
+	ListAppointments(ctx context.Context, spID string, from, to time.Time) ([]models.Appointment, error)
+	SetAppointmentStatus(ctx context.Context, spID string, id int64, from string, to string, ticket string) (*models.Appointment, error)
+	ExpireAppointments(ctx context.Context, before time.Time) (int64, error)
+	UpsertWorkingHours(ctx context.Context, spID string, hours models.WorkingHoursRequest) (*models.WorkingHours, error)
+	GetWorkingHours(ctx context.Context, spID string) (*models.WorkingHours, error)
+	DeleteWorkingHours(ctx context.Context, spID string) error
+	UpsertOfficeHours(ctx context.Context, officeNumber string, hours models.WorkingHoursRequest) (*models.WorkingHours, error)
+	GetOfficeHours(ctx context.Context, officeNumber string) (*models.WorkingHours, error)
+	CreateOperator(ctx context.Context, operator models.NewOperatorRequest) (*models.Operator, error)
+	GetOperator(ctx context.Context, id string) (*models.Operator, error)
+	ListOperators(ctx context.Context) ([]models.Operator, error)
+	StartSession(ctx context.Context, spID string, session models.NewSessionRequest) (*models.Session, error)
+	EndSession(ctx context.Context, spID string) (*models.Session, error)
+	GetActiveSession(ctx context.Context, spID string) (*models.Session, error)
+	ListSessions(ctx context.Context, from, to time.Time) ([]models.Session, error)
+	CreateCategory(ctx context.Context, category models.NewCategoryRequest) (*models.Category, error)
+	GetCategory(ctx context.Context, id string) (*models.Category, error)
+	ListCategories(ctx context.Context) ([]models.Category, error)
+	ListServicePointCategories(ctx context.Context, spID string) ([]models.Category, error)
+	SetServicePointCategories(ctx context.Context, spID string, categoryIDs []int64) ([]models.Category, error)
+	CreateCategoryTicket(ctx context.Context, categoryID int64, code string) (*models.CategoryTicket, error)
+	ListCategoryTickets(ctx context.Context, filter models.CategoryTicketFilter) ([]models.CategoryTicket, error)
+	ClaimCategoryTicket(ctx context.Context, categoryID int64, code string, spID string, call models.TicketCall) (*models.TicketRecord, error)
+	SetCategoryTicketStatus(ctx context.Context, ticketID int64, from string, to string) (*models.CategoryTicket, error)
+	CreateOrganisation(ctx context.Context, organisation models.NewOrganisationRequest) (*models.Organisation, error)
+	GetOrganisation(ctx context.Context, id string) (*models.Organisation, error)
+	GetOrganisationByHost(ctx context.Context, host string) (*models.Organisation, error)
+	ListOrganisations(ctx context.Context) ([]models.Organisation, error)
+	CreateBuilding(ctx context.Context, building models.NewBuildingRequest) (*models.Building, error)
+	GetBuilding(ctx context.Context, id string) (*models.Building, error)
+	ListBuildings(ctx context.Context) ([]models.Building, error)
+	CreateFloor(ctx context.Context, buildingID string, floor models.NewFloorRequest) (*models.Floor, error)
+	GetFloor(ctx context.Context, id string) (*models.Floor, error)
+	ListFloors(ctx context.Context, buildingID string) ([]models.Floor, error)
+	CreateOffice(ctx context.Context, floorID string, office models.NewOfficeRequest) (*models.Office, error)
+	ListOffices(ctx context.Context, floorID string) ([]models.Office, error)
+	GetLocation(ctx context.Context, officeID string) (*models.Location, error)
 	GetShortNameById(ctx context.Context, is string) (string, error)
 	GetOfficeNumberById(ctx context.Context, is string) (string, error)
 }
@@ -19,85 +81,354 @@ type SPStorage interface {
 type SPClient interface {
 	Enqueue(ctx context.Context, id string, shortname string) (*models.Ticket, error)
 	Dequeue(ctx context.Context, id string) (*models.Ticket, error)
+	Cancel(ctx context.Context, id string, ticket string) error
+	Insert(ctx context.Context, id string, ticket string, front bool) error
+	Length(ctx context.Context, id string) (int, error)
 }
 
 type SPProducer interface {
-	PublishTicket(ctx context.Context, ticket, officeNumber string) error
+	PublishTicket(ctx context.Context, ticket, officeNumber, deskNumber string, location *models.Location) error
+	PublishTicketEvent(ctx context.Context, event, ticket, officeNumber, deskNumber string) error
+	PublishStatus(ctx context.Context, sp models.ServicePoint) error
 }
 
 type SPService struct {
 	storage    SPStorage
 	httpClient SPClient
 	producer   SPProducer
+	maxRecalls int
+	policy     string
+	weights    map[string]int
+	now        func() time.Time
+	adminHost  string
+
+	// mu guards rounds, the weighted round-robin state of each service point.
+	mu     sync.Mutex
+	rounds map[string]map[string]int
 }
 
 func NewSPService(storage SPStorage, httpClient SPClient, producer SPProducer) *SPService {
-	return &SPService{storage: storage, httpClient: httpClient, producer: producer}
+	m := &SPService{storage: storage, httpClient: httpClient, producer: producer, maxRecalls: defaultMaxRecalls, now: time.Now}
+	_ = m.SetDequeuePolicy(PolicyStrict, nil)
+	return m
 }
 
-func (m *SPService) UpsertSP(ctx context.Context, id string, sp models.NewServicePointRequest) (*models.ServicePoint, error) {
-	if sp.Name == "" {
-		return nil, fmt.Errorf("name is required")
+// createRetries bounds how often CreateSP allocates a fresh id because the
+// previous one was already taken by a caller-chosen id.
+const createRetries = 5
+
+// patchRetries bounds how often PatchSP starts over when the service point
+// changes between its read and write and the caller gave no version.
+const patchRetries = 3
+
+// CreateSP stores a new service point under a server-allocated id.
+func (m *SPService) CreateSP(ctx context.Context, sp models.NewServicePointRequest) (*models.ServicePoint, error) {
+	sp, err := m.placeServicePoint(ctx, sp)
+	if err != nil {
+		return nil, err
+	}
+	sp, err = normalizeServicePoint(sp)
+	if err != nil {
+		return nil, err
 	}
-	if sp.ShortName == "" {
-		return nil, fmt.Errorf("short name is required")
+	if err := m.checkCategoryShortNames(ctx, sp.ShortName); err != nil {
+		return nil, err
 	}
-	if sp.OfficeNumber == "" {
-		return nil, fmt.Errorf("office number is required")
+	return m.create(ctx, sp)
+}
+
+// create stores a valid service point under a fresh id.
+func (m *SPService) create(ctx context.Context, sp models.NewServicePointRequest) (*models.ServicePoint, error) {
+	for attempt := 0; ; attempt++ {
+		id, err := m.storage.NextServicePointID(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		createdSP, err := m.storage.CreateServicePoint(ctx, strconv.FormatInt(id, 10), sp)
+		if errors.Is(err, models.ErrAlreadyExists) && attempt < createRetries {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return createdSP, nil
 	}
-	updatedSP, err := m.storage.UpsertServicePoint(ctx, id, sp)
+}
+
+// UpsertSP creates or replaces a service point. A non-zero ifVersion only
+// replaces an existing service point still at that version.
+func (m *SPService) UpsertSP(ctx context.Context, id string, sp models.NewServicePointRequest, ifVersion int64) (*models.ServicePoint, error) {
+	sp, err := m.placeServicePoint(ctx, sp)
+	if err != nil {
+		return nil, err
+	}
+	sp, err = normalizeServicePoint(sp)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkCategoryShortNames(ctx, sp.ShortName); err != nil {
+		return nil, err
+	}
+	updatedSP, err := m.storage.UpsertServicePoint(ctx, id, sp, ifVersion)
 	if err != nil {
 		return nil, err
 	}
 	return updatedSP, err
 }
 
-func (m *SPService) DeleteSP(ctx context.Context, id string) (*models.ServicePoint, error) {
-	deletedSP, err := m.storage.DeleteServicePoint(ctx, id)
+// PatchSP applies a merge patch to an existing service point, leaving the
+// fields the patch omits unchanged.
+func (m *SPService) PatchSP(ctx context.Context, id string, patch models.ServicePointPatch, ifVersion int64) (*models.ServicePoint, error) {
+	for attempt := 0; ; attempt++ {
+		current, err := m.storage.GetServicePointByID(ctx, id, false)
+		if err != nil {
+			return nil, err
+		}
+		if ifVersion != 0 && current.Version != ifVersion {
+			return nil, fmt.Errorf("failed to patch service point: %w", models.ErrVersionMismatch)
+		}
+
+		sp := models.NewServicePointRequest{
+			Name:             current.Name,
+			ShortName:        current.ShortName,
+			OfficeNumber:     current.OfficeNumber,
+			OfficeID:         current.OfficeID,
+			MaxQueueLength:   current.MaxQueueLength,
+			DailyTicketQuota: current.DailyTicketQuota,
+		}
+		if patch.Name != nil {
+			sp.Name = *patch.Name
+		}
+		if patch.ShortName != nil {
+			sp.ShortName = *patch.ShortName
+		}
+		if patch.OfficeID != nil {
+			sp.OfficeID = *patch.OfficeID
+			if sp.OfficeID != 0 && patch.OfficeNumber == nil {
+				sp.OfficeNumber = ""
+			}
+		}
+		if patch.OfficeNumber != nil {
+			sp.OfficeNumber = *patch.OfficeNumber
+		}
+		if patch.MaxQueueLength != nil {
+			sp.MaxQueueLength = *patch.MaxQueueLength
+		}
+		if patch.DailyTicketQuota != nil {
+			sp.DailyTicketQuota = *patch.DailyTicketQuota
+		}
+
+		sp, err = m.placeServicePoint(ctx, sp)
+		if err != nil {
+			return nil, err
+		}
+		sp, err = normalizeServicePoint(sp)
+		if err != nil {
+			return nil, err
+		}
+		if sp.ShortName != current.ShortName {
+			if err := m.checkCategoryShortNames(ctx, sp.ShortName); err != nil {
+				return nil, err
+			}
+		}
+
+		patchedSP, err := m.storage.UpsertServicePoint(ctx, id, sp, current.Version)
+		if errors.Is(err, models.ErrVersionMismatch) && ifVersion == 0 && attempt < patchRetries {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return patchedSP, nil
+	}
+}
+
+// DeleteSP soft-deletes a service point: it disappears from reads and
+// takes no new tickets until restored or purged. A non-zero ifVersion only
+// deletes it at that version.
+func (m *SPService) DeleteSP(ctx context.Context, id string, ifVersion int64) (*models.ServicePoint, error) {
+	deletedSP, err := m.storage.DeleteServicePoint(ctx, id, ifVersion)
 	if err != nil {
 		return nil, err
 	}
 	return deletedSP, err
 }
 
-func (m *SPService) GetSPByID(ctx context.Context, id string) (*models.ServicePoint, error) {
-	sp, err := m.storage.GetServicePointByID(ctx, id)
+// RestoreSP undoes a soft delete. A non-zero ifVersion only restores the
+// service point at that version.
+func (m *SPService) RestoreSP(ctx context.Context, id string, ifVersion int64) (*models.ServicePoint, error) {
+	restoredSP, err := m.storage.RestoreServicePoint(ctx, id, ifVersion)
+	if err != nil {
+		return nil, err
+	}
+	return restoredSP, nil
+}
+
+// PurgeSP hard-deletes service points that were soft-deleted more than
+// retention ago.
+func (m *SPService) PurgeSP(ctx context.Context, retention time.Duration) (int64, error) {
+	return m.storage.PurgeServicePoints(ctx, m.now().Add(-retention))
+}
+
+func (m *SPService) GetSPByID(ctx context.Context, id string, includeDeleted bool) (*models.ServicePoint, error) {
+	sp, err := m.storage.GetServicePointByID(ctx, id, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
 	return sp, err
 }
 
-// func (m *MainService) GetAllSP(ctx context.Context) (*[]models.ServicePoint, error) {
+// GetSPHistory returns up to limit audit records of a service point after
+// cursor, oldest first. History outlives the service point itself; only an
+// id with neither is reported as models.ErrNotFound.
+func (m *SPService) GetSPHistory(ctx context.Context, id string, cursor int64, limit int) (*models.HistoryPage, error) {
+	records, err := m.storage.GetServicePointHistory(ctx, id, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
 
-// }
+	if len(records) == 0 && cursor == 0 {
+		if _, err := m.storage.GetServicePointByID(ctx, id, true); err != nil {
+			return nil, err
+		}
+	}
 
-func (m *SPService) Enqueue(ctx context.Context, id string) (*models.Ticket, error) {
-	shortName, err := m.storage.GetShortNameById(ctx, id)
+	page := &models.HistoryPage{Records: records}
+	if len(records) > limit {
+		page.Records = records[:limit]
+		page.NextCursor = page.Records[limit-1].ID
+	}
+	return page, nil
+}
+
+func (m *SPService) ListSP(ctx context.Context, includeDeleted bool) ([]models.ServicePoint, error) {
+	servicePoints, err := m.storage.ListServicePoints(ctx, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
+	return servicePoints, nil
+}
 
-	ticket, err := m.httpClient.Enqueue(ctx, id, shortName)
+// ListTickets returns the recorded tickets of a service point. Like history,
+// tickets outlive their service point; only an id with neither is reported
+// as models.ErrNotFound.
+func (m *SPService) ListTickets(ctx context.Context, id string, filter models.TicketFilter) ([]models.TicketRecord, error) {
+	tickets, err := m.storage.ListTickets(ctx, id, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return ticket, nil
+	if len(tickets) == 0 {
+		if _, err := m.storage.GetServicePointByID(ctx, id, true); err != nil {
+			return nil, err
+		}
+	}
+	return tickets, nil
 }
 
+// Enqueue issues a ticket in the line of the given class, regular if empty.
+func (m *SPService) Enqueue(ctx context.Context, id string, class string) (*models.Ticket, error) {
+	class, err := normalizeClass(class)
+	if err != nil {
+		return nil, err
+	}
+
+	sp, err := m.storage.GetServicePointByID(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(sp, true); err != nil {
+		return nil, err
+	}
+
+	now, err := m.checkOpen(ctx, sp)
+	if err != nil {
+		return nil, err
+	}
+	load, err := m.checkCapacity(ctx, sp, now)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := m.httpClient.Enqueue(ctx, queueID(id, class), classPrefixes[class]+sp.ShortName)
+	if err != nil {
+		return nil, err
+	}
+	m.publishCapacity(ctx, sp, load, ticket.Ticket)
+
+	// The queue engine has issued the ticket by now, so failing to record it
+	// or to estimate the wait must not fail the request.
+	record, err := m.storage.CreateTicket(ctx, id, ticket.Ticket, class)
+	if err != nil {
+		log.Printf("failed to record ticket %s: %s", ticket.Ticket, err)
+		return ticket, nil
+	}
+
+	withWait, err := m.ticketStatus(ctx, record, sp)
+	if err != nil {
+		log.Printf("failed to estimate wait of ticket %s: %s", ticket.Ticket, err)
+		return ticket, nil
+	}
+	return withWait, nil
+}
+
+// Dequeue calls the next ticket from the line the dequeue policy picks, or
+// from the line of a category the service point serves if its oldest ticket
+// has waited longer.
 func (m *SPService) Dequeue(ctx context.Context, id string) (*models.Ticket, error) {
-	ticket, err := m.httpClient.Dequeue(ctx, id)
+	sp, err := m.storage.GetServicePointByID(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(sp, false); err != nil {
+		return nil, err
+	}
+
+	waiting, err := m.storage.ListTickets(ctx, id, models.TicketFilter{Status: models.TicketStatusWaiting})
+	if err != nil {
+		return nil, err
+	}
+	class := m.nextClass(id, waiting)
+
+	category, err := m.categoryTicket(ctx, id, class, waiting)
 	if err != nil {
 		return nil, err
 	}
+	queue := queueID(id, class)
+	if category != nil {
+		class = models.TicketClassRegular
+		queue = categoryQueueID(category.CategoryID)
+	}
 
-	officeNumber, err := m.storage.GetOfficeNumberById(ctx, id)
+	call := models.TicketCall{OfficeNumber: sp.OfficeNumber}
+	session, err := m.storage.GetActiveSession(ctx, id)
+	switch {
+	case err == nil:
+		call.OperatorID = session.OperatorID
+		call.DeskNumber = session.DeskNumber
+	case !errors.Is(err, models.ErrSessionNotFound):
+		return nil, err
+	}
+
+	ticket, err := m.httpClient.Dequeue(ctx, queue)
 	if err != nil {
 		return nil, err
 	}
+	ticket.Class = class
+	ticket.DeskNumber = call.DeskNumber
+
+	if category != nil {
+		ticket.CategoryID = category.CategoryID
+		_, err = m.storage.ClaimCategoryTicket(ctx, category.CategoryID, ticket.Ticket, id, call)
+	} else {
+		_, err = m.storage.CallTicket(ctx, id, ticket.Ticket, call)
+	}
+	if err != nil {
+		log.Printf("failed to record call of ticket %s: %s", ticket.Ticket, err)
+	}
 
-	err = m.producer.PublishTicket(ctx, ticket.Ticket, officeNumber)
+	err = m.producer.PublishTicket(ctx, ticket.Ticket, call.OfficeNumber, call.DeskNumber, m.location(ctx, sp))
 
 	if err != nil {
 		log.Printf("%s", err.Error())
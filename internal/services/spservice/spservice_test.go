@@ -1,3 +1,786 @@
 package spservice_test
 
-// new tests will appear here (didn't make them after changes)
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/services/spservice"
+	mocks "github.com/snnus/mainservice/internal/services/spservice/mocks"
+	"github.com/snnus/mainservice/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertSPValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        models.NewServicePointRequest
+		wantFields []string
+	}{
+		{"missing name", models.NewServicePointRequest{ShortName: "C", OfficeNumber: "101"}, []string{"name"}},
+		{"missing short name", models.NewServicePointRequest{Name: "Cash desk", OfficeNumber: "101"}, []string{"shortName"}},
+		{"missing office number", models.NewServicePointRequest{Name: "Cash desk", ShortName: "C"}, []string{"officeNumber"}},
+		{"blank fields", models.NewServicePointRequest{Name: " ", ShortName: "\t", OfficeNumber: "  "}, []string{"name", "shortName", "officeNumber"}},
+		{"name too long", models.NewServicePointRequest{Name: strings.Repeat("n", 256), ShortName: "C", OfficeNumber: "101"}, []string{"name"}},
+		{"short name too long", models.NewServicePointRequest{Name: "Cash desk", ShortName: "ABCDEFGHIJK", OfficeNumber: "101"}, []string{"shortName"}},
+		{"short name with punctuation", models.NewServicePointRequest{Name: "Cash desk", ShortName: "C-D", OfficeNumber: "101"}, []string{"shortName"}},
+		{"short name starting with digit", models.NewServicePointRequest{Name: "Cash desk", ShortName: "1C", OfficeNumber: "101"}, []string{"shortName"}},
+		{"short name ending with digit", models.NewServicePointRequest{Name: "Cash desk", ShortName: "C1", OfficeNumber: "101"}, []string{"shortName"}},
+		{"office number too long", models.NewServicePointRequest{Name: "Cash desk", ShortName: "C", OfficeNumber: "12345678901"}, []string{"officeNumber"}},
+		{"control characters", models.NewServicePointRequest{Name: "Cash\x00desk", ShortName: "C", OfficeNumber: "1\n1"}, []string{"name", "officeNumber"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := mocks.NewMockSPStorage(t)
+			service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+			_, err := service.UpsertSP(context.Background(), "1", tt.req, 0)
+			require.ErrorIs(t, err, models.ErrInvalidArgument)
+
+			var verr *models.ValidationError
+			require.ErrorAs(t, err, &verr)
+			var fields []string
+			for _, f := range verr.Fields {
+				fields = append(fields, f.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestUpsertSPTrimsWhitespace(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+	storage.EXPECT().ListCategories(mock.Anything).Return(nil, nil)
+
+	want := models.NewServicePointRequest{Name: "Cash desk", ShortName: "AB1C", OfficeNumber: "101"}
+	storage.EXPECT().UpsertServicePoint(mock.Anything, "1", want, int64(0)).Return(&models.ServicePoint{ID: 1}, nil)
+
+	_, err := service.UpsertSP(context.Background(), "1", models.NewServicePointRequest{
+		Name:         " Cash desk ",
+		ShortName:    "\tAB1C",
+		OfficeNumber: "101 ",
+	}, 0)
+	require.NoError(t, err)
+}
+
+func TestEnqueueUsesShortName(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	client := mocks.NewMockSPClient(t)
+	service := spservice.NewSPService(storage, client, mocks.NewMockSPProducer(t))
+
+	expectNoWorkingHours(storage)
+	client.EXPECT().Enqueue(mock.Anything, "1", "C").Return(&models.Ticket{Ticket: "C001"}, nil)
+	storage.EXPECT().CreateTicket(mock.Anything, "1", "C001", models.TicketClassRegular).Return(&models.TicketRecord{Code: "C001"}, nil)
+
+	ticket, err := service.Enqueue(context.Background(), "1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "C001", ticket.Ticket)
+}
+
+func TestDequeueIgnoresPublishFailure(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	client := mocks.NewMockSPClient(t)
+	producer := mocks.NewMockSPProducer(t)
+	service := spservice.NewSPService(storage, client, producer)
+
+	storage.EXPECT().ListTickets(mock.Anything, "1", mock.Anything).Return([]models.TicketRecord{
+		{Code: "C001", Status: models.TicketStatusWaiting, Class: models.TicketClassRegular},
+	}, nil)
+	storage.EXPECT().GetServicePointByID(mock.Anything, "1", false).Return(cashDesk, nil)
+	storage.EXPECT().ListServicePointCategories(mock.Anything, "1").Return([]models.Category{}, nil)
+	storage.EXPECT().GetActiveSession(mock.Anything, "1").Return(nil, models.ErrSessionNotFound)
+	client.EXPECT().Dequeue(mock.Anything, "1").Return(&models.Ticket{Ticket: "C001"}, nil)
+	storage.EXPECT().CallTicket(mock.Anything, "1", "C001", mock.Anything).Return(&models.TicketRecord{Code: "C001"}, nil)
+	producer.EXPECT().PublishTicket(mock.Anything, "C001", "101", "", (*models.Location)(nil)).Return(errors.New("kafka down"))
+
+	ticket, err := service.Dequeue(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "C001", ticket.Ticket)
+}
+
+func TestDequeueRecordsOperatorDesk(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	client := mocks.NewMockSPClient(t)
+	producer := mocks.NewMockSPProducer(t)
+	service := spservice.NewSPService(storage, client, producer)
+
+	storage.EXPECT().ListTickets(mock.Anything, "1", mock.Anything).Return([]models.TicketRecord{
+		{Code: "C001", Status: models.TicketStatusWaiting, Class: models.TicketClassRegular},
+	}, nil)
+	storage.EXPECT().GetServicePointByID(mock.Anything, "1", false).Return(cashDesk, nil)
+	storage.EXPECT().ListServicePointCategories(mock.Anything, "1").Return([]models.Category{}, nil)
+	storage.EXPECT().GetActiveSession(mock.Anything, "1").Return(&models.Session{OperatorID: 3, DeskNumber: "7"}, nil)
+	client.EXPECT().Dequeue(mock.Anything, "1").Return(&models.Ticket{Ticket: "C001"}, nil)
+	call := models.TicketCall{OfficeNumber: "101", OperatorID: 3, DeskNumber: "7"}
+	storage.EXPECT().CallTicket(mock.Anything, "1", "C001", call).Return(&models.TicketRecord{Code: "C001"}, nil)
+	producer.EXPECT().PublishTicket(mock.Anything, "C001", "101", "7", (*models.Location)(nil)).Return(nil)
+
+	ticket, err := service.Dequeue(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "7", ticket.DeskNumber)
+}
+
+func TestDequeuePublishesLocation(t *testing.T) {
+	location := &models.Location{
+		Office:   models.Office{ID: 5, Number: "101"},
+		Floor:    models.Floor{Name: "First floor", Level: 1},
+		Building: models.Building{Name: "Main building"},
+	}
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want *models.Location
+	}{
+		{"found", nil, location},
+		{"lookup fails", errors.New("storage unavailable"), nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := mocks.NewMockSPStorage(t)
+			client := mocks.NewMockSPClient(t)
+			producer := mocks.NewMockSPProducer(t)
+			service := spservice.NewSPService(storage, client, producer)
+
+			placed := *cashDesk
+			placed.OfficeID = 5
+			storage.EXPECT().ListTickets(mock.Anything, "1", mock.Anything).Return([]models.TicketRecord{
+				{Code: "C001", Status: models.TicketStatusWaiting, Class: models.TicketClassRegular},
+			}, nil)
+			storage.EXPECT().GetServicePointByID(mock.Anything, "1", false).Return(&placed, nil)
+			storage.EXPECT().ListServicePointCategories(mock.Anything, "1").Return([]models.Category{}, nil)
+			storage.EXPECT().GetActiveSession(mock.Anything, "1").Return(nil, models.ErrSessionNotFound)
+			client.EXPECT().Dequeue(mock.Anything, "1").Return(&models.Ticket{Ticket: "C001"}, nil)
+			storage.EXPECT().CallTicket(mock.Anything, "1", "C001", mock.Anything).Return(&models.TicketRecord{Code: "C001"}, nil)
+			if tc.err != nil {
+				storage.EXPECT().GetLocation(mock.Anything, "5").Return(nil, tc.err)
+			} else {
+				storage.EXPECT().GetLocation(mock.Anything, "5").Return(location, nil)
+			}
+			producer.EXPECT().PublishTicket(mock.Anything, "C001", "101", "", tc.want).Return(nil)
+
+			_, err := service.Dequeue(context.Background(), "1")
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestShortNamesOfCategoriesAndServicePointsDiffer(t *testing.T) {
+	t.Run("category", func(t *testing.T) {
+		storage := mocks.NewMockSPStorage(t)
+		service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+		storage.EXPECT().ListServicePoints(mock.Anything, true).Return([]models.ServicePoint{*cashDesk}, nil)
+
+		for _, shortName := range []string{"C", "PC"} {
+			_, err := service.CreateCategory(context.Background(), models.NewCategoryRequest{Name: "Payments", ShortName: shortName})
+			var verr *models.ValidationError
+			require.ErrorAs(t, err, &verr, shortName)
+			assert.Equal(t, "shortName", verr.Fields[0].Field)
+		}
+	})
+
+	t.Run("service point", func(t *testing.T) {
+		storage := mocks.NewMockSPStorage(t)
+		service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+		storage.EXPECT().ListCategories(mock.Anything).Return([]models.Category{{ID: 3, ShortName: "AK"}}, nil)
+
+		_, err := service.UpsertSP(context.Background(), "1", models.NewServicePointRequest{Name: "Loans", ShortName: "K", OfficeNumber: "101"}, 0)
+		var verr *models.ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, "shortName", verr.Fields[0].Field)
+	})
+}
+
+func TestUpsertSPTakesOfficeNumber(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+	storage.EXPECT().ListCategories(mock.Anything).Return(nil, nil)
+
+	storage.EXPECT().GetLocation(mock.Anything, "5").Return(&models.Location{Office: models.Office{ID: 5, Number: "201"}}, nil)
+	want := models.NewServicePointRequest{Name: "Cash desk", ShortName: "C", OfficeNumber: "201", OfficeID: 5}
+	storage.EXPECT().UpsertServicePoint(mock.Anything, "1", want, int64(0)).Return(&models.ServicePoint{ID: 1}, nil)
+
+	_, err := service.UpsertSP(context.Background(), "1", models.NewServicePointRequest{Name: "Cash desk", ShortName: "C", OfficeID: 5}, 0)
+	require.NoError(t, err)
+
+	_, err = service.UpsertSP(context.Background(), "1", models.NewServicePointRequest{Name: "Cash desk", ShortName: "C", OfficeNumber: "101", OfficeID: 5}, 0)
+	require.ErrorIs(t, err, models.ErrInvalidArgument)
+}
+
+func TestDequeueCallsOldestCategoryTicket(t *testing.T) {
+	now := time.Now()
+	for _, tc := range []struct {
+		name     string
+		issuedAt time.Time
+		queue    string
+	}{
+		{"category ticket waited longer", now.Add(-time.Hour), "category-2"},
+		{"own ticket waited longer", now.Add(time.Minute), "1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := mocks.NewMockSPStorage(t)
+			client := mocks.NewMockSPClient(t)
+			producer := mocks.NewMockSPProducer(t)
+			service := spservice.NewSPService(storage, client, producer)
+
+			storage.EXPECT().GetServicePointByID(mock.Anything, "1", false).Return(cashDesk, nil)
+			storage.EXPECT().ListTickets(mock.Anything, "1", mock.Anything).Return([]models.TicketRecord{
+				{Code: "C001", Status: models.TicketStatusWaiting, Class: models.TicketClassRegular, QueuedAt: now},
+			}, nil)
+			storage.EXPECT().ListServicePointCategories(mock.Anything, "1").Return([]models.Category{{ID: 2}}, nil)
+			storage.EXPECT().ListCategoryTickets(mock.Anything, models.CategoryTicketFilter{CategoryIDs: []int64{2}, Status: models.TicketStatusWaiting}).
+				Return([]models.CategoryTicket{{CategoryID: 2, Code: "K001", IssuedAt: tc.issuedAt}}, nil)
+			storage.EXPECT().GetActiveSession(mock.Anything, "1").Return(nil, models.ErrSessionNotFound)
+			producer.EXPECT().PublishTicket(mock.Anything, mock.Anything, "101", "", (*models.Location)(nil)).Return(nil)
+
+			if tc.queue == "1" {
+				client.EXPECT().Dequeue(mock.Anything, "1").Return(&models.Ticket{Ticket: "C001"}, nil)
+				storage.EXPECT().CallTicket(mock.Anything, "1", "C001", mock.Anything).Return(&models.TicketRecord{}, nil)
+			} else {
+				client.EXPECT().Dequeue(mock.Anything, tc.queue).Return(&models.Ticket{Ticket: "K001"}, nil)
+				storage.EXPECT().ClaimCategoryTicket(mock.Anything, int64(2), "K001", "1", mock.Anything).Return(&models.TicketRecord{}, nil)
+			}
+
+			_, err := service.Dequeue(context.Background(), "1")
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestResolveTenant(t *testing.T) {
+	city := &models.Organisation{ID: "city", Host: "queue.city.example.org"}
+
+	for _, tc := range []struct {
+		name, id, host string
+		want           string
+		wantErr        error
+	}{
+		{name: "header", id: "city", host: "elsewhere.example.org", want: "city"},
+		{name: "header matching the host", id: "city", host: "queue.city.example.org", want: "city"},
+		{name: "header naming another organisation than the host", id: "county", host: "queue.city.example.org", wantErr: models.ErrTenantMismatch},
+		{name: "header naming the default organisation at a host", id: tenant.Default, host: "queue.city.example.org", wantErr: models.ErrTenantMismatch},
+		{name: "unknown header", id: "nobody", wantErr: models.ErrOrganisationNotFound},
+		{name: "host with port", host: "Queue.City.example.org:8080", want: "city"},
+		{name: "unknown host", host: "elsewhere.example.org", want: tenant.Default},
+		{name: "no host", want: tenant.Default},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := mocks.NewMockSPStorage(t)
+			service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+			storage.EXPECT().GetOrganisation(mock.Anything, "city").Return(city, nil).Maybe()
+			storage.EXPECT().GetOrganisation(mock.Anything, "nobody").Return(nil, models.ErrOrganisationNotFound).Maybe()
+			storage.EXPECT().GetOrganisationByHost(mock.Anything, "queue.city.example.org").Return(city, nil).Maybe()
+			storage.EXPECT().GetOrganisationByHost(mock.Anything, "elsewhere.example.org").Return(nil, models.ErrOrganisationNotFound).Maybe()
+
+			got, err := service.ResolveTenant(context.Background(), tc.id, tc.host)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestIsAdminHost(t *testing.T) {
+	service := spservice.NewSPService(mocks.NewMockSPStorage(t), mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+	assert.False(t, service.IsAdminHost(""), "no admin host is configured")
+
+	service.SetAdminHost("Admin.example.org")
+	assert.True(t, service.IsAdminHost("admin.example.org:8080"))
+	assert.False(t, service.IsAdminHost("queue.city.example.org"))
+	assert.False(t, service.IsAdminHost(""))
+}
+
+func TestPatchSPRetriesConcurrentChange(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+	v1 := &models.ServicePoint{ID: 1, Name: "Cash desk", ShortName: "C", OfficeNumber: "101", Version: 1}
+	v2 := &models.ServicePoint{ID: 1, Name: "Accounts", ShortName: "C", OfficeNumber: "101", Version: 2}
+
+	storage.EXPECT().GetServicePointByID(mock.Anything, "1", false).Return(v1, nil).Once()
+	storage.EXPECT().UpsertServicePoint(mock.Anything, "1", mock.Anything, int64(1)).Return(nil, models.ErrVersionMismatch).Once()
+	storage.EXPECT().GetServicePointByID(mock.Anything, "1", false).Return(v2, nil).Once()
+	storage.EXPECT().UpsertServicePoint(mock.Anything, "1", models.NewServicePointRequest{
+		Name: "Accounts", ShortName: "C", OfficeNumber: "205",
+	}, int64(2)).Return(&models.ServicePoint{ID: 1, Version: 3}, nil).Once()
+
+	officeNumber := "205"
+	sp, err := service.PatchSP(context.Background(), "1", models.ServicePointPatch{OfficeNumber: &officeNumber}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), sp.Version)
+}
+
+func TestPatchSPDoesNotRetryWithIfMatch(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+	storage.EXPECT().GetServicePointByID(mock.Anything, "1", false).Return(&models.ServicePoint{ID: 1, Version: 2}, nil)
+
+	_, err := service.PatchSP(context.Background(), "1", models.ServicePointPatch{}, 1)
+	assert.ErrorIs(t, err, models.ErrVersionMismatch)
+}
+
+func TestCreateSPRetriesTakenID(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+	storage.EXPECT().ListCategories(mock.Anything).Return(nil, nil)
+
+	req := models.NewServicePointRequest{Name: "Cash desk", ShortName: "C", OfficeNumber: "101"}
+
+	storage.EXPECT().NextServicePointID(mock.Anything).Return(int64(1), nil).Once()
+	storage.EXPECT().CreateServicePoint(mock.Anything, "1", req).Return(nil, models.ErrAlreadyExists).Once()
+	storage.EXPECT().NextServicePointID(mock.Anything).Return(int64(2), nil).Once()
+	storage.EXPECT().CreateServicePoint(mock.Anything, "2", req).Return(&models.ServicePoint{ID: 2, Version: 1}, nil).Once()
+
+	sp, err := service.CreateSP(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), sp.ID)
+}
+
+func TestImportSPRetriesTakenIDs(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+	storage.EXPECT().ListCategories(mock.Anything).Return(nil, nil)
+
+	req := models.NewServicePointRequest{Name: "Cash desk", ShortName: "C", OfficeNumber: "101"}
+	rows := []models.ImportRow{{Name: "Cash desk", ShortName: "C", OfficeNumber: "101"}}
+
+	storage.EXPECT().NextServicePointID(mock.Anything).Return(int64(1), nil).Once()
+	storage.EXPECT().ImportServicePoints(mock.Anything, []models.ServicePointWrite{{ID: "1", Create: true, ServicePoint: req}}).
+		Return(nil, &models.RowError{Row: 1, Err: models.ErrAlreadyExists}).Once()
+	storage.EXPECT().NextServicePointID(mock.Anything).Return(int64(2), nil).Once()
+	storage.EXPECT().ImportServicePoints(mock.Anything, []models.ServicePointWrite{{ID: "2", Create: true, ServicePoint: req}}).
+		Return([]models.ServicePoint{{ID: 2, Version: 1}}, nil).Once()
+
+	result, err := service.ImportSP(context.Background(), rows, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, int64(2), result.Rows[0].ID)
+}
+
+func TestPurgeSPUsesRetention(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	service.SetClock(func() time.Time { return now })
+	storage.EXPECT().PurgeServicePoints(mock.Anything, now.Add(-24*time.Hour)).Return(2, nil)
+
+	purged, err := service.PurgeSP(context.Background(), 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+}
+
+func TestExpireAppointmentsUsesClock(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	service.SetClock(func() time.Time { return now })
+	storage.EXPECT().ExpireAppointments(mock.Anything, now).Return(3, nil)
+
+	expired, err := service.ExpireAppointments(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), expired)
+}
+
+func TestTransferTicketReopensTicketWhenQueueEngineFails(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	client := mocks.NewMockSPClient(t)
+	service := spservice.NewSPService(storage, client, mocks.NewMockSPProducer(t))
+
+	engineDown := errors.New("queue engine unavailable")
+	accounts := &models.ServicePoint{ID: 2, ShortName: "A", OfficeNumber: "202"}
+	storage.EXPECT().ListAllTickets(mock.Anything, models.TicketFilter{Code: "C001", Status: models.TicketStatusCalled}).
+		Return([]models.TicketRecord{{ID: 7, ServicePointID: 1, Code: "C001", Class: models.TicketClassRegular}}, nil)
+	storage.EXPECT().GetServicePointByID(mock.Anything, "2", false).Return(accounts, nil)
+	storage.EXPECT().GetWorkingHours(mock.Anything, "2").Return(nil, models.ErrHoursNotFound)
+	storage.EXPECT().GetOfficeHours(mock.Anything, "202").Return(nil, models.ErrHoursNotFound)
+
+	transferred := storage.EXPECT().SetTicketStatus(mock.Anything, "1", int64(7), models.TicketStatusCalled, models.TicketStatusTransferred).
+		Return(&models.TicketRecord{}, nil).Call
+	client.EXPECT().Insert(mock.Anything, "2", "C001", false).Return(engineDown).NotBefore(transferred)
+	storage.EXPECT().SetTicketStatus(mock.Anything, "1", int64(7), models.TicketStatusTransferred, models.TicketStatusCalled).
+		Return(&models.TicketRecord{}, nil)
+
+	_, err := service.TransferTicket(context.Background(), "C001", models.TicketTransfer{ServicePointID: 2})
+	assert.ErrorIs(t, err, engineDown)
+}
+
+func TestEnqueueIgnoresTicketRecordFailure(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	client := mocks.NewMockSPClient(t)
+	service := spservice.NewSPService(storage, client, mocks.NewMockSPProducer(t))
+
+	expectNoWorkingHours(storage)
+	client.EXPECT().Enqueue(mock.Anything, "1", "C").Return(&models.Ticket{Ticket: "C001"}, nil)
+	storage.EXPECT().CreateTicket(mock.Anything, "1", "C001", models.TicketClassRegular).Return(nil, errors.New("db down"))
+
+	ticket, err := service.Enqueue(context.Background(), "1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "C001", ticket.Ticket)
+}
+
+func TestServicePointStats(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	var tickets []models.TicketRecord
+	for i, wait := range []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100} {
+		issued := from.Add(9*time.Hour + time.Duration(i)*time.Minute)
+		called := issued.Add(time.Duration(wait) * time.Second)
+		tickets = append(tickets, models.TicketRecord{Status: models.TicketStatusServed, IssuedAt: issued, CalledAt: &called})
+	}
+	for _, hour := range []int{10, 10, 14} {
+		tickets = append(tickets, models.TicketRecord{IssuedAt: from.Add(time.Duration(hour) * time.Hour)})
+	}
+	tickets = append(tickets, models.TicketRecord{IssuedAt: from.Add(16 * time.Hour)})
+	for _, status := range []string{models.TicketStatusCalled, models.TicketStatusNoShow, models.TicketStatusTransferred} {
+		issued := from.Add(14 * time.Hour)
+		called := issued.Add(time.Hour)
+		tickets = append(tickets, models.TicketRecord{Status: status, IssuedAt: issued, CalledAt: &called})
+	}
+
+	storage.EXPECT().ListTickets(mock.Anything, "1", models.TicketFilter{IssuedFrom: from, IssuedTo: to}).Return(tickets, nil)
+
+	stats, err := service.ServicePointStats(context.Background(), "1", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.ServicePointID)
+	assert.Equal(t, 17, stats.Issued)
+	assert.Equal(t, 10, stats.Served, "only served tickets")
+	assert.InDelta(t, 55, stats.AvgWaitSeconds, 0.001)
+	assert.InDelta(t, 90, stats.P90WaitSeconds, 0.001)
+	assert.Equal(t, []models.HourCount{{Hour: 9, Issued: 10}, {Hour: 14, Issued: 4}, {Hour: 10, Issued: 2}}, stats.PeakHours)
+}
+
+func TestOfficeStatsGroupsByOffice(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+	now := time.Now()
+	storage.EXPECT().ListAllTickets(mock.Anything, mock.Anything).Return([]models.TicketRecord{
+		{ServicePointID: 1, IssuedAt: now},
+		{ServicePointID: 2, IssuedAt: now},
+		{ServicePointID: 3, IssuedAt: now},
+		{ServicePointID: 9, IssuedAt: now},
+	}, nil)
+	storage.EXPECT().ListServicePoints(mock.Anything, true).Return([]models.ServicePoint{
+		{ID: 1, OfficeNumber: "202"},
+		{ID: 2, OfficeNumber: "101"},
+		{ID: 3, OfficeNumber: "202"},
+	}, nil)
+
+	stats, err := service.OfficeStats(context.Background(), now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+	assert.Equal(t, "", stats[0].OfficeNumber, "purged service point")
+	assert.Equal(t, 1, stats[0].Issued)
+	assert.Equal(t, "101", stats[1].OfficeNumber)
+	assert.Equal(t, 1, stats[1].Issued)
+	assert.Equal(t, "202", stats[2].OfficeNumber)
+	assert.Equal(t, 2, stats[2].Issued)
+}
+
+func TestEnqueueEstimatesWait(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	client := mocks.NewMockSPClient(t)
+	service := spservice.NewSPService(storage, client, mocks.NewMockSPProducer(t))
+
+	now := time.Now()
+	expectNoWorkingHours(storage)
+	client.EXPECT().Enqueue(mock.Anything, "1", "C").Return(&models.Ticket{Ticket: "C004"}, nil)
+	record := models.TicketRecord{
+		ID: 7, ServicePointID: 1, Code: "C004", Class: models.TicketClassRegular,
+		Status: models.TicketStatusWaiting, QueuedAt: now,
+	}
+	storage.EXPECT().CreateTicket(mock.Anything, "1", "C004", models.TicketClassRegular).Return(&record, nil)
+	storage.EXPECT().ListTickets(mock.Anything, "1", models.TicketFilter{Status: models.TicketStatusWaiting}).Return([]models.TicketRecord{
+		{ID: 5, Code: "C003", Class: models.TicketClassRegular, Status: models.TicketStatusWaiting, QueuedAt: now.Add(-time.Minute)},
+		record,
+		// Queued later but called first under the strict policy.
+		{ID: 8, Code: "PC001", Class: models.TicketClassPriority, Status: models.TicketStatusWaiting, QueuedAt: now.Add(time.Second)},
+	}, nil)
+	storage.EXPECT().RecentCallTimes(mock.Anything, "1", mock.Anything).Return([]time.Time{
+		now,
+		now.Add(-60 * time.Second),
+		now.Add(-180 * time.Second),
+		now.Add(-3 * time.Hour), // overnight, not a service time
+	}, nil)
+
+	ticket, err := service.Enqueue(context.Background(), "1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "C004", ticket.Ticket)
+	assert.Equal(t, 3, ticket.Position)
+	require.NotNil(t, ticket.EstimatedWaitSeconds)
+	assert.Equal(t, int64(270), *ticket.EstimatedWaitSeconds)
+}
+
+func TestEnqueueWithoutCallHistoryHasNoEstimate(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	client := mocks.NewMockSPClient(t)
+	service := spservice.NewSPService(storage, client, mocks.NewMockSPProducer(t))
+
+	expectNoWorkingHours(storage)
+	client.EXPECT().Enqueue(mock.Anything, "1", "C").Return(&models.Ticket{Ticket: "C001"}, nil)
+	record := models.TicketRecord{
+		ID: 1, ServicePointID: 1, Code: "C001", Class: models.TicketClassRegular, Status: models.TicketStatusWaiting,
+	}
+	storage.EXPECT().CreateTicket(mock.Anything, "1", "C001", models.TicketClassRegular).Return(&record, nil)
+	storage.EXPECT().ListTickets(mock.Anything, "1", models.TicketFilter{Status: models.TicketStatusWaiting}).Return([]models.TicketRecord{record}, nil)
+	storage.EXPECT().RecentCallTimes(mock.Anything, "1", mock.Anything).Return(nil, nil)
+
+	ticket, err := service.Enqueue(context.Background(), "1", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, ticket.Position)
+	assert.Nil(t, ticket.EstimatedWaitSeconds)
+}
+
+func TestRecallMarksNoShowAfterMaxRecalls(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	producer := mocks.NewMockSPProducer(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), producer)
+	service.SetMaxRecalls(2)
+
+	current := models.TicketRecord{ID: 4, ServicePointID: 1, Code: "C004", Status: models.TicketStatusCalled, OfficeNumber: "101", Recalls: 2}
+	storage.EXPECT().ListTickets(mock.Anything, "1", models.TicketFilter{Status: models.TicketStatusCalled}).Return([]models.TicketRecord{current}, nil)
+	noShow := current
+	noShow.Status = models.TicketStatusNoShow
+	storage.EXPECT().SetTicketStatus(mock.Anything, "1", int64(4), models.TicketStatusCalled, models.TicketStatusNoShow).Return(&noShow, nil)
+	producer.EXPECT().PublishTicketEvent(mock.Anything, models.TicketEventNoShow, "C004", "101", "").Return(nil)
+
+	ticket, err := service.Recall(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, models.TicketStatusNoShow, ticket.Status)
+}
+
+func TestCancelTicketKeepsRecordWhenQueueEngineFails(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	client := mocks.NewMockSPClient(t)
+	service := spservice.NewSPService(storage, client, mocks.NewMockSPProducer(t))
+
+	storage.EXPECT().ListTickets(mock.Anything, "1", models.TicketFilter{Code: "C002", Status: models.TicketStatusWaiting}).Return([]models.TicketRecord{
+		{ID: 2, ServicePointID: 1, Code: "C002", Status: models.TicketStatusWaiting},
+	}, nil)
+	storage.EXPECT().GetOfficeNumberById(mock.Anything, "1").Return("101", nil)
+	client.EXPECT().Cancel(mock.Anything, "1", "C002").Return(errors.New("queue engine unavailable"))
+
+	_, err := service.CancelTicket(context.Background(), "1", "C002")
+	assert.Error(t, err)
+}
+
+// cashDesk is service point 1 in office 101, without limits.
+var cashDesk = &models.ServicePoint{ID: 1, ShortName: "C", OfficeNumber: "101"}
+
+// expectNoWorkingHours lets the cash desk issue tickets at any time.
+func expectNoWorkingHours(storage *mocks.MockSPStorage) {
+	storage.EXPECT().GetServicePointByID(mock.Anything, "1", false).Return(cashDesk, nil)
+	storage.EXPECT().GetWorkingHours(mock.Anything, "1").Return(nil, models.ErrHoursNotFound)
+	storage.EXPECT().GetOfficeHours(mock.Anything, "101").Return(nil, models.ErrHoursNotFound)
+}
+
+func TestEnqueueRespectsWorkingHours(t *testing.T) {
+	day := &models.DayHours{Open: "09:00", Close: "18:00", BreakStart: "13:00", BreakEnd: "14:00"}
+	hours := &models.WorkingHours{
+		Source:   models.WorkingHoursSourceServicePoint,
+		Timezone: "Europe/Berlin",
+		Week:     models.WeekHours{Monday: day, Tuesday: day, Wednesday: day},
+		Holidays: []models.Holiday{
+			{Date: "2026-10-20", Name: "Staff training"},
+			{Date: "2026-10-21", Hours: &models.DayHours{Open: "10:00", Close: "12:00"}},
+		},
+		StopBeforeCloseMinutes: 15,
+	}
+	errPassed := errors.New("passed the working hours check")
+
+	tests := []struct {
+		name    string
+		now     string
+		closed  bool
+		message string
+	}{
+		{"before opening", "2026-10-19T06:59:00Z", true, "opens at 09:00"},
+		{"at opening", "2026-10-19T07:00:00Z", false, ""},
+		{"on break", "2026-10-19T11:30:00Z", true, "on break until 14:00"},
+		{"just before auto-close", "2026-10-19T15:44:00Z", false, ""},
+		{"auto-close", "2026-10-19T15:45:00Z", true, "last 15 minutes"},
+		{"after closing", "2026-10-19T16:00:00Z", true, "closed at 18:00"},
+		{"holiday", "2026-10-20T10:00:00Z", true, "Staff training"},
+		{"before shortened holiday hours", "2026-10-21T07:30:00Z", true, "opens at 10:00"},
+		{"in shortened holiday hours", "2026-10-21T08:30:00Z", false, ""},
+		{"closed weekday", "2026-10-25T10:00:00Z", true, "closed on Sunday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := mocks.NewMockSPStorage(t)
+			client := mocks.NewMockSPClient(t)
+			service := spservice.NewSPService(storage, client, mocks.NewMockSPProducer(t))
+
+			now, err := time.Parse(time.RFC3339, tt.now)
+			require.NoError(t, err)
+			service.SetClock(func() time.Time { return now })
+
+			storage.EXPECT().GetServicePointByID(mock.Anything, "1", false).Return(cashDesk, nil)
+			storage.EXPECT().GetWorkingHours(mock.Anything, "1").Return(hours, nil)
+			client.EXPECT().Enqueue(mock.Anything, "1", "C").Return(nil, errPassed).Maybe()
+
+			_, err = service.Enqueue(context.Background(), "1", "")
+			if !tt.closed {
+				assert.ErrorIs(t, err, errPassed)
+				return
+			}
+			assert.ErrorIs(t, err, models.ErrClosed)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestSetWorkingHoursValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		hours  models.WorkingHoursRequest
+		fields []string
+	}{
+		{
+			name:   "unknown time zone",
+			hours:  models.WorkingHoursRequest{Timezone: "Mars/Olympus"},
+			fields: []string{"timezone"},
+		},
+		{
+			name:   "closing before opening",
+			hours:  models.WorkingHoursRequest{Week: models.WeekHours{Monday: &models.DayHours{Open: "18:00", Close: "09:00"}}},
+			fields: []string{"week.monday.close"},
+		},
+		{
+			name:   "break outside hours",
+			hours:  models.WorkingHoursRequest{Week: models.WeekHours{Friday: &models.DayHours{Open: "09:00", Close: "12:00", BreakStart: "12:00", BreakEnd: "13:00"}}},
+			fields: []string{"week.friday.close"},
+		},
+		{
+			name:   "break without end",
+			hours:  models.WorkingHoursRequest{Week: models.WeekHours{Friday: &models.DayHours{Open: "09:00", Close: "12:00", BreakStart: "10:00"}}},
+			fields: []string{"week.friday"},
+		},
+		{
+			name: "repeated holiday",
+			hours: models.WorkingHoursRequest{Holidays: []models.Holiday{
+				{Date: "2026-12-25"},
+				{Date: "2026-12-25"},
+			}},
+			fields: []string{"holidays[1].date"},
+		},
+		{
+			name:   "auto-close longer than a day",
+			hours:  models.WorkingHoursRequest{StopBeforeCloseMinutes: 1440},
+			fields: []string{"stopBeforeCloseMinutes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := spservice.NewSPService(mocks.NewMockSPStorage(t), mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+			_, err := service.SetWorkingHours(context.Background(), "1", tt.hours)
+			require.ErrorIs(t, err, models.ErrInvalidArgument)
+
+			var verr *models.ValidationError
+			require.ErrorAs(t, err, &verr)
+			var fields []string
+			for _, f := range verr.Fields {
+				fields = append(fields, f.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestEnqueueEnforcesLimits(t *testing.T) {
+	limited := &models.ServicePoint{ID: 1, ShortName: "C", OfficeNumber: "101", MaxQueueLength: 3, DailyTicketQuota: 10}
+
+	tests := []struct {
+		name    string
+		waiting int
+		issued  int
+		err     error
+		events  []string
+	}{
+		{"room left", 1, 5, nil, nil},
+		{"last place in the queue", 2, 5, nil, []string{models.TicketEventQueueFull}},
+		{"last ticket of the day", 0, 9, nil, []string{models.TicketEventQuotaReached}},
+		{"queue full", 3, 5, models.ErrQueueFull, nil},
+		{"quota reached", 0, 10, models.ErrQuotaReached, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := mocks.NewMockSPStorage(t)
+			client := mocks.NewMockSPClient(t)
+			producer := mocks.NewMockSPProducer(t)
+			service := spservice.NewSPService(storage, client, producer)
+
+			storage.EXPECT().GetServicePointByID(mock.Anything, "1", false).Return(limited, nil)
+			storage.EXPECT().GetWorkingHours(mock.Anything, "1").Return(nil, models.ErrHoursNotFound)
+			storage.EXPECT().GetOfficeHours(mock.Anything, "101").Return(nil, models.ErrHoursNotFound)
+			client.EXPECT().Length(mock.Anything, "1").Return(tt.waiting, nil)
+			client.EXPECT().Length(mock.Anything, mock.Anything).Return(0, nil)
+			storage.EXPECT().CountIssuedTickets(mock.Anything, "1", mock.Anything).Return(tt.issued, nil).Maybe()
+			client.EXPECT().Enqueue(mock.Anything, "1", "C").Return(&models.Ticket{Ticket: "C007"}, nil).Maybe()
+			storage.EXPECT().CreateTicket(mock.Anything, "1", "C007", models.TicketClassRegular).Return(nil, errors.New("db down")).Maybe()
+			for _, event := range tt.events {
+				producer.EXPECT().PublishTicketEvent(mock.Anything, event, "C007", "101", "").Return(nil)
+			}
+
+			_, err := service.Enqueue(context.Background(), "1", "")
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUpsertSPRejectsNegativeLimits(t *testing.T) {
+	service := spservice.NewSPService(mocks.NewMockSPStorage(t), mocks.NewMockSPClient(t), mocks.NewMockSPProducer(t))
+
+	_, err := service.UpsertSP(context.Background(), "1", models.NewServicePointRequest{
+		Name: "Cash desk", ShortName: "C", OfficeNumber: "101", MaxQueueLength: -1, DailyTicketQuota: -5,
+	}, 0)
+
+	var verr *models.ValidationError
+	require.ErrorAs(t, err, &verr)
+	fields := make([]string, 0, len(verr.Fields))
+	for _, f := range verr.Fields {
+		fields = append(fields, f.Field)
+	}
+	assert.ElementsMatch(t, []string{"maxQueueLength", "dailyTicketQuota"}, fields)
+}
+
+func TestPauseSPIgnoresPublishFailure(t *testing.T) {
+	storage := mocks.NewMockSPStorage(t)
+	producer := mocks.NewMockSPProducer(t)
+	service := spservice.NewSPService(storage, mocks.NewMockSPClient(t), producer)
+
+	paused := &models.ServicePoint{ID: 1, OfficeNumber: "101", Status: models.ServicePointStatusPaused, StatusReason: "Lunch break"}
+	storage.EXPECT().SetServicePointStatus(mock.Anything, "1", models.ServicePointStatusPaused, models.StatusChange{Reason: "Lunch break"}).Return(paused, nil)
+	producer.EXPECT().PublishStatus(mock.Anything, *paused).Return(errors.New("kafka down"))
+
+	sp, err := service.PauseSP(context.Background(), "1", models.StatusChange{Reason: "\tLunch break "})
+	require.NoError(t, err)
+	assert.Equal(t, models.ServicePointStatusPaused, sp.Status)
+}
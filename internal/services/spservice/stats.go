@@ -0,0 +1,108 @@
+package spservice
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// peakHours is how many of the busiest hours QueueStats lists.
+const peakHours = 3
+
+// ServicePointStats summarises the tickets of a service point issued in
+// [from, to).
+func (m *SPService) ServicePointStats(ctx context.Context, id string, from, to time.Time) (*models.QueueStats, error) {
+	tickets, err := m.ListTickets(ctx, id, models.TicketFilter{IssuedFrom: from, IssuedTo: to})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := computeStats(tickets, from, to)
+	stats.ServicePointID, _ = strconv.ParseInt(id, 10, 64)
+	return &stats, nil
+}
+
+// OfficeStats summarises the tickets issued in [from, to) per office, ordered
+// by office number. Tickets are counted under the current office of their
+// service point, or under an empty office number once it has been purged.
+func (m *SPService) OfficeStats(ctx context.Context, from, to time.Time) ([]models.QueueStats, error) {
+	tickets, err := m.storage.ListAllTickets(ctx, models.TicketFilter{IssuedFrom: from, IssuedTo: to})
+	if err != nil {
+		return nil, err
+	}
+
+	servicePoints, err := m.storage.ListServicePoints(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	offices := make(map[int64]string, len(servicePoints))
+	for _, sp := range servicePoints {
+		offices[sp.ID] = sp.OfficeNumber
+	}
+
+	byOffice := make(map[string][]models.TicketRecord)
+	for _, ticket := range tickets {
+		office := offices[ticket.ServicePointID]
+		byOffice[office] = append(byOffice[office], ticket)
+	}
+
+	stats := make([]models.QueueStats, 0, len(byOffice))
+	for office, officeTickets := range byOffice {
+		s := computeStats(officeTickets, from, to)
+		s.OfficeNumber = office
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].OfficeNumber < stats[j].OfficeNumber
+	})
+	return stats, nil
+}
+
+// computeStats counts issued and served tickets, the average and 90th
+// percentile wait between issue and call of the served ones, and the busiest
+// hours of the day by tickets issued. Called tickets that turned out to be
+// no-shows or were transferred away are not served here; a transferred
+// ticket is served, if at all, by the service point it was transferred to.
+func computeStats(tickets []models.TicketRecord, from, to time.Time) models.QueueStats {
+	stats := models.QueueStats{From: from, To: to, Issued: len(tickets), PeakHours: []models.HourCount{}}
+
+	var waits []float64
+	var byHour [24]int
+	for _, ticket := range tickets {
+		byHour[ticket.IssuedAt.UTC().Hour()]++
+		if ticket.Status == models.TicketStatusServed && ticket.CalledAt != nil {
+			waits = append(waits, ticket.CalledAt.Sub(ticket.IssuedAt).Seconds())
+		}
+	}
+
+	stats.Served = len(waits)
+	if len(waits) > 0 {
+		sort.Float64s(waits)
+		var sum float64
+		for _, w := range waits {
+			sum += w
+		}
+		stats.AvgWaitSeconds = sum / float64(len(waits))
+		// Nearest-rank percentile.
+		stats.P90WaitSeconds = waits[int(math.Ceil(0.9*float64(len(waits))))-1]
+	}
+
+	for hour, issued := range byHour {
+		if issued > 0 {
+			stats.PeakHours = append(stats.PeakHours, models.HourCount{Hour: hour, Issued: issued})
+		}
+	}
+	sort.SliceStable(stats.PeakHours, func(i, j int) bool {
+		return stats.PeakHours[i].Issued > stats.PeakHours[j].Issued
+	})
+	if len(stats.PeakHours) > peakHours {
+		stats.PeakHours = stats.PeakHours[:peakHours]
+	}
+
+	return stats
+}
@@ -0,0 +1,99 @@
+package spservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// maxReasonLength caps the reason shown to visitors for a pause or closure.
+const maxReasonLength = 200
+
+// PauseSP stops a service point from issuing and calling tickets until it
+// resumes, telling visitors why and, if known, when it returns.
+func (m *SPService) PauseSP(ctx context.Context, id string, change models.StatusChange) (*models.ServicePoint, error) {
+	return m.setStatus(ctx, id, models.ServicePointStatusPaused, change)
+}
+
+// ResumeSP opens a paused or closed service point again.
+func (m *SPService) ResumeSP(ctx context.Context, id string) (*models.ServicePoint, error) {
+	return m.setStatus(ctx, id, models.ServicePointStatusOpen, models.StatusChange{})
+}
+
+// CloseSP stops a service point from issuing tickets. The tickets already
+// waiting can still be called; the ticket being served is served.
+func (m *SPService) CloseSP(ctx context.Context, id string, change models.StatusChange) (*models.ServicePoint, error) {
+	return m.setStatus(ctx, id, models.ServicePointStatusClosed, change)
+}
+
+func (m *SPService) setStatus(ctx context.Context, id string, status string, change models.StatusChange) (*models.ServicePoint, error) {
+	change, err := m.normalizeStatusChange(change)
+	if err != nil {
+		return nil, err
+	}
+
+	sp, err := m.storage.SetServicePointStatus(ctx, id, status, change)
+	if err != nil {
+		return nil, err
+	}
+	if status == models.ServicePointStatusClosed {
+		m.serveCurrent(ctx, id)
+	}
+
+	// Displays learn about the change from the event, but the change itself
+	// is stored, so a failed publish must not fail the request.
+	if err := m.producer.PublishStatus(ctx, *sp); err != nil {
+		log.Printf("failed to publish status of service point %d: %s", sp.ID, err)
+	}
+	return sp, nil
+}
+
+func (m *SPService) normalizeStatusChange(change models.StatusChange) (models.StatusChange, error) {
+	var verr models.ValidationError
+
+	change.Reason = strings.TrimSpace(change.Reason)
+	if change.Reason != "" && checkLength(&verr, "reason", change.Reason, maxReasonLength) {
+		checkPrintable(&verr, "reason", change.Reason)
+	}
+	if change.ReturnAt != nil {
+		if !change.ReturnAt.After(m.now()) {
+			verr.Add("returnAt", "must be in the future")
+		}
+		returnAt := change.ReturnAt.UTC().Truncate(time.Second)
+		change.ReturnAt = &returnAt
+	}
+
+	return change, verr.Err()
+}
+
+// checkStatus reports models.ErrPaused for a paused service point and, if it
+// is about to issue a ticket, models.ErrClosed for a closed one, with the
+// reason and return time visitors are told.
+func checkStatus(sp *models.ServicePoint, issuing bool) error {
+	switch {
+	case sp.Status == models.ServicePointStatusPaused:
+		return statusError(models.ErrPaused, sp)
+	case sp.Status == models.ServicePointStatusClosed && issuing:
+		return statusError(models.ErrClosed, sp)
+	default:
+		return nil
+	}
+}
+
+func statusError(err error, sp *models.ServicePoint) error {
+	var details []string
+	if sp.StatusReason != "" {
+		details = append(details, sp.StatusReason)
+	}
+	if sp.ReturnAt != nil {
+		details = append(details, "back at "+sp.ReturnAt.UTC().Format(time.RFC3339))
+	}
+	if len(details) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w: %s", err, strings.Join(details, ", "))
+}
@@ -0,0 +1,87 @@
+package spservice
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// Column sizes of shard_N.service_points.
+const (
+	maxNameLength         = 255
+	maxShortNameLength    = 10
+	maxOfficeNumberLength = 10
+)
+
+// normalizeServicePoint trims surrounding whitespace from every field and
+// reports all fields that do not fit the service_points columns.
+func normalizeServicePoint(sp models.NewServicePointRequest) (models.NewServicePointRequest, error) {
+	sp.Name = strings.TrimSpace(sp.Name)
+	sp.ShortName = strings.TrimSpace(sp.ShortName)
+	sp.OfficeNumber = strings.TrimSpace(sp.OfficeNumber)
+
+	var verr models.ValidationError
+
+	checkLength(&verr, "name", sp.Name, maxNameLength)
+	checkPrintable(&verr, "name", sp.Name)
+
+	checkShortName(&verr, "shortName", sp.ShortName)
+
+	checkLength(&verr, "officeNumber", sp.OfficeNumber, maxOfficeNumberLength)
+	checkPrintable(&verr, "officeNumber", sp.OfficeNumber)
+
+	if sp.OfficeID < 0 {
+		verr.Add("officeId", "must not be negative")
+	}
+	if sp.MaxQueueLength < 0 {
+		verr.Add("maxQueueLength", "must not be negative")
+	}
+	if sp.DailyTicketQuota < 0 {
+		verr.Add("dailyTicketQuota", "must not be negative")
+	}
+
+	return sp, verr.Err()
+}
+
+// checkShortName reports a short name unfit to prefix ticket numbers. Short
+// names are limited to letters and digits, must start with a letter and must
+// not end with a digit: "B" and "A1B" are fine, "A1" would make ticket A1017
+// ambiguous.
+func checkShortName(verr *models.ValidationError, field, value string) {
+	if !checkLength(verr, field, value, maxShortNameLength) {
+		return
+	}
+	first, _ := utf8.DecodeRuneInString(value)
+	last, _ := utf8.DecodeLastRuneInString(value)
+	switch {
+	case strings.IndexFunc(value, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) >= 0:
+		verr.Add(field, "must contain only letters and digits")
+	case !unicode.IsLetter(first):
+		verr.Add(field, "must start with a letter")
+	case unicode.IsDigit(last):
+		verr.Add(field, "must not end with a digit")
+	}
+}
+
+// checkLength reports an empty or too long value and returns whether the
+// value passed.
+func checkLength(verr *models.ValidationError, field, value string, max int) bool {
+	n := utf8.RuneCountInString(value)
+	switch {
+	case n == 0:
+		verr.Add(field, "is required")
+		return false
+	case n > max:
+		verr.Add(field, "must be at most %d characters", max)
+		return false
+	}
+	return true
+}
+
+func checkPrintable(verr *models.ValidationError, field, value string) {
+	if strings.IndexFunc(value, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0 {
+		verr.Add(field, "must not contain control characters")
+	}
+}
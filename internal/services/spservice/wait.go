@@ -0,0 +1,161 @@
+package spservice
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+const (
+	// serviceTimeWindow is how many recent calls the service time of a
+	// service point is averaged over.
+	serviceTimeWindow = 10
+	// maxServiceGap is the longest gap between two calls that still counts
+	// as serving a visitor rather than a break or a closed office.
+	maxServiceGap = time.Hour
+)
+
+// TicketStatus returns the ticket issued with code by any service point or
+// category, with its current position and, for service points, estimated
+// wait. A ticket still waiting or called wins over older finished ones; if
+// several service points have one, the code is ambiguous.
+func (m *SPService) TicketStatus(ctx context.Context, code string) (*models.Ticket, error) {
+	tickets, err := m.storage.ListAllTickets(ctx, models.TicketFilter{Code: code})
+	if err != nil {
+		return nil, err
+	}
+	record, err := codeTicket(code, tickets)
+	if err != nil {
+		return nil, err
+	}
+	// A ticket still waiting in the line of a category is not a ticket of a
+	// service point yet.
+	waiting, err := m.storage.ListCategoryTickets(ctx, models.CategoryTicketFilter{Code: code, Status: models.TicketStatusWaiting})
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(waiting) > 0 && (record == nil || waiting[len(waiting)-1].IssuedAt.After(record.IssuedAt)):
+		return m.categoryTicketStatus(ctx, &waiting[len(waiting)-1])
+	case record != nil:
+		return m.ticketStatus(ctx, record, nil)
+	default:
+		return nil, models.ErrTicketNotFound
+	}
+}
+
+// codeTicket picks the ticket a code stands for among tickets ordered by
+// issue time: the newest still waiting or called, else the newest at all. A
+// transferred ticket lives on at its new service point under the same code
+// and is left out. It is nil if tickets is empty.
+func codeTicket(code string, tickets []models.TicketRecord) (*models.TicketRecord, error) {
+	var current *models.TicketRecord
+	for i := range tickets {
+		switch tickets[i].Status {
+		case models.TicketStatusWaiting, models.TicketStatusCalled:
+		default:
+			continue
+		}
+		if current != nil && current.ServicePointID != tickets[i].ServicePointID {
+			return nil, fmt.Errorf("%w: %s is in the queues of service points %d and %d, ask for it by service point",
+				models.ErrAmbiguousTicket, code, current.ServicePointID, tickets[i].ServicePointID)
+		}
+		current = &tickets[i]
+	}
+	if current == nil && len(tickets) > 0 {
+		current = &tickets[len(tickets)-1]
+	}
+	return current, nil
+}
+
+// ServicePointTicketStatus is TicketStatus for a code issued by one service
+// point, which stays unambiguous when several service points share a prefix.
+func (m *SPService) ServicePointTicketStatus(ctx context.Context, spID string, code string) (*models.Ticket, error) {
+	tickets, err := m.storage.ListTickets(ctx, spID, models.TicketFilter{Code: code})
+	if err != nil {
+		return nil, err
+	}
+	if len(tickets) == 0 {
+		return nil, models.ErrTicketNotFound
+	}
+	return m.ticketStatus(ctx, &tickets[len(tickets)-1], nil)
+}
+
+// ticketStatus turns a recorded ticket into what the visitor sees. Only
+// waiting tickets have a position and estimate, and are told if their service
+// point is paused or closed. sp is the service point of the ticket, or nil to
+// look it up.
+func (m *SPService) ticketStatus(ctx context.Context, record *models.TicketRecord, sp *models.ServicePoint) (*models.Ticket, error) {
+	ticket := &models.Ticket{
+		Ticket:         record.Code,
+		ServicePointID: record.ServicePointID,
+		Status:         record.Status,
+		OfficeNumber:   record.OfficeNumber,
+		DeskNumber:     record.DeskNumber,
+		Class:          record.Class,
+	}
+	if record.Status != models.TicketStatusWaiting {
+		return ticket, nil
+	}
+
+	spID := strconv.FormatInt(record.ServicePointID, 10)
+
+	if sp == nil {
+		var err error
+		sp, err = m.storage.GetServicePointByID(ctx, spID, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sp.Status != models.ServicePointStatusOpen {
+		ticket.ServicePointStatus = sp.Status
+		ticket.StatusReason = sp.StatusReason
+		ticket.ReturnAt = sp.ReturnAt
+	}
+
+	waiting, err := m.storage.ListTickets(ctx, spID, models.TicketFilter{Status: models.TicketStatusWaiting})
+	if err != nil {
+		return nil, err
+	}
+	position := m.linePosition(spID, waiting, record)
+	ticket.Position = position
+
+	serviceTime, err := m.serviceTime(ctx, spID)
+	if err != nil {
+		return nil, err
+	}
+	if serviceTime > 0 {
+		wait := int64((time.Duration(position) * serviceTime).Seconds())
+		ticket.EstimatedWaitSeconds = &wait
+	}
+	return ticket, nil
+}
+
+// serviceTime is the average time between the recent calls of a service
+// point, or 0 if there are too few to tell.
+func (m *SPService) serviceTime(ctx context.Context, spID string) (time.Duration, error) {
+	calls, err := m.storage.RecentCallTimes(ctx, spID, serviceTimeWindow+1)
+	if err != nil {
+		return 0, err
+	}
+
+	var total time.Duration
+	var gaps int
+	for i := 1; i < len(calls); i++ {
+		gap := calls[i-1].Sub(calls[i])
+		if gap > maxServiceGap {
+			continue
+		}
+		total += gap
+		gaps++
+	}
+
+	if gaps == 0 {
+		return 0, nil
+	}
+	return total / time.Duration(gaps), nil
+}
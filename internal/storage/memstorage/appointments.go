@@ -0,0 +1,193 @@
+package memstorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (s *SPStorage) UpsertSlotSchedule(ctx context.Context, spID string, schedule models.SlotScheduleRequest) (*models.SlotSchedule, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set slot schedule: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	stored := models.SlotSchedule{
+		ServicePointID: key,
+		SlotMinutes:    schedule.SlotMinutes,
+		Capacity:       schedule.Capacity,
+		UpdatedAt:      time.Now(),
+	}
+	d.schedules[key] = stored
+
+	return &stored, nil
+}
+
+func (s *SPStorage) GetSlotSchedule(ctx context.Context, spID string) (*models.SlotSchedule, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slot schedule: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	schedule, ok := d.schedules[key]
+	if !ok {
+		return nil, fmt.Errorf("failed to get slot schedule: %w", models.ErrScheduleNotFound)
+	}
+	return &schedule, nil
+}
+
+func (s *SPStorage) BookAppointment(ctx context.Context, spID string, appointment models.Appointment) (*models.Appointment, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to book appointment: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	schedule, ok := d.schedules[key]
+	if !ok {
+		return nil, fmt.Errorf("failed to book appointment: %w", models.ErrScheduleNotFound)
+	}
+
+	var booked int
+	for _, a := range d.appointments {
+		if a.ServicePointID == key && a.SlotStart.Equal(appointment.SlotStart) && takesSlot(a) {
+			booked++
+		}
+	}
+	if booked >= schedule.Capacity {
+		return nil, fmt.Errorf("failed to book appointment: %w", models.ErrSlotFull)
+	}
+
+	now := time.Now()
+	s.lastAppointmentID++
+	stored := models.Appointment{
+		ID:             s.lastAppointmentID,
+		ServicePointID: key,
+		SlotStart:      appointment.SlotStart,
+		SlotEnd:        appointment.SlotEnd,
+		Name:           appointment.Name,
+		Status:         models.AppointmentStatusBooked,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	d.appointments[stored.ID] = stored
+
+	return &stored, nil
+}
+
+func (s *SPStorage) GetAppointment(ctx context.Context, id string) (*models.Appointment, error) {
+	key, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointment: %w", models.ErrAppointmentNotFound)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	appointment, ok := d.appointments[key]
+	if !ok {
+		return nil, fmt.Errorf("failed to get appointment: %w", models.ErrAppointmentNotFound)
+	}
+	return &appointment, nil
+}
+
+func (s *SPStorage) ListAppointments(ctx context.Context, spID string, from, to time.Time) ([]models.Appointment, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list appointments: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	appointments := []models.Appointment{}
+	for _, a := range d.appointments {
+		if a.ServicePointID == key && !a.SlotStart.Before(from) && a.SlotStart.Before(to) {
+			appointments = append(appointments, a)
+		}
+	}
+
+	sort.Slice(appointments, func(i, j int) bool {
+		if !appointments[i].SlotStart.Equal(appointments[j].SlotStart) {
+			return appointments[i].SlotStart.Before(appointments[j].SlotStart)
+		}
+		return appointments[i].ID < appointments[j].ID
+	})
+	return appointments, nil
+}
+
+func (s *SPStorage) SetAppointmentStatus(ctx context.Context, spID string, id int64, from string, to string, ticket string) (*models.Appointment, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update appointment: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	appointment, ok := d.appointments[id]
+	if !ok || appointment.ServicePointID != key || appointment.Status != from {
+		return nil, fmt.Errorf("failed to update appointment: %w", models.ErrAppointmentState)
+	}
+	appointment.Status = to
+	if ticket != "" {
+		appointment.Ticket = ticket
+	}
+	appointment.UpdatedAt = time.Now()
+	d.appointments[id] = appointment
+
+	return &appointment, nil
+}
+
+// ExpireAppointments covers all organisations, whatever the one in ctx.
+func (s *SPStorage) ExpireAppointments(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tenantsMu.Lock()
+	defer s.tenantsMu.Unlock()
+
+	var expired int64
+	now := time.Now()
+	for _, d := range s.tenants {
+		for id, appointment := range d.appointments {
+			if appointment.Status == models.AppointmentStatusBooked && appointment.SlotEnd.Before(before) {
+				appointment.Status = models.AppointmentStatusExpired
+				appointment.UpdatedAt = now
+				d.appointments[id] = appointment
+				expired++
+			}
+		}
+	}
+	return expired, nil
+}
+
+// takesSlot reports whether an appointment counts against the capacity of
+// its slot.
+func takesSlot(a models.Appointment) bool {
+	return a.Status == models.AppointmentStatusBooked || a.Status == models.AppointmentStatusCheckedIn
+}
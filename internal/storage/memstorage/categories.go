@@ -0,0 +1,234 @@
+package memstorage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (s *SPStorage) CreateCategory(ctx context.Context, category models.NewCategoryRequest) (*models.Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	for _, other := range d.categories {
+		if other.ShortName == category.ShortName {
+			return nil, fmt.Errorf("failed to create category: %w", models.ErrAlreadyExists)
+		}
+	}
+
+	s.lastCategoryID++
+	c := models.Category{
+		ID:        s.lastCategoryID,
+		Name:      category.Name,
+		ShortName: category.ShortName,
+		CreatedAt: time.Now(),
+	}
+	d.categories = append(d.categories, c)
+	return d.withServicePoints(c), nil
+}
+
+func (s *SPStorage) GetCategory(ctx context.Context, id string) (*models.Category, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	for _, c := range d.categories {
+		if c.ID == key {
+			return d.withServicePoints(c), nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get category: %w", models.ErrCategoryNotFound)
+}
+
+func (s *SPStorage) ListCategories(ctx context.Context) ([]models.Category, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	categories := make([]models.Category, 0, len(d.categories))
+	for _, c := range d.categories {
+		categories = append(categories, *d.withServicePoints(c))
+	}
+	return categories, nil
+}
+
+func (s *SPStorage) ListServicePointCategories(ctx context.Context, spID string) ([]models.Category, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	return d.servicePointCategories(key), nil
+}
+
+func (s *SPStorage) SetServicePointCategories(ctx context.Context, spID string, categoryIDs []int64) ([]models.Category, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set categories: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	for _, id := range categoryIDs {
+		if !slices.ContainsFunc(d.categories, func(c models.Category) bool { return c.ID == id }) {
+			return nil, fmt.Errorf("failed to set categories: %w", models.ErrCategoryNotFound)
+		}
+	}
+
+	for _, c := range d.categories {
+		points := slices.DeleteFunc(d.categoryPoints[c.ID], func(id int64) bool { return id == key })
+		if slices.Contains(categoryIDs, c.ID) {
+			points = append(points, key)
+			slices.Sort(points)
+		}
+		d.categoryPoints[c.ID] = points
+	}
+	return d.servicePointCategories(key), nil
+}
+
+// servicePointCategories returns the categories a service point serves. The
+// caller must hold s.mu.
+func (d *tenantData) servicePointCategories(key int64) []models.Category {
+	categories := []models.Category{}
+	for _, c := range d.categories {
+		if slices.Contains(d.categoryPoints[c.ID], key) {
+			categories = append(categories, *d.withServicePoints(c))
+		}
+	}
+	return categories
+}
+
+// withServicePoints returns a copy of c listing the service points serving
+// it. The caller must hold s.mu.
+func (d *tenantData) withServicePoints(c models.Category) *models.Category {
+	c.ServicePointIDs = append([]int64{}, d.categoryPoints[c.ID]...)
+	return &c
+}
+
+func (s *SPStorage) CreateCategoryTicket(ctx context.Context, categoryID int64, code string) (*models.CategoryTicket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	s.lastCategoryTicketID++
+	ticket := models.CategoryTicket{
+		ID:         s.lastCategoryTicketID,
+		CategoryID: categoryID,
+		Code:       code,
+		Status:     models.TicketStatusWaiting,
+		IssuedAt:   time.Now(),
+	}
+	d.categoryTickets = append(d.categoryTickets, ticket)
+	return &ticket, nil
+}
+
+func (s *SPStorage) ListCategoryTickets(ctx context.Context, filter models.CategoryTicketFilter) ([]models.CategoryTicket, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	tickets := []models.CategoryTicket{}
+	for _, ticket := range d.categoryTickets {
+		if filter.CategoryIDs != nil && !slices.Contains(filter.CategoryIDs, ticket.CategoryID) {
+			continue
+		}
+		if filter.Code != "" && ticket.Code != filter.Code {
+			continue
+		}
+		if filter.Status != "" && ticket.Status != filter.Status {
+			continue
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, nil
+}
+
+func (s *SPStorage) SetCategoryTicketStatus(ctx context.Context, ticketID int64, from string, to string) (*models.CategoryTicket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	for i := range d.categoryTickets {
+		ticket := &d.categoryTickets[i]
+		if ticket.ID != ticketID || ticket.Status != from {
+			continue
+		}
+		ticket.Status = to
+		updated := *ticket
+		return &updated, nil
+	}
+	return nil, fmt.Errorf("failed to update category ticket: %w", models.ErrTicketNotFound)
+}
+
+func (s *SPStorage) ClaimCategoryTicket(ctx context.Context, categoryID int64, code string, spID string, call models.TicketCall) (*models.TicketRecord, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim category ticket: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	for i := len(d.categoryTickets) - 1; i >= 0; i-- {
+		claimed := &d.categoryTickets[i]
+		if claimed.CategoryID != categoryID || claimed.Code != code || claimed.Status != models.TicketStatusWaiting {
+			continue
+		}
+
+		now := time.Now()
+		claimed.Status = models.TicketStatusCalled
+		claimed.CalledAt = &now
+		claimed.ServicePointID = key
+
+		tickets := d.tickets[key]
+		for j := range tickets {
+			if tickets[j].Status == models.TicketStatusCalled {
+				tickets[j].Status = models.TicketStatusServed
+			}
+		}
+
+		s.lastTicketID++
+		ticket := models.TicketRecord{
+			ID:             s.lastTicketID,
+			ServicePointID: key,
+			Code:           code,
+			Status:         models.TicketStatusCalled,
+			IssuedAt:       claimed.IssuedAt,
+			CalledAt:       &now,
+			OfficeNumber:   call.OfficeNumber,
+			QueuedAt:       claimed.IssuedAt,
+			Class:          models.TicketClassRegular,
+			OperatorID:     call.OperatorID,
+			DeskNumber:     call.DeskNumber,
+			CategoryID:     categoryID,
+		}
+		d.tickets[key] = append(tickets, ticket)
+		return &ticket, nil
+	}
+	return nil, fmt.Errorf("failed to claim category ticket: %w", models.ErrTicketNotFound)
+}
@@ -0,0 +1,129 @@
+package memstorage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (s *SPStorage) UpsertWorkingHours(ctx context.Context, spID string, hours models.WorkingHoursRequest) (*models.WorkingHours, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set working hours: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	stored := workingHours(hours, models.WorkingHoursSourceServicePoint)
+	stored.ServicePointID = key
+	d.hours[key] = stored
+
+	return copyWorkingHours(stored), nil
+}
+
+func (s *SPStorage) GetWorkingHours(ctx context.Context, spID string) (*models.WorkingHours, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working hours: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	hours, ok := d.hours[key]
+	if !ok {
+		return nil, fmt.Errorf("failed to get working hours: %w", models.ErrHoursNotFound)
+	}
+	return copyWorkingHours(hours), nil
+}
+
+func (s *SPStorage) DeleteWorkingHours(ctx context.Context, spID string) error {
+	key, err := parseID(spID)
+	if err != nil {
+		return fmt.Errorf("failed to delete working hours: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	if _, ok := d.hours[key]; !ok {
+		return fmt.Errorf("failed to delete working hours: %w", models.ErrHoursNotFound)
+	}
+	delete(d.hours, key)
+	return nil
+}
+
+func (s *SPStorage) UpsertOfficeHours(ctx context.Context, officeNumber string, hours models.WorkingHoursRequest) (*models.WorkingHours, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	stored := workingHours(hours, models.WorkingHoursSourceOffice)
+	stored.OfficeNumber = officeNumber
+	d.officeHours[officeNumber] = stored
+
+	return copyWorkingHours(stored), nil
+}
+
+func (s *SPStorage) GetOfficeHours(ctx context.Context, officeNumber string) (*models.WorkingHours, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	hours, ok := d.officeHours[officeNumber]
+	if !ok {
+		return nil, fmt.Errorf("failed to get office hours: %w", models.ErrHoursNotFound)
+	}
+	return copyWorkingHours(hours), nil
+}
+
+func workingHours(hours models.WorkingHoursRequest, source string) models.WorkingHours {
+	return models.WorkingHours{
+		Source:                 source,
+		Timezone:               hours.Timezone,
+		Week:                   hours.Week,
+		Holidays:               hours.Holidays,
+		StopBeforeCloseMinutes: hours.StopBeforeCloseMinutes,
+		UpdatedAt:              time.Now(),
+	}
+}
+
+// copyWorkingHours deep-copies stored hours, like the JSONB round trip of
+// the Postgres storage, so callers cannot change them in place.
+func copyWorkingHours(hours models.WorkingHours) *models.WorkingHours {
+	copyDay := func(d *models.DayHours) *models.DayHours {
+		if d == nil {
+			return nil
+		}
+		c := *d
+		return &c
+	}
+
+	c := hours
+	c.Week = models.WeekHours{
+		Monday:    copyDay(hours.Week.Monday),
+		Tuesday:   copyDay(hours.Week.Tuesday),
+		Wednesday: copyDay(hours.Week.Wednesday),
+		Thursday:  copyDay(hours.Week.Thursday),
+		Friday:    copyDay(hours.Week.Friday),
+		Saturday:  copyDay(hours.Week.Saturday),
+		Sunday:    copyDay(hours.Week.Sunday),
+	}
+	c.Holidays = nil
+	for _, h := range hours.Holidays {
+		h.Hours = copyDay(h.Hours)
+		c.Holidays = append(c.Holidays, h)
+	}
+	return &c
+}
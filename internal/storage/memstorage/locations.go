@@ -0,0 +1,218 @@
+package memstorage
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (s *SPStorage) CreateBuilding(ctx context.Context, building models.NewBuildingRequest) (*models.Building, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	s.lastBuildingID++
+	b := models.Building{
+		ID:         s.lastBuildingID,
+		Name:       building.Name,
+		Address:    building.Address,
+		Directions: building.Directions,
+		CreatedAt:  time.Now(),
+	}
+	d.buildings = append(d.buildings, b)
+	return &b, nil
+}
+
+func (s *SPStorage) GetBuilding(ctx context.Context, id string) (*models.Building, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get building: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.tenant(ctx).building(key)
+	if !ok {
+		return nil, fmt.Errorf("failed to get building: %w", models.ErrBuildingNotFound)
+	}
+	return &b, nil
+}
+
+func (s *SPStorage) ListBuildings(ctx context.Context) ([]models.Building, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]models.Building{}, s.tenant(ctx).buildings...), nil
+}
+
+func (s *SPStorage) CreateFloor(ctx context.Context, buildingID string, floor models.NewFloorRequest) (*models.Floor, error) {
+	key, err := parseID(buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create floor: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	if _, ok := d.building(key); !ok {
+		return nil, fmt.Errorf("failed to create floor: %w", models.ErrBuildingNotFound)
+	}
+	for _, other := range d.floors {
+		if other.BuildingID == key && other.Level == floor.Level {
+			return nil, fmt.Errorf("failed to create floor: %w", models.ErrFloorExists)
+		}
+	}
+
+	s.lastFloorID++
+	f := models.Floor{
+		ID:         s.lastFloorID,
+		BuildingID: key,
+		Name:       floor.Name,
+		Level:      floor.Level,
+		Directions: floor.Directions,
+		CreatedAt:  time.Now(),
+	}
+	d.floors = append(d.floors, f)
+	return &f, nil
+}
+
+func (s *SPStorage) GetFloor(ctx context.Context, id string) (*models.Floor, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get floor: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f, ok := s.tenant(ctx).floor(key)
+	if !ok {
+		return nil, fmt.Errorf("failed to get floor: %w", models.ErrFloorNotFound)
+	}
+	return &f, nil
+}
+
+// ListFloors returns the floors of a building from the lowest level up, like
+// the Postgres storage.
+func (s *SPStorage) ListFloors(ctx context.Context, buildingID string) ([]models.Floor, error) {
+	key, err := parseID(buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list floors: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	floors := []models.Floor{}
+	for _, f := range s.tenant(ctx).floors {
+		if f.BuildingID == key {
+			floors = append(floors, f)
+		}
+	}
+	slices.SortFunc(floors, func(a, b models.Floor) int {
+		return cmp.Compare(a.Level, b.Level)
+	})
+	return floors, nil
+}
+
+func (s *SPStorage) CreateOffice(ctx context.Context, floorID string, office models.NewOfficeRequest) (*models.Office, error) {
+	key, err := parseID(floorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create office: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	if _, ok := d.floor(key); !ok {
+		return nil, fmt.Errorf("failed to create office: %w", models.ErrFloorNotFound)
+	}
+	for _, other := range d.offices {
+		if other.FloorID == key && other.Number == office.Number {
+			return nil, fmt.Errorf("failed to create office: %w", models.ErrOfficeExists)
+		}
+	}
+
+	s.lastOfficeID++
+	o := models.Office{
+		ID:         s.lastOfficeID,
+		FloorID:    key,
+		Number:     office.Number,
+		Name:       office.Name,
+		Directions: office.Directions,
+		CreatedAt:  time.Now(),
+	}
+	d.offices = append(d.offices, o)
+	return &o, nil
+}
+
+// ListOffices returns the offices on a floor ordered by number, like the
+// Postgres storage.
+func (s *SPStorage) ListOffices(ctx context.Context, floorID string) ([]models.Office, error) {
+	key, err := parseID(floorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offices: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	offices := []models.Office{}
+	for _, o := range s.tenant(ctx).offices {
+		if o.FloorID == key {
+			offices = append(offices, o)
+		}
+	}
+	slices.SortFunc(offices, func(a, b models.Office) int {
+		return cmp.Compare(a.Number, b.Number)
+	})
+	return offices, nil
+}
+
+func (s *SPStorage) GetLocation(ctx context.Context, officeID string) (*models.Location, error) {
+	key, err := parseID(officeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	for _, o := range d.offices {
+		if o.ID != key {
+			continue
+		}
+		f, _ := d.floor(o.FloorID)
+		b, _ := d.building(f.BuildingID)
+		return &models.Location{Office: o, Floor: f, Building: b}, nil
+	}
+	return nil, fmt.Errorf("failed to get location: %w", models.ErrOfficeNotFound)
+}
+
+func (d *tenantData) building(id int64) (models.Building, bool) {
+	i := slices.IndexFunc(d.buildings, func(b models.Building) bool { return b.ID == id })
+	if i < 0 {
+		return models.Building{}, false
+	}
+	return d.buildings[i], true
+}
+
+func (d *tenantData) floor(id int64) (models.Floor, bool) {
+	i := slices.IndexFunc(d.floors, func(f models.Floor) bool { return f.ID == id })
+	if i < 0 {
+		return models.Floor{}, false
+	}
+	return d.floors[i], true
+}
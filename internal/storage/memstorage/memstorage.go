@@ -0,0 +1,525 @@
+package memstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/snnus/mainservice/config"
+	"github.com/snnus/mainservice/internal/audit"
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/storage/shard"
+	"github.com/snnus/mainservice/internal/tenant"
+)
+
+// SPStorage keeps service points in memory. It follows the Postgres storage
+// semantics: ids are numeric, upserts keep created_at and bump updated_at and
+// version, missing rows are reported as models.ErrNotFound, deletes are soft,
+// every write is audited, every service point carries the shard id the
+// Postgres storage would have put it in, and every organisation only sees
+// its own data while ids stay unique across them.
+type SPStorage struct {
+	mu          sync.RWMutex
+	nShards     uint32
+	lastID      int64
+	lastAuditID int64
+	// lastTicketID numbers tickets across all service points, like the
+	// per-shard sequences of the Postgres storage.
+	lastTicketID         int64
+	lastAppointmentID    int64
+	lastOperatorID       int64
+	lastSessionID        int64
+	lastCategoryID       int64
+	lastCategoryTicketID int64
+	lastBuildingID       int64
+	lastFloorID          int64
+	lastOfficeID         int64
+
+	organisations []models.Organisation
+	// tenants holds the data of each organisation by its id. tenantsMu
+	// guards the map itself so it can grow under either mode of mu, which
+	// guards the data.
+	tenantsMu sync.Mutex
+	tenants   map[string]*tenantData
+}
+
+// tenantData is everything an organisation owns.
+type tenantData struct {
+	points  map[int64]models.ServicePoint
+	history map[int64][]models.AuditRecord
+	tickets map[int64][]models.TicketRecord
+
+	schedules    map[int64]models.SlotSchedule
+	appointments map[int64]models.Appointment
+
+	hours       map[int64]models.WorkingHours
+	officeHours map[string]models.WorkingHours
+
+	operators []models.Operator
+	sessions  []models.Session
+
+	categories []models.Category
+	// categoryPoints lists the service points serving each category.
+	categoryPoints  map[int64][]int64
+	categoryTickets []models.CategoryTicket
+
+	buildings []models.Building
+	floors    []models.Floor
+	offices   []models.Office
+}
+
+func NewSPStorage(cfg *config.Config) (*SPStorage, func() error, error) {
+	nShards := cfg.Postgres.NShards
+	if nShards == 0 {
+		nShards = 1
+	}
+	s := &SPStorage{
+		nShards:       nShards,
+		organisations: []models.Organisation{{ID: tenant.Default, Name: "Default", CreatedAt: time.Now()}},
+		tenants:       make(map[string]*tenantData),
+	}
+	return s, func() error { return nil }, nil
+}
+
+// tenant returns the data of the organisation in ctx, empty on first use.
+func (s *SPStorage) tenant(ctx context.Context) *tenantData {
+	s.tenantsMu.Lock()
+	defer s.tenantsMu.Unlock()
+
+	id := tenant.FromContext(ctx)
+	d, ok := s.tenants[id]
+	if !ok {
+		d = &tenantData{
+			points:  make(map[int64]models.ServicePoint),
+			history: make(map[int64][]models.AuditRecord),
+			tickets: make(map[int64][]models.TicketRecord),
+
+			schedules:    make(map[int64]models.SlotSchedule),
+			appointments: make(map[int64]models.Appointment),
+
+			hours:       make(map[int64]models.WorkingHours),
+			officeHours: make(map[string]models.WorkingHours),
+
+			categoryPoints: make(map[int64][]int64),
+		}
+		s.tenants[id] = d
+	}
+	return d
+}
+
+// taken reports whether another organisation than the one in ctx has a
+// service point, deleted or not, with the given id. s.mu must be held.
+func (s *SPStorage) taken(ctx context.Context, key int64) bool {
+	s.tenantsMu.Lock()
+	defer s.tenantsMu.Unlock()
+
+	for id, d := range s.tenants {
+		if _, ok := d.points[key]; ok && id != tenant.FromContext(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SPStorage) UpsertServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest, ifVersion int64) (*models.ServicePoint, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service point: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.upsert(ctx, key, id, sp, ifVersion)
+}
+
+// upsert is UpsertServicePoint with s.mu held.
+func (s *SPStorage) upsert(ctx context.Context, key int64, id string, sp models.NewServicePointRequest, ifVersion int64) (*models.ServicePoint, error) {
+	d := s.tenant(ctx)
+
+	if s.taken(ctx, key) {
+		return nil, fmt.Errorf("failed to create service point: %w", models.ErrAlreadyExists)
+	}
+
+	now := time.Now()
+	servicePoint, ok := d.points[key]
+	before := servicePoint
+	if ok && servicePoint.DeletedAt != nil {
+		return nil, fmt.Errorf("failed to update service point: %w", models.ErrDeleted)
+	}
+	if ifVersion != 0 && (!ok || servicePoint.Version != ifVersion) {
+		return nil, fmt.Errorf("failed to update service point: %w", models.ErrVersionMismatch)
+	}
+	if !ok {
+		servicePoint = models.ServicePoint{
+			ID:        key,
+			Status:    models.ServicePointStatusOpen,
+			CreatedAt: now,
+			ShardID:   int(shard.Of(shard.Hash(id), s.nShards)),
+		}
+	}
+	servicePoint.Version++
+	servicePoint.Name = sp.Name
+	servicePoint.ShortName = sp.ShortName
+	servicePoint.OfficeNumber = sp.OfficeNumber
+	servicePoint.OfficeID = sp.OfficeID
+	servicePoint.MaxQueueLength = sp.MaxQueueLength
+	servicePoint.DailyTicketQuota = sp.DailyTicketQuota
+	servicePoint.UpdatedAt = now
+	d.points[key] = servicePoint
+	s.lastID = max(s.lastID, key)
+
+	if ok {
+		s.record(ctx, models.AuditActionUpdate, &before, servicePoint)
+	} else {
+		s.record(ctx, models.AuditActionCreate, nil, servicePoint)
+	}
+
+	return &servicePoint, nil
+}
+
+// NextServicePointID hands out ids after the largest one ever stored.
+func (s *SPStorage) NextServicePointID(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	return s.lastID, nil
+}
+
+func (s *SPStorage) CreateServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest) (*models.ServicePoint, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service point: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.create(ctx, key, id, sp)
+}
+
+// create is CreateServicePoint with s.mu held.
+func (s *SPStorage) create(ctx context.Context, key int64, id string, sp models.NewServicePointRequest) (*models.ServicePoint, error) {
+	d := s.tenant(ctx)
+
+	if _, ok := d.points[key]; ok || s.taken(ctx, key) {
+		return nil, fmt.Errorf("failed to create service point: %w", models.ErrAlreadyExists)
+	}
+
+	now := time.Now()
+	servicePoint := models.ServicePoint{
+		ID:               key,
+		Name:             sp.Name,
+		ShortName:        sp.ShortName,
+		OfficeNumber:     sp.OfficeNumber,
+		OfficeID:         sp.OfficeID,
+		MaxQueueLength:   sp.MaxQueueLength,
+		DailyTicketQuota: sp.DailyTicketQuota,
+		Status:           models.ServicePointStatusOpen,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		ShardID:          int(shard.Of(shard.Hash(id), s.nShards)),
+		Version:          1,
+	}
+	d.points[key] = servicePoint
+	s.lastID = max(s.lastID, key)
+	s.record(ctx, models.AuditActionCreate, nil, servicePoint)
+
+	return &servicePoint, nil
+}
+
+// ImportServicePoints makes a batch of creates and replaces, all or none of
+// them, like the Postgres storage. Every write is checked before any is made.
+func (s *SPStorage) ImportServicePoints(ctx context.Context, writes []models.ServicePointWrite) ([]models.ServicePoint, error) {
+	keys := make([]int64, len(writes))
+	for i, w := range writes {
+		key, err := parseID(w.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import service points: %w", &models.RowError{Row: i + 1, Err: err})
+		}
+		keys[i] = key
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	written := make(map[int64]bool, len(writes))
+	for i, w := range writes {
+		var err error
+		servicePoint, ok := d.points[keys[i]]
+		switch {
+		case s.taken(ctx, keys[i]), w.Create && (ok || written[keys[i]]):
+			err = models.ErrAlreadyExists
+		case ok && servicePoint.DeletedAt != nil:
+			err = models.ErrDeleted
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to import service points: %w", &models.RowError{Row: i + 1, Err: err})
+		}
+		written[keys[i]] = true
+	}
+
+	servicePoints := make([]models.ServicePoint, 0, len(writes))
+	for i, w := range writes {
+		var servicePoint *models.ServicePoint
+		var err error
+		if w.Create {
+			servicePoint, err = s.create(ctx, keys[i], w.ID, w.ServicePoint)
+		} else {
+			servicePoint, err = s.upsert(ctx, keys[i], w.ID, w.ServicePoint, 0)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to import service points: %w", &models.RowError{Row: i + 1, Err: err})
+		}
+		servicePoints = append(servicePoints, *servicePoint)
+	}
+	return servicePoints, nil
+}
+
+func (s *SPStorage) DeleteServicePoint(ctx context.Context, id string, ifVersion int64) (*models.ServicePoint, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete service point: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	servicePoint, ok := d.points[key]
+	ok = ok && servicePoint.DeletedAt == nil
+	if ifVersion != 0 && (!ok || servicePoint.Version != ifVersion) {
+		return nil, fmt.Errorf("failed to delete service point: %w", models.ErrVersionMismatch)
+	}
+	if !ok {
+		return nil, fmt.Errorf("failed to delete service point: %w", models.ErrNotFound)
+	}
+	before := servicePoint
+	now := time.Now()
+	servicePoint.DeletedAt = &now
+	servicePoint.UpdatedAt = now
+	servicePoint.Version++
+	d.points[key] = servicePoint
+	s.record(ctx, models.AuditActionDelete, &before, servicePoint)
+
+	return &servicePoint, nil
+}
+
+func (s *SPStorage) RestoreServicePoint(ctx context.Context, id string, ifVersion int64) (*models.ServicePoint, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore service point: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	servicePoint, ok := d.points[key]
+	if !ok {
+		return nil, fmt.Errorf("failed to restore service point: %w", models.ErrNotFound)
+	}
+	if ifVersion != 0 && servicePoint.Version != ifVersion {
+		return nil, fmt.Errorf("failed to restore service point: %w", models.ErrVersionMismatch)
+	}
+	if servicePoint.DeletedAt == nil {
+		return &servicePoint, nil
+	}
+	before := servicePoint
+	servicePoint.DeletedAt = nil
+	servicePoint.UpdatedAt = time.Now()
+	servicePoint.Version++
+	d.points[key] = servicePoint
+	s.record(ctx, models.AuditActionRestore, &before, servicePoint)
+
+	return &servicePoint, nil
+}
+
+func (s *SPStorage) SetServicePointStatus(ctx context.Context, id string, status string, change models.StatusChange) (*models.ServicePoint, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set service point status: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	servicePoint, ok := d.points[key]
+	if !ok || servicePoint.DeletedAt != nil {
+		return nil, fmt.Errorf("failed to set service point status: %w", models.ErrNotFound)
+	}
+	before := servicePoint
+	servicePoint.Status = status
+	servicePoint.StatusReason = change.Reason
+	servicePoint.ReturnAt = nil
+	if change.ReturnAt != nil {
+		returnAt := *change.ReturnAt
+		servicePoint.ReturnAt = &returnAt
+	}
+	servicePoint.UpdatedAt = time.Now()
+	servicePoint.Version++
+	d.points[key] = servicePoint
+	s.record(ctx, statusActions[status], &before, servicePoint)
+
+	return &servicePoint, nil
+}
+
+// statusActions is the audit action of a change to each status.
+var statusActions = map[string]string{
+	models.ServicePointStatusOpen:   models.AuditActionResume,
+	models.ServicePointStatusPaused: models.AuditActionPause,
+	models.ServicePointStatusClosed: models.AuditActionClose,
+}
+
+// record appends an audit record of a change. s.mu must be held.
+func (s *SPStorage) record(ctx context.Context, action string, before *models.ServicePoint, after models.ServicePoint) {
+	meta := audit.FromContext(ctx)
+	s.lastAuditID++
+	record := models.AuditRecord{
+		ID:             s.lastAuditID,
+		ServicePointID: after.ID,
+		Action:         action,
+		After:          mustMarshal(after),
+		Caller:         meta.Caller,
+		RequestID:      meta.RequestID,
+		CreatedAt:      time.Now(),
+	}
+	if before != nil {
+		record.Before = mustMarshal(*before)
+	}
+	d := s.tenant(ctx)
+	d.history[after.ID] = append(d.history[after.ID], record)
+}
+
+func (s *SPStorage) GetServicePointHistory(ctx context.Context, id string, cursor int64, limit int) ([]models.AuditRecord, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service point history: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	records := []models.AuditRecord{}
+	for _, record := range d.history[key] {
+		if len(records) == limit {
+			break
+		}
+		if record.ID > cursor {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// PurgeServicePoints covers all organisations, whatever the one in ctx.
+func (s *SPStorage) PurgeServicePoints(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tenantsMu.Lock()
+	defer s.tenantsMu.Unlock()
+
+	var purged int64
+	for _, d := range s.tenants {
+		for key, servicePoint := range d.points {
+			if servicePoint.DeletedAt != nil && servicePoint.DeletedAt.Before(before) {
+				delete(d.points, key)
+				purged++
+			}
+		}
+	}
+	return purged, nil
+}
+
+func (s *SPStorage) GetServicePointByID(ctx context.Context, id string, includeDeleted bool) (*models.ServicePoint, error) {
+	servicePoint, err := s.get(ctx, id, includeDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service point: %w", err)
+	}
+	return &servicePoint, nil
+}
+
+func (s *SPStorage) ListServicePoints(ctx context.Context, includeDeleted bool) ([]models.ServicePoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	servicePoints := make([]models.ServicePoint, 0, len(d.points))
+	for _, servicePoint := range d.points {
+		if servicePoint.DeletedAt != nil && !includeDeleted {
+			continue
+		}
+		servicePoints = append(servicePoints, servicePoint)
+	}
+
+	sort.Slice(servicePoints, func(i, j int) bool {
+		return servicePoints[i].ID < servicePoints[j].ID
+	})
+	return servicePoints, nil
+}
+
+func (s *SPStorage) GetShortNameById(ctx context.Context, id string) (string, error) {
+	servicePoint, err := s.get(ctx, id, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to get short name: %w", err)
+	}
+	return servicePoint.ShortName, nil
+}
+
+func (s *SPStorage) GetOfficeNumberById(ctx context.Context, id string) (string, error) {
+	servicePoint, err := s.get(ctx, id, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to get office number: %w", err)
+	}
+	return servicePoint.OfficeNumber, nil
+}
+
+func (s *SPStorage) get(ctx context.Context, id string, includeDeleted bool) (models.ServicePoint, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return models.ServicePoint{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	servicePoint, ok := d.points[key]
+	if !ok || (servicePoint.DeletedAt != nil && !includeDeleted) {
+		return models.ServicePoint{}, models.ErrNotFound
+	}
+	return servicePoint, nil
+}
+
+// mustMarshal encodes a service point, which cannot fail.
+func mustMarshal(sp models.ServicePoint) json.RawMessage {
+	data, err := json.Marshal(sp)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func parseID(id string) (int64, error) {
+	key, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q: %w", id, err)
+	}
+	return key, nil
+}
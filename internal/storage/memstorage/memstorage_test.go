@@ -0,0 +1,22 @@
+package memstorage_test
+
+import (
+	"testing"
+
+	"github.com/snnus/mainservice/config"
+	"github.com/snnus/mainservice/internal/services/spservice"
+	"github.com/snnus/mainservice/internal/storage/memstorage"
+	"github.com/snnus/mainservice/internal/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	cfg := &config.Config{Postgres: config.PgConfig{NShards: 4}}
+
+	storagetest.Run(t, cfg.Postgres.NShards, func(t *testing.T) spservice.SPStorage {
+		s, close, err := memstorage.NewSPStorage(cfg)
+		require.NoError(t, err)
+		t.Cleanup(func() { close() })
+		return s
+	})
+}
@@ -0,0 +1,142 @@
+package memstorage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (s *SPStorage) CreateOperator(ctx context.Context, operator models.NewOperatorRequest) (*models.Operator, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	s.lastOperatorID++
+	o := models.Operator{ID: s.lastOperatorID, Name: operator.Name, CreatedAt: time.Now()}
+	d.operators = append(d.operators, o)
+	return &o, nil
+}
+
+func (s *SPStorage) GetOperator(ctx context.Context, id string) (*models.Operator, error) {
+	key, err := parseID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operator: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	for _, o := range d.operators {
+		if o.ID == key {
+			return &o, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get operator: %w", models.ErrOperatorNotFound)
+}
+
+func (s *SPStorage) ListOperators(ctx context.Context) ([]models.Operator, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	return append([]models.Operator{}, d.operators...), nil
+}
+
+func (s *SPStorage) StartSession(ctx context.Context, spID string, session models.NewSessionRequest) (*models.Session, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	for _, other := range d.sessions {
+		if other.EndedAt == nil && other.ServicePointID == key && other.OperatorID != session.OperatorID {
+			return nil, fmt.Errorf("failed to start session: %w", models.ErrSessionActive)
+		}
+	}
+
+	now := time.Now()
+	for i := range d.sessions {
+		if d.sessions[i].EndedAt == nil && d.sessions[i].OperatorID == session.OperatorID {
+			endedAt := now
+			d.sessions[i].EndedAt = &endedAt
+		}
+	}
+
+	s.lastSessionID++
+	started := models.Session{
+		ID:             s.lastSessionID,
+		OperatorID:     session.OperatorID,
+		ServicePointID: key,
+		DeskNumber:     session.DeskNumber,
+		StartedAt:      now,
+	}
+	d.sessions = append(d.sessions, started)
+	return &started, nil
+}
+
+func (s *SPStorage) EndSession(ctx context.Context, spID string) (*models.Session, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to end session: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	for i := range d.sessions {
+		if d.sessions[i].EndedAt == nil && d.sessions[i].ServicePointID == key {
+			now := time.Now()
+			d.sessions[i].EndedAt = &now
+			ended := d.sessions[i]
+			return &ended, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to end session: %w", models.ErrSessionNotFound)
+}
+
+func (s *SPStorage) GetActiveSession(ctx context.Context, spID string) (*models.Session, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	for _, session := range d.sessions {
+		if session.EndedAt == nil && session.ServicePointID == key {
+			return &session, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get session: %w", models.ErrSessionNotFound)
+}
+
+func (s *SPStorage) ListSessions(ctx context.Context, from, to time.Time) ([]models.Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	sessions := []models.Session{}
+	for _, session := range d.sessions {
+		if session.StartedAt.Before(to) && (session.EndedAt == nil || session.EndedAt.After(from)) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
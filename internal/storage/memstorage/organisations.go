@@ -0,0 +1,64 @@
+package memstorage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (s *SPStorage) CreateOrganisation(ctx context.Context, organisation models.NewOrganisationRequest) (*models.Organisation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, other := range s.organisations {
+		if other.ID == organisation.ID || (organisation.Host != "" && other.Host == organisation.Host) {
+			return nil, fmt.Errorf("failed to create organisation: %w", models.ErrOrganisationExists)
+		}
+	}
+
+	o := models.Organisation{
+		ID:        organisation.ID,
+		Name:      organisation.Name,
+		Host:      organisation.Host,
+		CreatedAt: time.Now(),
+	}
+	s.organisations = append(s.organisations, o)
+	return &o, nil
+}
+
+func (s *SPStorage) GetOrganisation(ctx context.Context, id string) (*models.Organisation, error) {
+	return s.findOrganisation(func(o models.Organisation) bool { return o.ID == id })
+}
+
+func (s *SPStorage) GetOrganisationByHost(ctx context.Context, host string) (*models.Organisation, error) {
+	return s.findOrganisation(func(o models.Organisation) bool { return o.Host != "" && o.Host == host })
+}
+
+func (s *SPStorage) findOrganisation(match func(models.Organisation) bool) (*models.Organisation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, o := range s.organisations {
+		if match(o) {
+			return &o, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get organisation: %w", models.ErrOrganisationNotFound)
+}
+
+// ListOrganisations returns the organisations ordered by id, like the
+// Postgres storage.
+func (s *SPStorage) ListOrganisations(ctx context.Context) ([]models.Organisation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	organisations := append([]models.Organisation{}, s.organisations...)
+	slices.SortFunc(organisations, func(a, b models.Organisation) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return organisations, nil
+}
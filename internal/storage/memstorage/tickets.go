@@ -0,0 +1,250 @@
+package memstorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+)
+
+func (s *SPStorage) CreateTicket(ctx context.Context, spID string, code string, class string) (*models.TicketRecord, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ticket: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	now := time.Now()
+	s.lastTicketID++
+	ticket := models.TicketRecord{
+		ID:             s.lastTicketID,
+		ServicePointID: key,
+		Code:           code,
+		Status:         models.TicketStatusWaiting,
+		IssuedAt:       now,
+		QueuedAt:       now,
+		Class:          class,
+	}
+	d.tickets[key] = append(d.tickets[key], ticket)
+
+	return &ticket, nil
+}
+
+func (s *SPStorage) CreateTransferredTicket(ctx context.Context, spID string, code string, class string, from int64, front bool) (*models.TicketRecord, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ticket: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	now := time.Now()
+	queuedAt := now
+	if front {
+		for _, ticket := range d.tickets[key] {
+			if ticket.Status == models.TicketStatusWaiting && !ticket.QueuedAt.After(queuedAt) {
+				queuedAt = ticket.QueuedAt.Add(-time.Microsecond)
+			}
+		}
+	}
+
+	s.lastTicketID++
+	ticket := models.TicketRecord{
+		ID:              s.lastTicketID,
+		ServicePointID:  key,
+		Code:            code,
+		Status:          models.TicketStatusWaiting,
+		IssuedAt:        now,
+		QueuedAt:        queuedAt,
+		TransferredFrom: from,
+		Class:           class,
+	}
+	d.tickets[key] = append(d.tickets[key], ticket)
+
+	return &ticket, nil
+}
+
+func (s *SPStorage) CallTicket(ctx context.Context, spID string, code string, call models.TicketCall) (*models.TicketRecord, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ticket: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	tickets := d.tickets[key]
+	for i := len(tickets) - 1; i >= 0; i-- {
+		if tickets[i].Code != code || tickets[i].Status != models.TicketStatusWaiting {
+			continue
+		}
+		for j := range tickets {
+			if tickets[j].Status == models.TicketStatusCalled {
+				tickets[j].Status = models.TicketStatusServed
+			}
+		}
+		now := time.Now()
+		tickets[i].Status = models.TicketStatusCalled
+		tickets[i].CalledAt = &now
+		tickets[i].OfficeNumber = call.OfficeNumber
+		tickets[i].OperatorID = call.OperatorID
+		tickets[i].DeskNumber = call.DeskNumber
+		ticket := tickets[i]
+		return &ticket, nil
+	}
+	return nil, fmt.Errorf("failed to call ticket: %w", models.ErrTicketNotFound)
+}
+
+func (s *SPStorage) RecallTicket(ctx context.Context, spID string, ticketID int64) (*models.TicketRecord, error) {
+	ticket, err := s.updateTicket(ctx, spID, ticketID, models.TicketStatusCalled, func(t *models.TicketRecord) {
+		t.Recalls++
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to recall ticket: %w", err)
+	}
+	return ticket, nil
+}
+
+func (s *SPStorage) SetTicketStatus(ctx context.Context, spID string, ticketID int64, from string, to string) (*models.TicketRecord, error) {
+	ticket, err := s.updateTicket(ctx, spID, ticketID, from, func(t *models.TicketRecord) {
+		t.Status = to
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update ticket: %w", err)
+	}
+	return ticket, nil
+}
+
+// updateTicket applies update to a ticket of a service point if it is in
+// status. It reports models.ErrTicketNotFound otherwise.
+func (s *SPStorage) updateTicket(ctx context.Context, spID string, ticketID int64, status string, update func(*models.TicketRecord)) (*models.TicketRecord, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.tenant(ctx)
+
+	tickets := d.tickets[key]
+	for i := range tickets {
+		if tickets[i].ID != ticketID || tickets[i].Status != status {
+			continue
+		}
+		update(&tickets[i])
+		ticket := tickets[i]
+		return &ticket, nil
+	}
+	return nil, models.ErrTicketNotFound
+}
+
+func (s *SPStorage) CountIssuedTickets(ctx context.Context, spID string, since time.Time) (int, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count issued tickets: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	var count int
+	for _, ticket := range d.tickets[key] {
+		if !ticket.IssuedAt.Before(since) && ticket.TransferredFrom == 0 && ticket.CategoryID == 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *SPStorage) RecentCallTimes(ctx context.Context, spID string, limit int) ([]time.Time, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call times: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	var times []time.Time
+	for _, ticket := range d.tickets[key] {
+		if ticket.CalledAt != nil {
+			times = append(times, *ticket.CalledAt)
+		}
+	}
+
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].After(times[j])
+	})
+	if len(times) > limit {
+		times = times[:limit]
+	}
+	return times, nil
+}
+
+func (s *SPStorage) ListTickets(ctx context.Context, spID string, filter models.TicketFilter) ([]models.TicketRecord, error) {
+	key, err := parseID(spID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tickets: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	return filterTickets(d.tickets[key], filter), nil
+}
+
+func (s *SPStorage) ListAllTickets(ctx context.Context, filter models.TicketFilter) ([]models.TicketRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d := s.tenant(ctx)
+
+	tickets := []models.TicketRecord{}
+	for _, spTickets := range d.tickets {
+		tickets = append(tickets, filterTickets(spTickets, filter)...)
+	}
+
+	sort.SliceStable(tickets, func(i, j int) bool {
+		return tickets[i].IssuedAt.Before(tickets[j].IssuedAt)
+	})
+	return tickets, nil
+}
+
+func filterTickets(tickets []models.TicketRecord, filter models.TicketFilter) []models.TicketRecord {
+	matched := []models.TicketRecord{}
+	for _, ticket := range tickets {
+		if filter.Code != "" && ticket.Code != filter.Code {
+			continue
+		}
+		if filter.Status != "" && ticket.Status != filter.Status {
+			continue
+		}
+		if !filter.IssuedFrom.IsZero() && ticket.IssuedAt.Before(filter.IssuedFrom) {
+			continue
+		}
+		if !filter.IssuedTo.IsZero() && !ticket.IssuedAt.Before(filter.IssuedTo) {
+			continue
+		}
+		matched = append(matched, ticket)
+	}
+	return matched
+}
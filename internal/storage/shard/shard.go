@@ -0,0 +1,15 @@
+package shard
+
+import "hash/fnv"
+
+// Hash returns the FNV-1a hash of a service point key.
+func Hash(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
+
+// Of returns the 1-based shard a hash belongs to out of nShards.
+func Of(h uint32, nShards uint32) uint32 {
+	return h%nShards + 1
+}
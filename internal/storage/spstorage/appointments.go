@@ -0,0 +1,253 @@
+package spstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/tenant"
+)
+
+// appointmentColumns is the column list scanAppointment expects.
+const appointmentColumns = "id, service_point_id, slot_start, slot_end, name, status, COALESCE(ticket, ''), created_at, updated_at"
+
+func scanAppointment(row interface{ Scan(...any) error }, a *models.Appointment) error {
+	return row.Scan(
+		&a.ID,
+		&a.ServicePointID,
+		&a.SlotStart,
+		&a.SlotEnd,
+		&a.Name,
+		&a.Status,
+		&a.Ticket,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+}
+
+// UpsertSlotSchedule creates or replaces the slot schedule of a service
+// point. Existing bookings are kept even if they no longer fit.
+func (p *SPStorage) UpsertSlotSchedule(ctx context.Context, spID string, schedule models.SlotScheduleRequest) (*models.SlotSchedule, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		INSERT INTO shard_%d.slot_schedules (service_point_id, slot_minutes, capacity, tenant_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (service_point_id) DO UPDATE SET
+			slot_minutes = EXCLUDED.slot_minutes,
+			capacity = EXCLUDED.capacity,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE shard_%d.slot_schedules.tenant_id = EXCLUDED.tenant_id
+		RETURNING service_point_id, slot_minutes, capacity, updated_at
+	`, shardID, shardID)
+
+	var s models.SlotSchedule
+
+	err := p.db.QueryRowContext(ctx, query, spID, schedule.SlotMinutes, schedule.Capacity, tenant.FromContext(ctx)).Scan(&s.ServicePointID, &s.SlotMinutes, &s.Capacity, &s.UpdatedAt)
+	if err != nil {
+		return nil, wrapErr("failed to set slot schedule", err)
+	}
+	return &s, nil
+}
+
+// GetSlotSchedule reports models.ErrScheduleNotFound if the service point
+// takes no appointments.
+func (p *SPStorage) GetSlotSchedule(ctx context.Context, spID string) (*models.SlotSchedule, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		SELECT service_point_id, slot_minutes, capacity, updated_at
+		FROM shard_%d.slot_schedules
+		WHERE service_point_id = $1 AND tenant_id = $2
+	`, shardID)
+
+	var s models.SlotSchedule
+
+	err := p.db.QueryRowContext(ctx, query, spID, tenant.FromContext(ctx)).Scan(&s.ServicePointID, &s.SlotMinutes, &s.Capacity, &s.UpdatedAt)
+	if err != nil {
+		return nil, wrapAppointmentErr("failed to get slot schedule", err, models.ErrScheduleNotFound)
+	}
+	return &s, nil
+}
+
+// BookAppointment books a slot of a service point. The schedule row is
+// locked while the slot is counted, so concurrent bookings cannot overfill
+// it; a full slot is reported as models.ErrSlotFull.
+func (p *SPStorage) BookAppointment(ctx context.Context, spID string, appointment models.Appointment) (*models.Appointment, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to book appointment: %w", err)
+	}
+	defer tx.Rollback()
+
+	tenantID := tenant.FromContext(ctx)
+
+	var capacity int
+	lock := fmt.Sprintf(`
+		SELECT capacity
+		FROM shard_%d.slot_schedules
+		WHERE service_point_id = $1 AND tenant_id = $2
+		FOR UPDATE
+	`, shardID)
+	if err := tx.QueryRowContext(ctx, lock, spID, tenantID).Scan(&capacity); err != nil {
+		return nil, wrapAppointmentErr("failed to book appointment", err, models.ErrScheduleNotFound)
+	}
+
+	var booked int
+	count := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM shard_%d.appointments
+		WHERE service_point_id = $1 AND slot_start = $2 AND status IN ($3, $4) AND tenant_id = $5
+	`, shardID)
+	err = tx.QueryRowContext(ctx, count, spID, appointment.SlotStart, models.AppointmentStatusBooked, models.AppointmentStatusCheckedIn, tenantID).Scan(&booked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to book appointment: %w", err)
+	}
+	if booked >= capacity {
+		return nil, fmt.Errorf("failed to book appointment: %w", models.ErrSlotFull)
+	}
+
+	insert := fmt.Sprintf(`
+		INSERT INTO shard_%d.appointments (service_point_id, slot_start, slot_end, name, status, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING %s
+	`, shardID, appointmentColumns)
+
+	var a models.Appointment
+
+	err = scanAppointment(tx.QueryRowContext(ctx, insert, spID, appointment.SlotStart, appointment.SlotEnd, appointment.Name, models.AppointmentStatusBooked, tenantID), &a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to book appointment: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to book appointment: %w", err)
+	}
+	return &a, nil
+}
+
+// GetAppointment looks an appointment up by id in every shard, since the id
+// does not tell which service point it belongs to.
+func (p *SPStorage) GetAppointment(ctx context.Context, id string) (*models.Appointment, error) {
+	key, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointment: %w", models.ErrAppointmentNotFound)
+	}
+
+	for shardID := uint32(1); shardID <= p.nShards; shardID++ {
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM shard_%d.appointments
+			WHERE id = $1 AND tenant_id = $2
+		`, appointmentColumns, shardID)
+
+		var a models.Appointment
+
+		err := scanAppointment(p.db.QueryRowContext(ctx, query, key, tenant.FromContext(ctx)), &a)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get appointment: %w", err)
+		}
+		return &a, nil
+	}
+	return nil, fmt.Errorf("failed to get appointment: %w", models.ErrAppointmentNotFound)
+}
+
+// ListAppointments returns the appointments of a service point with slots
+// starting in [from, to), in slot order.
+func (p *SPStorage) ListAppointments(ctx context.Context, spID string, from, to time.Time) ([]models.Appointment, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM shard_%d.appointments
+		WHERE service_point_id = $1 AND slot_start >= $2 AND slot_start < $3 AND tenant_id = $4
+		ORDER BY slot_start, id
+	`, appointmentColumns, shardID)
+
+	rows, err := p.db.QueryContext(ctx, query, spID, from, to, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list appointments: %w", err)
+	}
+	defer rows.Close()
+
+	appointments := []models.Appointment{}
+	for rows.Next() {
+		var a models.Appointment
+		if err := scanAppointment(rows, &a); err != nil {
+			return nil, fmt.Errorf("failed to list appointments: %w", err)
+		}
+		appointments = append(appointments, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list appointments: %w", err)
+	}
+	return appointments, nil
+}
+
+// SetAppointmentStatus moves an appointment from status from to status to,
+// recording ticket if it is not empty. It reports models.ErrAppointmentState
+// if the appointment is not in status from.
+func (p *SPStorage) SetAppointmentStatus(ctx context.Context, spID string, id int64, from string, to string, ticket string) (*models.Appointment, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		UPDATE shard_%d.appointments
+		SET
+			status = $4,
+			ticket = COALESCE(NULLIF($5, ''), ticket),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE service_point_id = $1 AND id = $2 AND status = $3 AND tenant_id = $6
+		RETURNING %s
+	`, shardID, appointmentColumns)
+
+	var a models.Appointment
+
+	err := scanAppointment(p.db.QueryRowContext(ctx, query, spID, id, from, to, ticket, tenant.FromContext(ctx)), &a)
+	if err != nil {
+		return nil, wrapAppointmentErr("failed to update appointment", err, models.ErrAppointmentState)
+	}
+	return &a, nil
+}
+
+// ExpireAppointments marks every booked appointment whose slot ended before
+// the given time as expired and returns how many there were. Like
+// PurgeServicePoints, it covers all organisations.
+func (p *SPStorage) ExpireAppointments(ctx context.Context, before time.Time) (int64, error) {
+	var expired int64
+
+	for shardID := uint32(1); shardID <= p.nShards; shardID++ {
+		query := fmt.Sprintf(`
+			UPDATE shard_%d.appointments
+			SET
+				status = $1,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE status = $2 AND slot_end < $3
+		`, shardID)
+
+		result, err := p.db.ExecContext(ctx, query, models.AppointmentStatusExpired, models.AppointmentStatusBooked, before)
+		if err != nil {
+			return expired, fmt.Errorf("failed to expire appointments: %w", err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return expired, fmt.Errorf("failed to expire appointments: %w", err)
+		}
+		expired += n
+	}
+	return expired, nil
+}
+
+// wrapAppointmentErr is wrapErr for queries on schedules and appointments,
+// reporting a missing row as notFound.
+func wrapAppointmentErr(msg string, err error, notFound error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = notFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -0,0 +1,325 @@
+package spstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/tenant"
+)
+
+// categoryQuery selects categories with the service points serving them;
+// the placeholder takes a WHERE clause.
+const categoryQuery = `
+	SELECT c.id, c.name, c.short_name, c.created_at,
+		COALESCE(array_agg(spc.service_point_id ORDER BY spc.service_point_id)
+			FILTER (WHERE spc.service_point_id IS NOT NULL), '{}')
+	FROM public.categories c
+	LEFT JOIN public.service_point_categories spc ON spc.category_id = c.id
+	%s
+	GROUP BY c.id
+	ORDER BY c.id
+`
+
+// categoryTicketColumns is the column list scanCategoryTicket expects.
+const categoryTicketColumns = "id, category_id, code, status, issued_at, called_at, COALESCE(service_point_id, 0)"
+
+func scanCategory(row interface{ Scan(...any) error }, c *models.Category) error {
+	return row.Scan(
+		&c.ID,
+		&c.Name,
+		&c.ShortName,
+		&c.CreatedAt,
+		pq.Array(&c.ServicePointIDs),
+	)
+}
+
+func scanCategoryTicket(row interface{ Scan(...any) error }, t *models.CategoryTicket) error {
+	return row.Scan(
+		&t.ID,
+		&t.CategoryID,
+		&t.Code,
+		&t.Status,
+		&t.IssuedAt,
+		&t.CalledAt,
+		&t.ServicePointID,
+	)
+}
+
+// CreateCategory records a new category. It reports models.ErrAlreadyExists
+// if another category of the organisation has the same short name.
+func (p *SPStorage) CreateCategory(ctx context.Context, category models.NewCategoryRequest) (*models.Category, error) {
+	query := `
+		INSERT INTO public.categories (name, short_name, tenant_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, short_name, created_at
+	`
+
+	c := models.Category{ServicePointIDs: []int64{}}
+
+	err := p.db.QueryRowContext(ctx, query, category.Name, category.ShortName, tenant.FromContext(ctx)).Scan(&c.ID, &c.Name, &c.ShortName, &c.CreatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, fmt.Errorf("failed to create category: %w", models.ErrAlreadyExists)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+	return &c, nil
+}
+
+func (p *SPStorage) GetCategory(ctx context.Context, id string) (*models.Category, error) {
+	query := fmt.Sprintf(categoryQuery, "WHERE c.id = $1 AND c.tenant_id = $2")
+
+	var c models.Category
+	if err := scanCategory(p.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)), &c); err != nil {
+		return nil, wrapCategoryErr("failed to get category", err)
+	}
+	return &c, nil
+}
+
+func (p *SPStorage) ListCategories(ctx context.Context) ([]models.Category, error) {
+	return p.listCategories(ctx, fmt.Sprintf(categoryQuery, "WHERE c.tenant_id = $1"), tenant.FromContext(ctx))
+}
+
+// ListServicePointCategories returns the categories a service point serves.
+func (p *SPStorage) ListServicePointCategories(ctx context.Context, spID string) ([]models.Category, error) {
+	query := fmt.Sprintf(categoryQuery, `
+		WHERE c.tenant_id = $2 AND c.id IN (
+			SELECT category_id
+			FROM public.service_point_categories
+			WHERE service_point_id = $1 AND tenant_id = $2
+		)`)
+
+	return p.listCategories(ctx, query, spID, tenant.FromContext(ctx))
+}
+
+func (p *SPStorage) listCategories(ctx context.Context, query string, args ...any) ([]models.Category, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		var c models.Category
+		if err := scanCategory(rows, &c); err != nil {
+			return nil, fmt.Errorf("failed to list categories: %w", err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categories, nil
+}
+
+// SetServicePointCategories replaces the categories a service point serves.
+// It reports models.ErrCategoryNotFound if one of them does not exist in the
+// organisation. categoryIDs must not repeat.
+func (p *SPStorage) SetServicePointCategories(ctx context.Context, spID string, categoryIDs []int64) ([]models.Category, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set categories: %w", err)
+	}
+	defer tx.Rollback()
+
+	tenantID := tenant.FromContext(ctx)
+
+	unassign := `
+		DELETE FROM public.service_point_categories
+		WHERE service_point_id = $1 AND tenant_id = $2
+	`
+
+	if _, err := tx.ExecContext(ctx, unassign, spID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to set categories: %w", err)
+	}
+
+	query := `
+		INSERT INTO public.service_point_categories (service_point_id, category_id, tenant_id)
+		SELECT $1, id, tenant_id
+		FROM public.categories
+		WHERE id = ANY($2::BIGINT[]) AND tenant_id = $3
+	`
+
+	result, err := tx.ExecContext(ctx, query, spID, pq.Array(categoryIDs), tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set categories: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set categories: %w", err)
+	}
+	if n != int64(len(categoryIDs)) {
+		return nil, fmt.Errorf("failed to set categories: %w", models.ErrCategoryNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to set categories: %w", err)
+	}
+	return p.ListServicePointCategories(ctx, spID)
+}
+
+// CreateCategoryTicket records a ticket just issued for a category.
+func (p *SPStorage) CreateCategoryTicket(ctx context.Context, categoryID int64, code string) (*models.CategoryTicket, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO public.category_tickets (category_id, code, status, tenant_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING %s
+	`, categoryTicketColumns)
+
+	var ticket models.CategoryTicket
+
+	err := scanCategoryTicket(p.db.QueryRowContext(ctx, query, categoryID, code, models.TicketStatusWaiting, tenant.FromContext(ctx)), &ticket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category ticket: %w", err)
+	}
+	return &ticket, nil
+}
+
+// ListCategoryTickets returns the category tickets matching filter, oldest
+// first.
+func (p *SPStorage) ListCategoryTickets(ctx context.Context, filter models.CategoryTicketFilter) ([]models.CategoryTicket, error) {
+	conds := []string{"tenant_id = $1"}
+	args := []any{tenant.FromContext(ctx)}
+	if filter.CategoryIDs != nil {
+		args = append(args, pq.Array(filter.CategoryIDs))
+		conds = append(conds, fmt.Sprintf("category_id = ANY($%d)", len(args)))
+	}
+	if filter.Code != "" {
+		args = append(args, filter.Code)
+		conds = append(conds, fmt.Sprintf("code = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM public.category_tickets
+		WHERE %s
+		ORDER BY issued_at, id
+	`, categoryTicketColumns, strings.Join(conds, " AND "))
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list category tickets: %w", err)
+	}
+	defer rows.Close()
+
+	tickets := []models.CategoryTicket{}
+	for rows.Next() {
+		var ticket models.CategoryTicket
+		if err := scanCategoryTicket(rows, &ticket); err != nil {
+			return nil, fmt.Errorf("failed to list category tickets: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list category tickets: %w", err)
+	}
+	return tickets, nil
+}
+
+// SetCategoryTicketStatus moves a category ticket from status from to status
+// to. It reports models.ErrTicketNotFound if the ticket is not in status from.
+func (p *SPStorage) SetCategoryTicketStatus(ctx context.Context, ticketID int64, from string, to string) (*models.CategoryTicket, error) {
+	query := fmt.Sprintf(`
+		UPDATE public.category_tickets
+		SET status = $3
+		WHERE id = $1 AND status = $2 AND tenant_id = $4
+		RETURNING %s
+	`, categoryTicketColumns)
+
+	var ticket models.CategoryTicket
+
+	err := scanCategoryTicket(p.db.QueryRowContext(ctx, query, ticketID, from, to, tenant.FromContext(ctx)), &ticket)
+	if err != nil {
+		return nil, wrapTicketErr("failed to update category ticket", err)
+	}
+	return &ticket, nil
+}
+
+// ClaimCategoryTicket moves the latest waiting ticket of a category with the
+// given code to a service point, called like CallTicket would. The ticket
+// keeps its issue time. It reports models.ErrTicketNotFound if there is no
+// such waiting ticket.
+func (p *SPStorage) ClaimCategoryTicket(ctx context.Context, categoryID int64, code string, spID string, call models.TicketCall) (*models.TicketRecord, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim category ticket: %w", err)
+	}
+	defer tx.Rollback()
+
+	tenantID := tenant.FromContext(ctx)
+
+	claim := `
+		UPDATE public.category_tickets
+		SET status = $4, called_at = CURRENT_TIMESTAMP, service_point_id = $3
+		WHERE id = (
+			SELECT id
+			FROM public.category_tickets
+			WHERE category_id = $1 AND code = $2 AND status = $5 AND tenant_id = $6
+			ORDER BY id DESC
+			LIMIT 1
+			FOR UPDATE
+		)
+		RETURNING issued_at
+	`
+
+	var claimed models.CategoryTicket
+
+	err = tx.QueryRowContext(ctx, claim, categoryID, code, spID, models.TicketStatusCalled, models.TicketStatusWaiting, tenantID).Scan(&claimed.IssuedAt)
+	if err != nil {
+		return nil, wrapTicketErr("failed to claim category ticket", err)
+	}
+
+	serve := fmt.Sprintf(`
+		UPDATE shard_%d.tickets
+		SET status = $2
+		WHERE service_point_id = $1 AND status = $3 AND tenant_id = $4
+	`, shardID)
+
+	_, err = tx.ExecContext(ctx, serve, spID, models.TicketStatusServed, models.TicketStatusCalled, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim category ticket: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO shard_%d.tickets (
+			service_point_id, code, status, class, issued_at, queued_at, called_at,
+			office_number, operator_id, desk_number, category_id, tenant_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $5, CURRENT_TIMESTAMP, $6, NULLIF($7, 0), NULLIF($8, ''), $9, $10)
+		RETURNING %s
+	`, shardID, ticketColumns)
+
+	var ticket models.TicketRecord
+
+	err = scanTicket(tx.QueryRowContext(ctx, query, spID, code, models.TicketStatusCalled, models.TicketClassRegular,
+		claimed.IssuedAt, call.OfficeNumber, call.OperatorID, call.DeskNumber, categoryID, tenantID), &ticket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim category ticket: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to claim category ticket: %w", err)
+	}
+	return &ticket, nil
+}
+
+func wrapCategoryErr(msg string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = models.ErrCategoryNotFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -0,0 +1,143 @@
+package spstorage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/tenant"
+)
+
+// UpsertWorkingHours creates or replaces the hours of a service point.
+func (p *SPStorage) UpsertWorkingHours(ctx context.Context, spID string, hours models.WorkingHoursRequest) (*models.WorkingHours, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		INSERT INTO shard_%d.working_hours (service_point_id, hours, tenant_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (service_point_id) DO UPDATE SET
+			hours = EXCLUDED.hours,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE shard_%d.working_hours.tenant_id = EXCLUDED.tenant_id
+		RETURNING service_point_id, hours, updated_at
+	`, shardID, shardID)
+
+	data, err := json.Marshal(hours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set working hours: %w", err)
+	}
+
+	w := models.WorkingHours{Source: models.WorkingHoursSourceServicePoint}
+	if err := scanWorkingHours(p.db.QueryRowContext(ctx, query, spID, string(data), tenant.FromContext(ctx)), &w.ServicePointID, &w); err != nil {
+		return nil, wrapErr("failed to set working hours", err)
+	}
+	return &w, nil
+}
+
+// GetWorkingHours returns the hours set for the service point itself and
+// reports models.ErrHoursNotFound if it has none.
+func (p *SPStorage) GetWorkingHours(ctx context.Context, spID string) (*models.WorkingHours, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		SELECT service_point_id, hours, updated_at
+		FROM shard_%d.working_hours
+		WHERE service_point_id = $1 AND tenant_id = $2
+	`, shardID)
+
+	w := models.WorkingHours{Source: models.WorkingHoursSourceServicePoint}
+	if err := scanWorkingHours(p.db.QueryRowContext(ctx, query, spID, tenant.FromContext(ctx)), &w.ServicePointID, &w); err != nil {
+		return nil, wrapHoursErr("failed to get working hours", err)
+	}
+	return &w, nil
+}
+
+// DeleteWorkingHours removes the hours of a service point so that it falls
+// back to the hours of its office.
+func (p *SPStorage) DeleteWorkingHours(ctx context.Context, spID string) error {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		DELETE FROM shard_%d.working_hours
+		WHERE service_point_id = $1 AND tenant_id = $2
+	`, shardID)
+
+	result, err := p.db.ExecContext(ctx, query, spID, tenant.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete working hours: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete working hours: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("failed to delete working hours: %w", models.ErrHoursNotFound)
+	}
+	return nil
+}
+
+// UpsertOfficeHours creates or replaces the default hours of an office.
+func (p *SPStorage) UpsertOfficeHours(ctx context.Context, officeNumber string, hours models.WorkingHoursRequest) (*models.WorkingHours, error) {
+	query := `
+		INSERT INTO public.office_working_hours (office_number, hours, tenant_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, office_number) DO UPDATE SET
+			hours = EXCLUDED.hours,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING office_number, hours, updated_at
+	`
+
+	data, err := json.Marshal(hours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set office hours: %w", err)
+	}
+
+	w := models.WorkingHours{Source: models.WorkingHoursSourceOffice}
+	if err := scanWorkingHours(p.db.QueryRowContext(ctx, query, officeNumber, string(data), tenant.FromContext(ctx)), &w.OfficeNumber, &w); err != nil {
+		return nil, fmt.Errorf("failed to set office hours: %w", err)
+	}
+	return &w, nil
+}
+
+// GetOfficeHours reports models.ErrHoursNotFound if the office has no
+// default hours.
+func (p *SPStorage) GetOfficeHours(ctx context.Context, officeNumber string) (*models.WorkingHours, error) {
+	query := `
+		SELECT office_number, hours, updated_at
+		FROM public.office_working_hours
+		WHERE office_number = $1 AND tenant_id = $2
+	`
+
+	w := models.WorkingHours{Source: models.WorkingHoursSourceOffice}
+	if err := scanWorkingHours(p.db.QueryRowContext(ctx, query, officeNumber, tenant.FromContext(ctx)), &w.OfficeNumber, &w); err != nil {
+		return nil, wrapHoursErr("failed to get office hours", err)
+	}
+	return &w, nil
+}
+
+// scanWorkingHours scans a key, hours and updated_at row into w, storing the
+// key in owner.
+func scanWorkingHours(row interface{ Scan(...any) error }, owner any, w *models.WorkingHours) error {
+	var data []byte
+	if err := row.Scan(owner, &data, &w.UpdatedAt); err != nil {
+		return err
+	}
+
+	var hours models.WorkingHoursRequest
+	if err := json.Unmarshal(data, &hours); err != nil {
+		return fmt.Errorf("failed to decode working hours: %w", err)
+	}
+	w.Timezone = hours.Timezone
+	w.Week = hours.Week
+	w.Holidays = hours.Holidays
+	w.StopBeforeCloseMinutes = hours.StopBeforeCloseMinutes
+	return nil
+}
+
+// wrapHoursErr is wrapErr for queries on working hours.
+func wrapHoursErr(msg string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = models.ErrHoursNotFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
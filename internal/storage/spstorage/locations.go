@@ -0,0 +1,248 @@
+package spstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/tenant"
+)
+
+// Column lists the scan functions below expect.
+const (
+	buildingColumns = "id, name, address, directions, created_at"
+	floorColumns    = "id, building_id, name, level, directions, created_at"
+	officeColumns   = "id, floor_id, number, name, directions, created_at"
+)
+
+func scanBuilding(row interface{ Scan(...any) error }, b *models.Building) error {
+	return row.Scan(&b.ID, &b.Name, &b.Address, &b.Directions, &b.CreatedAt)
+}
+
+func scanFloor(row interface{ Scan(...any) error }, f *models.Floor) error {
+	return row.Scan(&f.ID, &f.BuildingID, &f.Name, &f.Level, &f.Directions, &f.CreatedAt)
+}
+
+func scanOffice(row interface{ Scan(...any) error }, o *models.Office) error {
+	return row.Scan(&o.ID, &o.FloorID, &o.Number, &o.Name, &o.Directions, &o.CreatedAt)
+}
+
+func (p *SPStorage) CreateBuilding(ctx context.Context, building models.NewBuildingRequest) (*models.Building, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO public.buildings (name, address, directions, tenant_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING %s
+	`, buildingColumns)
+
+	var b models.Building
+
+	err := scanBuilding(p.db.QueryRowContext(ctx, query, building.Name, building.Address, building.Directions, tenant.FromContext(ctx)), &b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create building: %w", err)
+	}
+	return &b, nil
+}
+
+func (p *SPStorage) GetBuilding(ctx context.Context, id string) (*models.Building, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM public.buildings
+		WHERE id = $1 AND tenant_id = $2
+	`, buildingColumns)
+
+	var b models.Building
+	if err := scanBuilding(p.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)), &b); err != nil {
+		return nil, wrapLocationErr("failed to get building", err, models.ErrBuildingNotFound)
+	}
+	return &b, nil
+}
+
+func (p *SPStorage) ListBuildings(ctx context.Context) ([]models.Building, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM public.buildings
+		WHERE tenant_id = $1
+		ORDER BY id
+	`, buildingColumns)
+
+	rows, err := p.db.QueryContext(ctx, query, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buildings: %w", err)
+	}
+	defer rows.Close()
+
+	buildings := []models.Building{}
+	for rows.Next() {
+		var b models.Building
+		if err := scanBuilding(rows, &b); err != nil {
+			return nil, fmt.Errorf("failed to list buildings: %w", err)
+		}
+		buildings = append(buildings, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list buildings: %w", err)
+	}
+	return buildings, nil
+}
+
+// CreateFloor adds a floor to a building of the organisation. It reports
+// models.ErrBuildingNotFound if there is no such building and
+// models.ErrFloorExists if the level is taken.
+func (p *SPStorage) CreateFloor(ctx context.Context, buildingID string, floor models.NewFloorRequest) (*models.Floor, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO public.floors (building_id, name, level, directions, tenant_id)
+		SELECT id, $2, $3, $4, tenant_id
+		FROM public.buildings
+		WHERE id = $1 AND tenant_id = $5
+		RETURNING %s
+	`, floorColumns)
+
+	var f models.Floor
+
+	err := scanFloor(p.db.QueryRowContext(ctx, query, buildingID, floor.Name, floor.Level, floor.Directions, tenant.FromContext(ctx)), &f)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, fmt.Errorf("failed to create floor: %w", models.ErrFloorExists)
+	}
+	if err != nil {
+		return nil, wrapLocationErr("failed to create floor", err, models.ErrBuildingNotFound)
+	}
+	return &f, nil
+}
+
+func (p *SPStorage) GetFloor(ctx context.Context, id string) (*models.Floor, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM public.floors
+		WHERE id = $1 AND tenant_id = $2
+	`, floorColumns)
+
+	var f models.Floor
+	if err := scanFloor(p.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)), &f); err != nil {
+		return nil, wrapLocationErr("failed to get floor", err, models.ErrFloorNotFound)
+	}
+	return &f, nil
+}
+
+// ListFloors returns the floors of a building from the lowest level up.
+func (p *SPStorage) ListFloors(ctx context.Context, buildingID string) ([]models.Floor, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM public.floors
+		WHERE building_id = $1 AND tenant_id = $2
+		ORDER BY level
+	`, floorColumns)
+
+	rows, err := p.db.QueryContext(ctx, query, buildingID, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list floors: %w", err)
+	}
+	defer rows.Close()
+
+	floors := []models.Floor{}
+	for rows.Next() {
+		var f models.Floor
+		if err := scanFloor(rows, &f); err != nil {
+			return nil, fmt.Errorf("failed to list floors: %w", err)
+		}
+		floors = append(floors, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list floors: %w", err)
+	}
+	return floors, nil
+}
+
+// CreateOffice adds an office to a floor of the organisation. It reports
+// models.ErrFloorNotFound if there is no such floor and
+// models.ErrOfficeExists if the number is taken on it.
+func (p *SPStorage) CreateOffice(ctx context.Context, floorID string, office models.NewOfficeRequest) (*models.Office, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO public.offices (floor_id, number, name, directions, tenant_id)
+		SELECT id, $2, $3, $4, tenant_id
+		FROM public.floors
+		WHERE id = $1 AND tenant_id = $5
+		RETURNING %s
+	`, officeColumns)
+
+	var o models.Office
+
+	err := scanOffice(p.db.QueryRowContext(ctx, query, floorID, office.Number, office.Name, office.Directions, tenant.FromContext(ctx)), &o)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, fmt.Errorf("failed to create office: %w", models.ErrOfficeExists)
+	}
+	if err != nil {
+		return nil, wrapLocationErr("failed to create office", err, models.ErrFloorNotFound)
+	}
+	return &o, nil
+}
+
+// ListOffices returns the offices on a floor ordered by number.
+func (p *SPStorage) ListOffices(ctx context.Context, floorID string) ([]models.Office, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM public.offices
+		WHERE floor_id = $1 AND tenant_id = $2
+		ORDER BY number
+	`, officeColumns)
+
+	rows, err := p.db.QueryContext(ctx, query, floorID, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offices: %w", err)
+	}
+	defer rows.Close()
+
+	offices := []models.Office{}
+	for rows.Next() {
+		var o models.Office
+		if err := scanOffice(rows, &o); err != nil {
+			return nil, fmt.Errorf("failed to list offices: %w", err)
+		}
+		offices = append(offices, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list offices: %w", err)
+	}
+	return offices, nil
+}
+
+// GetLocation returns an office of the organisation with its floor and
+// building.
+func (p *SPStorage) GetLocation(ctx context.Context, officeID string) (*models.Location, error) {
+	query := `
+		SELECT o.id, o.floor_id, o.number, o.name, o.directions, o.created_at,
+			f.id, f.building_id, f.name, f.level, f.directions, f.created_at,
+			b.id, b.name, b.address, b.directions, b.created_at
+		FROM public.offices o
+		JOIN public.floors f ON f.id = o.floor_id
+		JOIN public.buildings b ON b.id = f.building_id
+		WHERE o.id = $1 AND o.tenant_id = $2
+	`
+
+	var l models.Location
+
+	err := p.db.QueryRowContext(ctx, query, officeID, tenant.FromContext(ctx)).Scan(
+		&l.Office.ID, &l.Office.FloorID, &l.Office.Number, &l.Office.Name, &l.Office.Directions, &l.Office.CreatedAt,
+		&l.Floor.ID, &l.Floor.BuildingID, &l.Floor.Name, &l.Floor.Level, &l.Floor.Directions, &l.Floor.CreatedAt,
+		&l.Building.ID, &l.Building.Name, &l.Building.Address, &l.Building.Directions, &l.Building.CreatedAt,
+	)
+	if err != nil {
+		return nil, wrapLocationErr("failed to get location", err, models.ErrOfficeNotFound)
+	}
+	return &l, nil
+}
+
+// wrapLocationErr is wrapErr for queries on buildings, floors and offices,
+// reporting missing rows as notFound.
+func wrapLocationErr(msg string, err error, notFound error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = notFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -0,0 +1,206 @@
+package spstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/tenant"
+)
+
+// sessionColumns is the column list scanSession expects.
+const sessionColumns = "id, operator_id, service_point_id, desk_number, started_at, ended_at"
+
+func scanSession(row interface{ Scan(...any) error }, s *models.Session) error {
+	return row.Scan(
+		&s.ID,
+		&s.OperatorID,
+		&s.ServicePointID,
+		&s.DeskNumber,
+		&s.StartedAt,
+		&s.EndedAt,
+	)
+}
+
+func (p *SPStorage) CreateOperator(ctx context.Context, operator models.NewOperatorRequest) (*models.Operator, error) {
+	query := `
+		INSERT INTO public.operators (name, tenant_id)
+		VALUES ($1, $2)
+		RETURNING id, name, created_at
+	`
+
+	var o models.Operator
+	if err := p.db.QueryRowContext(ctx, query, operator.Name, tenant.FromContext(ctx)).Scan(&o.ID, &o.Name, &o.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create operator: %w", err)
+	}
+	return &o, nil
+}
+
+func (p *SPStorage) GetOperator(ctx context.Context, id string) (*models.Operator, error) {
+	query := `
+		SELECT id, name, created_at
+		FROM public.operators
+		WHERE id = $1 AND tenant_id = $2
+	`
+
+	var o models.Operator
+	if err := p.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)).Scan(&o.ID, &o.Name, &o.CreatedAt); err != nil {
+		return nil, wrapOperatorErr("failed to get operator", err)
+	}
+	return &o, nil
+}
+
+func (p *SPStorage) ListOperators(ctx context.Context) ([]models.Operator, error) {
+	query := `
+		SELECT id, name, created_at
+		FROM public.operators
+		WHERE tenant_id = $1
+		ORDER BY id
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operators: %w", err)
+	}
+	defer rows.Close()
+
+	operators := []models.Operator{}
+	for rows.Next() {
+		var o models.Operator
+		if err := rows.Scan(&o.ID, &o.Name, &o.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to list operators: %w", err)
+		}
+		operators = append(operators, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list operators: %w", err)
+	}
+	return operators, nil
+}
+
+// StartSession logs an operator in to a service point, ending their session
+// at any other desk. It reports models.ErrSessionActive if another operator
+// is logged in to the service point.
+func (p *SPStorage) StartSession(ctx context.Context, spID string, session models.NewSessionRequest) (*models.Session, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer tx.Rollback()
+
+	tenantID := tenant.FromContext(ctx)
+
+	end := `
+		UPDATE public.desk_sessions
+		SET ended_at = CURRENT_TIMESTAMP
+		WHERE operator_id = $1 AND ended_at IS NULL AND tenant_id = $2
+	`
+
+	if _, err := tx.ExecContext(ctx, end, session.OperatorID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO public.desk_sessions (operator_id, service_point_id, desk_number, tenant_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING %s
+	`, sessionColumns)
+
+	var s models.Session
+
+	err = scanSession(tx.QueryRowContext(ctx, query, session.OperatorID, spID, session.DeskNumber, tenantID), &s)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, fmt.Errorf("failed to start session: %w", models.ErrSessionActive)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	return &s, nil
+}
+
+// EndSession logs out the operator of a service point. It reports
+// models.ErrSessionNotFound if nobody is logged in.
+func (p *SPStorage) EndSession(ctx context.Context, spID string) (*models.Session, error) {
+	query := fmt.Sprintf(`
+		UPDATE public.desk_sessions
+		SET ended_at = CURRENT_TIMESTAMP
+		WHERE service_point_id = $1 AND ended_at IS NULL AND tenant_id = $2
+		RETURNING %s
+	`, sessionColumns)
+
+	var s models.Session
+	if err := scanSession(p.db.QueryRowContext(ctx, query, spID, tenant.FromContext(ctx)), &s); err != nil {
+		return nil, wrapSessionErr("failed to end session", err)
+	}
+	return &s, nil
+}
+
+// GetActiveSession returns the session of the operator logged in to a
+// service point, or models.ErrSessionNotFound.
+func (p *SPStorage) GetActiveSession(ctx context.Context, spID string) (*models.Session, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM public.desk_sessions
+		WHERE service_point_id = $1 AND ended_at IS NULL AND tenant_id = $2
+	`, sessionColumns)
+
+	var s models.Session
+	if err := scanSession(p.db.QueryRowContext(ctx, query, spID, tenant.FromContext(ctx)), &s); err != nil {
+		return nil, wrapSessionErr("failed to get session", err)
+	}
+	return &s, nil
+}
+
+// ListSessions returns the sessions that overlap [from, to), active ones
+// included, ordered by id.
+func (p *SPStorage) ListSessions(ctx context.Context, from, to time.Time) ([]models.Session, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM public.desk_sessions
+		WHERE started_at < $2 AND (ended_at IS NULL OR ended_at > $1) AND tenant_id = $3
+		ORDER BY id
+	`, sessionColumns)
+
+	rows, err := p.db.QueryContext(ctx, query, from, to, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		var s models.Session
+		if err := scanSession(rows, &s); err != nil {
+			return nil, fmt.Errorf("failed to list sessions: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+func wrapOperatorErr(msg string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = models.ErrOperatorNotFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+func wrapSessionErr(msg string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = models.ErrSessionNotFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
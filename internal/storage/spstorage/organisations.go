@@ -0,0 +1,104 @@
+package spstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// Organisations are not owned by one, so unlike every other query these do
+// not look at the organisation in ctx.
+
+// CreateOrganisation reports models.ErrOrganisationExists if the id or host
+// is taken.
+func (p *SPStorage) CreateOrganisation(ctx context.Context, organisation models.NewOrganisationRequest) (*models.Organisation, error) {
+	query := `
+		INSERT INTO public.organisations (id, name, host)
+		VALUES ($1, $2, NULLIF($3, ''))
+		RETURNING id, name, COALESCE(host, ''), created_at
+	`
+
+	var o models.Organisation
+
+	err := scanOrganisation(p.db.QueryRowContext(ctx, query, organisation.ID, organisation.Name, organisation.Host), &o)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, fmt.Errorf("failed to create organisation: %w", models.ErrOrganisationExists)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organisation: %w", err)
+	}
+	return &o, nil
+}
+
+func (p *SPStorage) GetOrganisation(ctx context.Context, id string) (*models.Organisation, error) {
+	query := `
+		SELECT id, name, COALESCE(host, ''), created_at
+		FROM public.organisations
+		WHERE id = $1
+	`
+
+	var o models.Organisation
+	if err := scanOrganisation(p.db.QueryRowContext(ctx, query, id), &o); err != nil {
+		return nil, wrapOrganisationErr("failed to get organisation", err)
+	}
+	return &o, nil
+}
+
+// GetOrganisationByHost returns the organisation requests to host act for.
+func (p *SPStorage) GetOrganisationByHost(ctx context.Context, host string) (*models.Organisation, error) {
+	query := `
+		SELECT id, name, COALESCE(host, ''), created_at
+		FROM public.organisations
+		WHERE host = $1
+	`
+
+	var o models.Organisation
+	if err := scanOrganisation(p.db.QueryRowContext(ctx, query, host), &o); err != nil {
+		return nil, wrapOrganisationErr("failed to get organisation", err)
+	}
+	return &o, nil
+}
+
+func (p *SPStorage) ListOrganisations(ctx context.Context) ([]models.Organisation, error) {
+	query := `
+		SELECT id, name, COALESCE(host, ''), created_at
+		FROM public.organisations
+		ORDER BY id
+	`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organisations: %w", err)
+	}
+	defer rows.Close()
+
+	organisations := []models.Organisation{}
+	for rows.Next() {
+		var o models.Organisation
+		if err := scanOrganisation(rows, &o); err != nil {
+			return nil, fmt.Errorf("failed to list organisations: %w", err)
+		}
+		organisations = append(organisations, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list organisations: %w", err)
+	}
+	return organisations, nil
+}
+
+func scanOrganisation(row interface{ Scan(...any) error }, o *models.Organisation) error {
+	return row.Scan(&o.ID, &o.Name, &o.Host, &o.CreatedAt)
+}
+
+func wrapOrganisationErr(msg string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = models.ErrOrganisationNotFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -3,14 +3,23 @@ package spstorage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"hash/fnv"
+	"sort"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/snnus/mainservice/config"
+	"github.com/snnus/mainservice/internal/audit"
 	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/storage/shard"
+	"github.com/snnus/mainservice/internal/tenant"
 )
 
+// uniqueViolation is the Postgres error code for a duplicate key.
+const uniqueViolation = "23505"
+
 func NewConnection(cfg *config.Config) (*sql.DB, error) {
 	connStr := fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s sslmode=disable",
 		cfg.Postgres.User,
@@ -40,131 +49,569 @@ func NewSPStorage(cfg *config.Config) (*SPStorage, func() error, error) {
 }
 
 func (p *SPStorage) GetHash(key string) uint32 {
-	h := fnv.New32a()
-	h.Write([]byte(key))
-	return h.Sum32()
+	return shard.Hash(key)
 }
 
 func (p *SPStorage) GetShard(h uint32) uint32 {
-	return h%p.nShards + 1
+	return shard.Of(h, p.nShards)
 }
 
-func (p *SPStorage) UpsertServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest) (*models.ServicePoint, error) {
-	shard := p.GetShard(p.GetHash(id))
-	query := fmt.Sprintf(`
-		INSERT INTO shard_%d.service_points (id, name, short_name, office_number)
-		VALUES ($1, $2, $3, $4)
-		ON CONFLICT (id)
-		DO UPDATE SET
-			name = EXCLUDED.name, 
-			short_name = EXCLUDED.short_name, 
-			office_number = EXCLUDED.office_number
-		RETURNING id, name, short_name, office_number, created_at, updated_at
-	`, shard)
-
-	var servicePoint models.ServicePoint
-
-	err := p.db.QueryRowContext(ctx, query, id, sp.Name, sp.ShortName, sp.OfficeNumber).Scan(
-		&servicePoint.ID,
-		&servicePoint.Name,
-		&servicePoint.ShortName,
-		&servicePoint.OfficeNumber,
-		&servicePoint.CreatedAt,
-		&servicePoint.UpdatedAt,
+// servicePointColumns is the column list scanServicePoint expects.
+const servicePointColumns = "id, name, short_name, office_number, COALESCE(office_id, 0), max_queue_length, daily_ticket_quota, status, status_reason, return_at, created_at, updated_at, version, deleted_at"
+
+func scanServicePoint(row interface{ Scan(...any) error }, sp *models.ServicePoint) error {
+	return row.Scan(
+		&sp.ID,
+		&sp.Name,
+		&sp.ShortName,
+		&sp.OfficeNumber,
+		&sp.OfficeID,
+		&sp.MaxQueueLength,
+		&sp.DailyTicketQuota,
+		&sp.Status,
+		&sp.StatusReason,
+		&sp.ReturnAt,
+		&sp.CreatedAt,
+		&sp.UpdatedAt,
+		&sp.Version,
+		&sp.DeletedAt,
 	)
+}
+
+// wrapErr reports missing rows as models.ErrNotFound so callers don't need
+// to know about database/sql.
+func wrapErr(msg string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = models.ErrNotFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// wrapTicketErr is wrapErr for queries on tickets.
+func wrapTicketErr(msg string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = models.ErrTicketNotFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// UpsertServicePoint creates or replaces a service point. A non-zero
+// ifVersion turns it into a conditional update of an existing row at that
+// version; anything else is reported as models.ErrVersionMismatch. The id of a
+// soft-deleted service point stays taken and is reported as models.ErrDeleted;
+// one owned by another organisation is reported as models.ErrAlreadyExists.
+func (p *SPStorage) UpsertServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest, ifVersion int64) (*models.ServicePoint, error) {
+	shardID := p.GetShard(p.GetHash(id))
+
+	return p.audited(ctx, shardID, id, "failed to create service point", upsert(ctx, shardID, id, sp, ifVersion))
+}
+
+// upsert is the write of UpsertServicePoint.
+func upsert(ctx context.Context, shardID uint32, id string, sp models.NewServicePointRequest, ifVersion int64) writeFunc {
+	return func(tx *sql.Tx, before *models.ServicePoint) (*models.ServicePoint, string, error) {
+		if before != nil && before.DeletedAt != nil {
+			return nil, "", models.ErrDeleted
+		}
+		if ifVersion != 0 && (before == nil || before.Version != ifVersion) {
+			return nil, "", models.ErrVersionMismatch
+		}
+
+		query := fmt.Sprintf(`
+			INSERT INTO shard_%d.service_points (id, name, short_name, office_number, office_id, max_queue_length, daily_ticket_quota, tenant_id)
+			VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6, $7, $8)
+			ON CONFLICT (id)
+			DO UPDATE SET
+				name = EXCLUDED.name, 
+				short_name = EXCLUDED.short_name, 
+				office_number = EXCLUDED.office_number,
+				office_id = EXCLUDED.office_id,
+				max_queue_length = EXCLUDED.max_queue_length,
+				daily_ticket_quota = EXCLUDED.daily_ticket_quota,
+				version = shard_%d.service_points.version + 1
+			WHERE shard_%d.service_points.deleted_at IS NULL
+				AND shard_%d.service_points.tenant_id = EXCLUDED.tenant_id
+			RETURNING %s
+		`, shardID, shardID, shardID, shardID, servicePointColumns)
+
+		servicePoint := models.ServicePoint{ShardID: int(shardID)}
+
+		err := scanServicePoint(tx.QueryRowContext(ctx, query, id, sp.Name, sp.ShortName, sp.OfficeNumber, sp.OfficeID, sp.MaxQueueLength, sp.DailyTicketQuota, tenant.FromContext(ctx)), &servicePoint)
+		if errors.Is(err, sql.ErrNoRows) && before == nil {
+			return nil, "", models.ErrAlreadyExists
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", models.ErrDeleted
+		}
+		if err != nil {
+			return nil, "", err
+		}
 
+		if before == nil {
+			return &servicePoint, models.AuditActionCreate, nil
+		}
+		return &servicePoint, models.AuditActionUpdate, nil
+	}
+}
+
+// NextServicePointID allocates an id from the sequence shared by all shards.
+func (p *SPStorage) NextServicePointID(ctx context.Context) (int64, error) {
+	var id int64
+
+	err := p.db.QueryRowContext(ctx, `SELECT nextval('public.service_point_id_seq')`).Scan(&id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create service point: %w", err)
+		return 0, fmt.Errorf("failed to allocate service point id: %w", err)
 	}
-	return &servicePoint, nil
+	return id, nil
+}
+
+// CreateServicePoint inserts a new service point and reports
+// models.ErrAlreadyExists if the id is taken.
+func (p *SPStorage) CreateServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest) (*models.ServicePoint, error) {
+	shardID := p.GetShard(p.GetHash(id))
+
+	return p.audited(ctx, shardID, id, "failed to create service point", create(ctx, shardID, id, sp))
+}
+
+// create is the write of CreateServicePoint.
+func create(ctx context.Context, shardID uint32, id string, sp models.NewServicePointRequest) writeFunc {
+	return func(tx *sql.Tx, before *models.ServicePoint) (*models.ServicePoint, string, error) {
+		if before != nil {
+			return nil, "", models.ErrAlreadyExists
+		}
+
+		query := fmt.Sprintf(`
+			INSERT INTO shard_%d.service_points (id, name, short_name, office_number, office_id, max_queue_length, daily_ticket_quota, tenant_id)
+			VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6, $7, $8)
+			RETURNING %s
+		`, shardID, servicePointColumns)
+
+		servicePoint := models.ServicePoint{ShardID: int(shardID)}
+
+		err := scanServicePoint(tx.QueryRowContext(ctx, query, id, sp.Name, sp.ShortName, sp.OfficeNumber, sp.OfficeID, sp.MaxQueueLength, sp.DailyTicketQuota, tenant.FromContext(ctx)), &servicePoint)
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return nil, "", models.ErrAlreadyExists
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return &servicePoint, models.AuditActionCreate, nil
+	}
+}
+
+// ImportServicePoints runs a batch of creates and replaces in one
+// transaction across shards: either every write is made or none is. The
+// failing write is reported as a *models.RowError.
+func (p *SPStorage) ImportServicePoints(ctx context.Context, writes []models.ServicePointWrite) ([]models.ServicePoint, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import service points: %w", err)
+	}
+	defer tx.Rollback()
+
+	servicePoints := make([]models.ServicePoint, 0, len(writes))
+	for i, w := range writes {
+		shardID := p.GetShard(p.GetHash(w.ID))
+
+		write := upsert(ctx, shardID, w.ID, w.ServicePoint, 0)
+		if w.Create {
+			write = create(ctx, shardID, w.ID, w.ServicePoint)
+		}
+
+		sp, err := p.auditedTx(ctx, tx, shardID, w.ID, write)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import service points: %w", &models.RowError{Row: i + 1, Err: err})
+		}
+		servicePoints = append(servicePoints, *sp)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to import service points: %w", err)
+	}
+	return servicePoints, nil
+}
+
+// DeleteServicePoint soft-deletes a service point. A non-zero ifVersion only
+// deletes it at that version.
+func (p *SPStorage) DeleteServicePoint(ctx context.Context, id string, ifVersion int64) (*models.ServicePoint, error) {
+	shardID := p.GetShard(p.GetHash(id))
+
+	return p.audited(ctx, shardID, id, "failed to delete service point", func(tx *sql.Tx, before *models.ServicePoint) (*models.ServicePoint, string, error) {
+		live := before != nil && before.DeletedAt == nil
+		if ifVersion != 0 && (!live || before.Version != ifVersion) {
+			return nil, "", models.ErrVersionMismatch
+		}
+		if !live {
+			return nil, "", models.ErrNotFound
+		}
+
+		query := fmt.Sprintf(`
+			UPDATE shard_%d.service_points
+			SET
+				deleted_at = CURRENT_TIMESTAMP,
+				version = version + 1
+			WHERE id = $1
+			RETURNING %s
+		`, shardID, servicePointColumns)
+
+		servicePoint := models.ServicePoint{ShardID: int(shardID)}
+
+		if err := scanServicePoint(tx.QueryRowContext(ctx, query, id), &servicePoint); err != nil {
+			return nil, "", err
+		}
+		return &servicePoint, models.AuditActionDelete, nil
+	})
+}
+
+// RestoreServicePoint undoes a soft delete. Restoring a service point that
+// is not deleted returns it unchanged. A non-zero ifVersion only restores it
+// at that version.
+func (p *SPStorage) RestoreServicePoint(ctx context.Context, id string, ifVersion int64) (*models.ServicePoint, error) {
+	shardID := p.GetShard(p.GetHash(id))
+
+	return p.audited(ctx, shardID, id, "failed to restore service point", func(tx *sql.Tx, before *models.ServicePoint) (*models.ServicePoint, string, error) {
+		if before == nil {
+			return nil, "", models.ErrNotFound
+		}
+		if ifVersion != 0 && before.Version != ifVersion {
+			return nil, "", models.ErrVersionMismatch
+		}
+		if before.DeletedAt == nil {
+			return before, "", nil
+		}
+
+		query := fmt.Sprintf(`
+			UPDATE shard_%d.service_points
+			SET
+				deleted_at = NULL,
+				version = version + 1
+			WHERE id = $1
+			RETURNING %s
+		`, shardID, servicePointColumns)
+
+		servicePoint := models.ServicePoint{ShardID: int(shardID)}
+
+		if err := scanServicePoint(tx.QueryRowContext(ctx, query, id), &servicePoint); err != nil {
+			return nil, "", err
+		}
+		return &servicePoint, models.AuditActionRestore, nil
+	})
+}
+
+// SetServicePointStatus opens, pauses or closes a live service point with the
+// given reason and return time, and audits it as the matching action.
+func (p *SPStorage) SetServicePointStatus(ctx context.Context, id string, status string, change models.StatusChange) (*models.ServicePoint, error) {
+	shardID := p.GetShard(p.GetHash(id))
+
+	return p.audited(ctx, shardID, id, "failed to set service point status", func(tx *sql.Tx, before *models.ServicePoint) (*models.ServicePoint, string, error) {
+		if before == nil || before.DeletedAt != nil {
+			return nil, "", models.ErrNotFound
+		}
+
+		query := fmt.Sprintf(`
+			UPDATE shard_%d.service_points
+			SET
+				status = $2,
+				status_reason = $3,
+				return_at = $4,
+				version = version + 1
+			WHERE id = $1
+			RETURNING %s
+		`, shardID, servicePointColumns)
+
+		servicePoint := models.ServicePoint{ShardID: int(shardID)}
+
+		if err := scanServicePoint(tx.QueryRowContext(ctx, query, id, status, change.Reason, change.ReturnAt), &servicePoint); err != nil {
+			return nil, "", err
+		}
+		return &servicePoint, statusActions[status], nil
+	})
+}
+
+// statusActions is the audit action of a change to each status.
+var statusActions = map[string]string{
+	models.ServicePointStatusOpen:   models.AuditActionResume,
+	models.ServicePointStatusPaused: models.AuditActionPause,
+	models.ServicePointStatusClosed: models.AuditActionClose,
+}
+
+// writeFunc changes a service point within a transaction. It gets the
+// service point as it was, nil if there is none, and returns the new state and
+// the audit action; an empty action records nothing.
+type writeFunc func(tx *sql.Tx, before *models.ServicePoint) (*models.ServicePoint, string, error)
+
+// audited runs write in a transaction with the service point locked and
+// records the change it makes in the audit log of the same shard.
+func (p *SPStorage) audited(ctx context.Context, shardID uint32, id, msg string, write writeFunc) (*models.ServicePoint, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	defer tx.Rollback()
+
+	after, err := p.auditedTx(ctx, tx, shardID, id, write)
+	if err != nil {
+		return nil, wrapErr(msg, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return after, nil
 }
 
-func (p *SPStorage) DeleteServicePoint(ctx context.Context, id string) (*models.ServicePoint, error) {
-	shard := p.GetShard(p.GetHash(id))
+// auditedTx is audited within a transaction the caller commits, so several
+// writes, on any shards, succeed or fail together.
+func (p *SPStorage) auditedTx(ctx context.Context, tx *sql.Tx, shardID uint32, id string, write writeFunc) (*models.ServicePoint, error) {
+	before, err := p.lock(ctx, tx, shardID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	after, action, err := write(tx, before)
+	if err != nil {
+		return nil, err
+	}
+
+	if action != "" {
+		if err := insertAudit(ctx, tx, shardID, action, before, after); err != nil {
+			return nil, err
+		}
+	}
+	return after, nil
+}
+
+// lock reads a service point of the organisation in ctx, deleted or not, and
+// locks its row until the transaction ends. It returns nil if there is no
+// such service point.
+func (p *SPStorage) lock(ctx context.Context, tx *sql.Tx, shardID uint32, id string) (*models.ServicePoint, error) {
 	query := fmt.Sprintf(`
-		DELETE FROM shard_%d.service_points
-		WHERE id = $1
-		RETURNING id, name, short_name, office_number, created_at, updated_at
-	`, shard)
-
-	var servicePoint models.ServicePoint
-
-	err := p.db.QueryRowContext(ctx, query, id).Scan(
-		&servicePoint.ID,
-		&servicePoint.Name,
-		&servicePoint.ShortName,
-		&servicePoint.OfficeNumber,
-		&servicePoint.CreatedAt,
-		&servicePoint.UpdatedAt,
-	)
+		SELECT %s
+		FROM shard_%d.service_points
+		WHERE id = $1 AND tenant_id = $2
+		FOR UPDATE
+	`, servicePointColumns, shardID)
+
+	servicePoint := models.ServicePoint{ShardID: int(shardID)}
 
+	err := scanServicePoint(tx.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)), &servicePoint)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create service point: %w", err)
+		return nil, err
 	}
 	return &servicePoint, nil
 }
 
-func (p *SPStorage) GetServicePointByID(ctx context.Context, id string) (*models.ServicePoint, error) {
-	shard := p.GetShard(p.GetHash(id))
+func insertAudit(ctx context.Context, tx *sql.Tx, shardID uint32, action string, before, after *models.ServicePoint) error {
+	beforeJSON, err := marshalState(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := marshalState(after)
+	if err != nil {
+		return err
+	}
+
+	meta := audit.FromContext(ctx)
 	query := fmt.Sprintf(`
-		SELECT id, name, short_name, office_number, created_at, updated_at
+		INSERT INTO shard_%d.service_point_audit (service_point_id, action, before, after, caller, request_id, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, shardID)
+
+	_, err = tx.ExecContext(ctx, query, after.ID, action, beforeJSON, afterJSON, meta.Caller, meta.RequestID, tenant.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// marshalState encodes a service point for a JSONB column, nil as NULL.
+func marshalState(sp *models.ServicePoint) (any, error) {
+	if sp == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(sp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit state: %w", err)
+	}
+	return string(data), nil
+}
+
+// GetServicePointHistory returns up to limit audit records of a service
+// point with ids after cursor, oldest first.
+func (p *SPStorage) GetServicePointHistory(ctx context.Context, id string, cursor int64, limit int) ([]models.AuditRecord, error) {
+	shardID := p.GetShard(p.GetHash(id))
+	query := fmt.Sprintf(`
+		SELECT id, service_point_id, action, before, after, caller, request_id, created_at
+		FROM shard_%d.service_point_audit
+		WHERE service_point_id = $1 AND id > $2 AND tenant_id = $4
+		ORDER BY id
+		LIMIT $3
+	`, shardID)
+
+	rows, err := p.db.QueryContext(ctx, query, id, cursor, limit, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service point history: %w", err)
+	}
+	defer rows.Close()
+
+	records := []models.AuditRecord{}
+	for rows.Next() {
+		var record models.AuditRecord
+		var before, after []byte
+		err := rows.Scan(
+			&record.ID,
+			&record.ServicePointID,
+			&record.Action,
+			&before,
+			&after,
+			&record.Caller,
+			&record.RequestID,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service point history: %w", err)
+		}
+		record.Before = before
+		record.After = after
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get service point history: %w", err)
+	}
+	return records, nil
+}
+
+// PurgeServicePoints hard-deletes service points soft-deleted before the
+// given time and returns how many were removed. Their audit records are
+// kept. It is maintenance across all organisations, whatever the one in ctx.
+func (p *SPStorage) PurgeServicePoints(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+
+	for shardID := uint32(1); shardID <= p.nShards; shardID++ {
+		query := fmt.Sprintf(`
+			DELETE FROM shard_%d.service_points
+			WHERE deleted_at < $1
+		`, shardID)
+
+		res, err := p.db.ExecContext(ctx, query, before)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge service points: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge service points: %w", err)
+		}
+		purged += n
+	}
+	return purged, nil
+}
+
+// GetServicePointByID returns a service point. Soft-deleted ones are
+// reported as models.ErrNotFound unless includeDeleted is set.
+func (p *SPStorage) GetServicePointByID(ctx context.Context, id string, includeDeleted bool) (*models.ServicePoint, error) {
+	servicePoint, err := p.get(ctx, p.GetShard(p.GetHash(id)), id, includeDeleted)
+	if err != nil {
+		return nil, wrapErr("failed to get service point", err)
+	}
+	return servicePoint, nil
+}
+
+func (p *SPStorage) get(ctx context.Context, shardID uint32, id string, includeDeleted bool) (*models.ServicePoint, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM shard_%d.service_points
-		WHERE id = $1
-	`, shard)
-
-	var servicePoint models.ServicePoint
-
-	err := p.db.QueryRowContext(ctx, query, id).Scan(
-		&servicePoint.ID,
-		&servicePoint.Name,
-		&servicePoint.ShortName,
-		&servicePoint.OfficeNumber,
-		&servicePoint.CreatedAt,
-		&servicePoint.UpdatedAt,
-	)
+		WHERE id = $1 AND ($2 OR deleted_at IS NULL) AND tenant_id = $3
+	`, servicePointColumns, shardID)
+
+	servicePoint := models.ServicePoint{ShardID: int(shardID)}
 
+	err := scanServicePoint(p.db.QueryRowContext(ctx, query, id, includeDeleted, tenant.FromContext(ctx)), &servicePoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get service point: %w", err)
+		return nil, err
 	}
 	return &servicePoint, nil
 }
 
+// ListServicePoints reads every shard in turn and returns all service
+// points of the organisation in ctx ordered by id, skipping soft-deleted ones unless includeDeleted is
+// set.
+func (p *SPStorage) ListServicePoints(ctx context.Context, includeDeleted bool) ([]models.ServicePoint, error) {
+	var servicePoints []models.ServicePoint
+
+	for shardID := uint32(1); shardID <= p.nShards; shardID++ {
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM shard_%d.service_points
+			WHERE ($1 OR deleted_at IS NULL) AND tenant_id = $2
+		`, servicePointColumns, shardID)
+
+		rows, err := p.db.QueryContext(ctx, query, includeDeleted, tenant.FromContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list service points: %w", err)
+		}
+
+		for rows.Next() {
+			servicePoint := models.ServicePoint{ShardID: int(shardID)}
+			if err := scanServicePoint(rows, &servicePoint); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to list service points: %w", err)
+			}
+			servicePoints = append(servicePoints, servicePoint)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list service points: %w", err)
+		}
+	}
+
+	sort.Slice(servicePoints, func(i, j int) bool {
+		return servicePoints[i].ID < servicePoints[j].ID
+	})
+	return servicePoints, nil
+}
+
 func (p *SPStorage) GetShortNameById(ctx context.Context, id string) (string, error) {
-	shard := p.GetShard(p.GetHash(id))
+	shardID := p.GetShard(p.GetHash(id))
 	query := fmt.Sprintf(`
 		SELECT short_name
 		FROM shard_%d.service_points
-		WHERE id = $1
-	`, shard)
+		WHERE id = $1 AND deleted_at IS NULL AND tenant_id = $2
+	`, shardID)
 
 	var res string
 
-	err := p.db.QueryRowContext(ctx, query, id).Scan(
+	err := p.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)).Scan(
 		&res,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to get short name: %w", err)
+		return "", wrapErr("failed to get short name", err)
 	}
 	return res, nil
 }
 
 func (p *SPStorage) GetOfficeNumberById(ctx context.Context, id string) (string, error) {
-	shard := p.GetShard(p.GetHash(id))
+	shardID := p.GetShard(p.GetHash(id))
 	query := fmt.Sprintf(`
 		SELECT office_number
 		FROM shard_%d.service_points
-		WHERE id = $1
-	`, shard)
+		WHERE id = $1 AND deleted_at IS NULL AND tenant_id = $2
+	`, shardID)
 
 	var res string
 
-	err := p.db.QueryRowContext(ctx, query, id).Scan(
+	err := p.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)).Scan(
 		&res,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to get office number: %w", err)
+		return "", wrapErr("failed to get office number", err)
 	}
 	return res, nil
 }
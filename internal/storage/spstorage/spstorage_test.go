@@ -0,0 +1,43 @@
+package spstorage
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/snnus/mainservice/config"
+	"github.com/snnus/mainservice/internal/services/spservice"
+	"github.com/snnus/mainservice/internal/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConformance runs against a migrated database described by the config
+// file in SPSTORAGE_TEST_CONFIG. Every shard table and public table is
+// truncated between cases, and every organisation but the default one
+// removed.
+func TestConformance(t *testing.T) {
+	path := os.Getenv("SPSTORAGE_TEST_CONFIG")
+	if path == "" {
+		t.Skip("SPSTORAGE_TEST_CONFIG is not set")
+	}
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err)
+
+	s, close, err := NewSPStorage(cfg)
+	require.NoError(t, err)
+	defer close()
+
+	storagetest.Run(t, cfg.Postgres.NShards, func(t *testing.T) spservice.SPStorage {
+		for i := uint32(1); i <= cfg.Postgres.NShards; i++ {
+			_, err := s.db.Exec(fmt.Sprintf("TRUNCATE shard_%[1]d.service_points, shard_%[1]d.service_point_audit, shard_%[1]d.tickets, shard_%[1]d.slot_schedules, shard_%[1]d.appointments, shard_%[1]d.working_hours", i))
+			require.NoError(t, err)
+		}
+		// Service points of every shard reference offices, hence CASCADE.
+		_, err := s.db.Exec("TRUNCATE public.office_working_hours, public.desk_sessions, public.operators, public.category_tickets, public.service_point_categories, public.categories, public.offices, public.floors, public.buildings CASCADE")
+		require.NoError(t, err)
+		_, err = s.db.Exec("DELETE FROM public.organisations WHERE id <> 'default'")
+		require.NoError(t, err)
+		return s
+	})
+}
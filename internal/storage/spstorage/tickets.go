@@ -0,0 +1,310 @@
+package spstorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/tenant"
+)
+
+// ticketColumns is the column list scanTicket expects.
+const ticketColumns = "id, service_point_id, code, status, issued_at, called_at, COALESCE(office_number, ''), recalls, queued_at, COALESCE(transferred_from, 0), class, COALESCE(operator_id, 0), COALESCE(desk_number, ''), COALESCE(category_id, 0)"
+
+func scanTicket(row interface{ Scan(...any) error }, t *models.TicketRecord) error {
+	return row.Scan(
+		&t.ID,
+		&t.ServicePointID,
+		&t.Code,
+		&t.Status,
+		&t.IssuedAt,
+		&t.CalledAt,
+		&t.OfficeNumber,
+		&t.Recalls,
+		&t.QueuedAt,
+		&t.TransferredFrom,
+		&t.Class,
+		&t.OperatorID,
+		&t.DeskNumber,
+		&t.CategoryID,
+	)
+}
+
+// CreateTicket records a ticket just issued for a line of a service point.
+func (p *SPStorage) CreateTicket(ctx context.Context, spID string, code string, class string) (*models.TicketRecord, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		INSERT INTO shard_%d.tickets (service_point_id, code, status, class, tenant_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING %s
+	`, shardID, ticketColumns)
+
+	var ticket models.TicketRecord
+
+	err := scanTicket(p.db.QueryRowContext(ctx, query, spID, code, models.TicketStatusWaiting, class, tenant.FromContext(ctx)), &ticket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ticket: %w", err)
+	}
+	return &ticket, nil
+}
+
+// CreateTransferredTicket records a ticket transferred from another service
+// point, queued behind the waiting tickets or, if front is set, ahead of them.
+func (p *SPStorage) CreateTransferredTicket(ctx context.Context, spID string, code string, class string, from int64, front bool) (*models.TicketRecord, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		INSERT INTO shard_%d.tickets (service_point_id, code, status, class, transferred_from, tenant_id, queued_at)
+		VALUES ($1, $2, $3, $6, $4, $7, CASE WHEN $5 THEN COALESCE((
+			SELECT MIN(queued_at) - INTERVAL '1 microsecond'
+			FROM shard_%d.tickets
+			WHERE service_point_id = $1 AND status = $3 AND tenant_id = $7
+		), CURRENT_TIMESTAMP) ELSE CURRENT_TIMESTAMP END)
+		RETURNING %s
+	`, shardID, shardID, ticketColumns)
+
+	var ticket models.TicketRecord
+
+	err := scanTicket(p.db.QueryRowContext(ctx, query, spID, code, models.TicketStatusWaiting, from, front, class, tenant.FromContext(ctx)), &ticket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ticket: %w", err)
+	}
+	return &ticket, nil
+}
+
+// CallTicket marks the latest waiting ticket with the given code as called
+// to the office, and desk if any, of call. The tickets the service point
+// called before are served by now. It reports models.ErrTicketNotFound if
+// there is no such waiting ticket.
+func (p *SPStorage) CallTicket(ctx context.Context, spID string, code string, call models.TicketCall) (*models.TicketRecord, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ticket: %w", err)
+	}
+	defer tx.Rollback()
+
+	serve := fmt.Sprintf(`
+		UPDATE shard_%d.tickets
+		SET status = $2
+		WHERE service_point_id = $1 AND status = $3 AND tenant_id = $4
+	`, shardID)
+
+	_, err = tx.ExecContext(ctx, serve, spID, models.TicketStatusServed, models.TicketStatusCalled, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ticket: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE shard_%d.tickets
+		SET
+			status = $3,
+			called_at = CURRENT_TIMESTAMP,
+			office_number = $5,
+			operator_id = NULLIF($6, 0),
+			desk_number = NULLIF($7, '')
+		WHERE id = (
+			SELECT id
+			FROM shard_%d.tickets
+			WHERE service_point_id = $1 AND code = $2 AND status = $4 AND tenant_id = $8
+			ORDER BY id DESC
+			LIMIT 1
+			FOR UPDATE
+		)
+		RETURNING %s
+	`, shardID, shardID, ticketColumns)
+
+	var ticket models.TicketRecord
+
+	err = scanTicket(tx.QueryRowContext(ctx, query, spID, code, models.TicketStatusCalled, models.TicketStatusWaiting, call.OfficeNumber, call.OperatorID, call.DeskNumber, tenant.FromContext(ctx)), &ticket)
+	if err != nil {
+		return nil, wrapTicketErr("failed to call ticket", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to call ticket: %w", err)
+	}
+	return &ticket, nil
+}
+
+// RecallTicket counts another call of a ticket that is still called. It
+// reports models.ErrTicketNotFound if the ticket is not called.
+func (p *SPStorage) RecallTicket(ctx context.Context, spID string, ticketID int64) (*models.TicketRecord, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		UPDATE shard_%d.tickets
+		SET recalls = recalls + 1
+		WHERE service_point_id = $1 AND id = $2 AND status = $3 AND tenant_id = $4
+		RETURNING %s
+	`, shardID, ticketColumns)
+
+	var ticket models.TicketRecord
+
+	err := scanTicket(p.db.QueryRowContext(ctx, query, spID, ticketID, models.TicketStatusCalled, tenant.FromContext(ctx)), &ticket)
+	if err != nil {
+		return nil, wrapTicketErr("failed to recall ticket", err)
+	}
+	return &ticket, nil
+}
+
+// SetTicketStatus moves a ticket from status from to status to. It reports
+// models.ErrTicketNotFound if the ticket is not in status from.
+func (p *SPStorage) SetTicketStatus(ctx context.Context, spID string, ticketID int64, from string, to string) (*models.TicketRecord, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		UPDATE shard_%d.tickets
+		SET status = $4
+		WHERE service_point_id = $1 AND id = $2 AND status = $3 AND tenant_id = $5
+		RETURNING %s
+	`, shardID, ticketColumns)
+
+	var ticket models.TicketRecord
+
+	err := scanTicket(p.db.QueryRowContext(ctx, query, spID, ticketID, from, to, tenant.FromContext(ctx)), &ticket)
+	if err != nil {
+		return nil, wrapTicketErr("failed to update ticket", err)
+	}
+	return &ticket, nil
+}
+
+// CountIssuedTickets returns how many tickets a service point issued since
+// the given time. Tickets transferred in or claimed from a category were
+// issued elsewhere and do not count.
+func (p *SPStorage) CountIssuedTickets(ctx context.Context, spID string, since time.Time) (int, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM shard_%d.tickets
+		WHERE service_point_id = $1 AND issued_at >= $2 AND transferred_from IS NULL AND category_id IS NULL
+			AND tenant_id = $3
+	`, shardID)
+
+	var count int
+
+	err := p.db.QueryRowContext(ctx, query, spID, since, tenant.FromContext(ctx)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count issued tickets: %w", err)
+	}
+	return count, nil
+}
+
+// RecentCallTimes returns when the last limit tickets of a service point
+// were called, newest first.
+func (p *SPStorage) RecentCallTimes(ctx context.Context, spID string, limit int) ([]time.Time, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		SELECT called_at
+		FROM shard_%d.tickets
+		WHERE service_point_id = $1 AND called_at IS NOT NULL AND tenant_id = $3
+		ORDER BY called_at DESC
+		LIMIT $2
+	`, shardID)
+
+	rows, err := p.db.QueryContext(ctx, query, spID, limit, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call times: %w", err)
+	}
+	defer rows.Close()
+
+	var times []time.Time
+	for rows.Next() {
+		var t time.Time
+		if err := rows.Scan(&t); err != nil {
+			return nil, fmt.Errorf("failed to get call times: %w", err)
+		}
+		times = append(times, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get call times: %w", err)
+	}
+	return times, nil
+}
+
+// ListTickets returns the tickets of a service point matching filter in the
+// order they were issued.
+func (p *SPStorage) ListTickets(ctx context.Context, spID string, filter models.TicketFilter) ([]models.TicketRecord, error) {
+	shardID := p.GetShard(p.GetHash(spID))
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM shard_%d.tickets
+		WHERE service_point_id = $1 AND tenant_id = $6
+			AND ($2::text = '' OR code = $2::text)
+			AND ($3::text = '' OR status = $3::text)
+			AND ($4::timestamptz IS NULL OR issued_at >= $4::timestamptz)
+			AND ($5::timestamptz IS NULL OR issued_at < $5::timestamptz)
+		ORDER BY id
+	`, ticketColumns, shardID)
+
+	rows, err := p.db.QueryContext(ctx, query, spID, filter.Code, filter.Status, nullTime(filter.IssuedFrom), nullTime(filter.IssuedTo), tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tickets: %w", err)
+	}
+	defer rows.Close()
+
+	tickets := []models.TicketRecord{}
+	for rows.Next() {
+		var ticket models.TicketRecord
+		if err := scanTicket(rows, &ticket); err != nil {
+			return nil, fmt.Errorf("failed to list tickets: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tickets: %w", err)
+	}
+	return tickets, nil
+}
+
+// ListAllTickets returns the tickets of every service point of the
+// organisation in ctx matching filter from all shards, ordered by issue time.
+func (p *SPStorage) ListAllTickets(ctx context.Context, filter models.TicketFilter) ([]models.TicketRecord, error) {
+	tickets := []models.TicketRecord{}
+
+	for shardID := uint32(1); shardID <= p.nShards; shardID++ {
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM shard_%d.tickets
+			WHERE ($1::text = '' OR code = $1::text)
+				AND ($2::text = '' OR status = $2::text)
+				AND ($3::timestamptz IS NULL OR issued_at >= $3::timestamptz)
+				AND ($4::timestamptz IS NULL OR issued_at < $4::timestamptz)
+				AND tenant_id = $5
+		`, ticketColumns, shardID)
+
+		rows, err := p.db.QueryContext(ctx, query, filter.Code, filter.Status, nullTime(filter.IssuedFrom), nullTime(filter.IssuedTo), tenant.FromContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tickets: %w", err)
+		}
+
+		for rows.Next() {
+			var ticket models.TicketRecord
+			if err := scanTicket(rows, &ticket); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to list tickets: %w", err)
+			}
+			tickets = append(tickets, ticket)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list tickets: %w", err)
+		}
+	}
+
+	sort.SliceStable(tickets, func(i, j int) bool {
+		return tickets[i].IssuedAt.Before(tickets[j].IssuedAt)
+	})
+	return tickets, nil
+}
+
+// nullTime passes the zero time as NULL.
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,1284 @@
+// Package storagetest is a conformance suite every SPStorage implementation
+// must pass.
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/snnus/mainservice/internal/audit"
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/services/spservice"
+	"github.com/snnus/mainservice/internal/storage/shard"
+	"github.com/snnus/mainservice/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run executes the suite. newStorage must return an empty storage that
+// spreads service points over nShards shards.
+func Run(t *testing.T, nShards uint32, newStorage func(t *testing.T) spservice.SPStorage) {
+	ctx := context.Background()
+
+	cashDesk := models.NewServicePointRequest{Name: "Cash desk", ShortName: "C", OfficeNumber: "101"}
+	accounts := models.NewServicePointRequest{Name: "Accounts", ShortName: "A", OfficeNumber: "202"}
+
+	t.Run("upsert creates service point", func(t *testing.T) {
+		s := newStorage(t)
+
+		sp, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), sp.ID)
+		assert.Equal(t, cashDesk.Name, sp.Name)
+		assert.Equal(t, cashDesk.ShortName, sp.ShortName)
+		assert.Equal(t, cashDesk.OfficeNumber, sp.OfficeNumber)
+		assert.False(t, sp.CreatedAt.IsZero())
+		assert.False(t, sp.UpdatedAt.Before(sp.CreatedAt))
+	})
+
+	t.Run("upsert overwrites fields and keeps created_at", func(t *testing.T) {
+		s := newStorage(t)
+
+		created, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+
+		updated, err := s.UpsertServicePoint(ctx, "1", accounts, 0)
+		require.NoError(t, err)
+		assert.Equal(t, accounts.Name, updated.Name)
+		assert.Equal(t, accounts.ShortName, updated.ShortName)
+		assert.Equal(t, accounts.OfficeNumber, updated.OfficeNumber)
+		assert.True(t, created.CreatedAt.Equal(updated.CreatedAt))
+		assert.False(t, updated.UpdatedAt.Before(created.UpdatedAt))
+	})
+
+	t.Run("every write bumps version", func(t *testing.T) {
+		s := newStorage(t)
+
+		created, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), created.Version)
+
+		updated, err := s.UpsertServicePoint(ctx, "1", accounts, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+
+		sp, err := s.GetServicePointByID(ctx, "1", false)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), sp.Version)
+	})
+
+	t.Run("conditional upsert", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.UpsertServicePoint(ctx, "1", cashDesk, 1)
+		assert.ErrorIs(t, err, models.ErrVersionMismatch, "missing row")
+
+		_, err = s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+
+		_, err = s.UpsertServicePoint(ctx, "1", accounts, 2)
+		assert.ErrorIs(t, err, models.ErrVersionMismatch, "stale version")
+
+		updated, err := s.UpsertServicePoint(ctx, "1", accounts, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+		assert.Equal(t, accounts.Name, updated.Name)
+	})
+
+	t.Run("conditional delete", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+
+		_, err = s.DeleteServicePoint(ctx, "1", 7)
+		assert.ErrorIs(t, err, models.ErrVersionMismatch)
+
+		_, err = s.GetServicePointByID(ctx, "1", false)
+		require.NoError(t, err)
+
+		deleted, err := s.DeleteServicePoint(ctx, "1", 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted.Version)
+	})
+
+	t.Run("service points carry their shard id", func(t *testing.T) {
+		s := newStorage(t)
+
+		for i := 1; i <= 16; i++ {
+			id := strconv.Itoa(i)
+			want := int(shard.Of(shard.Hash(id), nShards))
+
+			sp, err := s.UpsertServicePoint(ctx, id, cashDesk, 0)
+			require.NoError(t, err)
+			assert.Equal(t, want, sp.ShardID, "upsert %s", id)
+
+			sp, err = s.GetServicePointByID(ctx, id, false)
+			require.NoError(t, err)
+			assert.Equal(t, want, sp.ShardID, "get %s", id)
+		}
+	})
+
+	t.Run("next id is unique and increasing", func(t *testing.T) {
+		s := newStorage(t)
+
+		var last int64
+		for i := 0; i < 5; i++ {
+			id, err := s.NextServicePointID(ctx)
+			require.NoError(t, err)
+			assert.Greater(t, id, last)
+			last = id
+		}
+	})
+
+	t.Run("create stores service point at version 1", func(t *testing.T) {
+		s := newStorage(t)
+
+		id, err := s.NextServicePointID(ctx)
+		require.NoError(t, err)
+		key := strconv.FormatInt(id, 10)
+
+		sp, err := s.CreateServicePoint(ctx, key, cashDesk)
+		require.NoError(t, err)
+		assert.Equal(t, id, sp.ID)
+		assert.Equal(t, cashDesk.Name, sp.Name)
+		assert.Equal(t, int64(1), sp.Version)
+		assert.Equal(t, int(shard.Of(shard.Hash(key), nShards)), sp.ShardID)
+
+		got, err := s.GetServicePointByID(ctx, key, false)
+		require.NoError(t, err)
+		assert.Equal(t, sp.ShardID, got.ShardID)
+	})
+
+	t.Run("create does not overwrite existing service point", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+
+		_, err = s.CreateServicePoint(ctx, "1", accounts)
+		assert.ErrorIs(t, err, models.ErrAlreadyExists)
+
+		sp, err := s.GetServicePointByID(ctx, "1", false)
+		require.NoError(t, err)
+		assert.Equal(t, cashDesk.Name, sp.Name)
+	})
+
+	t.Run("get returns stored service point", func(t *testing.T) {
+		s := newStorage(t)
+
+		upserted, err := s.UpsertServicePoint(ctx, "5", cashDesk, 0)
+		require.NoError(t, err)
+
+		sp, err := s.GetServicePointByID(ctx, "5", false)
+		require.NoError(t, err)
+		assert.Equal(t, upserted.ID, sp.ID)
+		assert.Equal(t, upserted.Name, sp.Name)
+		assert.True(t, upserted.CreatedAt.Equal(sp.CreatedAt))
+		assert.True(t, upserted.UpdatedAt.Equal(sp.UpdatedAt))
+
+		shortName, err := s.GetShortNameById(ctx, "5")
+		require.NoError(t, err)
+		assert.Equal(t, cashDesk.ShortName, shortName)
+
+		officeNumber, err := s.GetOfficeNumberById(ctx, "5")
+		require.NoError(t, err)
+		assert.Equal(t, cashDesk.OfficeNumber, officeNumber)
+	})
+
+	t.Run("missing service point is not found", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.GetServicePointByID(ctx, "404", false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		_, err = s.GetShortNameById(ctx, "404")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		_, err = s.GetOfficeNumberById(ctx, "404")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		_, err = s.DeleteServicePoint(ctx, "404", 0)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("delete removes service point", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.UpsertServicePoint(ctx, "3", cashDesk, 0)
+		require.NoError(t, err)
+		_, err = s.UpsertServicePoint(ctx, "4", accounts, 0)
+		require.NoError(t, err)
+
+		deleted, err := s.DeleteServicePoint(ctx, "3", 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), deleted.ID)
+		assert.Equal(t, cashDesk.Name, deleted.Name)
+
+		_, err = s.GetServicePointByID(ctx, "3", false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		_, err = s.GetServicePointByID(ctx, "4", false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("deleted service point is hidden but kept", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.UpsertServicePoint(ctx, "3", cashDesk, 0)
+		require.NoError(t, err)
+
+		deleted, err := s.DeleteServicePoint(ctx, "3", 0)
+		require.NoError(t, err)
+		require.NotNil(t, deleted.DeletedAt)
+
+		_, err = s.GetShortNameById(ctx, "3")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		_, err = s.GetOfficeNumberById(ctx, "3")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		_, err = s.DeleteServicePoint(ctx, "3", 0)
+		assert.ErrorIs(t, err, models.ErrNotFound, "already deleted")
+
+		sp, err := s.GetServicePointByID(ctx, "3", true)
+		require.NoError(t, err)
+		assert.Equal(t, cashDesk.Name, sp.Name)
+		assert.NotNil(t, sp.DeletedAt)
+
+		servicePoints, err := s.ListServicePoints(ctx, false)
+		require.NoError(t, err)
+		assert.Empty(t, servicePoints)
+
+		servicePoints, err = s.ListServicePoints(ctx, true)
+		require.NoError(t, err)
+		require.Len(t, servicePoints, 1)
+		assert.NotNil(t, servicePoints[0].DeletedAt)
+	})
+
+	t.Run("deleted id stays taken", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+		deleted, err := s.DeleteServicePoint(ctx, "1", 0)
+		require.NoError(t, err)
+
+		_, err = s.UpsertServicePoint(ctx, "1", accounts, 0)
+		assert.ErrorIs(t, err, models.ErrDeleted)
+
+		_, err = s.UpsertServicePoint(ctx, "1", accounts, deleted.Version)
+		assert.ErrorIs(t, err, models.ErrDeleted)
+
+		_, err = s.CreateServicePoint(ctx, "1", accounts)
+		assert.ErrorIs(t, err, models.ErrAlreadyExists)
+	})
+
+	t.Run("restore undoes delete", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+		deleted, err := s.DeleteServicePoint(ctx, "1", 0)
+		require.NoError(t, err)
+
+		_, err = s.RestoreServicePoint(ctx, "1", deleted.Version+1)
+		assert.ErrorIs(t, err, models.ErrVersionMismatch)
+
+		restored, err := s.RestoreServicePoint(ctx, "1", deleted.Version)
+		require.NoError(t, err)
+		assert.Nil(t, restored.DeletedAt)
+		assert.Equal(t, deleted.Version+1, restored.Version)
+
+		again, err := s.RestoreServicePoint(ctx, "1", 0)
+		require.NoError(t, err)
+		assert.Equal(t, restored.Version, again.Version, "restoring a live service point is a no-op")
+
+		shortName, err := s.GetShortNameById(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, cashDesk.ShortName, shortName)
+
+		_, err = s.RestoreServicePoint(ctx, "404", 0)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("purge removes only old deleted service points", func(t *testing.T) {
+		s := newStorage(t)
+
+		for _, id := range []string{"1", "2"} {
+			_, err := s.UpsertServicePoint(ctx, id, cashDesk, 0)
+			require.NoError(t, err)
+		}
+		_, err := s.DeleteServicePoint(ctx, "1", 0)
+		require.NoError(t, err)
+
+		purged, err := s.PurgeServicePoints(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, purged, "deleted too recently")
+
+		purged, err = s.PurgeServicePoints(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		_, err = s.GetServicePointByID(ctx, "1", true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		_, err = s.GetServicePointByID(ctx, "2", false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("every write is audited", func(t *testing.T) {
+		s := newStorage(t)
+		ctx := audit.NewContext(ctx, audit.Meta{Caller: "alice", RequestID: "req-1"})
+
+		_, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+		_, err = s.UpsertServicePoint(ctx, "1", accounts, 0)
+		require.NoError(t, err)
+		_, err = s.DeleteServicePoint(ctx, "1", 0)
+		require.NoError(t, err)
+		_, err = s.RestoreServicePoint(ctx, "1", 0)
+		require.NoError(t, err)
+		_, err = s.RestoreServicePoint(ctx, "1", 0)
+		require.NoError(t, err, "no-op restore")
+		_, err = s.UpsertServicePoint(ctx, "1", cashDesk, 1)
+		require.ErrorIs(t, err, models.ErrVersionMismatch, "failed write")
+
+		records, err := s.GetServicePointHistory(ctx, "1", 0, 10)
+		require.NoError(t, err)
+
+		var actions []string
+		for _, r := range records {
+			actions = append(actions, r.Action)
+			assert.Equal(t, int64(1), r.ServicePointID)
+			assert.Equal(t, "alice", r.Caller)
+			assert.Equal(t, "req-1", r.RequestID)
+			assert.False(t, r.CreatedAt.IsZero())
+		}
+		assert.Equal(t, []string{
+			models.AuditActionCreate,
+			models.AuditActionUpdate,
+			models.AuditActionDelete,
+			models.AuditActionRestore,
+		}, actions)
+
+		assert.Empty(t, records[0].Before)
+		var before, after models.ServicePoint
+		require.NoError(t, json.Unmarshal(records[1].Before, &before))
+		require.NoError(t, json.Unmarshal(records[1].After, &after))
+		assert.Equal(t, cashDesk.OfficeNumber, before.OfficeNumber)
+		assert.Equal(t, accounts.OfficeNumber, after.OfficeNumber)
+		assert.Equal(t, int64(2), after.Version)
+	})
+
+	t.Run("history pages by cursor", func(t *testing.T) {
+		s := newStorage(t)
+
+		for i := 0; i < 5; i++ {
+			_, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+			require.NoError(t, err)
+		}
+		_, err := s.UpsertServicePoint(ctx, "2", cashDesk, 0)
+		require.NoError(t, err)
+
+		first, err := s.GetServicePointHistory(ctx, "1", 0, 3)
+		require.NoError(t, err)
+		require.Len(t, first, 3)
+
+		rest, err := s.GetServicePointHistory(ctx, "1", first[2].ID, 3)
+		require.NoError(t, err)
+		require.Len(t, rest, 2)
+		assert.Greater(t, rest[0].ID, first[2].ID)
+
+		none, err := s.GetServicePointHistory(ctx, "404", 0, 3)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("history outlives purge", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+		_, err = s.DeleteServicePoint(ctx, "1", 0)
+		require.NoError(t, err)
+		_, err = s.PurgeServicePoints(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		records, err := s.GetServicePointHistory(ctx, "1", 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, records, 2)
+	})
+
+	t.Run("tickets are recorded and called", func(t *testing.T) {
+		s := newStorage(t)
+
+		issued, err := s.CreateTicket(ctx, "1", "C001", models.TicketClassRegular)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), issued.ServicePointID)
+		assert.Equal(t, models.TicketStatusWaiting, issued.Status)
+		assert.Equal(t, models.TicketClassRegular, issued.Class)
+		assert.False(t, issued.IssuedAt.IsZero())
+		assert.Nil(t, issued.CalledAt)
+
+		_, err = s.CreateTicket(ctx, "1", "C002", models.TicketClassRegular)
+		require.NoError(t, err)
+
+		called, err := s.CallTicket(ctx, "1", "C001", models.TicketCall{OfficeNumber: "101"})
+		require.NoError(t, err)
+		assert.Equal(t, issued.ID, called.ID)
+		assert.Equal(t, models.TicketStatusCalled, called.Status)
+		assert.Equal(t, "101", called.OfficeNumber)
+		require.NotNil(t, called.CalledAt)
+
+		_, err = s.CallTicket(ctx, "1", "C001", models.TicketCall{OfficeNumber: "101"})
+		assert.ErrorIs(t, err, models.ErrTicketNotFound, "already called")
+
+		_, err = s.CallTicket(ctx, "2", "C002", models.TicketCall{OfficeNumber: "101"})
+		assert.ErrorIs(t, err, models.ErrTicketNotFound, "other service point")
+
+		tickets, err := s.ListTickets(ctx, "1", models.TicketFilter{})
+		require.NoError(t, err)
+		require.Len(t, tickets, 2)
+		assert.Equal(t, "C001", tickets[0].Code)
+		assert.Equal(t, models.TicketStatusCalled, tickets[0].Status)
+		assert.Equal(t, models.TicketStatusWaiting, tickets[1].Status)
+	})
+
+	t.Run("calling a reused code takes the latest ticket", func(t *testing.T) {
+		s := newStorage(t)
+
+		old, err := s.CreateTicket(ctx, "1", "C001", models.TicketClassRegular)
+		require.NoError(t, err)
+		latest, err := s.CreateTicket(ctx, "1", "C001", models.TicketClassRegular)
+		require.NoError(t, err)
+
+		called, err := s.CallTicket(ctx, "1", "C001", models.TicketCall{OfficeNumber: "101"})
+		require.NoError(t, err)
+		assert.Equal(t, latest.ID, called.ID)
+
+		called, err = s.CallTicket(ctx, "1", "C001", models.TicketCall{OfficeNumber: "101"})
+		require.NoError(t, err)
+		assert.Equal(t, old.ID, called.ID)
+
+		tickets, err := s.ListTickets(ctx, "1", models.TicketFilter{})
+		require.NoError(t, err)
+		require.Len(t, tickets, 2)
+		assert.Equal(t, models.TicketStatusCalled, tickets[0].Status)
+		assert.Equal(t, models.TicketStatusServed, tickets[1].Status, "served once the next one is called")
+		assert.NotNil(t, tickets[1].CalledAt)
+	})
+
+	t.Run("tickets filter by code and issue time", func(t *testing.T) {
+		s := newStorage(t)
+
+		for _, code := range []string{"C001", "C002", "C001"} {
+			_, err := s.CreateTicket(ctx, "1", code, models.TicketClassRegular)
+			require.NoError(t, err)
+		}
+
+		tickets, err := s.ListTickets(ctx, "1", models.TicketFilter{Code: "C001"})
+		require.NoError(t, err)
+		assert.Len(t, tickets, 2)
+
+		tickets, err = s.ListTickets(ctx, "1", models.TicketFilter{IssuedFrom: time.Now().Add(-time.Hour), IssuedTo: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		assert.Len(t, tickets, 3)
+
+		tickets, err = s.ListTickets(ctx, "1", models.TicketFilter{IssuedTo: time.Now().Add(-time.Hour)})
+		require.NoError(t, err)
+		assert.Empty(t, tickets)
+
+		tickets, err = s.ListTickets(ctx, "1", models.TicketFilter{IssuedFrom: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		assert.Empty(t, tickets)
+	})
+
+	t.Run("all tickets across shards", func(t *testing.T) {
+		s := newStorage(t)
+
+		for i := 1; i <= 8; i++ {
+			_, err := s.CreateTicket(ctx, strconv.Itoa(i), "C001", models.TicketClassRegular)
+			require.NoError(t, err)
+		}
+
+		tickets, err := s.ListAllTickets(ctx, models.TicketFilter{})
+		require.NoError(t, err)
+		require.Len(t, tickets, 8)
+		for i := 1; i < len(tickets); i++ {
+			assert.False(t, tickets[i].IssuedAt.Before(tickets[i-1].IssuedAt))
+		}
+
+		tickets, err = s.ListAllTickets(ctx, models.TicketFilter{IssuedTo: time.Now().Add(-time.Hour)})
+		require.NoError(t, err)
+		assert.Empty(t, tickets)
+	})
+
+	t.Run("waiting tickets and call times", func(t *testing.T) {
+		s := newStorage(t)
+
+		var last *models.TicketRecord
+		for _, code := range []string{"C001", "C002", "C003"} {
+			ticket, err := s.CreateTicket(ctx, "1", code, models.TicketClassRegular)
+			require.NoError(t, err)
+			last = ticket
+		}
+		_, err := s.CreateTicket(ctx, "2", "D001", models.TicketClassRegular)
+		require.NoError(t, err)
+
+		waiting, err := s.ListTickets(ctx, "1", models.TicketFilter{Status: models.TicketStatusWaiting})
+		require.NoError(t, err)
+		assert.Len(t, waiting, 3)
+
+		calls, err := s.RecentCallTimes(ctx, "1", 10)
+		require.NoError(t, err)
+		assert.Empty(t, calls)
+
+		for _, code := range []string{"C001", "C002"} {
+			_, err := s.CallTicket(ctx, "1", code, models.TicketCall{OfficeNumber: "101"})
+			require.NoError(t, err)
+		}
+
+		waiting, err = s.ListTickets(ctx, "1", models.TicketFilter{Status: models.TicketStatusWaiting})
+		require.NoError(t, err)
+		require.Len(t, waiting, 1)
+		assert.Equal(t, last.ID, waiting[0].ID)
+
+		calls, err = s.RecentCallTimes(ctx, "1", 10)
+		require.NoError(t, err)
+		require.Len(t, calls, 2)
+		assert.False(t, calls[0].Before(calls[1]), "newest first")
+
+		calls, err = s.RecentCallTimes(ctx, "1", 1)
+		require.NoError(t, err)
+		assert.Len(t, calls, 1)
+	})
+
+	t.Run("recalls and status changes", func(t *testing.T) {
+		s := newStorage(t)
+
+		first, err := s.CreateTicket(ctx, "1", "C001", models.TicketClassRegular)
+		require.NoError(t, err)
+		second, err := s.CreateTicket(ctx, "1", "C002", models.TicketClassRegular)
+		require.NoError(t, err)
+
+		_, err = s.RecallTicket(ctx, "1", first.ID)
+		assert.ErrorIs(t, err, models.ErrTicketNotFound, "not called yet")
+
+		_, err = s.CallTicket(ctx, "1", "C001", models.TicketCall{OfficeNumber: "101"})
+		require.NoError(t, err)
+		recalled, err := s.RecallTicket(ctx, "1", first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, recalled.Recalls)
+		recalled, err = s.RecallTicket(ctx, "1", first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, recalled.Recalls)
+
+		noShow, err := s.SetTicketStatus(ctx, "1", first.ID, models.TicketStatusCalled, models.TicketStatusNoShow)
+		require.NoError(t, err)
+		assert.Equal(t, models.TicketStatusNoShow, noShow.Status)
+		assert.Equal(t, 2, noShow.Recalls)
+
+		_, err = s.SetTicketStatus(ctx, "1", second.ID, models.TicketStatusCalled, models.TicketStatusNoShow)
+		assert.ErrorIs(t, err, models.ErrTicketNotFound, "still waiting")
+		_, err = s.SetTicketStatus(ctx, "2", second.ID, models.TicketStatusWaiting, models.TicketStatusCancelled)
+		assert.ErrorIs(t, err, models.ErrTicketNotFound, "other service point")
+		_, err = s.SetTicketStatus(ctx, "1", second.ID, models.TicketStatusWaiting, models.TicketStatusCancelled)
+		require.NoError(t, err)
+
+		tickets, err := s.ListTickets(ctx, "1", models.TicketFilter{Status: models.TicketStatusCancelled})
+		require.NoError(t, err)
+		require.Len(t, tickets, 1)
+		assert.Equal(t, second.ID, tickets[0].ID)
+
+		tickets, err = s.ListAllTickets(ctx, models.TicketFilter{Status: models.TicketStatusNoShow})
+		require.NoError(t, err)
+		require.Len(t, tickets, 1)
+		assert.Equal(t, first.ID, tickets[0].ID)
+	})
+
+	t.Run("transferred tickets queue at the front or back", func(t *testing.T) {
+		s := newStorage(t)
+
+		first, err := s.CreateTicket(ctx, "2", "A001", models.TicketClassRegular)
+		require.NoError(t, err)
+		_, err = s.CreateTicket(ctx, "2", "A002", models.TicketClassRegular)
+		require.NoError(t, err)
+
+		back, err := s.CreateTransferredTicket(ctx, "2", "PC001", models.TicketClassPriority, 1, false)
+		require.NoError(t, err)
+		assert.Equal(t, models.TicketClassPriority, back.Class)
+		assert.Equal(t, int64(1), back.TransferredFrom)
+		assert.Equal(t, models.TicketStatusWaiting, back.Status)
+		front, err := s.CreateTransferredTicket(ctx, "2", "C002", models.TicketClassRegular, 1, true)
+		require.NoError(t, err)
+		assert.True(t, front.QueuedAt.Before(first.QueuedAt))
+
+		again, err := s.CreateTransferredTicket(ctx, "2", "C003", models.TicketClassRegular, 1, true)
+		require.NoError(t, err)
+		assert.True(t, again.QueuedAt.Before(front.QueuedAt), "ahead of the earlier front transfer")
+
+		waiting, err := s.ListTickets(ctx, "2", models.TicketFilter{Status: models.TicketStatusWaiting})
+		require.NoError(t, err)
+		sort.SliceStable(waiting, func(i, j int) bool {
+			return waiting[i].QueuedAt.Before(waiting[j].QueuedAt)
+		})
+		var codes []string
+		for _, ticket := range waiting {
+			codes = append(codes, ticket.Code)
+		}
+		assert.Equal(t, []string{"C003", "C002", "A001", "A002", "PC001"}, codes)
+
+		tickets, err := s.ListTickets(ctx, "2", models.TicketFilter{Code: "C002"})
+		require.NoError(t, err)
+		require.Len(t, tickets, 1)
+		assert.Equal(t, front.QueuedAt.UnixMicro(), tickets[0].QueuedAt.UnixMicro())
+	})
+
+	t.Run("appointments fill their slot", func(t *testing.T) {
+		s := newStorage(t)
+
+		slot := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+		booking := models.Appointment{SlotStart: slot, SlotEnd: slot.Add(30 * time.Minute), Name: "Ann"}
+
+		_, err := s.GetSlotSchedule(ctx, "1")
+		assert.ErrorIs(t, err, models.ErrScheduleNotFound)
+		_, err = s.BookAppointment(ctx, "1", booking)
+		assert.ErrorIs(t, err, models.ErrScheduleNotFound)
+
+		schedule, err := s.UpsertSlotSchedule(ctx, "1", models.SlotScheduleRequest{SlotMinutes: 30, Capacity: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), schedule.ServicePointID)
+		schedule, err = s.UpsertSlotSchedule(ctx, "1", models.SlotScheduleRequest{SlotMinutes: 30, Capacity: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 2, schedule.Capacity)
+
+		first, err := s.BookAppointment(ctx, "1", booking)
+		require.NoError(t, err)
+		assert.Equal(t, models.AppointmentStatusBooked, first.Status)
+		assert.True(t, slot.Equal(first.SlotStart))
+		second, err := s.BookAppointment(ctx, "1", booking)
+		require.NoError(t, err)
+		_, err = s.BookAppointment(ctx, "1", booking)
+		assert.ErrorIs(t, err, models.ErrSlotFull)
+
+		got, err := s.GetAppointment(ctx, strconv.FormatInt(second.ID, 10))
+		require.NoError(t, err)
+		assert.Equal(t, "Ann", got.Name)
+		_, err = s.GetAppointment(ctx, "999999")
+		assert.ErrorIs(t, err, models.ErrAppointmentNotFound)
+
+		_, err = s.SetAppointmentStatus(ctx, "1", first.ID, models.AppointmentStatusBooked, models.AppointmentStatusCancelled, "")
+		require.NoError(t, err)
+		_, err = s.SetAppointmentStatus(ctx, "1", first.ID, models.AppointmentStatusBooked, models.AppointmentStatusCancelled, "")
+		assert.ErrorIs(t, err, models.ErrAppointmentState)
+		_, err = s.BookAppointment(ctx, "1", booking)
+		require.NoError(t, err, "cancelled appointments free their place")
+
+		checkedIn, err := s.SetAppointmentStatus(ctx, "1", second.ID, models.AppointmentStatusBooked, models.AppointmentStatusCheckedIn, "AC001")
+		require.NoError(t, err)
+		assert.Equal(t, "AC001", checkedIn.Ticket)
+
+		appointments, err := s.ListAppointments(ctx, "1", slot, slot.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, appointments, 3)
+		assert.Equal(t, first.ID, appointments[0].ID)
+
+		expired, err := s.ExpireAppointments(ctx, slot.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), expired, "only booked appointments expire")
+		appointments, err = s.ListAppointments(ctx, "1", slot, slot.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, models.AppointmentStatusCancelled, appointments[0].Status)
+		assert.Equal(t, models.AppointmentStatusCheckedIn, appointments[1].Status)
+		assert.Equal(t, models.AppointmentStatusExpired, appointments[2].Status)
+	})
+
+	t.Run("working hours of service points and offices", func(t *testing.T) {
+		s := newStorage(t)
+
+		hours := models.WorkingHoursRequest{
+			Timezone: "Europe/Berlin",
+			Week: models.WeekHours{
+				Monday: &models.DayHours{Open: "09:00", Close: "18:00", BreakStart: "13:00", BreakEnd: "14:00"},
+			},
+			Holidays:               []models.Holiday{{Date: "2026-12-25", Name: "Christmas"}},
+			StopBeforeCloseMinutes: 15,
+		}
+
+		_, err := s.GetWorkingHours(ctx, "1")
+		assert.ErrorIs(t, err, models.ErrHoursNotFound)
+		_, err = s.GetOfficeHours(ctx, "101")
+		assert.ErrorIs(t, err, models.ErrHoursNotFound)
+		assert.ErrorIs(t, s.DeleteWorkingHours(ctx, "1"), models.ErrHoursNotFound)
+
+		stored, err := s.UpsertWorkingHours(ctx, "1", hours)
+		require.NoError(t, err)
+		assert.Equal(t, models.WorkingHoursSourceServicePoint, stored.Source)
+		assert.Equal(t, int64(1), stored.ServicePointID)
+
+		got, err := s.GetWorkingHours(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, hours.Timezone, got.Timezone)
+		assert.Equal(t, hours.Week, got.Week)
+		assert.Nil(t, got.Week.Tuesday)
+		assert.Equal(t, hours.Holidays, got.Holidays)
+		assert.Equal(t, 15, got.StopBeforeCloseMinutes)
+
+		hours.Week.Monday = nil
+		_, err = s.UpsertWorkingHours(ctx, "1", hours)
+		require.NoError(t, err)
+		got, err = s.GetWorkingHours(ctx, "1")
+		require.NoError(t, err)
+		assert.Nil(t, got.Week.Monday)
+
+		office, err := s.UpsertOfficeHours(ctx, "101", hours)
+		require.NoError(t, err)
+		assert.Equal(t, models.WorkingHoursSourceOffice, office.Source)
+		got, err = s.GetOfficeHours(ctx, "101")
+		require.NoError(t, err)
+		assert.Equal(t, "101", got.OfficeNumber)
+
+		require.NoError(t, s.DeleteWorkingHours(ctx, "1"))
+		_, err = s.GetWorkingHours(ctx, "1")
+		assert.ErrorIs(t, err, models.ErrHoursNotFound)
+	})
+
+	t.Run("limits are stored with the service point", func(t *testing.T) {
+		s := newStorage(t)
+
+		limited := cashDesk
+		limited.MaxQueueLength = 20
+		limited.DailyTicketQuota = 150
+		_, err := s.CreateServicePoint(ctx, "1", limited)
+		require.NoError(t, err)
+		sp, err := s.GetServicePointByID(ctx, "1", false)
+		require.NoError(t, err)
+		assert.Equal(t, 20, sp.MaxQueueLength)
+		assert.Equal(t, 150, sp.DailyTicketQuota)
+
+		updated, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+		assert.Zero(t, updated.MaxQueueLength)
+		assert.Zero(t, updated.DailyTicketQuota)
+	})
+
+	t.Run("issued tickets count without transfers", func(t *testing.T) {
+		s := newStorage(t)
+
+		for _, code := range []string{"C001", "C002"} {
+			_, err := s.CreateTicket(ctx, "1", code, models.TicketClassRegular)
+			require.NoError(t, err)
+		}
+		_, err := s.CreateTransferredTicket(ctx, "1", "A001", models.TicketClassRegular, 2, false)
+		require.NoError(t, err)
+		_, err = s.CreateTicket(ctx, "2", "A002", models.TicketClassRegular)
+		require.NoError(t, err)
+
+		issued, err := s.CountIssuedTickets(ctx, "1", time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 2, issued)
+
+		issued, err = s.CountIssuedTickets(ctx, "1", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, issued)
+	})
+
+	t.Run("status changes are stored and audited", func(t *testing.T) {
+		s := newStorage(t)
+
+		created, err := s.CreateServicePoint(ctx, "1", cashDesk)
+		require.NoError(t, err)
+		assert.Equal(t, models.ServicePointStatusOpen, created.Status)
+
+		returnAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		paused, err := s.SetServicePointStatus(ctx, "1", models.ServicePointStatusPaused, models.StatusChange{Reason: "Lunch break", ReturnAt: &returnAt})
+		require.NoError(t, err)
+		assert.Equal(t, created.Version+1, paused.Version)
+
+		got, err := s.GetServicePointByID(ctx, "1", false)
+		require.NoError(t, err)
+		assert.Equal(t, models.ServicePointStatusPaused, got.Status)
+		assert.Equal(t, "Lunch break", got.StatusReason)
+		require.NotNil(t, got.ReturnAt)
+		assert.True(t, returnAt.Equal(*got.ReturnAt))
+
+		updated, err := s.UpsertServicePoint(ctx, "1", accounts, 0)
+		require.NoError(t, err)
+		assert.Equal(t, models.ServicePointStatusPaused, updated.Status, "kept by upserts")
+
+		resumed, err := s.SetServicePointStatus(ctx, "1", models.ServicePointStatusOpen, models.StatusChange{})
+		require.NoError(t, err)
+		assert.Empty(t, resumed.StatusReason)
+		assert.Nil(t, resumed.ReturnAt)
+
+		records, err := s.GetServicePointHistory(ctx, "1", 0, 10)
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+		assert.Equal(t, models.AuditActionPause, records[1].Action)
+		assert.Equal(t, models.AuditActionResume, records[3].Action)
+
+		_, err = s.DeleteServicePoint(ctx, "1", 0)
+		require.NoError(t, err)
+		_, err = s.SetServicePointStatus(ctx, "1", models.ServicePointStatusClosed, models.StatusChange{})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = s.SetServicePointStatus(ctx, "2", models.ServicePointStatusClosed, models.StatusChange{})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("operators and desk sessions", func(t *testing.T) {
+		s := newStorage(t)
+
+		alice, err := s.CreateOperator(ctx, models.NewOperatorRequest{Name: "Alice"})
+		require.NoError(t, err)
+		bob, err := s.CreateOperator(ctx, models.NewOperatorRequest{Name: "Bob"})
+		require.NoError(t, err)
+
+		operators, err := s.ListOperators(ctx)
+		require.NoError(t, err)
+		require.Len(t, operators, 2)
+		assert.Equal(t, "Alice", operators[0].Name)
+
+		got, err := s.GetOperator(ctx, strconv.FormatInt(bob.ID, 10))
+		require.NoError(t, err)
+		assert.Equal(t, "Bob", got.Name)
+		_, err = s.GetOperator(ctx, strconv.FormatInt(bob.ID+1, 10))
+		assert.ErrorIs(t, err, models.ErrOperatorNotFound)
+
+		_, err = s.GetActiveSession(ctx, "1")
+		assert.ErrorIs(t, err, models.ErrSessionNotFound)
+
+		first, err := s.StartSession(ctx, "1", models.NewSessionRequest{OperatorID: alice.ID, DeskNumber: "7"})
+		require.NoError(t, err)
+		assert.Nil(t, first.EndedAt)
+
+		_, err = s.StartSession(ctx, "1", models.NewSessionRequest{OperatorID: bob.ID, DeskNumber: "8"})
+		assert.ErrorIs(t, err, models.ErrSessionActive)
+
+		_, err = s.StartSession(ctx, "2", models.NewSessionRequest{OperatorID: alice.ID, DeskNumber: "9"})
+		require.NoError(t, err)
+		_, err = s.GetActiveSession(ctx, "1")
+		assert.ErrorIs(t, err, models.ErrSessionNotFound, "logging in elsewhere ends the session")
+
+		active, err := s.GetActiveSession(ctx, "2")
+		require.NoError(t, err)
+		assert.Equal(t, alice.ID, active.OperatorID)
+		assert.Equal(t, "9", active.DeskNumber)
+
+		_, err = s.CreateTicket(ctx, "2", "C001", models.TicketClassRegular)
+		require.NoError(t, err)
+		called, err := s.CallTicket(ctx, "2", "C001", models.TicketCall{OfficeNumber: "101", OperatorID: alice.ID, DeskNumber: "9"})
+		require.NoError(t, err)
+		assert.Equal(t, alice.ID, called.OperatorID)
+		assert.Equal(t, "9", called.DeskNumber)
+
+		ended, err := s.EndSession(ctx, "2")
+		require.NoError(t, err)
+		require.NotNil(t, ended.EndedAt)
+		_, err = s.EndSession(ctx, "2")
+		assert.ErrorIs(t, err, models.ErrSessionNotFound)
+
+		sessions, err := s.ListSessions(ctx, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		assert.Equal(t, first.ID, sessions[0].ID)
+
+		sessions, err = s.ListSessions(ctx, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+
+	t.Run("categories and their tickets", func(t *testing.T) {
+		s := newStorage(t)
+
+		payments, err := s.CreateCategory(ctx, models.NewCategoryRequest{Name: "Payments", ShortName: "K"})
+		require.NoError(t, err)
+		assert.Empty(t, payments.ServicePointIDs)
+		_, err = s.CreateCategory(ctx, models.NewCategoryRequest{Name: "Loans", ShortName: "K"})
+		assert.ErrorIs(t, err, models.ErrAlreadyExists)
+		loans, err := s.CreateCategory(ctx, models.NewCategoryRequest{Name: "Loans", ShortName: "L"})
+		require.NoError(t, err)
+
+		_, err = s.GetCategory(ctx, strconv.FormatInt(loans.ID+1, 10))
+		assert.ErrorIs(t, err, models.ErrCategoryNotFound)
+
+		categories, err := s.SetServicePointCategories(ctx, "2", []int64{payments.ID, loans.ID})
+		require.NoError(t, err)
+		assert.Len(t, categories, 2)
+		_, err = s.SetServicePointCategories(ctx, "1", []int64{payments.ID})
+		require.NoError(t, err)
+		categories, err = s.SetServicePointCategories(ctx, "2", []int64{loans.ID})
+		require.NoError(t, err)
+		require.Len(t, categories, 1, "replaces the previous categories")
+		assert.Equal(t, loans.ID, categories[0].ID)
+		_, err = s.SetServicePointCategories(ctx, "1", []int64{loans.ID + 1})
+		assert.ErrorIs(t, err, models.ErrCategoryNotFound)
+
+		got, err := s.GetCategory(ctx, strconv.FormatInt(payments.ID, 10))
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, got.ServicePointIDs)
+
+		categories, err = s.ListCategories(ctx)
+		require.NoError(t, err)
+		require.Len(t, categories, 2)
+		assert.Equal(t, []int64{2}, categories[1].ServicePointIDs)
+
+		for _, code := range []string{"K001", "K002"} {
+			_, err := s.CreateCategoryTicket(ctx, payments.ID, code)
+			require.NoError(t, err)
+		}
+		_, err = s.CreateCategoryTicket(ctx, loans.ID, "L001")
+		require.NoError(t, err)
+
+		waiting, err := s.ListCategoryTickets(ctx, models.CategoryTicketFilter{CategoryIDs: []int64{payments.ID}, Status: models.TicketStatusWaiting})
+		require.NoError(t, err)
+		require.Len(t, waiting, 2)
+		assert.Equal(t, "K001", waiting[0].Code, "oldest first")
+
+		called, err := s.ClaimCategoryTicket(ctx, payments.ID, "K001", "1", models.TicketCall{OfficeNumber: "101"})
+		require.NoError(t, err)
+		assert.Equal(t, models.TicketStatusCalled, called.Status)
+		assert.Equal(t, payments.ID, called.CategoryID)
+		assert.True(t, waiting[0].IssuedAt.Equal(called.IssuedAt), "keeps its issue time")
+		_, err = s.ClaimCategoryTicket(ctx, payments.ID, "K001", "1", models.TicketCall{OfficeNumber: "101"})
+		assert.ErrorIs(t, err, models.ErrTicketNotFound)
+
+		tickets, err := s.ListTickets(ctx, "1", models.TicketFilter{Status: models.TicketStatusCalled})
+		require.NoError(t, err)
+		require.Len(t, tickets, 1)
+		assert.Equal(t, "K001", tickets[0].Code)
+
+		issued, err := s.CountIssuedTickets(ctx, "1", time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, issued, "issued for the category")
+
+		claimed, err := s.ListCategoryTickets(ctx, models.CategoryTicketFilter{Code: "K001"})
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, int64(1), claimed[0].ServicePointID)
+
+		cancelled, err := s.SetCategoryTicketStatus(ctx, waiting[1].ID, models.TicketStatusWaiting, models.TicketStatusCancelled)
+		require.NoError(t, err)
+		assert.Equal(t, models.TicketStatusCancelled, cancelled.Status)
+		assert.Equal(t, "K002", cancelled.Code)
+		_, err = s.SetCategoryTicketStatus(ctx, waiting[1].ID, models.TicketStatusWaiting, models.TicketStatusCancelled)
+		assert.ErrorIs(t, err, models.ErrTicketNotFound)
+		_, err = s.SetCategoryTicketStatus(tenant.NewContext(ctx, "city"), waiting[0].ID, models.TicketStatusCalled, models.TicketStatusServed)
+		assert.ErrorIs(t, err, models.ErrTicketNotFound, "other organisations do not see it")
+	})
+
+	t.Run("organisations", func(t *testing.T) {
+		s := newStorage(t)
+
+		organisations, err := s.ListOrganisations(ctx)
+		require.NoError(t, err)
+		require.Len(t, organisations, 1)
+		assert.Equal(t, tenant.Default, organisations[0].ID)
+
+		created, err := s.CreateOrganisation(ctx, models.NewOrganisationRequest{ID: "city", Name: "City hall", Host: "city.example.org"})
+		require.NoError(t, err)
+		assert.Equal(t, "city", created.ID)
+		assert.False(t, created.CreatedAt.IsZero())
+		_, err = s.CreateOrganisation(ctx, models.NewOrganisationRequest{ID: "city", Name: "Other"})
+		assert.ErrorIs(t, err, models.ErrOrganisationExists)
+		_, err = s.CreateOrganisation(ctx, models.NewOrganisationRequest{ID: "other", Name: "Other", Host: "city.example.org"})
+		assert.ErrorIs(t, err, models.ErrOrganisationExists, "host is taken")
+		_, err = s.CreateOrganisation(ctx, models.NewOrganisationRequest{ID: "bank", Name: "Bank"})
+		require.NoError(t, err)
+
+		got, err := s.GetOrganisation(ctx, "city")
+		require.NoError(t, err)
+		assert.Equal(t, "city.example.org", got.Host)
+		got, err = s.GetOrganisationByHost(ctx, "city.example.org")
+		require.NoError(t, err)
+		assert.Equal(t, "city", got.ID)
+		_, err = s.GetOrganisation(ctx, "nobody")
+		assert.ErrorIs(t, err, models.ErrOrganisationNotFound)
+		_, err = s.GetOrganisationByHost(ctx, "")
+		assert.ErrorIs(t, err, models.ErrOrganisationNotFound)
+
+		organisations, err = s.ListOrganisations(ctx)
+		require.NoError(t, err)
+		require.Len(t, organisations, 3)
+		assert.Equal(t, "bank", organisations[0].ID, "ordered by id")
+	})
+
+	t.Run("organisations keep their data apart", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.CreateOrganisation(ctx, models.NewOrganisationRequest{ID: "city", Name: "City hall"})
+		require.NoError(t, err)
+		city := tenant.NewContext(ctx, "city")
+
+		_, err = s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+		_, err = s.UpsertServicePoint(city, "2", accounts, 0)
+		require.NoError(t, err)
+
+		_, err = s.GetServicePointByID(city, "1", true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = s.GetShortNameById(city, "1")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = s.UpsertServicePoint(city, "1", accounts, 0)
+		assert.ErrorIs(t, err, models.ErrAlreadyExists, "ids are unique across organisations")
+		_, err = s.CreateServicePoint(city, "1", accounts)
+		assert.ErrorIs(t, err, models.ErrAlreadyExists)
+		_, err = s.DeleteServicePoint(city, "1", 0)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = s.SetServicePointStatus(city, "1", models.ServicePointStatusClosed, models.StatusChange{})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		list, err := s.ListServicePoints(ctx, true)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, int64(1), list[0].ID)
+		list, err = s.ListServicePoints(city, true)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, int64(2), list[0].ID)
+
+		history, err := s.GetServicePointHistory(city, "1", 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, history)
+
+		_, err = s.CreateTicket(ctx, "1", "C001", models.TicketClassRegular)
+		require.NoError(t, err)
+		_, err = s.CreateTicket(city, "2", "A001", models.TicketClassRegular)
+		require.NoError(t, err)
+		tickets, err := s.ListTickets(city, "1", models.TicketFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, tickets)
+		_, err = s.CallTicket(city, "1", "C001", models.TicketCall{OfficeNumber: "101"})
+		assert.ErrorIs(t, err, models.ErrTicketNotFound)
+		tickets, err = s.ListAllTickets(city, models.TicketFilter{})
+		require.NoError(t, err)
+		require.Len(t, tickets, 1)
+		assert.Equal(t, "A001", tickets[0].Code)
+
+		_, err = s.UpsertOfficeHours(ctx, "101", models.WorkingHoursRequest{Timezone: "UTC"})
+		require.NoError(t, err)
+		_, err = s.GetOfficeHours(city, "101")
+		assert.ErrorIs(t, err, models.ErrHoursNotFound)
+		_, err = s.UpsertOfficeHours(city, "101", models.WorkingHoursRequest{Timezone: "Europe/Paris"})
+		require.NoError(t, err, "office numbers are per organisation")
+		hours, err := s.GetOfficeHours(ctx, "101")
+		require.NoError(t, err)
+		assert.Equal(t, "UTC", hours.Timezone)
+
+		operator, err := s.CreateOperator(ctx, models.NewOperatorRequest{Name: "Alice"})
+		require.NoError(t, err)
+		_, err = s.GetOperator(city, strconv.FormatInt(operator.ID, 10))
+		assert.ErrorIs(t, err, models.ErrOperatorNotFound)
+		operators, err := s.ListOperators(city)
+		require.NoError(t, err)
+		assert.Empty(t, operators)
+
+		category, err := s.CreateCategory(ctx, models.NewCategoryRequest{Name: "Payments", ShortName: "K"})
+		require.NoError(t, err)
+		_, err = s.CreateCategory(city, models.NewCategoryRequest{Name: "Payments", ShortName: "K"})
+		require.NoError(t, err, "short names are per organisation")
+		_, err = s.GetCategory(city, strconv.FormatInt(category.ID, 10))
+		assert.ErrorIs(t, err, models.ErrCategoryNotFound)
+		_, err = s.SetServicePointCategories(city, "2", []int64{category.ID})
+		assert.ErrorIs(t, err, models.ErrCategoryNotFound)
+		_, err = s.CreateCategoryTicket(ctx, category.ID, "K001")
+		require.NoError(t, err)
+		waiting, err := s.ListCategoryTickets(city, models.CategoryTicketFilter{Code: "K001"})
+		require.NoError(t, err)
+		assert.Empty(t, waiting)
+
+		building, err := s.CreateBuilding(ctx, models.NewBuildingRequest{Name: "Main building"})
+		require.NoError(t, err)
+		buildingID := strconv.FormatInt(building.ID, 10)
+		_, err = s.GetBuilding(city, buildingID)
+		assert.ErrorIs(t, err, models.ErrBuildingNotFound)
+		_, err = s.CreateFloor(city, buildingID, models.NewFloorRequest{Name: "Ground floor"})
+		assert.ErrorIs(t, err, models.ErrBuildingNotFound)
+		buildings, err := s.ListBuildings(city)
+		require.NoError(t, err)
+		assert.Empty(t, buildings)
+		floor, err := s.CreateFloor(ctx, buildingID, models.NewFloorRequest{Name: "Ground floor"})
+		require.NoError(t, err)
+		office, err := s.CreateOffice(ctx, strconv.FormatInt(floor.ID, 10), models.NewOfficeRequest{Number: "101"})
+		require.NoError(t, err)
+		_, err = s.GetLocation(city, strconv.FormatInt(office.ID, 10))
+		assert.ErrorIs(t, err, models.ErrOfficeNotFound)
+	})
+
+	t.Run("buildings, floors and offices", func(t *testing.T) {
+		s := newStorage(t)
+
+		building, err := s.CreateBuilding(ctx, models.NewBuildingRequest{Name: "Main building", Address: "1 Lenin St", Directions: "Entrance from the courtyard"})
+		require.NoError(t, err)
+		buildingID := strconv.FormatInt(building.ID, 10)
+		got, err := s.GetBuilding(ctx, buildingID)
+		require.NoError(t, err)
+		assert.Equal(t, "1 Lenin St", got.Address)
+		_, err = s.GetBuilding(ctx, strconv.FormatInt(building.ID+1, 10))
+		assert.ErrorIs(t, err, models.ErrBuildingNotFound)
+		buildings, err := s.ListBuildings(ctx)
+		require.NoError(t, err)
+		assert.Len(t, buildings, 1)
+
+		second, err := s.CreateFloor(ctx, buildingID, models.NewFloorRequest{Name: "Second floor", Level: 2, Directions: "Lift on the left"})
+		require.NoError(t, err)
+		assert.Equal(t, building.ID, second.BuildingID)
+		_, err = s.CreateFloor(ctx, buildingID, models.NewFloorRequest{Name: "Also second", Level: 2})
+		assert.ErrorIs(t, err, models.ErrFloorExists)
+		_, err = s.CreateFloor(ctx, buildingID, models.NewFloorRequest{Name: "Ground floor", Level: 0})
+		require.NoError(t, err)
+		_, err = s.CreateFloor(ctx, strconv.FormatInt(building.ID+1, 10), models.NewFloorRequest{Name: "Ground floor"})
+		assert.ErrorIs(t, err, models.ErrBuildingNotFound)
+		floors, err := s.ListFloors(ctx, buildingID)
+		require.NoError(t, err)
+		require.Len(t, floors, 2)
+		assert.Equal(t, []int{0, 2}, []int{floors[0].Level, floors[1].Level})
+		floorID := strconv.FormatInt(second.ID, 10)
+		gotFloor, err := s.GetFloor(ctx, floorID)
+		require.NoError(t, err)
+		assert.Equal(t, "Lift on the left", gotFloor.Directions)
+
+		office, err := s.CreateOffice(ctx, floorID, models.NewOfficeRequest{Number: "202", Name: "Accounts"})
+		require.NoError(t, err)
+		assert.Equal(t, second.ID, office.FloorID)
+		_, err = s.CreateOffice(ctx, floorID, models.NewOfficeRequest{Number: "201"})
+		require.NoError(t, err)
+		_, err = s.CreateOffice(ctx, floorID, models.NewOfficeRequest{Number: "202"})
+		assert.ErrorIs(t, err, models.ErrOfficeExists)
+		_, err = s.CreateOffice(ctx, strconv.FormatInt(second.ID+10, 10), models.NewOfficeRequest{Number: "1"})
+		assert.ErrorIs(t, err, models.ErrFloorNotFound)
+		offices, err := s.ListOffices(ctx, floorID)
+		require.NoError(t, err)
+		require.Len(t, offices, 2)
+		assert.Equal(t, "201", offices[0].Number)
+
+		location, err := s.GetLocation(ctx, strconv.FormatInt(office.ID, 10))
+		require.NoError(t, err)
+		assert.Equal(t, "Accounts", location.Office.Name)
+		assert.Equal(t, 2, location.Floor.Level)
+		assert.Equal(t, "Entrance from the courtyard", location.Building.Directions)
+		_, err = s.GetLocation(ctx, strconv.FormatInt(office.ID+10, 10))
+		assert.ErrorIs(t, err, models.ErrOfficeNotFound)
+
+		placed := accounts
+		placed.OfficeID = office.ID
+		sp, err := s.UpsertServicePoint(ctx, "1", placed, 0)
+		require.NoError(t, err)
+		assert.Equal(t, office.ID, sp.OfficeID)
+		sp, err = s.UpsertServicePoint(ctx, "1", accounts, 0)
+		require.NoError(t, err)
+		assert.Zero(t, sp.OfficeID)
+		_, err = s.CreateServicePoint(ctx, "2", placed)
+		require.NoError(t, err)
+		sp, err = s.GetServicePointByID(ctx, "2", false)
+		require.NoError(t, err)
+		assert.Equal(t, office.ID, sp.OfficeID)
+	})
+
+	t.Run("import writes every service point or none", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.UpsertServicePoint(ctx, "1", cashDesk, 0)
+		require.NoError(t, err)
+		_, err = s.UpsertServicePoint(ctx, "2", cashDesk, 0)
+		require.NoError(t, err)
+		_, err = s.DeleteServicePoint(ctx, "2", 0)
+		require.NoError(t, err)
+
+		servicePoints, err := s.ImportServicePoints(ctx, []models.ServicePointWrite{
+			{ID: "1", ServicePoint: accounts},
+			{ID: "3", Create: true, ServicePoint: accounts},
+			{ID: "2", ServicePoint: accounts},
+		})
+		var rowErr *models.RowError
+		require.ErrorAs(t, err, &rowErr)
+		assert.Equal(t, 3, rowErr.Row)
+		assert.ErrorIs(t, err, models.ErrDeleted)
+		assert.Nil(t, servicePoints)
+
+		sp, err := s.GetServicePointByID(ctx, "1", false)
+		require.NoError(t, err)
+		assert.Equal(t, cashDesk.Name, sp.Name, "the failed import was rolled back")
+		_, err = s.GetServicePointByID(ctx, "3", true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		_, err = s.ImportServicePoints(ctx, []models.ServicePointWrite{
+			{ID: "4", Create: true, ServicePoint: cashDesk},
+			{ID: "1", Create: true, ServicePoint: cashDesk},
+		})
+		require.ErrorAs(t, err, &rowErr)
+		assert.Equal(t, 2, rowErr.Row)
+		assert.ErrorIs(t, err, models.ErrAlreadyExists)
+
+		// Ids on different shards land in one transaction.
+		servicePoints, err = s.ImportServicePoints(ctx, []models.ServicePointWrite{
+			{ID: "1", ServicePoint: accounts},
+			{ID: "3", Create: true, ServicePoint: accounts},
+			{ID: "4", ServicePoint: cashDesk},
+		})
+		require.NoError(t, err)
+		require.Len(t, servicePoints, 3)
+		assert.Equal(t, accounts.Name, servicePoints[0].Name)
+		assert.Equal(t, int64(2), servicePoints[0].Version)
+		assert.Equal(t, int64(1), servicePoints[1].Version)
+		assert.Equal(t, int(shard.Of(shard.Hash("4"), nShards)), servicePoints[2].ShardID)
+
+		records, err := s.GetServicePointHistory(ctx, "3", 0, 10)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, models.AuditActionCreate, records[0].Action)
+
+		_, err = s.CreateOrganisation(ctx, models.NewOrganisationRequest{ID: "city", Name: "City hall"})
+		require.NoError(t, err)
+		city := tenant.NewContext(ctx, "city")
+		_, err = s.ImportServicePoints(city, []models.ServicePointWrite{{ID: "1", ServicePoint: cashDesk}})
+		assert.ErrorIs(t, err, models.ErrAlreadyExists)
+	})
+
+	t.Run("list returns all shards ordered by id", func(t *testing.T) {
+		s := newStorage(t)
+
+		servicePoints, err := s.ListServicePoints(ctx, false)
+		require.NoError(t, err)
+		assert.Empty(t, servicePoints)
+
+		for _, id := range []string{"9", "2", "14", "5"} {
+			_, err := s.UpsertServicePoint(ctx, id, cashDesk, 0)
+			require.NoError(t, err)
+		}
+
+		servicePoints, err = s.ListServicePoints(ctx, false)
+		require.NoError(t, err)
+		require.Len(t, servicePoints, 4)
+		for i, want := range []int64{2, 5, 9, 14} {
+			assert.Equal(t, want, servicePoints[i].ID)
+			assert.Equal(t, int(shard.Of(shard.Hash(fmt.Sprint(want)), nShards)), servicePoints[i].ShardID)
+		}
+	})
+
+	t.Run("concurrent upserts", func(t *testing.T) {
+		s := newStorage(t)
+
+		var wg sync.WaitGroup
+		for i := 1; i <= 20; i++ {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				_, err := s.UpsertServicePoint(ctx, id, cashDesk, 0)
+				assert.NoError(t, err)
+			}(fmt.Sprint(i))
+		}
+		wg.Wait()
+
+		for i := 1; i <= 20; i++ {
+			_, err := s.GetServicePointByID(ctx, fmt.Sprint(i), false)
+			assert.NoError(t, err)
+		}
+	})
+}
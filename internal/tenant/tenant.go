@@ -0,0 +1,39 @@
+// Package tenant carries the organisation a request acts for through the
+// context so storage can keep the data of organisations apart.
+package tenant
+
+import "context"
+
+// Default is the organisation of requests that name none, and the owner of
+// everything created before there were organisations.
+const Default = "default"
+
+type idKey struct{}
+
+type adminKey struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// FromContext returns the organisation ID stored in ctx, or Default if there
+// is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	if id == "" {
+		return Default
+	}
+	return id
+}
+
+// NewAdminContext marks ctx as coming from an administrator of the
+// deployment, who may manage every organisation.
+func NewAdminContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin reports whether ctx was marked by NewAdminContext.
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
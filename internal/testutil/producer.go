@@ -0,0 +1,98 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/producer"
+	"github.com/snnus/mainservice/internal/tenant"
+)
+
+var ErrPublishFailed = errors.New("publish failed")
+
+// Producer records every published ticket and status change instead of
+// writing to Kafka.
+type Producer struct {
+	mu       sync.Mutex
+	messages []producer.TicketMessage
+	statuses []producer.StatusMessage
+	failing  bool
+}
+
+func NewProducer() *Producer {
+	return &Producer{}
+}
+
+func (p *Producer) PublishTicket(ctx context.Context, ticket, officeNumber, deskNumber string, location *models.Location) error {
+	return p.publishTicket(ctx, models.TicketEventCalled, ticket, officeNumber, deskNumber, location)
+}
+
+func (p *Producer) PublishTicketEvent(ctx context.Context, event, ticket, officeNumber, deskNumber string) error {
+	return p.publishTicket(ctx, event, ticket, officeNumber, deskNumber, nil)
+}
+
+func (p *Producer) publishTicket(ctx context.Context, event, ticket, officeNumber, deskNumber string, location *models.Location) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failing {
+		return ErrPublishFailed
+	}
+
+	p.messages = append(p.messages, producer.TicketMessage{
+		Tenant:       tenant.FromContext(ctx),
+		Event:        event,
+		Ticket:       ticket,
+		OfficeNumber: officeNumber,
+		DeskNumber:   deskNumber,
+		Location:     location,
+	})
+	return nil
+}
+
+func (p *Producer) PublishStatus(ctx context.Context, sp models.ServicePoint) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failing {
+		return ErrPublishFailed
+	}
+
+	msg := producer.StatusMessage{
+		Tenant:         tenant.FromContext(ctx),
+		Event:          models.ServicePointEventStatus,
+		ServicePointID: sp.ID,
+		OfficeNumber:   sp.OfficeNumber,
+		Status:         sp.Status,
+		Reason:         sp.StatusReason,
+	}
+	if sp.ReturnAt != nil {
+		msg.ReturnAt = sp.ReturnAt.UTC().Format(time.RFC3339)
+	}
+	p.statuses = append(p.statuses, msg)
+	return nil
+}
+
+// SetFailing makes every subsequent publish return ErrPublishFailed.
+func (p *Producer) SetFailing(failing bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failing = failing
+}
+
+// Messages returns a copy of everything published so far.
+func (p *Producer) Messages() []producer.TicketMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]producer.TicketMessage(nil), p.messages...)
+}
+
+// StatusMessages returns a copy of every status change published so far.
+func (p *Producer) StatusMessages() []producer.StatusMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]producer.StatusMessage(nil), p.statuses...)
+}
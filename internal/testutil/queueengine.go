@@ -0,0 +1,181 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/snnus/mainservice/config"
+	"github.com/snnus/mainservice/internal/models"
+)
+
+// QueueEngine is an in-process stand-in for the external queue engine. It
+// speaks the same HTTP protocol as the real one: POST /enqueue/{id}?sname=X
+// and POST /dequeue/{id}, both answering 200 with a models.Ticket, and
+// POST /cancel/{id}?ticket=X and POST /insert/{id}?ticket=X&front=B answering
+// 200 with no body, and GET /length/{id} answering 200 with {"length": N}.
+type QueueEngine struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	queues   map[string][]string
+	counters map[string]int
+	failing  bool
+}
+
+func NewQueueEngine() *QueueEngine {
+	qe := &QueueEngine{
+		queues:   make(map[string][]string),
+		counters: make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /enqueue/{id}", qe.enqueue)
+	mux.HandleFunc("POST /dequeue/{id}", qe.dequeue)
+	mux.HandleFunc("POST /cancel/{id}", qe.cancel)
+	mux.HandleFunc("POST /insert/{id}", qe.insert)
+	mux.HandleFunc("GET /length/{id}", qe.length)
+	qe.server = httptest.NewServer(mux)
+
+	return qe
+}
+
+func (qe *QueueEngine) Close() {
+	qe.server.Close()
+}
+
+// Config returns a config pointing the HTTP client at the stand-in.
+func (qe *QueueEngine) Config() config.QeConfig {
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(qe.server.URL, "http://"))
+	return config.QeConfig{Addr: host, Port: port}
+}
+
+// SetFailing makes every subsequent request answer 500 until reset.
+func (qe *QueueEngine) SetFailing(failing bool) {
+	qe.mu.Lock()
+	defer qe.mu.Unlock()
+	qe.failing = failing
+}
+
+// Queue returns the tickets waiting for the given service point in order.
+func (qe *QueueEngine) Queue(id string) []string {
+	qe.mu.Lock()
+	defer qe.mu.Unlock()
+	return append([]string(nil), qe.queues[id]...)
+}
+
+// Len returns the number of tickets waiting for the given service point.
+func (qe *QueueEngine) Len(id string) int {
+	qe.mu.Lock()
+	defer qe.mu.Unlock()
+	return len(qe.queues[id])
+}
+
+func (qe *QueueEngine) enqueue(w http.ResponseWriter, r *http.Request) {
+	qe.mu.Lock()
+	defer qe.mu.Unlock()
+
+	if qe.failing {
+		http.Error(w, "queue engine unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	id := r.PathValue("id")
+	sname := r.URL.Query().Get("sname")
+	if sname == "" {
+		http.Error(w, "sname is required", http.StatusBadRequest)
+		return
+	}
+
+	qe.counters[id]++
+	ticket := fmt.Sprintf("%s%03d", sname, qe.counters[id])
+	qe.queues[id] = append(qe.queues[id], ticket)
+
+	writeTicket(w, ticket)
+}
+
+func (qe *QueueEngine) dequeue(w http.ResponseWriter, r *http.Request) {
+	qe.mu.Lock()
+	defer qe.mu.Unlock()
+
+	if qe.failing {
+		http.Error(w, "queue engine unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	id := r.PathValue("id")
+	if len(qe.queues[id]) == 0 {
+		http.Error(w, "queue is empty", http.StatusNotFound)
+		return
+	}
+
+	ticket := qe.queues[id][0]
+	qe.queues[id] = qe.queues[id][1:]
+
+	writeTicket(w, ticket)
+}
+
+func (qe *QueueEngine) cancel(w http.ResponseWriter, r *http.Request) {
+	qe.mu.Lock()
+	defer qe.mu.Unlock()
+
+	if qe.failing {
+		http.Error(w, "queue engine unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	id := r.PathValue("id")
+	ticket := r.URL.Query().Get("ticket")
+	for i, queued := range qe.queues[id] {
+		if queued == ticket {
+			qe.queues[id] = append(qe.queues[id][:i], qe.queues[id][i+1:]...)
+			return
+		}
+	}
+	http.Error(w, "ticket is not queued", http.StatusNotFound)
+}
+
+func (qe *QueueEngine) insert(w http.ResponseWriter, r *http.Request) {
+	qe.mu.Lock()
+	defer qe.mu.Unlock()
+
+	if qe.failing {
+		http.Error(w, "queue engine unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	id := r.PathValue("id")
+	ticket := r.URL.Query().Get("ticket")
+	if ticket == "" {
+		http.Error(w, "ticket is required", http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Query().Get("front") == "true" {
+		qe.queues[id] = append([]string{ticket}, qe.queues[id]...)
+	} else {
+		qe.queues[id] = append(qe.queues[id], ticket)
+	}
+}
+
+func (qe *QueueEngine) length(w http.ResponseWriter, r *http.Request) {
+	qe.mu.Lock()
+	defer qe.mu.Unlock()
+
+	if qe.failing {
+		http.Error(w, "queue engine unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"length": len(qe.queues[r.PathValue("id")])})
+}
+
+func writeTicket(w http.ResponseWriter, ticket string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.Ticket{Ticket: ticket})
+}
@@ -0,0 +1,45 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/snnus/mainservice/config"
+	"github.com/snnus/mainservice/internal/models"
+	"github.com/snnus/mainservice/internal/services/spservice"
+	"github.com/snnus/mainservice/internal/storage/memstorage"
+)
+
+var ErrStorageUnavailable = errors.New("storage unavailable")
+
+// Storage is the in-memory storage with a switch to make writes fail.
+type Storage struct {
+	spservice.SPStorage
+
+	mu      sync.Mutex
+	failing bool
+}
+
+func NewStorage() *Storage {
+	s, _, _ := memstorage.NewSPStorage(&config.Config{})
+	return &Storage{SPStorage: s}
+}
+
+// SetFailing makes every subsequent upsert return ErrStorageUnavailable.
+func (s *Storage) SetFailing(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = failing
+}
+
+func (s *Storage) UpsertServicePoint(ctx context.Context, id string, sp models.NewServicePointRequest, ifVersion int64) (*models.ServicePoint, error) {
+	s.mu.Lock()
+	failing := s.failing
+	s.mu.Unlock()
+
+	if failing {
+		return nil, ErrStorageUnavailable
+	}
+	return s.SPStorage.UpsertServicePoint(ctx, id, sp, ifVersion)
+}
@@ -0,0 +1,14 @@
+-- Optimistic concurrency: every write bumps version, clients send it back
+-- in If-Match.
+
+ALTER TABLE shard_1.service_points
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE shard_2.service_points
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE shard_3.service_points
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE shard_4.service_points
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,11 @@
+-- Service point ids are global across shards, so they come from one central
+-- sequence instead of the per-shard SERIAL defaults. Start after every id
+-- callers have already chosen.
+CREATE SEQUENCE public.service_point_id_seq AS BIGINT;
+
+SELECT setval('public.service_point_id_seq', GREATEST(
+    (SELECT COALESCE(MAX(id), 0) FROM shard_1.service_points),
+    (SELECT COALESCE(MAX(id), 0) FROM shard_2.service_points),
+    (SELECT COALESCE(MAX(id), 0) FROM shard_3.service_points),
+    (SELECT COALESCE(MAX(id), 0) FROM shard_4.service_points)
+) + 1, false);
@@ -0,0 +1,14 @@
+-- Soft delete: deleted service points keep their row so tickets issued for
+-- them still resolve, and are hard-deleted by the purge job later.
+
+ALTER TABLE shard_1.service_points
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE shard_2.service_points
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE shard_3.service_points
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE shard_4.service_points
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
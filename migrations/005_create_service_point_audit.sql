@@ -0,0 +1,60 @@
+-- Audit log: every write to a service point records its state before and
+-- after, who made it and in which request. Records live in the same shard as
+-- the service point so they are written in the same transaction, and outlive
+-- the service point when it is purged.
+
+CREATE TABLE shard_1.service_point_audit (
+    id BIGSERIAL PRIMARY KEY,
+    service_point_id BIGINT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    before JSONB,
+    after JSONB,
+    caller TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX service_point_audit_service_point_id_shard_1
+    ON shard_1.service_point_audit (service_point_id, id);
+
+CREATE TABLE shard_2.service_point_audit (
+    id BIGSERIAL PRIMARY KEY,
+    service_point_id BIGINT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    before JSONB,
+    after JSONB,
+    caller TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX service_point_audit_service_point_id_shard_2
+    ON shard_2.service_point_audit (service_point_id, id);
+
+CREATE TABLE shard_3.service_point_audit (
+    id BIGSERIAL PRIMARY KEY,
+    service_point_id BIGINT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    before JSONB,
+    after JSONB,
+    caller TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX service_point_audit_service_point_id_shard_3
+    ON shard_3.service_point_audit (service_point_id, id);
+
+CREATE TABLE shard_4.service_point_audit (
+    id BIGSERIAL PRIMARY KEY,
+    service_point_id BIGINT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    before JSONB,
+    after JSONB,
+    caller TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX service_point_audit_service_point_id_shard_4
+    ON shard_4.service_point_audit (service_point_id, id);
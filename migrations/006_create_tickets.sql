@@ -0,0 +1,50 @@
+-- Tickets issued and called through mainservice, kept in the shard of their
+-- service point. The queue engine reuses codes, so they are not unique.
+
+CREATE TABLE shard_1.tickets (
+    id BIGSERIAL PRIMARY KEY,
+    service_point_id BIGINT NOT NULL,
+    code VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    called_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX tickets_service_point_id_code_shard_1
+    ON shard_1.tickets (service_point_id, code, issued_at);
+
+CREATE TABLE shard_2.tickets (
+    id BIGSERIAL PRIMARY KEY,
+    service_point_id BIGINT NOT NULL,
+    code VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    called_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX tickets_service_point_id_code_shard_2
+    ON shard_2.tickets (service_point_id, code, issued_at);
+
+CREATE TABLE shard_3.tickets (
+    id BIGSERIAL PRIMARY KEY,
+    service_point_id BIGINT NOT NULL,
+    code VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    called_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX tickets_service_point_id_code_shard_3
+    ON shard_3.tickets (service_point_id, code, issued_at);
+
+CREATE TABLE shard_4.tickets (
+    id BIGSERIAL PRIMARY KEY,
+    service_point_id BIGINT NOT NULL,
+    code VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    called_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX tickets_service_point_id_code_shard_4
+    ON shard_4.tickets (service_point_id, code, issued_at);
@@ -0,0 +1,14 @@
+-- Office a ticket was called to, as published to Kafka. Kept on the ticket
+-- because the service point may move to another office later.
+
+ALTER TABLE shard_1.tickets
+    ADD COLUMN office_number VARCHAR(10);
+
+ALTER TABLE shard_2.tickets
+    ADD COLUMN office_number VARCHAR(10);
+
+ALTER TABLE shard_3.tickets
+    ADD COLUMN office_number VARCHAR(10);
+
+ALTER TABLE shard_4.tickets
+    ADD COLUMN office_number VARCHAR(10);
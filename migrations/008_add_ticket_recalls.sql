@@ -0,0 +1,13 @@
+-- How often the desk called a ticket again after it did not show up.
+
+ALTER TABLE shard_1.tickets
+    ADD COLUMN recalls INT NOT NULL DEFAULT 0;
+
+ALTER TABLE shard_2.tickets
+    ADD COLUMN recalls INT NOT NULL DEFAULT 0;
+
+ALTER TABLE shard_3.tickets
+    ADD COLUMN recalls INT NOT NULL DEFAULT 0;
+
+ALTER TABLE shard_4.tickets
+    ADD COLUMN recalls INT NOT NULL DEFAULT 0;
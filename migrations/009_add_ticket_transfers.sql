@@ -0,0 +1,23 @@
+-- Transfers: a ticket sent to another service point is recorded there as a
+-- new ticket with the same code. queued_at orders the waiting tickets, so a
+-- ticket transferred to the front of a queue counts as ahead of the others.
+
+ALTER TABLE shard_1.tickets
+    ADD COLUMN queued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN transferred_from BIGINT;
+UPDATE shard_1.tickets SET queued_at = issued_at;
+
+ALTER TABLE shard_2.tickets
+    ADD COLUMN queued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN transferred_from BIGINT;
+UPDATE shard_2.tickets SET queued_at = issued_at;
+
+ALTER TABLE shard_3.tickets
+    ADD COLUMN queued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN transferred_from BIGINT;
+UPDATE shard_3.tickets SET queued_at = issued_at;
+
+ALTER TABLE shard_4.tickets
+    ADD COLUMN queued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN transferred_from BIGINT;
+UPDATE shard_4.tickets SET queued_at = issued_at;
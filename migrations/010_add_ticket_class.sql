@@ -0,0 +1,14 @@
+-- Ticket classes: every service point has a line per class, and tickets
+-- issued before classes existed were all regular.
+
+ALTER TABLE shard_1.tickets
+    ADD COLUMN class VARCHAR(16) NOT NULL DEFAULT 'regular';
+
+ALTER TABLE shard_2.tickets
+    ADD COLUMN class VARCHAR(16) NOT NULL DEFAULT 'regular';
+
+ALTER TABLE shard_3.tickets
+    ADD COLUMN class VARCHAR(16) NOT NULL DEFAULT 'regular';
+
+ALTER TABLE shard_4.tickets
+    ADD COLUMN class VARCHAR(16) NOT NULL DEFAULT 'regular';
@@ -0,0 +1,93 @@
+-- Appointments: every service point taking bookings has a slot schedule, and
+-- appointments live in the shard of their service point. Appointment ids are
+-- global across shards, like service point ids.
+
+CREATE SEQUENCE public.appointment_id_seq AS BIGINT;
+
+CREATE TABLE shard_1.slot_schedules (
+    service_point_id BIGINT PRIMARY KEY,
+    slot_minutes INT NOT NULL,
+    capacity INT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE shard_1.appointments (
+    id BIGINT PRIMARY KEY DEFAULT nextval('public.appointment_id_seq'),
+    service_point_id BIGINT NOT NULL,
+    slot_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    slot_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    ticket VARCHAR(16),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX appointments_service_point_id_slot_start_shard_1
+    ON shard_1.appointments (service_point_id, slot_start);
+
+CREATE TABLE shard_2.slot_schedules (
+    service_point_id BIGINT PRIMARY KEY,
+    slot_minutes INT NOT NULL,
+    capacity INT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE shard_2.appointments (
+    id BIGINT PRIMARY KEY DEFAULT nextval('public.appointment_id_seq'),
+    service_point_id BIGINT NOT NULL,
+    slot_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    slot_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    ticket VARCHAR(16),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX appointments_service_point_id_slot_start_shard_2
+    ON shard_2.appointments (service_point_id, slot_start);
+
+CREATE TABLE shard_3.slot_schedules (
+    service_point_id BIGINT PRIMARY KEY,
+    slot_minutes INT NOT NULL,
+    capacity INT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE shard_3.appointments (
+    id BIGINT PRIMARY KEY DEFAULT nextval('public.appointment_id_seq'),
+    service_point_id BIGINT NOT NULL,
+    slot_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    slot_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    ticket VARCHAR(16),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX appointments_service_point_id_slot_start_shard_3
+    ON shard_3.appointments (service_point_id, slot_start);
+
+CREATE TABLE shard_4.slot_schedules (
+    service_point_id BIGINT PRIMARY KEY,
+    slot_minutes INT NOT NULL,
+    capacity INT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE shard_4.appointments (
+    id BIGINT PRIMARY KEY DEFAULT nextval('public.appointment_id_seq'),
+    service_point_id BIGINT NOT NULL,
+    slot_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    slot_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    ticket VARCHAR(16),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX appointments_service_point_id_slot_start_shard_4
+    ON shard_4.appointments (service_point_id, slot_start);
@@ -0,0 +1,34 @@
+-- Working hours: offices have default hours that a service point may
+-- override with its own. Hours are kept as one JSONB document since they are
+-- always read and written as a whole. Offices span shards, so their hours
+-- live in the public schema.
+
+CREATE TABLE public.office_working_hours (
+    office_number VARCHAR(10) PRIMARY KEY,
+    hours JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE shard_1.working_hours (
+    service_point_id BIGINT PRIMARY KEY,
+    hours JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE shard_2.working_hours (
+    service_point_id BIGINT PRIMARY KEY,
+    hours JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE shard_3.working_hours (
+    service_point_id BIGINT PRIMARY KEY,
+    hours JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE shard_4.working_hours (
+    service_point_id BIGINT PRIMARY KEY,
+    hours JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,18 @@
+-- Admission control: a service point may cap how many tickets wait at once
+-- and how many are issued per day. Zero means no limit.
+
+ALTER TABLE shard_1.service_points
+    ADD COLUMN max_queue_length INT NOT NULL DEFAULT 0,
+    ADD COLUMN daily_ticket_quota INT NOT NULL DEFAULT 0;
+
+ALTER TABLE shard_2.service_points
+    ADD COLUMN max_queue_length INT NOT NULL DEFAULT 0,
+    ADD COLUMN daily_ticket_quota INT NOT NULL DEFAULT 0;
+
+ALTER TABLE shard_3.service_points
+    ADD COLUMN max_queue_length INT NOT NULL DEFAULT 0,
+    ADD COLUMN daily_ticket_quota INT NOT NULL DEFAULT 0;
+
+ALTER TABLE shard_4.service_points
+    ADD COLUMN max_queue_length INT NOT NULL DEFAULT 0,
+    ADD COLUMN daily_ticket_quota INT NOT NULL DEFAULT 0;
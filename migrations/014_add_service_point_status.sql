@@ -0,0 +1,22 @@
+-- Service point status: operators pause a service point for a break and close
+-- it for the day. A pause may say why and when the service point returns.
+
+ALTER TABLE shard_1.service_points
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'open',
+    ADD COLUMN status_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN return_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE shard_2.service_points
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'open',
+    ADD COLUMN status_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN return_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE shard_3.service_points
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'open',
+    ADD COLUMN status_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN return_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE shard_4.service_points
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'open',
+    ADD COLUMN status_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN return_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,40 @@
+-- Operators log in to a service point at a desk; the tickets they call
+-- record who called them and where. Operators and their sessions span
+-- shards, so they live in the public schema.
+
+CREATE TABLE public.operators (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE public.desk_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    operator_id BIGINT NOT NULL REFERENCES public.operators (id),
+    service_point_id BIGINT NOT NULL,
+    desk_number VARCHAR(10) NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMP WITH TIME ZONE
+);
+
+-- At most one active session per service point and per operator.
+CREATE UNIQUE INDEX desk_sessions_active_service_point
+    ON public.desk_sessions (service_point_id) WHERE ended_at IS NULL;
+CREATE UNIQUE INDEX desk_sessions_active_operator
+    ON public.desk_sessions (operator_id) WHERE ended_at IS NULL;
+
+ALTER TABLE shard_1.tickets
+    ADD COLUMN operator_id BIGINT,
+    ADD COLUMN desk_number VARCHAR(10);
+
+ALTER TABLE shard_2.tickets
+    ADD COLUMN operator_id BIGINT,
+    ADD COLUMN desk_number VARCHAR(10);
+
+ALTER TABLE shard_3.tickets
+    ADD COLUMN operator_id BIGINT,
+    ADD COLUMN desk_number VARCHAR(10);
+
+ALTER TABLE shard_4.tickets
+    ADD COLUMN operator_id BIGINT,
+    ADD COLUMN desk_number VARCHAR(10);
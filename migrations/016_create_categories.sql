@@ -0,0 +1,40 @@
+-- Service categories are served by several service points. Their tickets
+-- wait in one line shared across shards until a service point calls them,
+-- then continue as tickets of that service point.
+
+CREATE TABLE public.categories (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    short_name VARCHAR(10) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE public.service_point_categories (
+    service_point_id BIGINT NOT NULL,
+    category_id BIGINT NOT NULL REFERENCES public.categories (id),
+    PRIMARY KEY (service_point_id, category_id)
+);
+
+CREATE INDEX service_point_categories_category_id
+    ON public.service_point_categories (category_id);
+
+CREATE TABLE public.category_tickets (
+    id BIGSERIAL PRIMARY KEY,
+    category_id BIGINT NOT NULL REFERENCES public.categories (id),
+    code VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    called_at TIMESTAMP WITH TIME ZONE,
+    service_point_id BIGINT
+);
+
+CREATE INDEX category_tickets_category_id_status
+    ON public.category_tickets (category_id, status, issued_at);
+
+ALTER TABLE shard_1.tickets ADD COLUMN category_id BIGINT;
+
+ALTER TABLE shard_2.tickets ADD COLUMN category_id BIGINT;
+
+ALTER TABLE shard_3.tickets ADD COLUMN category_id BIGINT;
+
+ALTER TABLE shard_4.tickets ADD COLUMN category_id BIGINT;
@@ -0,0 +1,123 @@
+-- Organisations share one deployment. Every row belongs to one of them and
+-- every query is scoped to the organisation of the request; rows from before
+-- there were organisations belong to the default one. Service point ids stay
+-- unique across organisations.
+
+CREATE TABLE public.organisations (
+    id VARCHAR(64) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    host VARCHAR(255) UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO public.organisations (id, name) VALUES ('default', 'Default');
+
+ALTER TABLE public.office_working_hours
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE public.operators
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE public.desk_sessions
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE public.categories
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE public.service_point_categories
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE public.category_tickets
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE public.office_working_hours
+    DROP CONSTRAINT office_working_hours_pkey,
+    ADD PRIMARY KEY (tenant_id, office_number);
+
+ALTER TABLE public.categories
+    DROP CONSTRAINT categories_short_name_key,
+    ADD CONSTRAINT categories_tenant_id_short_name_key UNIQUE (tenant_id, short_name);
+
+ALTER TABLE shard_1.service_points
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_1.service_point_audit
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_1.tickets
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_1.slot_schedules
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_1.appointments
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_1.working_hours
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+CREATE INDEX service_points_tenant_id_shard_1
+    ON shard_1.service_points (tenant_id);
+
+ALTER TABLE shard_2.service_points
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_2.service_point_audit
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_2.tickets
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_2.slot_schedules
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_2.appointments
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_2.working_hours
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+CREATE INDEX service_points_tenant_id_shard_2
+    ON shard_2.service_points (tenant_id);
+
+ALTER TABLE shard_3.service_points
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_3.service_point_audit
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_3.tickets
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_3.slot_schedules
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_3.appointments
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_3.working_hours
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+CREATE INDEX service_points_tenant_id_shard_3
+    ON shard_3.service_points (tenant_id);
+
+ALTER TABLE shard_4.service_points
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_4.service_point_audit
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_4.tickets
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_4.slot_schedules
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_4.appointments
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+ALTER TABLE shard_4.working_hours
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES public.organisations (id);
+
+CREATE INDEX service_points_tenant_id_shard_4
+    ON shard_4.service_points (tenant_id);
@@ -0,0 +1,48 @@
+-- Locations: buildings have floors and floors have offices, each with a
+-- wayfinding hint for visitors. A service point may be placed in an office,
+-- whose number then becomes its office number.
+
+CREATE TABLE public.buildings (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    address VARCHAR(255) NOT NULL DEFAULT '',
+    directions VARCHAR(500) NOT NULL DEFAULT '',
+    tenant_id VARCHAR(64) NOT NULL REFERENCES public.organisations (id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX buildings_tenant_id ON public.buildings (tenant_id);
+
+CREATE TABLE public.floors (
+    id BIGSERIAL PRIMARY KEY,
+    building_id BIGINT NOT NULL REFERENCES public.buildings (id),
+    name VARCHAR(255) NOT NULL,
+    level INT NOT NULL,
+    directions VARCHAR(500) NOT NULL DEFAULT '',
+    tenant_id VARCHAR(64) NOT NULL REFERENCES public.organisations (id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (building_id, level)
+);
+
+CREATE TABLE public.offices (
+    id BIGSERIAL PRIMARY KEY,
+    floor_id BIGINT NOT NULL REFERENCES public.floors (id),
+    number VARCHAR(10) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    directions VARCHAR(500) NOT NULL DEFAULT '',
+    tenant_id VARCHAR(64) NOT NULL REFERENCES public.organisations (id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (floor_id, number)
+);
+
+ALTER TABLE shard_1.service_points
+    ADD COLUMN office_id BIGINT REFERENCES public.offices (id);
+
+ALTER TABLE shard_2.service_points
+    ADD COLUMN office_id BIGINT REFERENCES public.offices (id);
+
+ALTER TABLE shard_3.service_points
+    ADD COLUMN office_id BIGINT REFERENCES public.offices (id);
+
+ALTER TABLE shard_4.service_points
+    ADD COLUMN office_id BIGINT REFERENCES public.offices (id);
@@ -0,0 +1,14 @@
+-- Looking a ticket up by its code alone searches every service point of an
+-- organisation, which the service point index does not cover.
+
+CREATE INDEX tickets_tenant_id_code_shard_1
+    ON shard_1.tickets (tenant_id, code, issued_at);
+
+CREATE INDEX tickets_tenant_id_code_shard_2
+    ON shard_2.tickets (tenant_id, code, issued_at);
+
+CREATE INDEX tickets_tenant_id_code_shard_3
+    ON shard_3.tickets (tenant_id, code, issued_at);
+
+CREATE INDEX tickets_tenant_id_code_shard_4
+    ON shard_4.tickets (tenant_id, code, issued_at);